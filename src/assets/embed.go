@@ -10,3 +10,15 @@ var Themes embed.FS
 
 //go:embed code/*
 var Code embed.FS
+
+//go:embed layouts/*
+var Layouts embed.FS
+
+//go:embed web/*
+var Web embed.FS
+
+//go:embed standard/*
+var Standard embed.FS
+
+//go:embed percentiles/*
+var Percentiles embed.FS
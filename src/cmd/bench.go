@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal/config"
+	"gti/src/internal/session"
+)
+
+var benchInputs []string
+
+var benchCmd = &cobra.Command{
+	Use:   "bench --input <file>",
+	Short: "Score recorded key presses without the interface",
+	Long: `Play recorded key presses through the typing engine, with nothing drawn and
+nothing saved, and print the results the results screen would show. Use it to
+script gti, to see how a change to scoring treats real runs, or to test the
+engine in CI.
+
+Each input is a JSON file (or - for standard input) holding the text and its
+key presses, timed from the start of the run:
+
+  {
+    "text": "the quick brown fox",
+    "keystrokes": [{"t": 0, "k": "t"}, {"t": 180, "k": "h"}, ...]
+  }
+
+Keystrokes are timed in milliseconds, as in the replay files gti keeps of your
+best runs. The "events" of a JSON file from --export-timing, timed in
+microseconds, can be used in their place. Keys are single characters, "\n"
+for Enter, or "backspace". Optional fields: "code" to type the text as code,
+"seconds" for a timed test, "stop_on_error" and "no_backspace".
+
+EXAMPLES:
+  gti bench --input run.json          # Print the results of one run
+  gti bench -i a.json -i b.json       # Score several runs
+  gti bench -i run.json --json        # Print the results as JSON
+
+OPTIONS:
+  -i, --input <file>          Recorded run to play back (repeatable, - for standard input)`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(benchInputs) == 0 {
+			return fmt.Errorf("give a recorded run with --input <file>")
+		}
+		cfg := config.GetConfig()
+
+		var reports []session.ResultReport
+		for _, input := range benchInputs {
+			run, err := readBenchRun(input)
+			if err != nil {
+				return err
+			}
+			report, err := session.Bench(cfg, run)
+			if err != nil {
+				return fmt.Errorf("%s: %w", input, err)
+			}
+			reports = append(reports, report)
+			if !jsonOutput {
+				printBenchReport(input, report)
+			}
+		}
+		if jsonOutput {
+			if len(reports) == 1 {
+				return printJSON(reports[0])
+			}
+			return printJSON(reports)
+		}
+		return nil
+	},
+}
+
+func init() {
+	benchCmd.Flags().StringArrayVarP(&benchInputs, "input", "i", nil, "recorded run to play back (repeatable, - for standard input)")
+}
+
+// readBenchRun loads a recorded run from a file, or from standard input for "-"
+func readBenchRun(input string) (session.BenchRun, error) {
+	var run session.BenchRun
+	var data []byte
+	var err error
+	if input == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(config.ExpandPath(input))
+	}
+	if err != nil {
+		return run, err
+	}
+	if err := json.Unmarshal(data, &run); err != nil {
+		return run, fmt.Errorf("%s is not a recorded run: %w", input, err)
+	}
+	return run, nil
+}
+
+func printBenchReport(input string, report session.ResultReport) {
+	status := "finished"
+	if !report.Completed {
+		status = "not finished"
+	}
+	fmt.Printf("%s: %s\n", input, status)
+	fmt.Printf("  WPM:       %.1f (net %.1f)\n", report.WPM, report.NetWPM)
+	fmt.Printf("  Accuracy:  %.1f%%\n", report.Accuracy)
+	fmt.Printf("  Mistakes:  %d (%d corrected, %d left)\n", report.Mistakes, report.CorrectedErrors, report.UncorrectedErrors)
+	fmt.Printf("  Duration:  %.2fs\n", report.DurationSeconds)
+	fmt.Printf("  Typed:     %d characters, %d backspaces\n", report.TotalChars, report.BackspaceCount)
+	if report.Fatigue > 0 {
+		fmt.Printf("  Fatigue:   slowed %.0f%% in the final third\n", report.Fatigue)
+	} else if report.Fatigue < 0 {
+		fmt.Printf("  Fatigue:   sped up %.0f%% in the final third\n", -report.Fatigue)
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal/app"
+	"gti/src/internal/book"
+)
+
+var bookStart int
+var bookList bool
+
+var bookCmd = &cobra.Command{
+	Use:   "book <file>",
+	Short: "Type through a long text or e-book, resuming where you stopped",
+	Long: `Type a long text a paragraph at a time, from a plain text, Markdown or EPUB
+file. A bookmark is kept for each book, so the next 'gti book' on the same
+file carries on from the first paragraph not yet finished. The status bar
+shows the paragraph and how far through the book you are.
+
+Plain text paragraphs are separated by blank lines, and lines wrapped inside
+a paragraph are joined, as in Project Gutenberg texts.
+
+EXAMPLES:
+  gti book moby-dick.txt      # Resume the book
+  gti book novel.epub --start 1
+                              # Start again from the first paragraph
+  gti book --list             # Your books and how far you are in each
+
+OPTIONS:
+  --start <num>               Start from this paragraph instead of the bookmark
+  --list                      List your books and their progress`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if bookList {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if bookList {
+			listBooks()
+			return nil
+		}
+		if cmd.Flags().Changed("start") && bookStart < 1 {
+			return fmt.Errorf("--start counts paragraphs from 1")
+		}
+		return app.StartBook(args[0], bookStart)
+	},
+}
+
+func init() {
+	bookCmd.Flags().IntVar(&bookStart, "start", 0, "start from this paragraph instead of the bookmark")
+	bookCmd.Flags().BoolVar(&bookList, "list", false, "list your books and their progress")
+}
+
+// listBooks prints every book with a bookmark, most recently read first
+func listBooks() {
+	shelf := book.Shelf()
+	if len(shelf) == 0 {
+		fmt.Println("No books yet, start one with 'gti book <file>'")
+		return
+	}
+	for _, b := range shelf {
+		progress := fmt.Sprintf("paragraph %d/%d", min(b.Paragraph+1, b.Paragraphs), b.Paragraphs)
+		if b.Finished() {
+			progress = "finished"
+		}
+		fmt.Printf("  %5.1f%%  %-24s %s  (%s)\n", b.Percent(), progress, b.File, b.Updated.Format("2006-01-02"))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 	"gti/src/internal"
 	"gti/src/internal/app"
+	"gti/src/internal/config"
 )
 
 var codeLanguage string
@@ -14,28 +15,67 @@ var codeCount int
 var codeTimed string
 var codeCustom string
 var codeStart int
+var codeOpenAPI string
+var codeDifficulty string
+var codeRepo string
+var codeURL string
+var codeSkipComments bool
+var codeWords int
 
 var codeCmd = &cobra.Command{
 	Use:   "code [language]",
 	Short: "Practice typing with code snippets",
 	Long: `Practice typing with actual code snippets from various programming languages.
 
-Supported languages: go, python, javascript, java, cpp, rust, typescript
+Supported languages: go, python, javascript, java, cpp, rust, typescript,
+csharp, ruby, php, kotlin, swift, sql, bash, html, css
 
 EXAMPLES:
   gti code                    # Practice Go code (default)
   gti code python             # Practice Python code
   gti code javascript -n 3    # Practice 3 JavaScript snippets
   gti code -t 60              # Timed code practice (60 seconds)
+  gti code -w 50              # Code practice that ends after 50 words
   gti code java               # Practice Java code
+  gti code ruby               # Practice Ruby code
+  gti code rust --difficulty hard  # Practice long, symbol-heavy Rust snippets
+  gti code python --openapi api.yaml  # Practice client/handler code for an API spec
+  gti code --repo .           # Practice functions from your own codebase
+  gti code --url https://gist.github.com/<user>/<id>  # Practice code from a gist
 
 OPTIONS:
   -l, --language <lang>       Programming language (go, python, javascript, etc.)
   -n, --count <num>           Number of code snippets (default: 1)
   -c, --custom <file>         Practice with custom code file (.py, .go, .js, etc.)
   --start <num>               Start from paragraph number (for custom files)
-  -t, --timed <duration>      Timed mode with duration (e.g., 30, 10s, 5m)`,
+  -t, --timed <duration>      Timed mode with duration (e.g., 30, 10s, 5m)
+  -w, --words <num>           End once <num> words are typed, moving on to new snippets as needed
+  --difficulty <tier>         Snippet difficulty: easy (short, idiomatic) or hard (long, symbol-heavy)
+  --openapi <spec>            Generate request/handler snippets from an OpenAPI spec (YAML or JSON)
+  --repo <dir>                Practice functions from a local repository, respecting .gitignore
+                              (uses its most common language unless one is given)
+  --url <url>                 Download a source file (raw URL, GitHub file page or gist) and practice
+                              its functions; the language is detected unless one is given
+  --skip-comments             Leave out comment-only lines (or set skip_comments under [code])
+  --json-result               Print the results as JSON on standard output on exit`,
+	ValidArgsFunction: firstArg(completeCodeLanguages),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if codeSkipComments {
+			config.GetConfig().Code.SkipComments = true
+		}
+
+		if codeWords != 0 {
+			if codeWords < 0 {
+				return fmt.Errorf("--words must be a positive number of words")
+			}
+			if codeCustom != "" || codeTimed != "" || codeRepo != "" || codeURL != "" || codeOpenAPI != "" {
+				return fmt.Errorf("-w ends practice on generated snippets by word count, it cannot be used with -c, -t, --repo, --url or --openapi")
+			}
+			if cmd.Flags().Changed("count") {
+				return fmt.Errorf("-w sets the length of the practice, it cannot be used with -n")
+			}
+		}
+
 		// Check if custom file is specified
 		if codeCustom != "" {
 			// Handle custom code file - use custom-code mode for proper code rendering
@@ -66,13 +106,22 @@ OPTIONS:
 		if codeLanguage != "" {
 			language = codeLanguage
 		}
+		if (codeRepo != "" || codeURL != "") && len(args) == 0 && codeLanguage == "" {
+			// Let the repository or downloaded file decide
+			language = ""
+		}
 
 		// Validate language
-		if err := internal.ValidateCodeLanguage(language); err != nil {
+		if err := internal.ValidateCodeLanguage(language); language != "" && err != nil {
 			supportedLanguages := internal.GetSupportedCodeLanguages()
 			return fmt.Errorf("%s. Supported languages: %s", err.Error(), strings.Join(supportedLanguages, ", "))
 		}
 
+		codeDifficulty = strings.ToLower(codeDifficulty)
+		if err := internal.ValidateCodeDifficulty(codeDifficulty); err != nil {
+			return err
+		}
+
 		// Validate count
 		if codeCount < 1 {
 			codeCount = 1
@@ -81,13 +130,40 @@ OPTIONS:
 			codeCount = 10
 		}
 
+		if codeURL != "" {
+			timedSeconds := 0
+			if codeTimed != "" {
+				timedSeconds = parseDuration(codeTimed)
+			}
+			return app.StartURLPractice(codeURL, language, codeCount, timedSeconds)
+		}
+
+		if codeRepo != "" {
+			timedSeconds := 0
+			if codeTimed != "" {
+				timedSeconds = parseDuration(codeTimed)
+			}
+			return app.StartRepoPractice(codeRepo, language, codeCount, timedSeconds)
+		}
+
+		if codeOpenAPI != "" {
+			timedSeconds := 0
+			if codeTimed != "" {
+				timedSeconds = parseDuration(codeTimed)
+			}
+			return app.StartOpenAPIPractice(codeOpenAPI, language, codeCount, timedSeconds)
+		}
+
 		// Handle different nodes
+		if codeWords > 0 {
+			return app.StartCodePracticeWords(language, codeDifficulty, codeWords)
+		}
 		if codeTimed != "" {
 			// Timed 
 			timedSeconds := parseDuration(codeTimed)
-			return app.StartCodePracticeTimed(language, codeCount, timedSeconds)
+			return app.StartCodePracticeTimed(language, codeCount, codeDifficulty, timedSeconds)
 		} else {
-			return app.StartCodePractice(language, codeCount)
+			return app.StartCodePractice(language, codeCount, codeDifficulty)
 		}
 	},
 }
@@ -98,4 +174,12 @@ func init() {
 	codeCmd.Flags().StringVarP(&codeCustom, "custom", "c", "", "practice with custom code file (.py, .go, .js, etc.)")
 	codeCmd.Flags().IntVar(&codeStart, "start", 1, "start from paragraph number (for custom files)")
 	codeCmd.Flags().StringVarP(&codeTimed, "timed", "t", "", "timed mode with duration (e.g., 30, 10s, 5m)")
+	codeCmd.Flags().IntVarP(&codeWords, "words", "w", 0, "end once this many words are typed")
+	codeCmd.Flags().StringVar(&codeDifficulty, "difficulty", "", "snippet difficulty (easy, hard)")
+	codeCmd.Flags().StringVar(&codeOpenAPI, "openapi", "", "generate request/handler snippets from an OpenAPI spec")
+	codeCmd.Flags().StringVar(&codeRepo, "repo", "", "practice functions from a local repository")
+	codeCmd.Flags().StringVar(&codeURL, "url", "", "practice functions from a source file at a URL or GitHub gist")
+	codeCmd.Flags().BoolVar(&codeSkipComments, "skip-comments", false, "leave out comment-only lines")
+	codeCmd.RegisterFlagCompletionFunc("language", completeCodeLanguages)
+	codeCmd.RegisterFlagCompletionFunc("difficulty", cobra.FixedCompletions([]string{"easy", "hard"}, cobra.ShellCompDirectiveNoFileComp))
 }
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal"
+)
+
+// completeLanguages offers the word-list languages and auto for --language. A list such as
+// english,sp completes its last entry, keeping the ones before it.
+func completeLanguages(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	done, last := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		done, last = toComplete[:i+1], toComplete[i+1:]
+	}
+	var completions []string
+	if done == "" && strings.HasPrefix(internal.AutoLanguage, last) {
+		completions = append(completions, internal.AutoLanguage)
+	}
+	for _, lang := range internal.GetSupportedLanguages() {
+		if strings.HasPrefix(lang, last) && !strings.Contains(","+done, ","+lang+",") {
+			completions = append(completions, done+lang)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeCodeLanguages offers the languages there are code snippets for
+func completeCodeLanguages(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	langs := internal.GetSupportedCodeLanguages()
+	sort.Strings(langs)
+	return langs, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeThemes offers the built-in and imported themes
+func completeThemes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return getAvailableThemeNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// firstArg limits a completion to a command's first argument, so nothing is offered once it is given
+func firstArg(complete cobra.CompletionFunc) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(cmd, args, toComplete)
+	}
+}
@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"gti/src/internal"
 	"gti/src/internal/config"
+	"gti/src/internal/keymap"
+	"gti/src/internal/tts"
 )
 
 var (
@@ -16,17 +20,42 @@ var configCmd = &cobra.Command{
 	Use:   "config [flags]",
 	Short: "view and manage GTI configuration settings",
 	Long: `usage: gti config [flags]
+       gti config <command>
+
+commands:
+  list                  list every setting with its value
+  get <key>             print one setting, e.g. gti config get theme.active
+  set <key> <value>     change a setting and save it, e.g.
+                        gti config set theme.colors.accent "#ff00ff"
 
 flags:
-  --show        display current configuration values
+  --show        display current configuration values (as JSON with --json)
   --reset       reset configuration to default settings`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if showFlag {
+		if showFlag && jsonOutput {
+			if err := printJSON(config.Values(config.GetConfig())); err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+		} else if showFlag {
 			cfg := config.GetConfig()
 			fmt.Printf("Config file: %s\n\n", config.ConfigFile)
 			printTimedConfig(cfg.Timed)
 			printThemeConfig(cfg.Theme)
 			printHistoryConfig(cfg.History)
+			printCodeConfig(cfg.Code)
+			printKeyboardConfig(cfg.Keyboard)
+			printResultsConfig(cfg.Results)
+			printKioskConfig(cfg.Kiosk)
+			printMixedConfig(cfg.Mixed)
+			printSoundConfig(cfg.Sound)
+			printTTSConfig(cfg.TTS)
+			printNewsConfig(cfg.News)
+			printIdleConfig(cfg.Idle)
+			printImportConfig(cfg.Import)
+			printHooksConfig(cfg.Hooks)
+			printSyncConfig(cfg.Sync)
+			printStreaksConfig(cfg.Streaks)
+			printTipsConfig(cfg.Tips)
 		} else if resetFlag {
 			fmt.Println("Resetting config to defaults...")
 			if err := config.GenerateConfig(); err != nil {
@@ -40,6 +69,84 @@ flags:
 	},
 }
 
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list every setting with its value",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.GetConfig()
+		for _, key := range config.Keys(cfg) {
+			value, _ := config.Get(cfg, key)
+			fmt.Printf("%s = %s\n", key, value)
+		}
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "print one setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, err := config.Get(config.GetConfig(), args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "change a setting and save it",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+		key, err := config.CanonicalKey(cfg, args[0])
+		if err != nil {
+			return err
+		}
+		value := args[1]
+		if validate, ok := configValidators[key]; ok {
+			if err := validate(value); err != nil {
+				return err
+			}
+		}
+		if err := config.Set(cfg, key, value); err != nil {
+			return err
+		}
+		if key == "theme.active" {
+			// Switching themes brings the theme's colors along, as theme --set does
+			cfg.Theme.Colors = getThemeColors(value)
+		}
+		if err := config.SaveConfig(); err != nil {
+			return err
+		}
+		fmt.Printf("%s = %s\n", key, value)
+		return nil
+	},
+}
+
+// configValidators check settings whose valid values are known elsewhere in gti
+var configValidators = map[string]func(string) error{
+	"theme.active": func(value string) error {
+		if !isThemeAvailable(config.GetConfig(), value) {
+			return fmt.Errorf("theme '%s' is not available, see gti theme --list", value)
+		}
+		return nil
+	},
+	"language.default": internal.ValidateLanguage,
+	"keyboard.layout":  keymap.ValidateLayout,
+	"tts.engine":       tts.ValidateEngine,
+	"keyboard.emulate": func(value string) error {
+		if value == "" {
+			return nil
+		}
+		_, err := keymap.LoadEmulation(value)
+		return err
+	},
+}
+
 func printTimedConfig(timed config.TimedConfig) {
 	fmt.Println("Timed:")
 	fmt.Printf("  Default Seconds: %d\n", timed.DefaultSeconds)
@@ -56,10 +163,168 @@ func printHistoryConfig(history config.HistoryConfig) {
 	fmt.Println("History:")
 	fmt.Printf("  Enabled: %t\n", history.Enabled)
 	fmt.Printf("  File:    %s\n", history.File)
+	fmt.Printf("  Encrypt: %t\n", history.Encrypt)
+	fmt.Println()
+}
+
+func printCodeConfig(code config.CodeConfig) {
+	fmt.Println("Code:")
+	fmt.Printf("  Auto Indent:   %t\n", code.AutoIndent)
+	fmt.Printf("  Skip Comments: %t\n", code.SkipComments)
+	fmt.Println()
+}
+
+func printKeyboardConfig(keyboard config.KeyboardConfig) {
+	fmt.Println("Keyboard:")
+	fmt.Printf("  Layout:  %s\n", keyboard.Layout)
+	fmt.Printf("  Emulate: %s\n", keyboard.Emulate)
+	fmt.Printf("  Paste:   %s\n", keyboard.Paste)
+	fmt.Printf("  Accents: %s\n", keyboard.Accents)
+	fmt.Println()
+}
+
+func printResultsConfig(results config.ResultsConfig) {
+	table := results.PercentileTable
+	if table == "" {
+		table = "(built in)"
+	}
+	fmt.Println("Results:")
+	fmt.Printf("  Auto Dismiss Seconds: %d\n", results.AutoDismissSeconds)
+	fmt.Printf("  Auto Chain:           %t\n", results.AutoChain)
+	fmt.Printf("  Percentiles:          %t\n", results.Percentiles)
+	fmt.Printf("  Percentile Table:     %s\n", table)
+	fmt.Println()
+}
+
+func printKioskConfig(kiosk config.KioskConfig) {
+	passcode := "(not set)"
+	if kiosk.Passcode != "" {
+		passcode = "(set)"
+	}
+	fmt.Println("Kiosk:")
+	fmt.Printf("  Passcode:     %s\n", passcode)
+	fmt.Printf("  Idle Seconds: %d\n", kiosk.IdleSeconds)
+	fmt.Printf("  Seconds:      %d\n", kiosk.Seconds)
+	fmt.Println()
+}
+
+func printMixedConfig(mixed config.MixedConfig) {
+	language := mixed.CodeLanguage
+	if language == "" {
+		language = "(any)"
+	}
+	fmt.Println("Mixed:")
+	fmt.Printf("  Chunks:        %d\n", mixed.Chunks)
+	fmt.Printf("  Weights:       words %d, quotes %d, code %d\n", mixed.Words, mixed.Quotes, mixed.Code)
+	fmt.Printf("  Code Language: %s\n", language)
+	fmt.Println()
+}
+
+func printSoundConfig(sound config.SoundConfig) {
+	fmt.Println("Sound:")
+	fmt.Printf("  Enabled: %t\n", sound.Enabled)
+	fmt.Printf("  Volume:  %d\n", sound.Volume)
+	fmt.Printf("  Pack:    %s\n", sound.Pack)
+	fmt.Printf("  Errors:  %t\n", sound.Errors)
+	fmt.Println()
+}
+
+func printTTSConfig(speech config.TTSConfig) {
+	voice := speech.Voice
+	if voice == "" {
+		voice = "(engine default)"
+	}
+	fmt.Println("Text-to-Speech:")
+	fmt.Printf("  Engine: %s\n", speech.Engine)
+	fmt.Printf("  Voice:  %s\n", voice)
+	fmt.Printf("  Rate:   %d wpm\n", speech.Rate)
+	fmt.Printf("  Volume: %d\n", speech.Volume)
+	fmt.Println()
+}
+
+func printNewsConfig(news config.NewsConfig) {
+	fmt.Println("News:")
+	fmt.Printf("  Cache: %d minutes\n", news.CacheMinutes)
+	fmt.Println("  Feeds:")
+	if len(news.Feeds) == 0 {
+		fmt.Println("    (none)")
+	}
+	for _, feed := range news.Feeds {
+		fmt.Printf("    %s\n", feed)
+	}
+	fmt.Println()
+}
+
+func printIdleConfig(idle config.IdleConfig) {
+	seconds := "(never pauses)"
+	if idle.Seconds > 0 {
+		seconds = fmt.Sprintf("%d", idle.Seconds)
+	}
+	fmt.Println("Idle:")
+	fmt.Printf("  Seconds: %s\n", seconds)
+	fmt.Println()
+}
+
+func printImportConfig(imp config.ImportConfig) {
+	fmt.Println("Import:")
+	fmt.Printf("  Quotes:     %t\n", imp.Quotes)
+	fmt.Printf("  Dashes:     %t\n", imp.Dashes)
+	fmt.Printf("  Whitespace: %t\n", imp.Whitespace)
+	fmt.Printf("  Control:    %t\n", imp.Control)
+	fmt.Println()
+}
+
+func printHooksConfig(hooks config.HooksConfig) {
+	fmt.Println("Hooks:")
+	fmt.Printf("  On Complete: %s\n", hooks.OnComplete)
+	fmt.Println()
+}
+
+func printSyncConfig(sync config.SyncConfig) {
+	backend := sync.Backend
+	if backend == "" {
+		backend = "(off)"
+	}
+	fmt.Println("Sync:")
+	fmt.Printf("  Backend:  %s\n", backend)
+	fmt.Printf("  URL:      %s\n", sync.URL)
+	fmt.Printf("  Bucket:   %s\n", sync.Bucket)
+	fmt.Printf("  Region:   %s\n", sync.Region)
+	fmt.Printf("  Prefix:   %s\n", sync.Prefix)
+	fmt.Printf("  Username: %s\n", sync.Username)
+	fmt.Println()
+}
+
+func printStreaksConfig(streaks config.StreaksConfig) {
+	restDays := "(none)"
+	if len(streaks.RestDays) > 0 {
+		restDays = strings.Join(streaks.RestDays, ", ")
+	}
+	fmt.Println("Streaks:")
+	fmt.Printf("  Rest Days:         %s\n", restDays)
+	fmt.Printf("  Freezes Per Month: %d\n", streaks.FreezesPerMonth)
+	fmt.Println()
+}
+
+func printTipsConfig(tips config.TipsConfig) {
+	file := tips.File
+	if file == "" {
+		file = "(tips.txt beside config.toml)"
+	}
+	fmt.Println("Tips:")
+	fmt.Printf("  Enabled:        %t\n", tips.Enabled)
+	fmt.Printf("  File:           %s\n", file)
+	fmt.Printf("  Built-in:       %t\n", tips.Builtin)
+	fmt.Printf("  Advice:         %t\n", tips.Advice)
+	fmt.Printf("  Rotate Seconds: %d\n", tips.RotateSeconds)
 	fmt.Println()
 }
 
 func init() {
 	configCmd.Flags().BoolVar(&showFlag, "show", false, "display current configuration values")
 	configCmd.Flags().BoolVar(&resetFlag, "reset", false, "reset configuration to default settings")
+
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
 }
@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/x/term"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+	"gti/src/internal/app"
+	"gti/src/internal/config"
+	"gti/src/internal/session"
+	"gti/src/internal/tts"
+	"gti/src/internal/vault"
+)
+
+var doctorOffline bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the setup and suggest fixes for what is wrong",
+	Long: `Check everything gti depends on and print a fix for each problem found:
+
+  config      config.toml parses, has no unknown keys and holds valid values
+  themes      imported theme files can be read and hold valid colors
+  speech      a speech engine for this system is installed
+  quotes      the quote provider can be reached
+  terminal    the terminal is large enough and shows colors
+  files       the config, data and cache folders and the history file are writable
+
+Exits with status 1 when a problem is found, so it can be used in scripts;
+--json prints the checks as JSON instead.
+
+EXAMPLES:
+  gti doctor                  # Run every check
+  gti doctor --offline        # Skip the network check
+  gti doctor --json           # Report the checks as JSON
+
+OPTIONS:
+  --offline                   Do not contact the quote provider`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+		d := &doctor{}
+
+		d.section("Config")
+		d.checkConfig()
+		d.section("Themes")
+		d.checkThemes(cfg)
+		d.section("Speech")
+		d.checkSpeech(cfg)
+		d.section("Quotes")
+		d.checkQuotes(cfg)
+		d.section("Terminal")
+		d.checkTerminal()
+		d.section("Files")
+		d.checkFiles(cfg)
+
+		if jsonOutput {
+			if err := printJSON(d); err != nil {
+				return err
+			}
+		} else {
+			fmt.Println()
+		}
+		if d.Problems == 0 {
+			if !jsonOutput {
+				fmt.Printf("No problems found (%d warnings).\n", d.Warnings)
+			}
+			return nil
+		}
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d problems found", d.Problems)
+	},
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorOffline, "offline", false, "do not contact the quote provider")
+}
+
+// doctor tallies what the checks found; only problems make the command fail
+type doctor struct {
+	Checks   []doctorCheck `json:"checks"`
+	Problems int           `json:"problems"`
+	Warnings int           `json:"warnings"`
+
+	current string
+}
+
+// doctorCheck is one line of the report, as --json prints it
+type doctorCheck struct {
+	Section string `json:"section"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Fix     string `json:"fix,omitempty"`
+}
+
+func (d *doctor) section(name string) {
+	d.current = name
+	if !jsonOutput {
+		fmt.Printf("\n%s\n", name)
+	}
+}
+
+func (d *doctor) ok(format string, args ...any) {
+	d.report("ok", fmt.Sprintf(format, args...), "")
+}
+
+func (d *doctor) warn(message, fix string) {
+	d.Warnings++
+	d.report("warn", message, fix)
+}
+
+func (d *doctor) fail(message, fix string) {
+	d.Problems++
+	d.report("fail", message, fix)
+}
+
+// report records a check, printing it straight away unless the report is wanted as JSON
+func (d *doctor) report(status, message, fix string) {
+	d.Checks = append(d.Checks, doctorCheck{Section: d.current, Status: status, Message: message, Fix: fix})
+	if jsonOutput {
+		return
+	}
+	fmt.Printf("  %-6s %s\n", "["+strings.ToUpper(status)+"]", message)
+	if fix != "" {
+		fmt.Printf("         fix: %s\n", fix)
+	}
+}
+
+// checkConfig reads config.toml afresh, since a file that fails to parse has already been replaced by the defaults
+func (d *doctor) checkConfig() {
+	if _, err := os.Stat(config.ConfigFile); err != nil {
+		d.fail(fmt.Sprintf("cannot read %s: %v", config.ConfigFile, err), "run 'gti config --reset' to write a fresh one")
+		return
+	}
+	cfg := config.DefaultConfig()
+	meta, err := toml.DecodeFile(config.ConfigFile, cfg)
+	if err != nil {
+		d.fail(fmt.Sprintf("%s does not parse: %v", config.ConfigFile, err),
+			"correct the line named above, or run 'gti config --reset' to start over (this discards your settings)")
+		return
+	}
+	d.ok("%s parses", config.ConfigFile)
+
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		sort.Strings(keys)
+		d.warn("unknown keys are ignored: "+strings.Join(keys, ", "),
+			"remove or rename them; 'gti config list' shows every key gti reads")
+	}
+
+	invalid := config.Validate(cfg)
+	for _, key := range config.Keys(cfg) {
+		validate, ok := configValidators[key]
+		if !ok {
+			continue
+		}
+		value, _ := config.Get(cfg, key)
+		if err := validate(value); err != nil {
+			invalid = append(invalid, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	for _, err := range invalid {
+		d.fail(err.Error(), "set a valid value with 'gti config set'")
+	}
+	if len(invalid) == 0 {
+		d.ok("every setting holds a valid value")
+	}
+}
+
+// checkThemes reads each imported theme the way the theme list does, reporting files it would silently skip
+func (d *doctor) checkThemes(cfg *config.Config) {
+	entries, err := os.ReadDir(config.ThemesDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		d.ok("no imported themes")
+	} else if err != nil {
+		d.fail(fmt.Sprintf("cannot read %s: %v", config.ThemesDir, err), "check the folder's permissions")
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(config.ThemesDir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			d.fail(fmt.Sprintf("cannot read theme %s: %v", path, err), "check the file's permissions")
+			continue
+		}
+		colors, err := parseThemeColors(data)
+		if err != nil {
+			d.fail(fmt.Sprintf("theme %s does not parse: %v", entry.Name(), err), "re-import it with 'gti theme import <file> --force'")
+			continue
+		}
+		if _, err := loadThemeFromEmbeddedFile("themes/" + entry.Name()); err == nil {
+			d.warn(fmt.Sprintf("theme %s is hidden by the built-in theme of the same name", entry.Name()),
+				"rename "+path)
+			continue
+		}
+		themeCfg := config.DefaultConfig()
+		themeCfg.Theme.Colors = colors
+		if errs := config.Validate(themeCfg); len(errs) > 0 {
+			for _, err := range errs {
+				d.fail(fmt.Sprintf("theme %s: %v", entry.Name(), err), "edit "+path+" or re-import it with 'gti theme import <file> --force'")
+			}
+			continue
+		}
+		if colors == (config.ThemeColorsConfig{}) {
+			d.fail(fmt.Sprintf("theme %s has no colors", entry.Name()), "delete "+path+" or re-import it")
+			continue
+		}
+		d.ok("theme %s", entry.Name())
+	}
+
+	if !isThemeAvailable(cfg, cfg.Theme.Active) {
+		d.fail(fmt.Sprintf("the active theme '%s' does not exist", cfg.Theme.Active),
+			"pick one from 'gti theme --list' with 'gti theme --set <name>'")
+	} else {
+		d.ok("active theme %s", cfg.Theme.Active)
+	}
+}
+
+// checkSpeech looks for the speech engine the listen mode and the context view would use
+func (d *doctor) checkSpeech(cfg *config.Config) {
+	speaker, err := tts.For(cfg.TTS)
+	if err != nil {
+		fix := "only needed for 'gti listen' and reading words aloud, otherwise safe to ignore"
+		if cfg.TTS.Engine != "auto" {
+			fix = "install it, or run 'gti config set tts.engine auto' to use whichever engine is installed"
+		}
+		d.warn(err.Error(), fix)
+		return
+	}
+	d.ok("words are read aloud with %s", speaker.Engine())
+}
+
+// checkQuotes asks the provider for a quote; without it quote mode falls back on the offline cache
+func (d *doctor) checkQuotes(cfg *config.Config) {
+	cached, _ := app.LoadCachedQuotes()
+	if doctorOffline {
+		d.ok("skipped, %d quotes cached for offline use", len(cached))
+		return
+	}
+	if err := app.CheckQuoteAPI(cfg); err != nil {
+		fix := fmt.Sprintf("check your connection, or raise network.timeout_ms (now %d)", cfg.Network.TimeoutMs)
+		if len(cached) > 0 {
+			fix += fmt.Sprintf("; until then quotes come from the %d cached ones", len(cached))
+		}
+		d.warn("the quote provider cannot be reached: "+err.Error(), fix)
+		return
+	}
+	d.ok("the quote provider answers, %d quotes cached for offline use", len(cached))
+}
+
+// checkTerminal holds the terminal to the 40x10 the typing screen needs and the 80x20 of the statistics screen
+func (d *doctor) checkTerminal() {
+	if !term.IsTerminal(os.Stdout.Fd()) {
+		d.warn("output is not a terminal, so its size and colors cannot be checked", "run 'gti doctor' directly in the terminal you type in")
+		return
+	}
+	width, height, err := term.GetSize(os.Stdout.Fd())
+	switch {
+	case err != nil:
+		d.warn("cannot read the terminal size: "+err.Error(), "")
+	case width < 40 || height < 10:
+		d.fail(fmt.Sprintf("the terminal is %dx%d, too small to type in", width, height), "resize it to at least 40x10")
+	case width < 80 || height < 20:
+		d.warn(fmt.Sprintf("the terminal is %dx%d, too small for the statistics screen", width, height), "resize it to at least 80x20")
+	default:
+		d.ok("the terminal is %dx%d", width, height)
+	}
+
+	switch termenv.NewOutput(os.Stdout).EnvColorProfile() {
+	case termenv.TrueColor:
+		d.ok("true color")
+	case termenv.ANSI256:
+		d.ok("256 colors, theme colors are shown as the nearest of them")
+	case termenv.ANSI:
+		d.warn("only 16 colors, so themes lose most of their colors",
+			"set COLORTERM=truecolor if your terminal supports it, or use a terminal that does")
+	default:
+		d.warn("no color support, mistakes show only through error marks",
+			"unset NO_COLOR, set TERM to your terminal (e.g. xterm-256color), or run 'gti config set theme.styles.mark_errors underline'")
+	}
+}
+
+// checkFiles makes sure everything gti saves to can be written; folders not made yet are checked at the nearest one that exists
+func (d *doctor) checkFiles(cfg *config.Config) {
+	for _, dir := range []string{config.ConfigDir, config.DataDir, config.CacheDir} {
+		if err := writable(dir); err != nil {
+			d.fail(fmt.Sprintf("cannot write to %s: %v", dir, err), "make it writable, e.g. chmod u+rwx "+dir)
+			continue
+		}
+		d.ok("%s is writable", dir)
+	}
+
+	if cfg.Results.Percentiles && cfg.Results.PercentileTable != "" {
+		if _, err := session.LoadPercentiles(cfg); err != nil {
+			d.fail(fmt.Sprintf("the percentile table cannot be used: %v", err), "fix the file, or clear results.percentile_table to use gti's own table")
+		} else {
+			d.ok("percentile table %s is readable", config.ExpandPath(cfg.Results.PercentileTable))
+		}
+	}
+
+	if !cfg.History.Enabled {
+		d.warn("history is off, so no results are saved for statistics", "run 'gti config set history.enabled true'")
+		return
+	}
+	history := config.ExpandPath(cfg.History.File)
+	if f, err := os.OpenFile(history, os.O_WRONLY, 0); err == nil {
+		f.Close()
+		d.ok("%s is writable", history)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		d.fail(fmt.Sprintf("cannot write to %s: %v", history, err), "make it writable, e.g. chmod u+rw "+history)
+	} else if err := writable(filepath.Dir(history)); err != nil {
+		d.fail(fmt.Sprintf("cannot create %s: %v", history, err), "make its folder writable, or point history.file elsewhere")
+	} else {
+		d.ok("%s will be created after the first test", history)
+	}
+	if cfg.History.Encrypt && !vault.HasKey(cfg) {
+		d.fail("history.encrypt is on but there is no key, so sessions are not saved", "run 'gti encrypt' to choose a passphrase")
+	} else if cfg.History.Encrypt {
+		d.ok("history is encrypted with %s", vault.KeyPath(cfg))
+	}
+}
+
+// writable reports whether a file can be created in dir, or in the nearest folder above it when dir does not exist yet
+func writable(dir string) error {
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return fmt.Errorf("no parent folder exists")
+		}
+		dir = parent
+	}
+	f, err := os.CreateTemp(dir, ".gti-doctor-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal"
+	"gti/src/internal/app"
+)
+
+var documentCount int
+var documentTimed string
+
+var documentCmd = &cobra.Command{
+	Use:   "document [format]",
+	Short: "Practice typing structured config documents",
+	Long: `Practice typing realistic, deeply nested configuration documents.
+
+Mistyped brackets, quotes, colons, commas and equals signs count as three
+mistakes each, since a single one breaks the whole file.
+
+Supported formats: json, yaml, toml
+
+EXAMPLES:
+  gti document                # Practice a JSON document (default)
+  gti document yaml           # Practice a YAML document
+  gti document toml -n 2      # Practice 2 TOML documents
+  gti document json -t 60     # Timed document practice (60 seconds)
+
+OPTIONS:
+  -n, --count <num>           Number of documents (default: 1)
+  -t, --timed <duration>      Timed mode with duration (e.g., 30, 10s, 5m)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := "json"
+		if len(args) > 0 {
+			format = strings.ToLower(args[0])
+		}
+
+		if err := internal.ValidateDocumentFormat(format); err != nil {
+			return fmt.Errorf("%s. Supported formats: %s", err.Error(), strings.Join(internal.GetSupportedDocumentFormats(), ", "))
+		}
+
+		if documentCount < 1 {
+			documentCount = 1
+		}
+		if documentCount > 5 {
+			documentCount = 5
+		}
+
+		timedSeconds := 0
+		if documentTimed != "" {
+			timedSeconds = parseDuration(documentTimed)
+		}
+		return app.StartDocumentPractice(format, documentCount, timedSeconds)
+	},
+}
+
+func init() {
+	documentCmd.Flags().IntVarP(&documentCount, "count", "n", 1, "number of documents")
+	documentCmd.Flags().StringVarP(&documentTimed, "timed", "t", "", "timed mode with duration (e.g., 30, 10s, 5m)")
+}
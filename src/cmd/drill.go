@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal"
+	"gti/src/internal/app"
+)
+
+var drillBoard string
+var drillCount int
+var drillTimed string
+
+var drillCmd = &cobra.Command{
+	Use:   "drill [command]",
+	Short: "Drills for numbers, symbols, random text, and ortholinear and split keyboards",
+	Long: `Practice the transitions that change most when moving to an ortholinear
+or split keyboard: bottom-row reaches, the centre columns and thumb keys.
+Each round focuses on one of them, with guidance shown while you type it.
+
+Supported boards: ortho (ortholinear), split
+
+COMMANDS:
+  numbers                     Text heavy in digits: prices, times, dates, versions
+  symbols                     Brackets, operators and punctuation around words
+  pseudo                      Pronounceable made-up words
+  random                      Random strings, with as many symbols as you like
+
+EXAMPLES:
+  gti drill --board split     # Split keyboard drill
+  gti drill --board ortho -n 20  # Longer ortholinear rounds
+  gti drill --board split -t 120 # Stop after two minutes
+  gti drill numbers -n 40     # 40 number tokens
+  gti drill symbols -t 60     # One minute of symbols
+  gti drill random --symbols 30  # Random strings, about a third symbols
+
+OPTIONS:
+  --board <type>              Keyboard type (ortho, split)
+  -n, --count <num>           Words per round (default: 12)
+  -t, --timed <duration>      Timed mode with duration (e.g., 30, 10s, 5m)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := internal.ValidateBoard(drillBoard); err != nil {
+			return fmt.Errorf("%s. Supported boards: %s", err.Error(), strings.Join(internal.GetSupportedBoards(), ", "))
+		}
+
+		if drillCount < 1 {
+			drillCount = 1
+		}
+		if drillCount > 50 {
+			drillCount = 50
+		}
+
+		timedSeconds := 0
+		if drillTimed != "" {
+			timedSeconds = parseDuration(drillTimed)
+		}
+		return app.StartBoardDrill(drillBoard, drillCount, timedSeconds)
+	},
+}
+
+var drillNumbersCmd = &cobra.Command{
+	Use:   "numbers",
+	Short: "Drill the number row",
+	Long: `Practice text made mostly of digits, the way they turn up in real text:
+plain numbers, prices, times, dates, phone numbers, percentages and version
+numbers, with an occasional word in between.
+
+EXAMPLES:
+  gti drill numbers           # 25 tokens
+  gti drill numbers -n 50     # 50 tokens
+  gti drill numbers -t 60     # Timed number drill (60 seconds)
+
+OPTIONS:
+  -n, --count <num>           Number of tokens (default: 25)
+  -t, --timed <duration>      Timed mode with duration (e.g., 30, 10s, 5m)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count, seconds := drillTokenOptions()
+		return app.StartNumberDrill(count, seconds)
+	},
+}
+
+var drillSymbolsCmd = &cobra.Command{
+	Use:   "symbols",
+	Short: "Drill brackets, operators and punctuation",
+	Long: `Practice the symbols that word lists leave out: brackets and quotes around
+words, operators such as && and !=, and punctuation, prefixes and suffixes
+like #, @, $, ; and ?.
+
+EXAMPLES:
+  gti drill symbols           # 25 tokens
+  gti drill symbols -n 50     # 50 tokens
+  gti drill symbols -t 60     # Timed symbol drill (60 seconds)
+
+OPTIONS:
+  -n, --count <num>           Number of tokens (default: 25)
+  -t, --timed <duration>      Timed mode with duration (e.g., 30, 10s, 5m)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count, seconds := drillTokenOptions()
+		return app.StartSymbolDrill(count, seconds)
+	},
+}
+
+var drillPseudoCmd = &cobra.Command{
+	Use:   "pseudo",
+	Short: "Drill pronounceable made-up words",
+	Long: `Practice words that are easy to say but in no dictionary, built from
+English-like syllables. With no spelling to remember, every letter has to be
+read and typed, which trains raw accuracy rather than word memory.
+
+EXAMPLES:
+  gti drill pseudo            # 25 pseudo-words
+  gti drill pseudo -n 50      # 50 pseudo-words
+  gti drill pseudo -t 60      # Timed pseudo-word drill (60 seconds)
+
+OPTIONS:
+  -n, --count <num>           Number of words (default: 25)
+  -t, --timed <duration>      Timed mode with duration (e.g., 30, 10s, 5m)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count, seconds := drillTokenOptions()
+		return app.StartPseudoWordDrill(count, seconds)
+	},
+}
+
+var drillSymbolPercent int
+
+var drillRandomCmd = &cobra.Command{
+	Use:   "random",
+	Short: "Drill random character strings",
+	Long: `Practice strings of random characters, with nothing to read ahead or
+guess. --symbols sets how many of the characters are digits and symbols
+rather than letters, from 0 for letters only to 100 for no letters at all.
+
+EXAMPLES:
+  gti drill random            # 25 strings of letters
+  gti drill random --symbols 20  # About one character in five a symbol
+  gti drill random -t 60      # Timed random drill (60 seconds)
+
+OPTIONS:
+  -n, --count <num>           Number of strings (default: 25)
+  --symbols <percent>         Share of digits and symbols (default: 0)
+  -t, --timed <duration>      Timed mode with duration (e.g., 30, 10s, 5m)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count, seconds := drillTokenOptions()
+		return app.StartRandomDrill(count, drillSymbolPercent, seconds)
+	},
+}
+
+var drillTokens int
+var drillTokensTimed string
+
+// drillTokenOptions clamps the token count and parses the time limit of the token drills
+func drillTokenOptions() (int, int) {
+	count := max(1, min(drillTokens, 200))
+	seconds := 0
+	if drillTokensTimed != "" {
+		seconds = parseDuration(drillTokensTimed)
+	}
+	return count, seconds
+}
+
+func init() {
+	drillCmd.Flags().StringVar(&drillBoard, "board", "", "keyboard type (ortho, split)")
+	drillCmd.Flags().IntVarP(&drillCount, "count", "n", 12, "words per round")
+	drillCmd.Flags().StringVarP(&drillTimed, "timed", "t", "", "timed mode with duration (e.g., 30, 10s, 5m)")
+
+	drillNumbersCmd.Flags().IntVarP(&drillTokens, "count", "n", 25, "number of tokens")
+	drillNumbersCmd.Flags().StringVarP(&drillTokensTimed, "timed", "t", "", "timed mode with duration (e.g., 30, 10s, 5m)")
+	drillSymbolsCmd.Flags().IntVarP(&drillTokens, "count", "n", 25, "number of tokens")
+	drillSymbolsCmd.Flags().StringVarP(&drillTokensTimed, "timed", "t", "", "timed mode with duration (e.g., 30, 10s, 5m)")
+	drillPseudoCmd.Flags().IntVarP(&drillTokens, "count", "n", 25, "number of words")
+	drillPseudoCmd.Flags().StringVarP(&drillTokensTimed, "timed", "t", "", "timed mode with duration (e.g., 30, 10s, 5m)")
+	drillRandomCmd.Flags().IntVarP(&drillTokens, "count", "n", 25, "number of strings")
+	drillRandomCmd.Flags().IntVar(&drillSymbolPercent, "symbols", 0, "percentage of characters that are digits and symbols")
+	drillRandomCmd.Flags().StringVarP(&drillTokensTimed, "timed", "t", "", "timed mode with duration (e.g., 30, 10s, 5m)")
+	drillCmd.AddCommand(drillNumbersCmd)
+	drillCmd.AddCommand(drillSymbolsCmd)
+	drillCmd.AddCommand(drillPseudoCmd)
+	drillCmd.AddCommand(drillRandomCmd)
+}
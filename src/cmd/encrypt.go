@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal/config"
+	"gti/src/internal/session"
+	"gti/src/internal/vault"
+)
+
+var encryptOff bool
+
+var encryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt your session history with a passphrase",
+	Long: `Encrypt your session history, daily rollups and statistics and keystroke
+timing exports, for anyone who shares a machine. The first time, choose a
+passphrase; gti makes a key beside the history file and turns history.encrypt on.
+
+Sessions are saved without asking for anything. Reading them back, with
+'gti statistics' or 'gti serve', asks for the passphrase, or takes it from
+GTI_PASSPHRASE; 'gti sync' sends them as they are, still encrypted. Exports
+are saved with a .sealed extension; read one with 'gti decrypt <file>'.
+
+There is no way back in without the passphrase. To read the history on
+another machine, copy the history.key file beside it there too.
+
+EXAMPLES:
+  gti encrypt                 # Choose a passphrase and encrypt the history
+  gti encrypt --off           # Decrypt the history and stop encrypting
+
+OPTIONS:
+  --off                       Decrypt the history and turn encryption off`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cfg := config.GetConfig()
+
+		if encryptOff {
+			if !cfg.History.Encrypt && !vault.HasKey(cfg) {
+				fmt.Println("The session history is not encrypted.")
+				return nil
+			}
+			if err := vault.Unlock(cfg); err != nil {
+				return err
+			}
+			cfg.History.Encrypt = false
+			count, err := session.ResealHistory(cfg)
+			if err != nil {
+				return fmt.Errorf("decrypting the history: %w", err)
+			}
+			if err := config.SaveConfig(); err != nil {
+				return fmt.Errorf("saving config: %w", err)
+			}
+			fmt.Printf("[SUCCESS] Decrypted %d sessions; new ones are saved in the clear\n", count)
+			return nil
+		}
+
+		if cfg.History.Encrypt && vault.HasKey(cfg) {
+			fmt.Println("The session history is already encrypted.")
+			return nil
+		}
+		if vault.HasKey(cfg) {
+			// Encrypting again after --off keeps the old key, so earlier exports stay readable
+			if err := vault.Unlock(cfg); err != nil {
+				return err
+			}
+		} else {
+			passphrase, err := newPassphrase()
+			if err != nil {
+				return err
+			}
+			if err := vault.Create(cfg, passphrase); err != nil {
+				return err
+			}
+			fmt.Printf("Key saved to %s\n", vault.KeyPath(cfg))
+		}
+		cfg.History.Encrypt = true
+		count, err := session.ResealHistory(cfg)
+		if err != nil {
+			return fmt.Errorf("encrypting the history: %w", err)
+		}
+		if err := config.SaveConfig(); err != nil {
+			return fmt.Errorf("saving config: %w", err)
+		}
+		fmt.Printf("[SUCCESS] Encrypted %d sessions; keep the passphrase safe, as they cannot be read without it\n", count)
+		return nil
+	},
+}
+
+var decryptCmd = &cobra.Command{
+	Use:   "decrypt <file>",
+	Short: "Print a file encrypted by gti",
+	Long: `Print an encrypted export, or the encrypted history, in the clear. Asks for
+the passphrase, or takes it from GTI_PASSPHRASE.
+
+EXAMPLES:
+  gti decrypt ~/Downloads/gti_statistics_all-time_2026-10-16_09-00-00.json.sealed
+  gti decrypt timing.csv.sealed > timing.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cfg := config.GetConfig()
+		data, err := os.ReadFile(config.ExpandPath(args[0]))
+		if err != nil {
+			return err
+		}
+
+		out := bufio.NewWriter(os.Stdout)
+		defer out.Flush()
+		for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+			if !vault.IsSealed(line) {
+				out.Write(line)
+				continue
+			}
+			opened, err := vault.Open(cfg, line)
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[0], err)
+			}
+			out.Write(opened)
+			if bytes.HasSuffix(line, []byte("\n")) && !bytes.HasSuffix(opened, []byte("\n")) {
+				out.WriteByte('\n')
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	encryptCmd.Flags().BoolVar(&encryptOff, "off", false, "decrypt the history and turn encryption off")
+}
+
+// newPassphrase asks for a new passphrase twice, or takes it from GTI_PASSPHRASE
+func newPassphrase() (string, error) {
+	passphrase, err := vault.Passphrase("New passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if os.Getenv(vault.PassphraseEnv) == "" {
+		again, err := vault.Passphrase("Repeat the passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", fmt.Errorf("the passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+// unlockHistory asks for the passphrase of an encrypted history before anything reads it, and then
+// brings the daily rollups up to date, which launching skips while the history is locked
+func unlockHistory(cfg *config.Config) error {
+	if !cfg.History.Encrypt || !cfg.History.Enabled {
+		return nil
+	}
+	if err := vault.Unlock(cfg); err != nil {
+		return err
+	}
+	if err := session.SnapshotRollups(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to update statistics rollups: %v\n", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+	"gti/src/internal/config"
+)
+
+var genDocsFormat string
+
+var genDocsCmd = &cobra.Command{
+	Use:    "gen-docs [dir]",
+	Short:  "Write man pages and Markdown docs for every command",
+	Hidden: true,
+	Long: `Write a manual for every command, for packagers to ship with gti: man pages
+to <dir>/man and Markdown to <dir>/markdown. The directory defaults to docs.
+
+Set SOURCE_DATE_EPOCH to date the man pages reproducibly.
+
+EXAMPLES:
+  gti gen-docs                # Write docs/man and docs/markdown
+  gti gen-docs out --format man
+
+OPTIONS:
+  --format <format>           man, markdown or all (default: all)`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "docs"
+		if len(args) > 0 {
+			dir = args[0]
+		}
+		if genDocsFormat != "man" && genDocsFormat != "markdown" && genDocsFormat != "all" {
+			return fmt.Errorf("--format must be man, markdown or all")
+		}
+
+		// The generated tag carries the day the docs were made, which would change every packaged build
+		rootCmd.DisableAutoGenTag = true
+
+		if genDocsFormat != "markdown" {
+			manDir := filepath.Join(dir, "man")
+			if err := config.EnsureDir(manDir); err != nil {
+				return err
+			}
+			header := &doc.GenManHeader{
+				Title:   "GTI",
+				Section: "1",
+				Source:  "GTI " + Version,
+				Manual:  "Typing Test Application",
+			}
+			if err := doc.GenManTree(rootCmd, header, manDir); err != nil {
+				return fmt.Errorf("writing man pages: %w", err)
+			}
+			fmt.Printf("[SUCCESS] Man pages written to %s\n", manDir)
+		}
+		if genDocsFormat != "man" {
+			mdDir := filepath.Join(dir, "markdown")
+			if err := config.EnsureDir(mdDir); err != nil {
+				return err
+			}
+			if err := doc.GenMarkdownTree(rootCmd, mdDir); err != nil {
+				return fmt.Errorf("writing Markdown docs: %w", err)
+			}
+			fmt.Printf("[SUCCESS] Markdown docs written to %s\n", mdDir)
+		}
+		return nil
+	},
+}
+
+func init() {
+	genDocsCmd.Flags().StringVar(&genDocsFormat, "format", "all", "man, markdown or all")
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"gti/src/internal/app"
+)
+
+var hangulWords int
+var hangulTimed string
+
+var hangulCmd = &cobra.Command{
+	Use:   "hangul",
+	Short: "Practice Hangul jamo on the 2-set layout",
+	Long: `Practice typing Hangul syllables one jamo at a time, using the keys of the
+standard 2-set (dubeolsik) layout on a US keyboard (한 = ㅎ ㅏ ㄴ = g k s).
+Accuracy is scored per jamo.
+
+EXAMPLES:
+  gti hangul                  # Practice 10 words
+  gti hangul -n 20            # Practice 20 words
+  gti hangul -t 60            # Timed Hangul practice (60 seconds)
+
+OPTIONS:
+  -n, --words <num>           Number of Hangul words (default: 10)
+  -t, --timed <duration>      Timed mode with duration (e.g., 30, 10s, 5m)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if hangulWords < 1 {
+			hangulWords = 1
+		}
+
+		timedSeconds := 0
+		if hangulTimed != "" {
+			timedSeconds = parseDuration(hangulTimed)
+		}
+		return app.StartHangulPractice(hangulWords, timedSeconds)
+	},
+}
+
+func init() {
+	hangulCmd.Flags().IntVarP(&hangulWords, "words", "n", 10, "number of Hangul words")
+	hangulCmd.Flags().StringVarP(&hangulTimed, "timed", "t", "", "timed mode with duration (e.g., 30, 10s, 5m)")
+}
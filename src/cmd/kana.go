@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal/app"
+)
+
+var kanaWords int
+var kanaTimed string
+
+var kanaCmd = &cobra.Command{
+	Use:   "kana [script]",
+	Short: "Practice hiragana/katakana with romaji input",
+	Long: `Practice reading and typing kana. Each kana is shown on screen and typed
+in Hepburn romaji (か = ka, し = shi); accuracy is scored per kana.
+
+Supported scripts: hiragana, katakana, mixed
+
+EXAMPLES:
+  gti kana                    # Practice hiragana (default)
+  gti kana katakana           # Practice katakana
+  gti kana mixed -n 20        # Practice 20 words mixing both scripts
+  gti kana -t 60              # Timed kana practice (60 seconds)
+
+OPTIONS:
+  -n, --words <num>           Number of kana words (default: 10)
+  -t, --timed <duration>      Timed mode with duration (e.g., 30, 10s, 5m)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		script := "hiragana"
+		if len(args) > 0 {
+			script = strings.ToLower(args[0])
+		}
+		if kanaWords < 1 {
+			kanaWords = 1
+		}
+
+		timedSeconds := 0
+		if kanaTimed != "" {
+			timedSeconds = parseDuration(kanaTimed)
+		}
+		return app.StartKanaPractice(script, kanaWords, timedSeconds)
+	},
+}
+
+func init() {
+	kanaCmd.Flags().IntVarP(&kanaWords, "words", "n", 10, "number of kana words")
+	kanaCmd.Flags().StringVarP(&kanaTimed, "timed", "t", "", "timed mode with duration (e.g., 30, 10s, 5m)")
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"gti/src/internal/app"
+	"gti/src/internal/tui"
+)
+
+var kioskPasscode string
+var kioskIdle int
+var kioskTimed string
+
+var kioskCmd = &cobra.Command{
+	Use:   "kiosk",
+	Short: "Unattended demo mode for shared machines",
+	Long: `Run gti on a library or school demo machine. The kiosk idles on an
+animated title screen alternating with a self-playing demo, starts a timed
+test when anyone presses a key, and goes back to the title screen after a
+stretch without input. Ctrl+C and Ctrl+Q ask for the passcode instead of
+quitting.
+
+EXAMPLES:
+  gti kiosk --passcode 4321           # Run the kiosk
+  gti kiosk --passcode 4321 -t 60     # 60 second tests for visitors
+  gti kiosk --idle 120                # Passcode taken from [kiosk] in the config
+
+OPTIONS:
+  --passcode <code>           Passcode needed to exit (default: passcode under [kiosk])
+  --idle <seconds>            Seconds without input before resetting (default: 60)
+  -t, --timed <duration>      Length of each visitor's test (default: 30s)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		seconds := 0
+		if kioskTimed != "" {
+			seconds = parseDuration(kioskTimed)
+		}
+		return app.StartKiosk(tui.KioskOptions{
+			Passcode:    kioskPasscode,
+			IdleSeconds: kioskIdle,
+			Seconds:     seconds,
+		})
+	},
+}
+
+func init() {
+	kioskCmd.Flags().StringVar(&kioskPasscode, "passcode", "", "passcode needed to exit kiosk mode")
+	kioskCmd.Flags().IntVar(&kioskIdle, "idle", 0, "seconds without input before returning to the attract screen")
+	kioskCmd.Flags().StringVarP(&kioskTimed, "timed", "t", "", "length of each visitor's test (e.g., 30, 10s, 1m)")
+}
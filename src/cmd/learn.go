@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal/app"
+	"gti/src/internal/config"
+	"gti/src/internal/course"
+)
+
+var learnWords int
+var learnList bool
+
+var learnCmd = &cobra.Command{
+	Use:   "learn [number]",
+	Short: "Take the beginner typing course",
+	Long: `A typing course for beginners, taken one lesson at a time in order:
+the home row, the top row, the bottom row, numbers, symbols, capitals and
+finally the most common words. Each lesson is typed in rounds and passed by
+reaching its speed and accuracy target over the whole run.
+
+Without a number, the first lesson not yet passed is started. The letter
+rows follow the keyboard layout from the config.
+
+EXAMPLES:
+  gti learn                   # Next lesson of the course
+  gti learn 4                 # Lesson 4, numbers
+  gti learn --list            # The lessons and your progress
+
+OPTIONS:
+  -n, --words <num>           Words per round (default: 20)
+  --list                      List the lessons and your progress`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if learnList {
+			listCourse()
+			return nil
+		}
+
+		number := 0
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid lesson number '%s'", args[0])
+			}
+			number = n
+		}
+		if learnWords < 1 {
+			learnWords = 1
+		}
+		return app.StartCourse(number, learnWords)
+	},
+}
+
+func init() {
+	learnCmd.Flags().IntVarP(&learnWords, "words", "n", 20, "words per round")
+	learnCmd.Flags().BoolVar(&learnList, "list", false, "list the lessons and your progress")
+}
+
+// listCourse prints the course's lessons with their targets, marking those passed and the next one
+func listCourse() {
+	lessons := course.Curriculum(config.GetConfig().Keyboard.Layout)
+	progress := course.LoadProgress()
+	next := progress.Next(lessons)
+
+	fmt.Printf("Typing course, %d/%d lessons passed:\n\n", min(progress.Completed, len(lessons)), len(lessons))
+	for _, l := range lessons {
+		mark := " "
+		switch {
+		case l.Number <= progress.Completed:
+			mark = "✓"
+		case l.Number == next:
+			mark = ">"
+		}
+		line := fmt.Sprintf("  %s %d  %-14s %3.0f wpm at %.0f%%", mark, l.Number, l.Name, l.MinWPM, l.MinAccuracy)
+		if best, ok := progress.BestRun(l.Number); ok {
+			line += fmt.Sprintf("   best %.0f wpm at %.1f%%", best.WPM, best.Accuracy)
+		}
+		fmt.Println(line)
+	}
+}
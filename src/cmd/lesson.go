@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal/app"
+	"gti/src/internal/config"
+	"gti/src/internal/lesson"
+)
+
+var lessonLayout string
+var lessonWords int
+var lessonList bool
+
+var lessonCmd = &cobra.Command{
+	Use:   "lesson [number]",
+	Short: "Learn a keyboard layout key by key",
+	Long: `Learn a layout through lessons that start on the home row and add a few keys
+at a time. Each lesson is practised in rounds of words that use only the keys
+learned so far, with the finger for every new key shown while you type.
+
+A lesson is passed once each of its new keys has been typed at least 10 times
+in a run at 90% accuracy or better; speed does not count. Without a number,
+the first lesson not yet passed is started.
+
+The layout is the one being emulated, else the keyboard layout from the
+config. Lessons for a layout to emulate (dvorak, colemak, ...) are typed
+through emulation.
+
+EXAMPLES:
+  gti lesson                    # Next lesson on your layout
+  gti lesson 3                  # Lesson 3
+  gti lesson --layout colemak   # Next Colemak lesson, emulated
+  gti lesson --layout dvorak --list  # The Dvorak lessons and your progress
+
+OPTIONS:
+  --layout <name>             Layout to learn
+  -n, --words <num>           Words per round (default: 15)
+  --list                      List the lessons and your key coverage`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+		layout := lessonLayout
+		if layout == "" {
+			layout = cfg.Keyboard.EmulatedLayout()
+		}
+		if layout == "" {
+			layout = cfg.Keyboard.Layout
+		}
+
+		if lessonList {
+			return listLessons(layout)
+		}
+
+		number := 0
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid lesson number '%s'", args[0])
+			}
+			number = n
+		}
+		if lessonWords < 1 {
+			lessonWords = 1
+		}
+		return app.StartLesson(layout, number, lessonWords)
+	},
+}
+
+func init() {
+	lessonCmd.Flags().StringVar(&lessonLayout, "layout", "", "layout to learn (qwerty, colemak, dvorak, ...)")
+	lessonCmd.Flags().IntVarP(&lessonWords, "words", "n", 15, "words per round")
+	lessonCmd.Flags().BoolVar(&lessonList, "list", false, "list the lessons and your key coverage")
+}
+
+// listLessons prints the layout's lessons, marking those passed and the next one
+func listLessons(layout string) error {
+	plan, err := lesson.PlanFor(layout)
+	if err != nil {
+		return err
+	}
+	progress := lesson.LoadProgress()
+	lp := progress.Layout(plan.Layout)
+	next := plan.Next(progress)
+
+	name := plan.Layout
+	if plan.Emulated {
+		name += " (emulated)"
+	}
+	fmt.Printf("Lessons for %s, %d/%d keys covered:\n\n", name, lp.Covered(plan.Keys()), len(plan.Keys()))
+	for _, l := range plan.Lessons {
+		mark := " "
+		switch {
+		case l.Number <= lp.Completed:
+			mark = "✓"
+		case l.Number == next:
+			mark = ">"
+		}
+		keys := make([]string, len(l.Keys))
+		for i, k := range l.Keys {
+			keys[i] = string(k)
+		}
+		fmt.Printf("  %s %2d  %-36s %s\n", mark, l.Number, l.Name, strings.Join(keys, " "))
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"gti/src/internal/app"
+)
+
+var listenCount int
+
+var listenCmd = &cobra.Command{
+	Use:   "listen",
+	Short: "Type sentences as you hear them read aloud",
+	Long: `Practice transcription: each sentence is read aloud but never shown, and you
+type what you hear, pressing Enter when done. The transcription is compared
+with the sentence word by word, ignoring case and punctuation, and the words
+missed, misheard or added are shown before the next sentence is read.
+
+Sentences come from the offline quote cache (see 'gti quote prefetch') when
+the language is English, or are made from the language's word list.
+Speech uses the engine set under [tts] in the config.
+
+KEYS:
+  Enter                       Check the transcription
+  Ctrl+W                      Hear the sentence again
+
+EXAMPLES:
+  gti listen                  # Five sentences
+  gti listen -n 10            # Ten sentences
+
+OPTIONS:
+  -n, --count <num>           Number of sentences (default: 5)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if listenCount < 1 {
+			listenCount = 1
+		}
+		return app.StartListening(listenCount)
+	},
+}
+
+func init() {
+	listenCmd.Flags().IntVarP(&listenCount, "count", "n", 5, "number of sentences")
+}
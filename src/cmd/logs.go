@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal"
+	"gti/src/internal/app"
+)
+
+var logsCount int
+var logsTimed string
+
+var logsCmd = &cobra.Command{
+	Use:   "logs [kind]",
+	Short: "Practice typing log lines and stack traces",
+	Long: `Practice transcribing realistic log lines and stack traces, full of
+timestamps, hex IDs and status codes. Every run is randomized.
+
+Supported kinds: app, access, system, trace, mixed
+
+EXAMPLES:
+  gti logs                    # Practice a mix of logs and traces (default)
+  gti logs access             # Practice web server access logs
+  gti logs trace -n 2         # Practice 2 stack traces
+  gti logs -t 60              # Timed log practice (60 seconds)
+
+OPTIONS:
+  -n, --count <num>           Number of log blocks (default: 2)
+  -t, --timed <duration>      Timed mode with duration (e.g., 30, 10s, 5m)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind := "mixed"
+		if len(args) > 0 {
+			kind = strings.ToLower(args[0])
+		}
+
+		if err := internal.ValidateLogKind(kind); err != nil {
+			return fmt.Errorf("%s. Supported kinds: %s, mixed", err.Error(), strings.Join(internal.GetSupportedLogKinds(), ", "))
+		}
+
+		if logsCount < 1 {
+			logsCount = 1
+		}
+		if logsCount > 10 {
+			logsCount = 10
+		}
+
+		timedSeconds := 0
+		if logsTimed != "" {
+			timedSeconds = parseDuration(logsTimed)
+		}
+		return app.StartLogPractice(kind, logsCount, timedSeconds)
+	},
+}
+
+func init() {
+	logsCmd.Flags().IntVarP(&logsCount, "count", "n", 2, "number of log blocks")
+	logsCmd.Flags().StringVarP(&logsTimed, "timed", "t", "", "timed mode with duration (e.g., 30, 10s, 5m)")
+}
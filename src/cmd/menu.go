@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gti/src/internal/app"
+	"gti/src/internal/config"
+	"gti/src/internal/tui"
+)
+
+// runStartMenu shows the home screen and runs what is picked there, coming back to it afterwards until Quit.
+// Each entry starts the same way as its command with default options.
+func runStartMenu() error {
+	selected := tui.MenuPractice
+	for {
+		cfg := config.GetConfig()
+		p := tea.NewProgram(tui.NewMenuModel(cfg, selected), tea.WithAltScreen(), tea.WithMouseCellMotion())
+		final, err := p.Run()
+		if err != nil {
+			return fmt.Errorf("failed to run start menu: %w", err)
+		}
+
+		selected = final.(tui.MenuModel).Chosen
+		switch selected {
+		case tui.MenuPractice:
+			err = app.StartAppWithOptions(app.WithMode("practice"), app.WithChunkCount(defaultGroups*chunksPerGroup))
+		case tui.MenuTimed:
+			err = app.StartAppWithOptions(app.WithMode("timed"), app.WithTimeLimit(cfg.Timed.DefaultSeconds))
+		case tui.MenuQuote:
+			err = quoteCmd.RunE(quoteCmd, nil)
+		case tui.MenuCode:
+			err = codeCmd.RunE(codeCmd, nil)
+		case tui.MenuChallenge:
+			err = app.StartChallengeGame()
+		case tui.MenuStatistics:
+			err = statisticsCmd.RunE(statisticsCmd, nil)
+		default:
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
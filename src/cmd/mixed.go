@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"gti/src/internal/app"
+)
+
+var mixedChunks int
+
+var mixedCmd = &cobra.Command{
+	Use:   "mixed",
+	Short: "Practice a weighted mix of words, quotes and code",
+	Long: `Practice chunks drawn at random from generated words, quotes and code
+snippets, for varied practice in one sitting. By default 60% of chunks are
+words, 25% quotes and 15% code; change the weights, the number of chunks and
+the code language under [mixed] in config.toml.
+
+EXAMPLES:
+  gti mixed                   # Practice the configured number of chunks
+  gti mixed -n 10             # Practice 10 chunks
+
+OPTIONS:
+  -n, --chunks <num>          Number of chunks (default: chunks under [mixed])`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return app.StartMixed(mixedChunks)
+	},
+}
+
+func init() {
+	mixedCmd.Flags().IntVarP(&mixedChunks, "chunks", "n", 0, "number of chunks, 0 for the [mixed] setting")
+}
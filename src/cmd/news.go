@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"gti/src/internal/app"
+)
+
+var newsCount int
+var newsRefresh bool
+
+var newsCmd = &cobra.Command{
+	Use:   "news",
+	Short: "Type headlines and summaries from news feeds",
+	Long: `Type stories from RSS or Atom news feeds, each its headline followed by its
+summary. Stories are picked at random from the newest across all feeds.
+
+Feeds are listed under [news] in the config:
+
+  [news]
+  feeds = ["https://feeds.bbci.co.uk/news/rss.xml", "https://example.com/atom.xml"]
+  cache_minutes = 30
+
+Fetched feeds are cached and reused for cache_minutes, and a feed that
+cannot be reached falls back to its cached stories, so news works offline
+once fetched.
+
+EXAMPLES:
+  gti news                    # Three stories
+  gti news -n 5               # Five stories
+  gti news --refresh          # Fetch the feeds again, ignoring the cache
+
+OPTIONS:
+  -n, --count <num>           Stories to type (default: 3)
+  --refresh                   Fetch every feed now instead of using the cache`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if newsCount < 1 {
+			newsCount = 1
+		}
+		return app.StartNews(newsCount, newsRefresh)
+	},
+}
+
+func init() {
+	newsCmd.Flags().IntVarP(&newsCount, "count", "n", 3, "stories to type")
+	newsCmd.Flags().BoolVar(&newsRefresh, "refresh", false, "fetch every feed now instead of using the cache")
+}
@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// jsonOutput is set by --json, asking informational commands for JSON instead of formatted text
+var jsonOutput bool
+
+// printJSON prints v as indented JSON on standard output, for --json
+func printJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"gti/src/internal/app"
+)
+
+var pinyinWords int
+var pinyinTimed string
+
+var pinyinCmd = &cobra.Command{
+	Use:   "pinyin",
+	Short: "Practice Chinese characters with pinyin input",
+	Long: `Practice reading and typing Chinese. Characters from the Chinese word list
+are shown on screen and typed as pinyin with tone numbers (你 = ni3). Tone
+numbers are optional: keep typing and a skipped tone is filled in for you;
+accuracy is scored per character.
+
+EXAMPLES:
+  gti pinyin                  # Practice 10 characters
+  gti pinyin -n 30            # Practice 30 characters
+  gti pinyin -t 60            # Timed pinyin practice (60 seconds)
+
+OPTIONS:
+  -n, --words <num>           Number of characters (default: 10)
+  -t, --timed <duration>      Timed mode with duration (e.g., 30, 10s, 5m)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if pinyinWords < 1 {
+			pinyinWords = 1
+		}
+
+		timedSeconds := 0
+		if pinyinTimed != "" {
+			timedSeconds = parseDuration(pinyinTimed)
+		}
+		return app.StartPinyinPractice(pinyinWords, timedSeconds)
+	},
+}
+
+func init() {
+	pinyinCmd.Flags().IntVarP(&pinyinWords, "words", "n", 10, "number of characters")
+	pinyinCmd.Flags().StringVarP(&pinyinTimed, "timed", "t", "", "timed mode with duration (e.g., 30, 10s, 5m)")
+}
@@ -1,16 +1,17 @@
 package cmd
 
 import (
+	"fmt"
+
 	"gti/src/internal/app"
 	"gti/src/internal/config"
 	"gti/src/internal/session"
-	"gti/src/internal/tui"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 )
 
 var quoteCount int
+var prefetchCount int
 
 var quoteCmd = &cobra.Command{
 	Use:   "quote [options]",
@@ -19,31 +20,71 @@ var quoteCmd = &cobra.Command{
 
 options:
   -n, --count <num>    number of quotes to type (default: 2)
-  -h, --help           display help information`,
+  --json-result        print the results as JSON on standard output on exit
+  -h, --help           display help information
+
+commands:
+  prefetch             fill the offline quote cache
+
+Quotes come from the offline cache when it holds enough of them, so
+sessions start without waiting on the network.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg := config.GetConfig()
 
 		// If quoteCount is 1 or default (2), use the appropriate session creation
 		if quoteCount <= 1 {
 			// Single quote mode
-			quote := app.FetchQuoteWithAuthor(cfg)
-			sess := session.NewSessionWithQuotes(cfg, []session.Quote{quote})
-			model := tui.NewModelWithSession(cfg, sess)
-			p := tea.NewProgram(model, tea.WithAltScreen())
-			_, err := p.Run()
+			return app.StartQuotes([]session.Quote{app.FetchQuoteWithAuthor(cfg)})
+		}
+		// Multi-quote mode
+		return app.StartQuotes(app.FetchMultipleQuotes(cfg, quoteCount))
+	},
+}
+
+var quotePrefetchCmd = &cobra.Command{
+	Use:   "prefetch [options]",
+	Short: "fill the offline quote cache",
+	Long: `usage: gti quote prefetch [options]
+
+Download quotes into the offline cache in one batch, pacing requests to stay
+within the quote provider's rate limit. Run it on a schedule (e.g. from cron)
+so quote sessions never wait on the network.
+
+options:
+  -n, --count <num>    number of new quotes to fetch (default: 50)
+  -h, --help           display help information`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if prefetchCount <= 0 {
+			return fmt.Errorf("count must be positive")
+		}
+		cfg := config.GetConfig()
+
+		added, err := app.PrefetchQuotes(cfg, prefetchCount, func(added int, status string) {
+			if status != "" {
+				fmt.Printf("Fetched %d/%d quotes (%s)\n", added, prefetchCount, status)
+			} else {
+				fmt.Printf("Fetched %d/%d quotes\n", added, prefetchCount)
+			}
+		})
+		if err != nil {
 			return err
-		} else {
-			// Multi-quote mode
-			quoteList := app.FetchMultipleQuotes(cfg, quoteCount)
-			sess := session.NewSessionWithQuotes(cfg, quoteList)
-			model := tui.NewModelWithSession(cfg, sess)
-			p := tea.NewProgram(model, tea.WithAltScreen())
-			_, err := p.Run()
+		}
+
+		cached, err := app.LoadCachedQuotes()
+		if err != nil {
 			return err
 		}
+		if added < prefetchCount {
+			fmt.Printf("The provider had only %d new quotes to offer.\n", added)
+		}
+		fmt.Printf("Offline cache holds %d quotes.\n", len(cached))
+		return nil
 	},
 }
 
 func init() {
 	quoteCmd.Flags().IntVarP(&quoteCount, "count", "n", 2, "number of quotes to type")
+	quotePrefetchCmd.Flags().IntVarP(&prefetchCount, "count", "n", 50, "number of new quotes to fetch")
+	quoteCmd.AddCommand(quotePrefetchCmd)
 }
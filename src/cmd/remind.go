@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal/config"
+	"gti/src/internal/remind"
+	"gti/src/internal/session"
+)
+
+var remindAt string
+var remindDaemon bool
+var remindRemove bool
+
+var remindCmd = &cobra.Command{
+	Use:   "remind",
+	Short: "Get a desktop notification when today's streak is at risk",
+	Long: `Get a desktop notification at a time of your choosing when you have a
+practice streak going and have not practised yet today. Rest days never
+remind you; a streak that a freeze would save still does.
+
+By default the reminder is handed to the system's scheduler (cron on Linux,
+launchd on macOS, Task Scheduler on Windows), so it runs whether or not gti
+is open. With --daemon, gti stays running and reminds you itself, for
+systems without a scheduler; start it from your session's startup programs.
+
+Notifications use notify-send on Linux, which comes with libnotify. An
+encrypted history is read with the passphrase in GTI_PASSPHRASE, as there
+is no one to ask for it.
+
+EXAMPLES:
+  gti remind                  # Remind me at 18:00 each day
+  gti remind --at 20:30       # Remind me at 20:30 instead
+  gti remind --daemon         # Stay running and remind me at 18:00
+  gti remind --remove         # Stop reminding me
+  gti remind check            # Remind me now, if the streak is at risk
+
+OPTIONS:
+  --at <HH:MM>                Time of day to check, 24-hour (default: 18:00)
+  --daemon                    Stay running instead of using the system's scheduler
+  --remove                    Remove the scheduled reminder`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if remindRemove {
+			cmd.SilenceUsage = true
+			removed, err := remind.Remove()
+			if err != nil {
+				return err
+			}
+			if !removed {
+				fmt.Println("No reminder is scheduled.")
+				return nil
+			}
+			fmt.Println("[SUCCESS] Removed the reminder")
+			return nil
+		}
+
+		hour, minute, err := remind.ParseTime(remindAt)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		if remindDaemon {
+			fmt.Printf("Reminding at %02d:%02d each day (Ctrl+C to stop)\n", hour, minute)
+			for {
+				time.Sleep(time.Until(remind.Next(time.Now(), hour, minute)))
+				// A failed check is reported and the next day's still comes
+				if _, err := remindIfAtRisk(config.GetConfig(), time.Now()); err != nil {
+					fmt.Printf("Error: %v\n", err)
+				}
+			}
+		}
+
+		where, err := remind.Install(hour, minute)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("[SUCCESS] Reminding at %02d:%02d each day, scheduled in %s\n", hour, minute, where)
+		return nil
+	},
+}
+
+var remindCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Send the reminder now if today's streak is at risk",
+	Long: `Send the reminder now if you have a practice streak going, have not
+practised yet today and today is not a rest day. This is what the scheduled
+reminder runs.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		message, err := remindIfAtRisk(config.GetConfig(), time.Now())
+		if err != nil {
+			return err
+		}
+		if message == "" {
+			fmt.Println("Today's streak is not at risk.")
+			return nil
+		}
+		fmt.Println(message)
+		return nil
+	},
+}
+
+func init() {
+	remindCmd.Flags().StringVar(&remindAt, "at", "18:00", "time of day to check, 24-hour")
+	remindCmd.Flags().BoolVar(&remindDaemon, "daemon", false, "stay running instead of using the system's scheduler")
+	remindCmd.Flags().BoolVar(&remindRemove, "remove", false, "remove the scheduled reminder")
+	remindCmd.AddCommand(remindCheckCmd)
+}
+
+// remindIfAtRisk sends a notification when the current streak ends today without practice, and
+// returns its message, or "" when nothing was sent
+func remindIfAtRisk(cfg *config.Config, now time.Time) (string, error) {
+	if !cfg.History.Enabled {
+		return "", fmt.Errorf("history.enabled is off, so there is no streak to keep")
+	}
+	if err := unlockHistory(cfg); err != nil {
+		return "", err
+	}
+	records, err := session.LoadSessionRecords(cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to load session records: %w", err)
+	}
+	stats := calculateStatistics(records, cfg.Streaks)
+	if stats.CurrentStreak == 0 || cfg.Streaks.RestWeekdays()[now.Weekday()] {
+		return "", nil
+	}
+	today := now.Format("2006-01-02")
+	// A session cut short still keeps the streak, so it is enough for today too
+	for _, r := range stats.practised {
+		if r.Timestamp.Format("2006-01-02") == today {
+			return "", nil
+		}
+	}
+
+	message := fmt.Sprintf("Your %d-day streak ends today. A short session keeps it going.", stats.CurrentStreak)
+	switch {
+	case stats.FreezesLeft == 1:
+		message = fmt.Sprintf("Practise today to keep your %d-day streak without spending your last freeze this month.", stats.CurrentStreak)
+	case stats.FreezesLeft > 1:
+		message = fmt.Sprintf("Practise today to keep your %d-day streak without spending a freeze (%d left this month).", stats.CurrentStreak, stats.FreezesLeft)
+	}
+	if err := remind.Notify("gti: your streak is at risk", message); err != nil {
+		return "", err
+	}
+	return message, nil
+}
@@ -3,15 +3,21 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"gti/src/internal"
 	"gti/src/internal/app"
 	"gti/src/internal/config"
+	"gti/src/internal/keymap"
+	"gti/src/internal/protocol"
+	"gti/src/internal/session"
+	"gti/src/internal/sound"
+	"gti/src/internal/tts"
+	"gti/src/internal/vault"
 )
 
 var cfgFile string
@@ -19,6 +25,23 @@ var chunksPerGroup int
 var defaultGroups int
 var language string
 var startParagraph int
+var botWPM float64
+var focusReps int
+var timingFile string
+var audioMarkers bool
+var protocolFile string
+var participant string
+var emulateLayout string
+var jsonResult bool
+var rowsFlag string
+var minLen int
+var maxLen int
+var focusChars string
+var sentencesFlag bool
+var clipboardFlag bool
+var pageURL string
+var wordTarget int
+var seedFlag int64
 
 var rootCmd = &cobra.Command{
 	Use:   "gti",
@@ -34,29 +57,171 @@ QUICK START
   gti -g 3               Start practice with 3 groups (6 chunks)
   gti -t 30              Start 30-second timed test
   gti -c file.txt        Practice with custom text
+  gti --url https://example.com/article
+                         Practice an article from the web
+  cat notes.txt | gti -c -
+                         Practice with text piped in
+  gti --bot 65           Race a 65 WPM bot
+  gti --focus 3          Loop mistyped words until typed cleanly 3 times
+  gti --rows home        Practice words typed on the home row only
+  gti --focus-chars qzx --min-len 5
+                         Practice longer words with q, z or x in them
+  gti --sentences        Practice generated text written as sentences
+  gti --protocol study.toml --participant P07
+                         Run a locked research protocol
   gti statistics         View typing statistics
 
 COMMANDS
   quote                  Start with random quotes
   challenge              Progressive challenge with levels
   code                   Practice typing with code snippets
+  document               Practice typing JSON/YAML/TOML documents
+  logs                   Practice typing log lines and stack traces
+  kana                   Practice hiragana/katakana with romaji input
+  hangul                 Practice Hangul jamo on the 2-set layout
+  pinyin                 Practice Chinese characters with pinyin input
+  drill                  Drills for numbers, symbols, random text and split/ortho keyboards
+  lesson [number]        Learn a keyboard layout key by key
+  learn [number]         Take the beginner typing course
+  listen                 Type sentences as you hear them read aloud
+  book <file>            Type through a book, resuming where you stopped
+  wiki [topic]           Type the summary of a Wikipedia article
+  news                   Type headlines and summaries from news feeds
+  mixed                  Practice a weighted mix of words, quotes and code
+  kiosk                  Unattended demo mode for shared machines
+  run [preset]           Start a test preset from the config file
   statistics             View detailed typing statistics
   theme <command>        Manage color themes
   config <command>       View and manage configuration
+  doctor                 Check the setup and suggest fixes
+  bench --input <file>   Score recorded key presses without the interface
+  serve                  Serve statistics over HTTP with a small dashboard
+  sync push|pull         Share history and progress between machines
+  encrypt                Encrypt the session history with a passphrase
+  decrypt <file>         Print a file encrypted by gti
+  remind --at <HH:MM>    Get a notification when today's streak is at risk
+  test --standard        Take the standard 3-minute test for a signed result
   version                Display version information
 
 OPTIONS
   -n <count>             Number of chunks per group (default: 2)
   -g <count>             Number of groups (default: 1)
-  -c, --custom <file>    Start with custom text file
+  -c, --custom <file>    Start with custom text file, - for standard input
+  --clipboard            Practice the text on the clipboard
+  --url <url>            Practice the readable text of a web page
   --start <num>          Start from paragraph number
   -t, --timed <time>     Start timed mode with duration
+  -w, --words <n>        End the test once <n> words are typed
+  --bot <wpm>            Race against a simulated opponent
+  --focus <reps>         Repeat each mistyped word until <reps> clean reps
+  --export-timing <file> Save every keystroke's timing (CSV, or JSON for .json)
+  --audio-markers        Ring the bell at start/end as audio sync markers
+  --protocol <file>      Run a test locked to a research protocol file
+  --participant <id>     Participant ID stamped into protocol results
+  --emulate <layout>     Type as if on another layout (dvorak, colemak, ...)
+  --json-result          Print the results as JSON on standard output on exit
+  --json                 Print JSON from theme --list, config --show, statistics, version, doctor, bench
+  --rows <rows>          Only words on these rows: number, top, home, bottom (e.g. top+home)
+  --min-len <n>          Only words at least <n> characters long
+  --max-len <n>          Only words at most <n> characters long
+  --focus-chars <chars>  Only words containing any of <chars>
+  --sentences            Generate capitalized sentences with commas and periods
+  --seed <n>             Generate the same text as any other run with seed <n>
   -s, --shortcuts        Show shortcuts and exit
   -h, --help             Display help information
   -v, --version          Display version information`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if protocolFile != "" {
+			return startProtocol(cmd)
+		}
+		if participant != "" {
+			return fmt.Errorf("--participant is only used with --protocol")
+		}
+		if cmd.Flags().Changed("seed") {
+			internal.Seed(seedFlag)
+		}
+		if cmd.Flags().NFlag() == 0 && config.GetConfig().Display.StartMenu {
+			return runStartMenu()
+		}
+
 		custom, _ := cmd.Flags().GetString("custom")
 		timed, _ := cmd.Flags().GetString("timed")
+		if clipboardFlag && pageURL != "" {
+			return fmt.Errorf("--clipboard and --url both pick the text, use one of them")
+		}
+		if clipboardFlag {
+			if custom != "" {
+				return fmt.Errorf("--clipboard and -c both pick the text, use one of them")
+			}
+			file, err := app.SaveClipboard()
+			if err != nil {
+				return err
+			}
+			custom = file
+		}
+		if pageURL != "" {
+			if custom != "" {
+				return fmt.Errorf("--url and -c both pick the text, use one of them")
+			}
+			file, err := app.FetchWebPage(config.GetConfig(), pageURL)
+			if err != nil {
+				return err
+			}
+			custom = file
+		}
+		if custom == internal.StdinFile {
+			// Read the pipe up front, so an empty or missing one is reported before the screen opens
+			if _, err := internal.ReadFile(custom); err != nil {
+				return err
+			}
+		}
+
+		if language == internal.AutoLanguage {
+			if custom == "" {
+				return fmt.Errorf("--language auto samples your custom text, use it with -c <file>")
+			}
+			detected, err := internal.DetectFileLanguage(custom)
+			if err != nil {
+				return err
+			}
+			language = detected
+			setDefaultLanguage(language)
+		}
+
+		if rowsFlag != "" {
+			if custom != "" {
+				return fmt.Errorf("--rows picks the words of generated text, it cannot be used with -c")
+			}
+			if err := restrictRows(rowsFlag); err != nil {
+				return err
+			}
+		}
+		if minLen != 0 || maxLen != 0 || focusChars != "" {
+			if custom != "" {
+				return fmt.Errorf("--min-len, --max-len and --focus-chars pick the words of generated text, they cannot be used with -c")
+			}
+			if err := filterWords(); err != nil {
+				return err
+			}
+		}
+		if sentencesFlag {
+			if custom != "" {
+				return fmt.Errorf("--sentences shapes generated text, it cannot be used with -c")
+			}
+			internal.UseSentences()
+		}
+
+		if wordTarget != 0 {
+			if wordTarget < 0 {
+				return fmt.Errorf("--words must be a positive number of words")
+			}
+			if custom != "" || timed != "" {
+				return fmt.Errorf("-w ends a test of generated words by word count, it cannot be used with -c or -t")
+			}
+			if cmd.Flags().Changed("chunks") || cmd.Flags().Changed("groups") {
+				return fmt.Errorf("-w sets the length of the test, it cannot be used with -n or -g")
+			}
+		}
 
 		if custom != "" {
 			seconds := 0
@@ -66,7 +231,7 @@ OPTIONS
 			return startCustomFile(custom, startParagraph, seconds)
 		}
 		if timed != "" {
-			return app.StartTimed(parseDuration(timed))
+			return app.StartAppWithOptions(app.WithMode("timed"), app.WithTimeLimit(parseDuration(timed)), app.WithBot(botWPM), app.WithFocus(focusReps), app.WithTimingExport(timingFile, audioMarkers))
 		}
 		if shortcuts, _ := cmd.Flags().GetBool("shortcuts"); shortcuts {
 			return showShortcuts()
@@ -79,22 +244,68 @@ OPTIONS
 				fmt.Fprintf(os.Stderr, "Error: %s. Run 'gti --help' to see available languages.\n", err.Error())
 				os.Exit(1)
 			}
+			language = strings.Join(internal.Languages(language), ",")
 
-			cfg := config.GetConfig()
-			if cfg.Language.Default != language {
-				cfg.Language.Default = language
-				if err := config.SaveConfig(); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: Failed to save language preference: %v\n", err)
-				} else {
-					fmt.Printf("Default language set to: %s\n", language)
-				}
-			}
-			return app.StartPracticeWithChunksAndLanguage(totalChunks, language)
+			setDefaultLanguage(language)
+			return app.StartAppWithOptions(app.WithMode("practice"), app.WithChunkCount(totalChunks), app.WithWordTarget(wordTarget), app.WithLanguage(language), app.WithBot(botWPM), app.WithFocus(focusReps), app.WithTimingExport(timingFile, audioMarkers))
 		}
-		return app.StartPracticeWithChunks(totalChunks)
+		return app.StartAppWithOptions(app.WithMode("practice"), app.WithChunkCount(totalChunks), app.WithWordTarget(wordTarget), app.WithBot(botWPM), app.WithFocus(focusReps), app.WithTimingExport(timingFile, audioMarkers))
 	},
 }
 
+// restrictRows limits generated words to the keys of the named rows, on the layout being emulated if any
+func restrictRows(spec string) error {
+	cfg := config.GetConfig()
+	rows := keymap.KeyboardRows(cfg.Keyboard.Layout)
+	if name := cfg.Keyboard.EmulatedLayout(); name != "" {
+		emulation, err := keymap.LoadEmulation(name)
+		if err != nil {
+			return err
+		}
+		rows = emulation.Rows
+	}
+	keys, err := keymap.RowKeys(rows, spec)
+	if err != nil {
+		return err
+	}
+	internal.RestrictWordsToKeys(keys)
+	return nil
+}
+
+// filterWords narrows generated words to the lengths and characters asked for, refusing a filter
+// that leaves the language no words at all
+func filterWords() error {
+	if minLen < 0 || maxLen < 0 {
+		return fmt.Errorf("--min-len and --max-len must not be negative")
+	}
+	if maxLen > 0 && minLen > maxLen {
+		return fmt.Errorf("--min-len %d is longer than --max-len %d", minLen, maxLen)
+	}
+	internal.FilterWords(internal.WordFilter{MinLen: minLen, MaxLen: maxLen, Chars: focusChars})
+
+	lang := language
+	if lang == "" {
+		lang = config.GetConfig().Language.Default
+	}
+	if internal.FilteredWordCount(lang) == 0 {
+		return fmt.Errorf("no %s words pass --min-len, --max-len and --focus-chars, loosen them", lang)
+	}
+	return nil
+}
+
+// setDefaultLanguage saves the language as the preference for future generated text
+func setDefaultLanguage(language string) {
+	cfg := config.GetConfig()
+	if cfg.Language.Default != language {
+		cfg.Language.Default = language
+		if err := config.SaveConfig(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to save language preference: %v\n", err)
+		} else {
+			fmt.Printf("Default language set to: %s\n", language)
+		}
+	}
+}
+
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
@@ -113,15 +324,58 @@ func init() {
 
 	rootCmd.Flags().IntVarP(&chunksPerGroup, "chunks", "n", 2, "number of chunks per group for default practice")
 	rootCmd.Flags().IntVarP(&defaultGroups, "groups", "g", 1, "number of groups for default practice")
-	rootCmd.Flags().StringP("custom", "c", "", "start with custom text file")
+	rootCmd.Flags().StringP("custom", "c", "", "start with custom text file, or - to read it from standard input")
 	rootCmd.Flags().IntVar(&startParagraph, "start", 1, "start from paragraph number (for custom mode)")
 	rootCmd.Flags().StringP("timed", "t", "", "start timed mode with duration (e.g., 30, 10s, 5m)")
-	rootCmd.Flags().StringVarP(&language, "language", "l", "", "language for word generation (english, spanish, french, german, japanese, etc.)")
+	rootCmd.Flags().IntVarP(&wordTarget, "words", "w", 0, "end the test once this many words are typed")
+	rootCmd.Flags().StringVarP(&language, "language", "l", "", "language for word generation (english, spanish, french, german, japanese, etc., a list such as english,spanish to mix them, or auto)")
 	rootCmd.Flags().BoolP("shortcuts", "s", false, "show shortcuts and exit")
+	rootCmd.Flags().Float64Var(&botWPM, "bot", 0, "race against a simulated opponent typing at this WPM")
+	rootCmd.Flags().IntVar(&focusReps, "focus", 0, "repeat each mistyped word until typed cleanly this many times in a row")
+	rootCmd.Flags().StringVar(&timingFile, "export-timing", "", "save every keystroke's timing to a CSV (or .json) file for keyboard analysis")
+	rootCmd.Flags().BoolVar(&audioMarkers, "audio-markers", false, "ring the bell at start and end and log them as sync markers in the timing export")
+	rootCmd.Flags().StringVar(&protocolFile, "protocol", "", "run a test locked to a research protocol file (TOML)")
+	rootCmd.Flags().BoolVar(&clipboardFlag, "clipboard", false, "practice the text on the clipboard")
+	rootCmd.Flags().StringVar(&pageURL, "url", "", "practice the readable text of a web page")
+	rootCmd.Flags().StringVar(&rowsFlag, "rows", "", "only use words typed on these keyboard rows, e.g. home or top+home")
+	rootCmd.Flags().IntVar(&minLen, "min-len", 0, "only use words at least this many characters long")
+	rootCmd.Flags().IntVar(&maxLen, "max-len", 0, "only use words at most this many characters long")
+	rootCmd.Flags().StringVar(&focusChars, "focus-chars", "", "only use words containing any of these characters, e.g. qzx")
+	rootCmd.Flags().BoolVar(&sentencesFlag, "sentences", false, "generate text as capitalized sentences with commas and periods")
+	rootCmd.Flags().Int64Var(&seedFlag, "seed", 0, "seed the text generator, so the same seed gives the same words")
+	rootCmd.Flags().StringVar(&participant, "participant", "", "participant ID stamped into protocol result files")
+	rootCmd.RegisterFlagCompletionFunc("language", completeLanguages)
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "print JSON instead of formatted text from theme --list, theme list, config --show, statistics, version, doctor and bench")
+	rootCmd.PersistentFlags().BoolVar(&jsonResult, "json-result", false, "print the results as JSON on standard output on exit")
+	rootCmd.PersistentFlags().StringVar(&emulateLayout, "emulate", "", "type as if on another layout, e.g. dvorak, colemak, colemak-dh or workman, without changing the system's layout")
 
 	rootCmd.AddCommand(quoteCmd)
 	rootCmd.AddCommand(challengeCmd)
 	rootCmd.AddCommand(codeCmd)
+	rootCmd.AddCommand(documentCmd)
+	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(kanaCmd)
+	rootCmd.AddCommand(hangulCmd)
+	rootCmd.AddCommand(pinyinCmd)
+	rootCmd.AddCommand(drillCmd)
+	rootCmd.AddCommand(mixedCmd)
+	rootCmd.AddCommand(lessonCmd)
+	rootCmd.AddCommand(learnCmd)
+	rootCmd.AddCommand(listenCmd)
+	rootCmd.AddCommand(bookCmd)
+	rootCmd.AddCommand(wikiCmd)
+	rootCmd.AddCommand(newsCmd)
+	rootCmd.AddCommand(kioskCmd)
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(benchCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(encryptCmd)
+	rootCmd.AddCommand(decryptCmd)
+	rootCmd.AddCommand(remindCmd)
+	rootCmd.AddCommand(testCmd)
+	rootCmd.AddCommand(genDocsCmd)
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(themeCmd)
 	rootCmd.AddCommand(statisticsCmd)
@@ -130,6 +384,35 @@ func init() {
 
 func initConfig() {
 	config.InitConfig(cfgFile)
+	cfg := config.GetConfig()
+	cfg.Results.JSONOnce = jsonResult
+	if emulateLayout != "" {
+		if _, err := keymap.LoadEmulation(emulateLayout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.Keyboard.EmulateOnce = emulateLayout
+	} else if cfg.Keyboard.Emulate != "" {
+		if _, err := keymap.LoadEmulation(cfg.Keyboard.Emulate); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Not emulating a layout: %v\n", err)
+		}
+	}
+	if cfg.History.Encrypt && !vault.HasKey(cfg) {
+		fmt.Fprintln(os.Stderr, "Warning: history.encrypt is on but there is no key, so sessions are not saved: run 'gti encrypt'")
+	}
+	// The first launch of each day rolls the finished days up, so lifetime stats outlive the raw history
+	if err := session.SnapshotRollups(config.GetConfig()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to update statistics rollups: %v\n", err)
+	}
+	if _, err := sound.For(config.GetConfig().Sound); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Typing sounds are off: %v\n", err)
+	}
+	// Speech is only used once the context view is turned on, so a missing engine is only worth a warning when one was chosen
+	if cfg.TTS.Engine != "auto" {
+		if _, err := tts.For(cfg.TTS); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Words will not be read aloud: %v\n", err)
+		}
+	}
 }
 
 func parseDuration(durationStr string) int {
@@ -144,54 +427,70 @@ func parseDuration(durationStr string) int {
 	return 60
 }
 
+// startProtocol runs a research protocol. Every parameter comes from the protocol file, so any other
+// option is refused rather than quietly changing the conditions of the study.
+func startProtocol(cmd *cobra.Command) error {
+	var overrides []string
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if f.Name != "protocol" && f.Name != "participant" {
+			overrides = append(overrides, "--"+f.Name)
+		}
+	})
+	if len(overrides) > 0 {
+		return fmt.Errorf("--protocol locks all test parameters, remove %s", strings.Join(overrides, ", "))
+	}
+
+	p, err := protocol.Load(protocolFile)
+	if err != nil {
+		return err
+	}
+	return app.StartProtocol(p, participant, Version)
+}
+
 // isCodeFile checks if the given file path has a code file extension
 func isCodeFile(filename string) bool {
-	ext := strings.ToLower(filepath.Ext(filename))
-	codeExtensions := map[string]bool{
-		".go":   true,
-		".py":   true,
-		".js":   true,
-		".java": true,
-		".cpp":  true,
-		".rs":   true,
-		".ts":   true,
-	}
-	return codeExtensions[ext]
+	return internal.DetectCodeLanguage(filename) != ""
 }
 
 // startCustomFile starts a typing session with a custom file, automatically detecting if it's code
 func startCustomFile(file string, start int, seconds int) error {
 	if isCodeFile(file) {
 		return app.StartApp(app.AppOptions{
-			Mode:    "custom-code",
-			File:    file,
-			Start:   start,
-			Seconds: seconds,
+			Mode:         "custom-code",
+			File:         file,
+			Start:        start,
+			Seconds:      seconds,
+			BotWPM:       botWPM,
+			TimingFile:   timingFile,
+			AudioMarkers: audioMarkers,
 		})
 	}
-	if seconds > 0 {
-		return app.StartCustomTimed(file, start, seconds)
-	}
-	return app.StartCustom(file, start)
+	return app.StartAppWithOptions(app.WithMode("custom"), app.WithCustomFile(file, start), app.WithTimeLimit(seconds), app.WithBot(botWPM), app.WithFocus(focusReps), app.WithTimingExport(timingFile, audioMarkers))
 }
 
 func showShortcuts() error {
+	keys := keymap.NewBindings(config.GetConfig().Keybindings)
 	shortcuts := []struct{ key, desc string }{
 		{"GLOBAL SHORTCUTS", ""},
-		{"Ctrl+C", "Force quit application"},
-		{"Ctrl+Q", "Quit with confirmation"},
-		{"Esc", "Close overlays / Cancel"},
+		{keys.Label(keymap.ActionForceQuit), "Force quit application"},
+		{keys.Label(keymap.ActionPause), "Pause with live stats: resume, restart, save and quit"},
+		{keys.Label(keymap.ActionQuit), "Quit, via the pause screen so the run can be saved"},
+		{keys.Label(keymap.ActionBack), "Close overlays / Cancel"},
 		{"", ""},
 		{"TYPING SESSION CONTROLS", ""},
-		{"Tab/Enter", "Submit text / Accept results"},
-		{"Ctrl+R", "Restart current session"},
+		{keys.Label(keymap.ActionNext), "Accept results and go again"},
+		{keys.Label(keymap.ActionRestart), "Restart current session"},
+		{keys.Label(keymap.ActionRepeat), "Repeat the run on exactly the same text"},
+		{keys.Label(keymap.ActionSkip), "Skip to new text without recording the run"},
 		{"Backspace", "Delete characters"},
-		{"Ctrl+H", "Show help overlay"},
+		{keys.Label(keymap.ActionHelp), "Show help overlay"},
+		{keys.Label(keymap.ActionTTS), "Toggle text-to-speech of the next word"},
+		{keys.Label(keymap.ActionCheatSheet), "Toggle key cheat sheet for your layout"},
 		{"", ""},
 		{"NAVIGATION", ""},
 		{"Left/Right", "Navigate text segments"},
-		{"Up/Down", "Scroll content"},
-		{"PgUp/PgDn", "Page scroll"},
+		{keys.Label(keymap.ActionScrollUp) + "/" + keys.Label(keymap.ActionScrollDown), "Scroll content"},
+		{keys.Label(keymap.ActionPageUp) + "/" + keys.Label(keymap.ActionPageDown), "Page scroll"},
 		{"", ""},
 		{"QUICK START FLAGS", ""},
 		{"-c <file>", "Start with custom text"},
@@ -219,6 +518,7 @@ func showShortcuts() error {
 	fmt.Println("  gti -s           # Show shortcuts")
 	fmt.Println("  gti -c file.txt  # Custom text")
 	fmt.Println("  gti -t 30        # 30-second test")
+	fmt.Println("\nRebind any of these under [keybindings] in config.toml.")
 
 	return nil
 }
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal/config"
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run [preset]",
+	Short: "Start a test preset from the config file",
+	Long: `Start a test saved as a preset in the config file. Each [presets.<name>]
+section names a test and the options it runs with:
+
+  [presets.sprint]
+  seconds = 15
+  punctuation = false
+
+  [presets.marathon]
+  words = 200
+  language = "english,spanish"
+
+Keys: seconds, words, language, punctuation, rows, min_len, max_len,
+focus_chars, bot and focus, each doing what the option of the same name does.
+Run without a preset to list the presets.
+
+EXAMPLES:
+  gti run                     # List the presets
+  gti run sprint              # Start the sprint preset`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+		names := cfg.PresetNames()
+		if len(args) == 0 {
+			if len(names) == 0 {
+				fmt.Printf("No presets yet; add a [presets.<name>] section to %s\n", config.ConfigFile)
+				return nil
+			}
+			for _, name := range names {
+				var shown []string
+				flags := presetFlags(cfg.Presets[name])
+				for i := 0; i < len(flags); i += 2 {
+					shown = append(shown, strings.TrimSpace(flags[i]+" "+flags[i+1]))
+				}
+				fmt.Printf("%-16s %s\n", name, strings.Join(shown, " "))
+			}
+			return nil
+		}
+
+		preset, ok := cfg.Presets[args[0]]
+		if !ok {
+			if len(names) == 0 {
+				return fmt.Errorf("no preset named %s, add a [presets.%s] section to %s", args[0], args[0], config.ConfigFile)
+			}
+			return fmt.Errorf("no preset named %s, the presets are: %s", args[0], strings.Join(names, ", "))
+		}
+		flags := presetFlags(preset)
+		for i := 0; i < len(flags); i += 2 {
+			value := flags[i+1]
+			if value == "" {
+				value = "true"
+			}
+			if err := rootCmd.Flags().Set(strings.TrimPrefix(flags[i], "--"), value); err != nil {
+				return fmt.Errorf("preset %s: %s %v", args[0], flags[i], err)
+			}
+		}
+		return rootCmd.RunE(rootCmd, nil)
+	},
+}
+
+// presetFlags spells a preset out as the options of the main command, each followed by its value, or
+// by "" for a switch
+func presetFlags(p config.PresetConfig) []string {
+	var flags []string
+	add := func(name, value string) {
+		flags = append(flags, "--"+name, value)
+	}
+	if p.Seconds > 0 {
+		add("timed", strconv.Itoa(p.Seconds))
+	}
+	if p.Words > 0 {
+		add("words", strconv.Itoa(p.Words))
+	}
+	if p.Language != "" {
+		add("language", p.Language)
+	}
+	if p.Punctuation {
+		add("sentences", "")
+	}
+	if p.Rows != "" {
+		add("rows", p.Rows)
+	}
+	if p.MinLen > 0 {
+		add("min-len", strconv.Itoa(p.MinLen))
+	}
+	if p.MaxLen > 0 {
+		add("max-len", strconv.Itoa(p.MaxLen))
+	}
+	if p.FocusChars != "" {
+		add("focus-chars", p.FocusChars)
+	}
+	if p.Bot > 0 {
+		add("bot", strconv.FormatFloat(p.Bot, 'f', -1, 64))
+	}
+	if p.Focus > 0 {
+		add("focus", strconv.Itoa(p.Focus))
+	}
+	return flags
+}
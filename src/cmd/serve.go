@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gti/src/assets"
+	"gti/src/internal/config"
+	"gti/src/internal/session"
+)
+
+var servePort int
+var serveHost string
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve your statistics over HTTP with a small dashboard",
+	Long: `Serve your typing statistics over HTTP, read-only, for a browser or for
+tools such as Grafana. The history file is read on every request, so new
+sessions show up without a restart.
+
+ENDPOINTS:
+  /                           Dashboard with your averages, bests and recent sessions
+  /stats                      Statistics as JSON, as 'gti statistics --json' prints them
+                              (?view=session, daily, weekly or all-time)
+  /sessions                   Sessions as JSON, newest first (?limit=<n>, ?mode=<mode>)
+  /bests                      Fastest finished session of each mode as JSON
+
+Only this machine can connect unless --host says otherwise, and then only
+through localhost, 127.0.0.1 or [::1], so other web sites cannot reach it. When the history
+is encrypted, every request also needs the token printed at startup, as
+?token=<token> or an 'Authorization: Bearer <token>' header; it changes on
+every run.
+
+EXAMPLES:
+  gti serve                   # Serve at http://127.0.0.1:8080
+  gti serve --port 9000       # Serve on another port
+  gti serve --host 0.0.0.0    # Let other machines on the network connect
+
+OPTIONS:
+  --port <port>               Port to listen on (default: 8080)
+  --host <address>            Address to listen on (default: 127.0.0.1)`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+		if err := unlockHistory(cfg); err != nil {
+			return err
+		}
+		dashboard, err := template.ParseFS(assets.Web, "web/dashboard.html")
+		if err != nil {
+			return err
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			dashboard.Execute(w, cfg.Theme.Colors)
+		})
+		mux.HandleFunc("GET /stats", func(w http.ResponseWriter, r *http.Request) {
+			view := r.URL.Query().Get("view")
+			switch view {
+			case "", "session", "daily", "weekly", "all-time":
+			default:
+				http.Error(w, "view must be session, daily, weekly or all-time", http.StatusBadRequest)
+				return
+			}
+			report, err := statisticsReport(cfg, view)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			delete(report, "sessions")
+			serveJSON(w, report)
+		})
+		mux.HandleFunc("GET /sessions", func(w http.ResponseWriter, r *http.Request) {
+			records, err := session.LoadSessionRecords(cfg)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if mode := r.URL.Query().Get("mode"); mode != "" {
+				var filtered []*session.SessionRecord
+				for _, record := range records {
+					if record.Mode == mode {
+						filtered = append(filtered, record)
+					}
+				}
+				records = filtered
+			}
+			if limit := r.URL.Query().Get("limit"); limit != "" {
+				n, err := strconv.Atoi(limit)
+				if err != nil || n < 0 {
+					http.Error(w, "limit must be a whole number of 0 or more", http.StatusBadRequest)
+					return
+				}
+				records = records[:min(n, len(records))]
+			}
+			if records == nil {
+				records = []*session.SessionRecord{}
+			}
+			serveJSON(w, records)
+		})
+		mux.HandleFunc("GET /bests", func(w http.ResponseWriter, r *http.Request) {
+			records, err := session.LoadSessionRecords(cfg)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			serveJSON(w, bestsByMode(records))
+		})
+
+		var handler http.Handler = mux
+		query := ""
+		if cfg.History.Enabled && cfg.History.Encrypt {
+			// The history was unlocked with the passphrase, so whoever asks has to show they were given it
+			token, err := serveToken()
+			if err != nil {
+				return err
+			}
+			handler = requireToken(token, mux)
+			query = "/?token=" + token
+		}
+
+		addr := net.JoinHostPort(serveHost, strconv.Itoa(servePort))
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+		if ip := net.ParseIP(serveHost); serveHost == "localhost" || (ip != nil && ip.IsLoopback()) {
+			handler = requireLocalHost(listener.Addr().(*net.TCPAddr).Port, handler)
+		}
+		if !cfg.History.Enabled {
+			fmt.Println("History is off, so there are no sessions to show; turn it on with 'gti config set history.enabled true'")
+		}
+		fmt.Printf("Serving statistics at http://%s%s (Ctrl+C to stop)\n", listener.Addr(), query)
+		return http.Serve(listener, handler)
+	},
+}
+
+func init() {
+	serveCmd.Flags().IntVar(&servePort, "port", 8080, "port to listen on")
+	serveCmd.Flags().StringVar(&serveHost, "host", "127.0.0.1", "address to listen on")
+}
+
+// serveToken is a random token for one run of the server
+func serveToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// requireToken refuses requests that do not carry token, in the query or as a bearer token
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := r.URL.Query().Get("token")
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			given = bearer
+		}
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			http.Error(w, "the history is encrypted: use the token gti serve printed", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireLocalHost refuses requests not addressed to this machine by name, so a web page whose
+// domain has been pointed at 127.0.0.1 cannot read the history from the browser
+func requireLocalHost(port int, next http.Handler) http.Handler {
+	allowed := make(map[string]bool)
+	for _, host := range []string{"localhost", "127.0.0.1", "::1"} {
+		allowed[net.JoinHostPort(host, strconv.Itoa(port))] = true
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowed[r.Host] {
+			http.Error(w, "only requests to localhost are served", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bestsByMode picks the fastest session of each mode that statistics count and that was typed to the end
+func bestsByMode(records []*session.SessionRecord) map[string]*session.SessionRecord {
+	bests := make(map[string]*session.SessionRecord)
+	for _, record := range session.CountedRecords(records) {
+		if record.Partial {
+			continue
+		}
+		if best, ok := bests[record.Mode]; !ok || record.WPM > best.WPM {
+			bests[record.Mode] = record
+		}
+	}
+	return bests
+}
+
+func serveJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal"
+)
+
+var snippetsName string
+var snippetsTags []string
+var snippetsTagFilter string
+var snippetsUntag bool
+
+var snippetsCmd = &cobra.Command{
+	Use:   "snippets <command>",
+	Short: "Manage your personal code snippets",
+	Long: `Curate the personal snippet library that gti code drills alongside its
+built-in snippets. Snippets are kept per language; tags are recorded in a
+small index, and an "easy" or "hard" tag also sets the tier --difficulty uses.
+
+EXAMPLES:
+  gti code snippets list                      # List all your snippets
+  gti code snippets list go --tag http        # List Go snippets tagged http
+  gti code snippets add go server.go -n "HTTP server" --tag http
+  cat query.sql | gti code snippets add sql -n "Monthly report"
+  gti code snippets show go "HTTP server"     # Preview a snippet
+  gti code snippets tag go "HTTP server" hard # Add tags
+  gti code snippets remove go "HTTP server"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var snippetsListCmd = &cobra.Command{
+	Use:   "list [language]",
+	Short: "List your snippets",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		language := ""
+		if len(args) > 0 {
+			language = strings.ToLower(args[0])
+		}
+		snippets, err := internal.ListPersonalSnippets(language, snippetsTagFilter)
+		if err != nil {
+			return err
+		}
+		if len(snippets) == 0 {
+			fmt.Println("No snippets found. Add one with: gti code snippets add <language> <file> -n <name>")
+			return nil
+		}
+
+		fmt.Printf("%-12s %-32s %5s  %s\n", "LANGUAGE", "NAME", "LINES", "TAGS")
+		for _, snippet := range snippets {
+			fmt.Printf("%-12s %-32s %5d  %s\n", snippet.Language, snippet.Name,
+				strings.Count(snippet.Code, "\n")+1, strings.Join(snippet.Tags, ", "))
+		}
+		return nil
+	},
+}
+
+var snippetsAddCmd = &cobra.Command{
+	Use:   "add <language> [file]",
+	Short: "Add a snippet from a file or stdin",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		language := strings.ToLower(args[0])
+		name := snippetsName
+
+		var data []byte
+		var err error
+		if len(args) > 1 {
+			data, err = os.ReadFile(args[1])
+			if name == "" {
+				name = strings.TrimSuffix(filepath.Base(args[1]), filepath.Ext(args[1]))
+			}
+		} else {
+			data, err = io.ReadAll(os.Stdin)
+		}
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			return fmt.Errorf("give the snippet a name with --name")
+		}
+
+		code := strings.ReplaceAll(string(data), "\t", "    ")
+		if err := internal.AddPersonalSnippet(language, name, code, snippetsTags); err != nil {
+			return err
+		}
+		fmt.Printf("Added '%s' to your %s snippets\n", strings.TrimSpace(name), language)
+		return nil
+	},
+}
+
+var snippetsShowCmd = &cobra.Command{
+	Use:   "show <language> <name>",
+	Short: "Preview a snippet",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snippet, err := internal.GetPersonalSnippet(strings.ToLower(args[0]), args[1])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s (%s)\n", snippet.Name, snippet.Language)
+		if len(snippet.Tags) > 0 {
+			fmt.Printf("Tags: %s\n", strings.Join(snippet.Tags, ", "))
+		}
+		if !snippet.Added.IsZero() {
+			fmt.Printf("Added: %s\n", snippet.Added.Format("2006-01-02"))
+		}
+		fmt.Println()
+		for i, line := range strings.Split(snippet.Code, "\n") {
+			fmt.Printf("%3d  %s\n", i+1, line)
+		}
+		return nil
+	},
+}
+
+var snippetsTagCmd = &cobra.Command{
+	Use:   "tag <language> <name> <tag>...",
+	Short: "Add tags to a snippet (or remove them with --remove)",
+	Args:  cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		language := strings.ToLower(args[0])
+		if err := internal.TagPersonalSnippet(language, args[1], args[2:], snippetsUntag); err != nil {
+			return err
+		}
+		snippet, err := internal.GetPersonalSnippet(language, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Tags for '%s': %s\n", snippet.Name, strings.Join(snippet.Tags, ", "))
+		return nil
+	},
+}
+
+var snippetsRemoveCmd = &cobra.Command{
+	Use:   "remove <language> <name>",
+	Short: "Remove a snippet",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		language := strings.ToLower(args[0])
+		if err := internal.RemovePersonalSnippet(language, args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Removed '%s' from your %s snippets\n", strings.TrimSpace(args[1]), language)
+		return nil
+	},
+}
+
+func init() {
+	snippetsListCmd.Flags().StringVar(&snippetsTagFilter, "tag", "", "only list snippets with this tag")
+	snippetsAddCmd.Flags().StringVarP(&snippetsName, "name", "n", "", "snippet name (defaults to the file name)")
+	snippetsAddCmd.Flags().StringSliceVar(&snippetsTags, "tag", nil, "tag the snippet (repeatable or comma-separated)")
+	snippetsTagCmd.Flags().BoolVar(&snippetsUntag, "remove", false, "remove the tags instead of adding them")
+
+	snippetsCmd.AddCommand(snippetsListCmd)
+	snippetsCmd.AddCommand(snippetsAddCmd)
+	snippetsCmd.AddCommand(snippetsShowCmd)
+	snippetsCmd.AddCommand(snippetsTagCmd)
+	snippetsCmd.AddCommand(snippetsRemoveCmd)
+	codeCmd.AddCommand(snippetsCmd)
+}
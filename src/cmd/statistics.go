@@ -9,6 +9,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"gti/src/internal/card"
 	"gti/src/internal/config"
 	"gti/src/internal/session"
 	"gti/src/internal/tui"
@@ -37,12 +38,23 @@ type Statistics struct {
 
 	CurrentStreak int
 	LongestStreak int
+	// FreezesLeft is how many missed days this month the current streak can still survive
+	FreezesLeft int
+
+	// practised are the sessions streaks count, partial ones included
+	practised []*session.SessionRecord
 }
 
 type statisticsCmdFlags struct {
 	view   string
 	export bool
 	json   bool
+	// compare and to name the two date ranges whose error heatmaps are diffed
+	compare string
+	to      string
+	// card saves a shareable card of the latest session, to output when set
+	card   bool
+	output string
 }
 
 var statsFlags statisticsCmdFlags
@@ -69,6 +81,10 @@ EXAMPLES:
   gti statistics --view daily      # View today's performance
   gti statistics --export          # Export data to Downloads folder
   gti statistics --json            # Output machine-readable JSON
+  gti statistics --compare 2026-09-01..2026-09-30 --to 2026-10-01..
+                                    # Which keys got better or worse
+  gti statistics --card             # Save a card of your last result to Downloads
+  gti statistics --card -o card.txt # ...as text (.png, .ans for color, or - to print it)
 
 CONTROLS:
   q         Quit statistics view
@@ -90,13 +106,30 @@ CONTROLS:
 			}
 		}
 
+		// The flags are fine, so a wrong passphrase needs no usage printed under it
+		cmd.SilenceUsage = true
+		if err := unlockHistory(cfg); err != nil {
+			return err
+		}
+
 		if statsFlags.json {
 			return exportStatisticsJSON(cfg, statsFlags.view)
 		}
 
+		if statsFlags.card {
+			return saveResultCard(cfg, statsFlags.output)
+		}
+		if statsFlags.output != "" {
+			return fmt.Errorf("--output goes with --card")
+		}
+
+		if statsFlags.compare != "" || statsFlags.to != "" {
+			return compareHeatmaps(cfg, statsFlags.compare, statsFlags.to)
+		}
+
 		model := tui.NewStatisticsModel(cfg)
 
-		p := tea.NewProgram(model, tea.WithAltScreen())
+		p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 
 		if _, err := p.Run(); err != nil {
 			return fmt.Errorf("failed to run statistics interface: %w", err)
@@ -107,9 +140,21 @@ CONTROLS:
 }
 
 func exportStatisticsJSON(cfg *config.Config, viewFilter string) error {
+	report, err := statisticsReport(cfg, viewFilter)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// statisticsReport is what --json prints: the statistics of the sessions in the view, and the sessions
+func statisticsReport(cfg *config.Config, viewFilter string) (map[string]interface{}, error) {
 	records, err := session.LoadSessionRecords(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to load session records: %w", err)
+		return nil, fmt.Errorf("failed to load session records: %w", err)
 	}
 
 	var filteredRecords []*session.SessionRecord
@@ -143,28 +188,97 @@ func exportStatisticsJSON(cfg *config.Config, viewFilter string) error {
 		filteredRecords = records
 	}
 
-	stats := calculateStatistics(filteredRecords)
+	stats := calculateStatistics(filteredRecords, cfg.Streaks)
 
-	exportData := map[string]interface{}{
-		"view":       viewFilter,
-		"generated":  now.Format(time.RFC3339),
-		"statistics": stats,
-		"sessions":   filteredRecords,
+	report := map[string]interface{}{
+		"view":        viewFilter,
+		"generated":   now.Format(time.RFC3339),
+		"statistics":  stats,
+		"time_of_day": session.AnalyzeTimeOfDay(stats.ValidSessions),
+		"fatigue":     session.AnalyzeFatigue(stats.ValidSessions),
+		"sessions":    filteredRecords,
+	}
+	// The average net speed of the sessions long enough to count is what the statistics screen compares
+	if netWPM := session.AverageNetWPM(stats.ValidSessions); cfg.Results.Percentiles && netWPM > 0 {
+		table, err := session.LoadPercentiles(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the percentile table: %w", err)
+		}
+		report["percentile"] = table.Rank(netWPM)
 	}
+	return report, nil
+}
 
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(exportData)
+// saveResultCard saves a card of the latest session to file, the Downloads folder when file is "",
+// or prints it in color when file is "-"
+func saveResultCard(cfg *config.Config, file string) error {
+	records, err := session.LoadSessionRecords(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load session records: %w", err)
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("no sessions yet, finish a test to get a result card")
+	}
+
+	if file == "-" {
+		fmt.Print(card.Text(records[0], cfg.Theme.Colors, true))
+		return nil
+	}
+	if file == "" {
+		file = card.DefaultFile()
+	}
+	if err := card.Save(file, records[0], cfg.Theme.Colors); err != nil {
+		return fmt.Errorf("failed to save the result card: %w", err)
+	}
+	fmt.Printf("Saved the result card to %s\n", file)
+	return nil
+}
+
+// compareHeatmaps prints the per-key error heatmap diff between two date ranges.
+// Without --to the second range runs from the day after the first one ends until now.
+func compareHeatmaps(cfg *config.Config, compare, to string) error {
+	if compare == "" {
+		return fmt.Errorf("--to needs --compare to name the earlier date range")
+	}
+	before, err := tui.ParseDateRange(compare)
+	if err != nil {
+		return err
+	}
+
+	var after tui.DateRange
+	if to != "" {
+		if after, err = tui.ParseDateRange(to); err != nil {
+			return err
+		}
+	} else {
+		if before.To.IsZero() {
+			return fmt.Errorf("--compare %s runs until now, so give the range to compare it with using --to", compare)
+		}
+		after = tui.DateRange{From: before.To}
+	}
+
+	records, err := session.LoadSessionRecords(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load session records: %w", err)
+	}
+
+	fmt.Print(tui.RenderHeatmapComparison(cfg, records, before, after))
+	return nil
 }
 
 func init() {
 	statisticsCmd.Flags().StringVar(&statsFlags.view, "view", "", "statistics view (session, daily, weekly, all-time)")
 	statisticsCmd.Flags().BoolVar(&statsFlags.export, "export", false, "export current view data to Downloads folder")
 	statisticsCmd.Flags().BoolVar(&statsFlags.json, "json", false, "output statistics in JSON format")
+	statisticsCmd.Flags().StringVar(&statsFlags.compare, "compare", "", "earlier date range for the error heatmap diff (YYYY-MM-DD..YYYY-MM-DD)")
+	statisticsCmd.Flags().StringVar(&statsFlags.to, "to", "", "later date range for the error heatmap diff (default: after --compare until now)")
+	statisticsCmd.Flags().BoolVar(&statsFlags.card, "card", false, "save a shareable card of your last result (PNG in the Downloads folder)")
+	statisticsCmd.Flags().StringVarP(&statsFlags.output, "output", "o", "", "with --card, the file to save it to: .png, .ans (color text) or text; - prints it")
 }
 
-func calculateStatistics(records []*session.SessionRecord) *Statistics {
+func calculateStatistics(records []*session.SessionRecord, streaks config.StreaksConfig) *Statistics {
 	stats := &Statistics{}
+	records = session.CountedRecords(records)
 	totalSessions := len(records)
 	if totalSessions == 0 {
 		return stats
@@ -175,33 +289,43 @@ func calculateStatistics(records []*session.SessionRecord) *Statistics {
 	var totalDurationMs int64
 
 	for _, r := range records {
-		totalWPM += r.WPM
-		totalAccuracy += r.Accuracy
-		totalMistakes += r.Mistakes
 		totalDurationMs += r.DurationMs
-
-		if r.WPM > stats.RawPeakWPM {
-			stats.RawPeakWPM = r.WPM
-		}
-		if r.Accuracy > stats.RawBestAccuracy {
-			stats.RawBestAccuracy = r.Accuracy
-		}
 	}
-
 	stats.TotalSessions = totalSessions
 	stats.TotalTime = time.Duration(totalDurationMs) * time.Millisecond
-	stats.RawAvgWPM = totalWPM / float64(totalSessions)
-	stats.RawAvgAccuracy = totalAccuracy / float64(totalSessions)
-	stats.AvgMistakes = float64(totalMistakes) / float64(totalSessions)
+
+	// Sessions cut short count as time practised, but their speed only covers part of a test
+	if finished := session.FinishedRecords(records); len(finished) > 0 {
+		for _, r := range finished {
+			totalWPM += r.WPM
+			totalAccuracy += r.Accuracy
+			totalMistakes += r.Mistakes
+
+			if r.WPM > stats.RawPeakWPM {
+				stats.RawPeakWPM = r.WPM
+			}
+			if r.Accuracy > stats.RawBestAccuracy {
+				stats.RawBestAccuracy = r.Accuracy
+			}
+		}
+		stats.RawAvgWPM = totalWPM / float64(len(finished))
+		stats.RawAvgAccuracy = totalAccuracy / float64(len(finished))
+		stats.AvgMistakes = float64(totalMistakes) / float64(len(finished))
+	}
 	stats.BackspaceRate = 0
 
-	valid := make([]*session.SessionRecord, 0, totalSessions)
+	practised := make([]*session.SessionRecord, 0, totalSessions)
 	for _, r := range records {
 		d := time.Duration(r.DurationMs) * time.Millisecond
 		if d >= 15*time.Second && r.TextLength >= 60 {
-			valid = append(valid, r)
+			practised = append(practised, r)
 		}
 	}
+	stats.practised = practised
+	streak := session.CalculateStreaks(practised, streaks, time.Now())
+	stats.CurrentStreak, stats.LongestStreak, stats.FreezesLeft = streak.Current, streak.Longest, streak.FreezesLeft
+
+	valid := session.FinishedRecords(practised)
 	stats.ValidSessions = valid
 	stats.OutlierCount = totalSessions - len(valid)
 
@@ -260,7 +384,5 @@ func calculateStatistics(records []*session.SessionRecord) *Statistics {
 		}
 	}
 
-	stats.CurrentStreak, stats.LongestStreak = session.CalculateStreaks(valid)
-
 	return stats
 }
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal/config"
+	"gti/src/internal/remote"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync <command>",
+	Short: "Share your history and progress between machines",
+	Long: `Share your session history and lesson, course, challenge and book progress
+between machines through storage you choose: a Git repository, an S3 bucket
+(or MinIO, Backblaze B2 and the like), or a WebDAV folder such as Nextcloud.
+Sync is off until sync.backend is set in the config file.
+
+Sessions from every machine are merged by when they were saved, so none are
+lost however often each machine pushes. Progress files are not merged: the
+copy changed most recently wins, and the other machine is told to pull.
+
+COMMANDS:
+  push                        Send this machine's sessions and newer progress
+  pull                        Fetch other machines' sessions and newer progress
+
+SETUP:
+  gti config set sync.backend git
+  gti config set sync.url git@github.com:you/gti-data.git
+
+  gti config set sync.backend s3
+  gti config set sync.bucket my-bucket     # keys from AWS_ACCESS_KEY_ID and
+  gti config set sync.region eu-west-1     # AWS_SECRET_ACCESS_KEY
+
+  gti config set sync.backend webdav
+  gti config set sync.url https://cloud.example.com/remote.php/dav/files/you
+  gti config set sync.username you         # password from GTI_SYNC_PASSWORD
+
+Files go in the folder sync.prefix names (default: gti).`,
+}
+
+var syncPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Send this machine's sessions and newer progress",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		report, err := remote.Push(config.GetConfig())
+		if err != nil {
+			return err
+		}
+		printSyncReport(report)
+		fmt.Println("[SUCCESS] Pushed to", syncTarget(config.GetConfig()))
+		return nil
+	},
+}
+
+var syncPullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Fetch other machines' sessions and newer progress",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		report, err := remote.Pull(config.GetConfig())
+		if err != nil {
+			return err
+		}
+		printSyncReport(report)
+		fmt.Println("[SUCCESS] Pulled from", syncTarget(config.GetConfig()))
+		return nil
+	},
+}
+
+func init() {
+	syncCmd.AddCommand(syncPushCmd)
+	syncCmd.AddCommand(syncPullCmd)
+}
+
+func printSyncReport(report []string) {
+	for _, line := range report {
+		fmt.Println("  " + line)
+	}
+}
+
+// syncTarget names where sync keeps the files, for the success message
+func syncTarget(cfg *config.Config) string {
+	if cfg.Sync.Backend == config.SyncS3 {
+		return "s3://" + cfg.Sync.Bucket
+	}
+	return cfg.Sync.URL
+}
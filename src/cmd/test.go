@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal/app"
+	"gti/src/internal/standard"
+)
+
+var testStandard bool
+var testOutput string
+var testVerify string
+
+var testCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Take the standard test and check signed results",
+	Long: `Take the standard test: the same three-minute passage of prose for everyone,
+under rules that cannot be changed, scored by net WPM, where every error
+left uncorrected costs a word. The text never changes between attempts,
+pasted text is refused, and accents must be typed exactly.
+
+Each finished run is signed and saved as a result file. The test cannot be
+paused: the pause key ends it early, and a run stopped early, or one that
+text was pasted into, is not signed. Results are signed
+with a key gti creates beside the config file on first use, so a signature
+only ties a file to that key; share the key's fingerprint with a result so
+others can tell it is yours. Checking a file scores the typed text against
+the passage again.
+
+EXAMPLES:
+  gti test --standard                      # Take the standard test
+  gti test --standard --output ~/results   # Save signed results to ~/results
+  gti test --verify gti_standard.json      # Check a signed result file
+
+OPTIONS:
+  --standard                  Take the standard test
+  --output <dir>              Where signed results go (default: ~/Downloads)
+  --verify <file>             Check a signed result file`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if testVerify != "" {
+			if testStandard {
+				return fmt.Errorf("--verify checks a result file, drop --standard")
+			}
+			cmd.SilenceUsage = true
+			return verifyStandardResult(testVerify)
+		}
+		if !testStandard {
+			return fmt.Errorf("choose a test: --standard, or --verify <file> to check a result")
+		}
+
+		dir := testOutput
+		if dir == "" {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				homeDir = "."
+			}
+			dir = filepath.Join(homeDir, "Downloads")
+		}
+		cmd.SilenceUsage = true
+		return app.StartStandardTest(dir, Version)
+	},
+}
+
+// verifyStandardResult checks a signed result file and prints what it vouches for
+func verifyStandardResult(path string) error {
+	result, fingerprint, err := standard.Verify(path)
+	if err != nil {
+		return fmt.Errorf("%s does not verify: %w", path, err)
+	}
+	fmt.Printf("[SUCCESS] %s is a valid standard test result\n", path)
+	fmt.Printf("Net WPM:  %.1f\n", result.Results.NetWPM)
+	fmt.Printf("Raw WPM:  %.1f\n", result.Results.WPM)
+	fmt.Printf("Accuracy: %.1f%%\n", result.Results.Accuracy)
+	fmt.Printf("Uncorrected errors: %d\n", result.Results.UncorrectedErrors)
+	fmt.Printf("Taken:    %s (gti %s)\n", result.Started.Local().Format("2006-01-02 15:04"), result.GTIVersion)
+	fmt.Printf("Signed by key %s\n", fingerprint)
+	return nil
+}
+
+func init() {
+	testCmd.Flags().BoolVar(&testStandard, "standard", false, "take the standard three-minute test")
+	testCmd.Flags().StringVar(&testOutput, "output", "", "directory signed results are saved to (default: ~/Downloads)")
+	testCmd.Flags().StringVar(&testVerify, "verify", "", "check a signed result file")
+}
@@ -3,6 +3,8 @@ package cmd
 import (
 	"bufio"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -25,11 +27,20 @@ var themeCmd = &cobra.Command{
 flags:
   --list              list all available themes (built-in and custom)
   --set <name>        set the active theme
-  --preview <name>    preview a theme's colors without activating it`,
+  --preview <name>    preview a theme's colors without activating it
+
+commands:
+  list                browse the themes with a live preview and apply one
+  use <name>          set the active theme
+  import <file>       convert a base16, alacritty or wezterm color scheme into a theme`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := config.GetConfig()
 
-		if listFlag {
+		if listFlag && jsonOutput {
+			if err := printThemesJSON(cfg); err != nil {
+				fmt.Printf("[ERROR] %v\n", err)
+			}
+		} else if listFlag {
 			fmt.Println("Available themes:")
 			for _, themeName := range getAvailableThemeNames() {
 				fmt.Printf("  [✓] %s\n", themeName)
@@ -65,6 +76,15 @@ func init() {
 	themeCmd.Flags().BoolVar(&listFlag, "list", false, "list all available themes (built-in and custom)")
 	themeCmd.Flags().StringVar(&setFlag, "set", "", "set the active theme")
 	themeCmd.Flags().StringVar(&previewFlag, "preview", "", "preview a theme's colors without activating it")
+
+	themeImportCmd.Flags().StringVar(&importName, "name", "", "name for the imported theme (default: the scheme's own name)")
+	themeImportCmd.Flags().BoolVar(&importSet, "set", false, "make the imported theme active")
+	themeImportCmd.Flags().BoolVar(&importForce, "force", false, "replace an imported theme of the same name")
+	themeCmd.RegisterFlagCompletionFunc("set", completeThemes)
+	themeCmd.RegisterFlagCompletionFunc("preview", completeThemes)
+	themeCmd.AddCommand(themeListCmd)
+	themeCmd.AddCommand(themeUseCmd)
+	themeCmd.AddCommand(themeImportCmd)
 }
 
 func isThemeAvailable(cfg *config.Config, themeName string) bool {
@@ -111,6 +131,21 @@ func loadAvailableThemes() map[string]config.ThemeColorsConfig {
 		}
 	}
 
+	// Imported themes sit beside the built-in ones; import refuses names that would shadow a built-in
+	userEntries, _ := os.ReadDir(config.ThemesDir)
+	for _, entry := range userEntries {
+		if entry.IsDir() {
+			continue
+		}
+		if data, err := os.ReadFile(filepath.Join(config.ThemesDir, entry.Name())); err == nil {
+			if _, builtin := themes[entry.Name()]; !builtin {
+				if colors, err := parseThemeColors(data); err == nil {
+					themes[entry.Name()] = colors
+				}
+			}
+		}
+	}
+
 	return themes
 }
 
@@ -119,7 +154,11 @@ func loadThemeFromEmbeddedFile(filePath string) (config.ThemeColorsConfig, error
 	if err != nil {
 		return config.ThemeColorsConfig{}, err
 	}
+	return parseThemeColors(data)
+}
 
+// parseThemeColors reads a theme file of "Name: #color" lines
+func parseThemeColors(data []byte) (config.ThemeColorsConfig, error) {
 	var colors config.ThemeColorsConfig
 	scanner := bufio.NewScanner(strings.NewReader(string(data)))
 
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal/config"
+)
+
+var importName string
+var importSet bool
+var importForce bool
+
+var themeImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Convert a terminal color scheme into a gti theme",
+	Long: `Convert a terminal color scheme into a gti theme. The background and
+foreground become the text colors, green and red mark correct and incorrect
+characters, the cursor color marks the current one and blue is the accent.
+
+Supported formats:
+  base16 / base24 schemes (.yaml)
+  alacritty color files (.toml, or the older .yml)
+  wezterm color schemes (.toml)
+
+Imported themes are saved to the themes folder of your config directory and
+appear in 'gti theme --list'.
+
+EXAMPLES:
+  gti theme import tomorrow-night.yaml
+  gti theme import ~/.config/alacritty/themes/dracula.toml --set
+  gti theme import Catppuccin.toml --name catppuccin
+
+OPTIONS:
+  --name <name>               Theme name (default: the scheme's own name)
+  --set                       Make the imported theme active
+  --force                     Replace an imported theme of the same name`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, colors, err := config.ImportColorScheme(args[0])
+		if err != nil {
+			return err
+		}
+		if importName != "" {
+			name = config.ThemeName(importName)
+		}
+		if name == "" {
+			return fmt.Errorf("could not name the theme, pass one with --name")
+		}
+		if _, err := loadThemeFromEmbeddedFile("themes/" + name); err == nil {
+			return fmt.Errorf("'%s' is a built-in theme, pass another name with --name", name)
+		}
+
+		path := filepath.Join(config.ThemesDir, name)
+		if _, err := os.Stat(path); err == nil && !importForce {
+			return fmt.Errorf("theme '%s' already exists, pass --force to replace it", name)
+		}
+		if err := config.EnsureDir(config.ThemesDir); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(formatThemeColors(colors)), 0644); err != nil {
+			return err
+		}
+		fmt.Printf("[SUCCESS] Imported theme: %s\n\n", name)
+		previewTheme(name)
+
+		if importSet {
+			cfg := config.GetConfig()
+			cfg.Theme.Active = name
+			cfg.Theme.Colors = colors
+			if err := config.SaveConfig(); err != nil {
+				return fmt.Errorf("saving config: %w", err)
+			}
+			fmt.Printf("\n[SUCCESS] Theme set to: %s\n", name)
+		}
+		return nil
+	},
+}
+
+// formatThemeColors writes colors in the format of the built-in theme files
+func formatThemeColors(colors config.ThemeColorsConfig) string {
+	return fmt.Sprintf(`Background: %s
+Text Primary: %s
+Text Secondary: %s
+Correct: %s
+Incorrect: %s
+Current: %s
+Pending: %s
+Word Highlight: %s
+Accent: %s
+Border: %s
+Status Bar: %s
+`, colors.Background, colors.TextPrimary, colors.TextSecondary, colors.Correct, colors.Incorrect,
+		colors.Current, colors.Pending, colors.WordHighlight, colors.Accent, colors.Border, colors.StatusBar)
+}
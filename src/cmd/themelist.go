@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"gti/src/internal/config"
+	"gti/src/internal/tui"
+)
+
+var themeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Browse the themes with a live preview and apply one",
+	Long: `Browse every built-in and imported theme, each shown on a sample typing
+line in its own colors. Move with up/down, press Enter to apply the
+highlighted theme, or Esc to leave without changing anything.
+
+For a plain list of names, use 'gti theme --list'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+		if jsonOutput {
+			return printThemesJSON(cfg)
+		}
+		themes := loadAvailableThemes()
+
+		var gallery []tui.GalleryTheme
+		for _, name := range getAvailableThemeNames() {
+			gallery = append(gallery, tui.GalleryTheme{
+				Name:     name,
+				Colors:   themes[name],
+				Imported: isImportedTheme(name),
+			})
+		}
+
+		p := tea.NewProgram(tui.NewThemeGalleryModel(cfg, gallery), tea.WithAltScreen(), tea.WithMouseCellMotion())
+		final, err := p.Run()
+		if err != nil {
+			return fmt.Errorf("failed to run theme gallery: %w", err)
+		}
+
+		chosen := final.(tui.ThemeGalleryModel).Chosen
+		if chosen == "" {
+			return nil
+		}
+		cfg.Theme.Active = chosen
+		cfg.Theme.Colors = themes[chosen]
+		if err := config.SaveConfig(); err != nil {
+			return fmt.Errorf("saving config: %w", err)
+		}
+		fmt.Printf("[SUCCESS] Theme set to: %s\n", chosen)
+		return nil
+	},
+}
+
+// isImportedTheme reports whether the theme came from the themes folder rather than with gti
+func isImportedTheme(name string) bool {
+	_, err := os.Stat(filepath.Join(config.ThemesDir, name))
+	_, builtinErr := loadThemeFromEmbeddedFile("themes/" + name)
+	return err == nil && builtinErr != nil
+}
+
+// printThemesJSON lists the themes as JSON, for --json
+func printThemesJSON(cfg *config.Config) error {
+	type themeEntry struct {
+		Name     string `json:"name"`
+		Imported bool   `json:"imported"`
+		Active   bool   `json:"active"`
+	}
+	entries := []themeEntry{}
+	for _, name := range getAvailableThemeNames() {
+		entries = append(entries, themeEntry{Name: name, Imported: isImportedTheme(name), Active: name == cfg.Theme.Active})
+	}
+	return printJSON(entries)
+}
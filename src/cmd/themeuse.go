@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal/config"
+)
+
+var themeUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Make a theme the active one",
+	Long: `Make a built-in or imported theme the active one, as 'gti theme --set'
+does. Press Tab after 'gti theme use' to complete the theme's name.
+
+EXAMPLES:
+  gti theme use dracula
+  gti theme use deuteranopia`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArg(completeThemes),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+		name := args[0]
+		if !isThemeAvailable(cfg, name) {
+			return fmt.Errorf("theme '%s' is not available, see gti theme --list", name)
+		}
+
+		cfg.Theme.Active = name
+		cfg.Theme.Colors = getThemeColors(name)
+		if err := config.SaveConfig(); err != nil {
+			return fmt.Errorf("saving config: %w", err)
+		}
+		fmt.Printf("[SUCCESS] Theme set to: %s\n", name)
+		return nil
+	},
+}
@@ -12,7 +12,11 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print GTI version",
 	Long:  "Print the current GTI version.",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if jsonOutput {
+			return printJSON(map[string]string{"version": Version})
+		}
 		fmt.Printf("gti %s\n", Version)
+		return nil
 	},
 }
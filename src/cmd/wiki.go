@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gti/src/internal/app"
+)
+
+var wikiCount int
+
+var wikiCmd = &cobra.Command{
+	Use:   "wiki [topic]",
+	Short: "Type the summary of a Wikipedia article",
+	Long: `Type the opening summary of a Wikipedia article: the one on the given
+topic, or a random article each run for fresh prose that is never the same
+twice. Articles come from the Wikipedia edition of the configured language,
+and bracketed pronunciations are left out.
+
+Needs a network connection; the wait is capped by timeout_ms under [network].
+
+EXAMPLES:
+  gti wiki                    # A random article
+  gti wiki -n 3               # Three random articles
+  gti wiki Alan Turing        # The article on Alan Turing
+
+OPTIONS:
+  -n, --count <num>           Random articles to type (default: 1)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if wikiCount < 1 {
+			wikiCount = 1
+		}
+		if wikiCount > 10 {
+			wikiCount = 10
+		}
+		return app.StartWiki(strings.Join(args, " "), wikiCount)
+	},
+}
+
+func init() {
+	wikiCmd.Flags().IntVarP(&wikiCount, "count", "n", 1, "random articles to type")
+}
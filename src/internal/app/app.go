@@ -1,12 +1,25 @@
 package app
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
+	"unicode"
 
 	"gti/src/internal"
+	"gti/src/internal/book"
 	"gti/src/internal/challenge"
+	"gti/src/internal/cjk"
 	"gti/src/internal/config"
+	"gti/src/internal/course"
+	"gti/src/internal/lesson"
+	"gti/src/internal/openapi"
+	"gti/src/internal/protocol"
+	"gti/src/internal/repo"
 	"gti/src/internal/session"
+	"gti/src/internal/standard"
+	"gti/src/internal/tts"
 	"gti/src/internal/tui"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -21,13 +34,58 @@ type AppOptions struct {
 	Start      int    // for custom mode
 	Seconds    int    // for timed modes
 	CodeCount  int    // for code mode (multiple snippets)
+	Difficulty string // snippet difficulty tier for code mode, "" for any
+	BotWPM     float64 // simulated opponent speed, 0 to disable
+	FocusReps  int     // clean reps required for each mistyped word, 0 to disable
+	TimingFile string  // keystroke timing export path, "" to disable
+	AudioMarkers bool  // ring the bell at start and end and log sync markers in the timing export
+	Words        int   // words to type before the session ends, 0 for no word target
 }
 
+// listeningMaxWords keeps the quotes read aloud short enough to hold in mind while typing
+const listeningMaxWords = 16
+
 func runTUIModel(cfg *config.Config, opts tui.ModelOptions) error {
 	model := tui.NewModel(cfg, opts)
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	_, err := p.Run()
-	return err
+	programOpts := []tea.ProgramOption{tea.WithAltScreen()}
+	if internal.StdinRead() {
+		// The practice text came through the pipe, so keys have to come from the terminal itself
+		programOpts = append(programOpts, tea.WithInputTTY())
+	}
+	if cfg.Results.JSONOnce {
+		// Standard output carries the JSON, likely into a pipe, so the screen is drawn on standard error
+		programOpts = append(programOpts, tea.WithOutput(os.Stderr))
+	}
+	p := tea.NewProgram(model, programOpts...)
+	final, err := p.Run()
+	if err != nil {
+		return err
+	}
+	if cfg.Results.JSONOnce {
+		// A rerun from the results screen swaps the session, so the one the program ended on is reported
+		sess := model.Session()
+		if ended, ok := final.(interface{ Session() *session.Session }); ok {
+			sess = ended.Session()
+		}
+		return printJSONResult(sess)
+	}
+	return nil
+}
+
+// printJSONResult prints the session's results as JSON on standard output, once the screen is back
+func printJSONResult(sess *session.Session) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(session.NewResultsCalculator().BuildReport(sess))
+}
+
+// StartQuotes types the given quotes one after another
+func StartQuotes(quotes []session.Quote) error {
+	cfg := config.GetConfig()
+	sess := session.NewSession(cfg, "quotes", session.WithQuotes(quotes), session.WithQuoteSource(func(count int) []session.Quote {
+		return FetchMultipleQuotes(cfg, count)
+	}))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
 }
 
 // StartApp starts the typing application with the given options
@@ -43,7 +101,10 @@ func StartApp(opts AppOptions) error {
 
 	switch opts.Mode {
 	case "practice":
-		if opts.ChunkCount > 0 {
+		if opts.Words > 0 {
+			sess := session.NewSession(cfg, "practice", session.WithWordTarget(opts.Words))
+			modelOpts = tui.ModelOptions{Session: sess}
+		} else if opts.ChunkCount > 0 {
 			sess := session.NewSessionWithChunkLimit(cfg, opts.ChunkCount)
 			modelOpts = tui.ModelOptions{Session: sess}
 		} else {
@@ -80,16 +141,16 @@ func StartApp(opts AppOptions) error {
 	case "code":
 		if opts.CodeCount > 1 {
 			// Multiple snippets (timed or untimed)
-			sess := session.NewSessionWithCodeSnippetsTimed(cfg, opts.Language, opts.CodeCount, opts.Seconds)
+			sess := session.NewSession(cfg, "code", session.WithCodeLanguage(opts.Language), session.WithCodeCount(opts.CodeCount), session.WithDifficulty(opts.Difficulty), session.WithTimeLimit(opts.Seconds), session.WithWordTarget(opts.Words))
 			modelOpts = tui.ModelOptions{Session: sess}
 		} else if opts.Seconds > 0 {
 			// Single timed snippet
-			text := internal.GenerateCodeSnippet(opts.Language)
-			sess := session.NewSessionTimed(cfg, "code", text, nil, 0, opts.Seconds)
+			text := internal.GenerateCodeSnippet(opts.Language, opts.Difficulty)
+			sess := session.NewSession(cfg, "code", session.WithText(text, nil, 0), session.WithCodeLanguage(opts.Language), session.WithTimeLimit(opts.Seconds))
 			modelOpts = tui.ModelOptions{Session: sess}
 		} else {
 			// Single untimed snippet
-			sess := session.NewSessionWithCodeSnippet(cfg, "code")
+			sess := session.NewSession(cfg, "code", session.WithCodeLanguage(opts.Language), session.WithDifficulty(opts.Difficulty), session.WithWordTarget(opts.Words))
 			modelOpts = tui.ModelOptions{Session: sess}
 		}
 
@@ -97,6 +158,10 @@ func StartApp(opts AppOptions) error {
 		return fmt.Errorf("unknown mode: %s", opts.Mode)
 	}
 
+	modelOpts.BotWPM = opts.BotWPM
+	modelOpts.FocusReps = opts.FocusReps
+	modelOpts.TimingFile = opts.TimingFile
+	modelOpts.AudioMarkers = opts.AudioMarkers
 	return runTUIModel(cfg, modelOpts)
 }
 
@@ -158,6 +223,42 @@ func WithCodeCount(count int) AppOption {
 	}
 }
 
+// WithDifficulty sets the snippet difficulty tier for code mode
+func WithDifficulty(difficulty string) AppOption {
+	return func(o *AppOptions) {
+		o.Difficulty = difficulty
+	}
+}
+
+// WithBot adds a simulated opponent typing at the given WPM
+func WithBot(wpm float64) AppOption {
+	return func(o *AppOptions) {
+		o.BotWPM = wpm
+	}
+}
+
+// WithFocus loops each mistyped word until it is typed cleanly reps times in a row
+func WithFocus(reps int) AppOption {
+	return func(o *AppOptions) {
+		o.FocusReps = reps
+	}
+}
+
+// WithWordTarget ends the session once this many words have been typed
+func WithWordTarget(words int) AppOption {
+	return func(o *AppOptions) {
+		o.Words = words
+	}
+}
+
+// WithTimingExport writes every key press with its timing to file when the session ends
+func WithTimingExport(file string, audioMarkers bool) AppOption {
+	return func(o *AppOptions) {
+		o.TimingFile = file
+		o.AudioMarkers = audioMarkers
+	}
+}
+
 // Legacy functions for backward compatibility
 func StartPractice() error {
 	return StartAppWithOptions(WithMode("practice"))
@@ -187,12 +288,361 @@ func StartCustomTimed(file string, start int, seconds int) error {
 	return StartAppWithOptions(WithMode("custom"), WithCustomFile(file, start), WithTimeLimit(seconds))
 }
 
-func StartCodePractice(language string, count int) error {
-	return StartAppWithOptions(WithMode("code"), WithLanguage(language), WithCodeCount(count))
+func StartCodePractice(language string, count int, difficulty string) error {
+	return StartAppWithOptions(WithMode("code"), WithLanguage(language), WithCodeCount(count), WithDifficulty(difficulty))
+}
+
+// StartCodePracticeWords practices snippets, one after another, until the given number of words is typed
+func StartCodePracticeWords(language string, difficulty string, words int) error {
+	return StartAppWithOptions(WithMode("code"), WithLanguage(language), WithDifficulty(difficulty), WithWordTarget(words))
+}
+
+func StartCodePracticeTimed(language string, count int, difficulty string, seconds int) error {
+	return StartAppWithOptions(WithMode("code"), WithLanguage(language), WithCodeCount(count), WithDifficulty(difficulty), WithTimeLimit(seconds))
+}
+
+// StartOpenAPIPractice practices client and handler snippets generated from an OpenAPI spec
+func StartOpenAPIPractice(specFile string, language string, count int, seconds int) error {
+	cfg := config.GetConfig()
+
+	if err := openapi.ValidateLanguage(language); err != nil {
+		return err
+	}
+	spec, err := openapi.LoadSpec(specFile)
+	if err != nil {
+		return err
+	}
+
+	snippets := openapi.Snippets(spec, language, count)
+	sess := session.NewSession(cfg, "code", session.WithText(strings.Join(snippets, "\n\n"), nil, 0), session.WithCodeLanguage(language), session.WithTimeLimit(seconds))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartRepoPractice practices functions taken from the source files of a local repository
+func StartRepoPractice(root string, language string, count int, seconds int) error {
+	cfg := config.GetConfig()
+
+	snippets, language, err := repo.Snippets(root, language, count)
+	if err != nil {
+		return err
+	}
+	sess := session.NewSession(cfg, "code", session.WithText(strings.Join(snippets, "\n\n"), nil, 0), session.WithCodeLanguage(language), session.WithTimeLimit(seconds))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartURLPractice practices functions from a source file downloaded from a URL or GitHub gist
+func StartURLPractice(rawURL string, language string, count int, seconds int) error {
+	cfg := config.GetConfig()
+
+	path, err := FetchSource(cfg, rawURL)
+	if err != nil {
+		return err
+	}
+	if language == "" {
+		language = internal.DetectCodeLanguage(path)
+	}
+	if language == "" {
+		return fmt.Errorf("could not detect the language of %s, pass it with --language", rawURL)
+	}
+
+	snippets, err := repo.FileSnippets(path, language, count)
+	if err != nil {
+		return err
+	}
+	sess := session.NewSession(cfg, "code", session.WithText(strings.Join(snippets, "\n\n"), nil, 0), session.WithCodeLanguage(language), session.WithTimeLimit(seconds))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartDocumentPractice practices generated JSON, YAML or TOML config documents
+func StartDocumentPractice(format string, count int, seconds int) error {
+	cfg := config.GetConfig()
+
+	text := internal.GenerateDocuments(count, format)
+	sess := session.NewSession(cfg, "document", session.WithText(text, nil, 0), session.WithCodeLanguage(format), session.WithTimeLimit(seconds))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartLogPractice practices generated log lines and stack traces
+func StartLogPractice(kind string, count int, seconds int) error {
+	cfg := config.GetConfig()
+
+	text := internal.GenerateLogs(count, kind)
+	sess := session.NewSession(cfg, "logs", session.WithText(text, nil, 0), session.WithTimeLimit(seconds))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
 }
 
-func StartCodePracticeTimed(language string, count int, seconds int) error {
-	return StartAppWithOptions(WithMode("code"), WithLanguage(language), WithCodeCount(count), WithTimeLimit(seconds))
+// StartKanaPractice practices hiragana and/or katakana typed as romaji
+func StartKanaPractice(script string, words int, seconds int) error {
+	cfg := config.GetConfig()
+
+	if err := cjk.ValidateKanaScript(script); err != nil {
+		return err
+	}
+	sess := session.NewSession(cfg, "kana", session.WithDrill(cjk.GenerateKana(script, words)), session.WithTimeLimit(seconds))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartHangulPractice practices Hangul syllables typed jamo by jamo on the standard 2-set layout
+func StartHangulPractice(words int, seconds int) error {
+	cfg := config.GetConfig()
+
+	sess := session.NewSession(cfg, "hangul", session.WithDrill(cjk.GenerateHangul(words)), session.WithTimeLimit(seconds))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartPinyinPractice practices characters from the Chinese word list typed as their pinyin
+func StartPinyinPractice(words int, seconds int) error {
+	cfg := config.GetConfig()
+
+	chars := strings.Fields(internal.GenerateWordsDynamic(words, "chinese"))
+	sess := session.NewSession(cfg, "pinyin", session.WithDrill(cjk.GeneratePinyin(chars)), session.WithTimeLimit(seconds))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartBoardDrill practices the transitions that change most on ortholinear or split keyboards, one round at a time
+func StartBoardDrill(board string, words int, seconds int) error {
+	cfg := config.GetConfig()
+
+	if err := internal.ValidateBoard(board); err != nil {
+		return err
+	}
+	rounds, guidance := internal.GenerateBoardRounds(board, words)
+	sess := session.NewSession(cfg, "board", session.WithText(rounds[0], rounds, 0), session.WithRoundTips(guidance), session.WithTimeLimit(seconds))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartNumberDrill practices text made mostly of digits: numbers, prices, times, dates and versions
+func StartNumberDrill(count int, seconds int) error {
+	cfg := config.GetConfig()
+
+	text := internal.GenerateNumberDrill(count, cfg.Language.Default)
+	sess := session.NewSession(cfg, "numbers", session.WithText(text, nil, 0), session.WithTimeLimit(seconds))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartSymbolDrill practices brackets, operators and punctuation around short words
+func StartSymbolDrill(count int, seconds int) error {
+	cfg := config.GetConfig()
+
+	text := internal.GenerateSymbolDrill(count, cfg.Language.Default)
+	sess := session.NewSession(cfg, "symbols", session.WithText(text, nil, 0), session.WithTimeLimit(seconds))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartPseudoWordDrill practices pronounceable made-up words, which no remembered spelling helps with
+func StartPseudoWordDrill(count int, seconds int) error {
+	cfg := config.GetConfig()
+
+	text := internal.GeneratePseudoWords(count)
+	sess := session.NewSession(cfg, "pseudo", session.WithText(text, nil, 0), session.WithTimeLimit(seconds))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartRandomDrill practices strings of random letters with the given percentage of digits and symbols
+func StartRandomDrill(count int, symbolPercent int, seconds int) error {
+	cfg := config.GetConfig()
+
+	if err := internal.ValidateSymbolDensity(symbolPercent); err != nil {
+		return err
+	}
+	text := internal.GenerateRandomStrings(count, float64(symbolPercent)/100)
+	sess := session.NewSession(cfg, "random", session.WithText(text, nil, 0), session.WithTimeLimit(seconds))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartLesson practices one lesson of the layout's plan, the first one not yet passed when number is 0
+func StartLesson(layout string, number int, words int) error {
+	cfg := config.GetConfig()
+
+	plan, err := lesson.PlanFor(layout)
+	if err != nil {
+		return err
+	}
+	if number == 0 {
+		number = plan.Next(lesson.LoadProgress())
+	}
+	if number < 1 || number > len(plan.Lessons) {
+		return fmt.Errorf("%s has lessons 1 to %d", plan.Layout, len(plan.Lessons))
+	}
+	l := plan.Lessons[number-1]
+	if plan.Emulated {
+		cfg.Keyboard.EmulateOnce = plan.Layout
+	} else {
+		cfg.Keyboard.Layout = plan.Layout
+	}
+
+	rounds := make([]string, lesson.Rounds)
+	guidance := make([]string, lesson.Rounds)
+	for i := range rounds {
+		rounds[i] = l.Text(words, cfg.Language.Default)
+		guidance[i] = l.Guidance()
+	}
+	sess := session.NewSession(cfg, "lesson", session.WithText(rounds[0], rounds, 0), session.WithRoundTips(guidance))
+	sess.SetTier(fmt.Sprintf("%s-%d", plan.Layout, l.Number))
+	sess.EnableLessonCheck(plan.Check(l))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartCourse runs one lesson of the beginner course, the first one not yet passed when number is 0
+func StartCourse(number int, words int) error {
+	cfg := config.GetConfig()
+
+	lessons := course.Curriculum(cfg.Keyboard.Layout)
+	if number == 0 {
+		number = course.LoadProgress().Next(lessons)
+	}
+	if number < 1 || number > len(lessons) {
+		return fmt.Errorf("the course has lessons 1 to %d", len(lessons))
+	}
+	l := lessons[number-1]
+
+	rounds := make([]string, lesson.Rounds)
+	guidance := make([]string, lesson.Rounds)
+	for i := range rounds {
+		rounds[i] = l.Text(words, cfg.Language.Default)
+		guidance[i] = l.Guidance()
+	}
+	sess := session.NewSession(cfg, "learn", session.WithText(rounds[0], rounds, 0), session.WithRoundTips(guidance))
+	sess.SetTier(fmt.Sprint(l.Number))
+	sess.EnableRunCheck(course.Check(lessons, l))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartListening reads sentences aloud one at a time and scores what is typed for each, word by word
+func StartListening(count int) error {
+	cfg := config.GetConfig()
+
+	if _, err := tts.For(cfg.TTS); err != nil {
+		return fmt.Errorf("listening drills read sentences aloud: %w", err)
+	}
+	sentences := listeningSentences(cfg.Language.Default, count)
+	sess := session.NewSession(cfg, "listen", session.WithText(sentences[0], sentences, 0), session.WithListening())
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// listeningSentences picks short quotes from the offline cache for English, topping them up
+// with sentences made from the language's word list
+func listeningSentences(language string, count int) []string {
+	var sentences []string
+	if language == "english" {
+		quotes, _ := LoadCachedQuotes()
+		for _, i := range internal.Perm(len(quotes)) {
+			if len(sentences) == count {
+				break
+			}
+			if n := len(strings.Fields(quotes[i].Text)); n >= 4 && n <= listeningMaxWords {
+				sentences = append(sentences, quotes[i].Text)
+			}
+		}
+	}
+	for len(sentences) < count {
+		words := []rune(internal.GenerateWordsDynamic(5+internal.Intn(6), language))
+		words[0] = unicode.ToUpper(words[0])
+		sentences = append(sentences, string(words)+".")
+	}
+	return sentences
+}
+
+// StartWiki types the summary of the Wikipedia article on topic, or of count random articles,
+// with each article's title shown while it is typed
+func StartWiki(topic string, count int) error {
+	cfg := config.GetConfig()
+	if topic != "" {
+		count = 1
+	}
+
+	var texts, titles []string
+	for range count {
+		article, err := FetchWikiArticle(cfg, topic, cfg.Language.Default)
+		if err != nil {
+			return err
+		}
+		texts = append(texts, article.Extract)
+		titles = append(titles, "Wikipedia: "+article.Title)
+	}
+	sess := session.NewSession(cfg, "wiki", session.WithText(texts[0], texts, 0), session.WithRoundTips(titles))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartNews types count stories from the configured news feeds, each headline followed by its summary
+func StartNews(count int, refresh bool) error {
+	cfg := config.GetConfig()
+
+	items, err := LoadNews(cfg, refresh)
+	if err != nil {
+		return err
+	}
+	var texts, sources []string
+	for _, item := range PickNews(items, count) {
+		texts = append(texts, item.Text)
+		sources = append(sources, "News: "+item.Source)
+	}
+	sess := session.NewSession(cfg, "news", session.WithText(texts[0], texts, 0), session.WithRoundTips(sources))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartBook types a book a paragraph at a time from paragraph start, counting from 1, or from its
+// bookmark when start is 0. The bookmark moves on with every paragraph finished.
+func StartBook(file string, start int) error {
+	cfg := config.GetConfig()
+
+	paragraphs, err := book.Load(file)
+	if err != nil {
+		return err
+	}
+	next := start - 1
+	if start == 0 {
+		next = 0
+		// A finished book starts over, and one edited since to be shorter resumes at its last paragraph
+		if mark, ok := book.LoadBookmark(file); ok && !mark.Finished() {
+			next = min(mark.Paragraph, len(paragraphs)-1)
+		}
+	}
+	if next < 0 || next >= len(paragraphs) {
+		return fmt.Errorf("%s has paragraphs 1 to %d", file, len(paragraphs))
+	}
+
+	sess := session.NewSession(cfg, "book", session.WithText(paragraphs[next], paragraphs, next))
+	sess.EnableBookmark(func(next int) {
+		book.SaveBookmark(file, next, len(paragraphs))
+	})
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartProtocol runs a test locked to a research protocol, writing a protocol-stamped result file after every run
+func StartProtocol(p *protocol.Protocol, participant string, gtiVersion string) error {
+	cfg := protocol.LockedConfig(config.GetConfig(), p)
+
+	sess := session.NewSession(cfg, "protocol", session.WithText(p.Text(), nil, 0), session.WithTimeLimit(p.Seconds), session.WithInputPolicy(p.StopOnError(), p.NoBackspace()))
+	sess.EnableProtocolResult(p.OutputDir(), p.ResultName(participant), p.Stamp(participant, gtiVersion))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartStandardTest runs the standard test, signing each finished run's result into dir
+func StartStandardTest(dir string, gtiVersion string) error {
+	cfg := standard.LockedConfig(config.GetConfig())
+
+	sess := session.NewSession(cfg, standard.Mode, session.WithText(standard.Text(), nil, 0), session.WithTimeLimit(standard.Seconds))
+	sess.EnableStandardResult(func(run session.StandardRun) (string, error) {
+		return standard.Save(run, dir, gtiVersion)
+	})
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
+
+// StartKiosk runs the unattended demo-machine loop until someone enters the passcode
+func StartKiosk(opts tui.KioskOptions) error {
+	cfg := config.GetConfig()
+
+	if opts.Passcode == "" {
+		opts.Passcode = cfg.Kiosk.Passcode
+	}
+	if opts.Passcode == "" {
+		return fmt.Errorf("kiosk mode needs a passcode to exit: pass --passcode or set passcode under [kiosk] in the config")
+	}
+
+	p := tea.NewProgram(tui.NewKioskModel(cfg, opts), tea.WithAltScreen())
+	_, err := p.Run()
+	return err
 }
 
 func StartChallengeGame() error {
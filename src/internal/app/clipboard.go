@@ -0,0 +1,35 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/atotto/clipboard"
+
+	"gti/src/internal"
+	"gti/src/internal/config"
+)
+
+// SaveClipboard writes the clipboard's text, made typeable, to the cache and returns the file, so it
+// can be practised like any custom file. Each copy replaces the last.
+func SaveClipboard() (string, error) {
+	text, err := clipboard.ReadAll()
+	if err != nil {
+		return "", fmt.Errorf("could not read the clipboard: %w", err)
+	}
+	text = internal.NormalizeText(text)
+	if strings.TrimSpace(text) == "" {
+		return "", fmt.Errorf("the clipboard has no text to practice")
+	}
+
+	if err := config.EnsureDir(config.CacheDir); err != nil {
+		return "", err
+	}
+	file := filepath.Join(config.CacheDir, "clipboard.txt")
+	if err := os.WriteFile(file, []byte(text), 0644); err != nil {
+		return "", err
+	}
+	return file, nil
+}
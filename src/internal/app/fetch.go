@@ -0,0 +1,111 @@
+package app
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gti/src/internal/config"
+)
+
+// MaxSourceSize caps how much of a remote source file is downloaded
+const MaxSourceSize = 512 * 1024
+
+func sourceCacheDir() string {
+	return filepath.Join(config.CacheDir, "sources")
+}
+
+// rawSourceURL points GitHub file pages and gists at their raw content
+func rawSourceURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	switch u.Host {
+	case "github.com":
+		// github.com/<owner>/<repo>/blob/<ref>/<path> -> raw.githubusercontent.com/<owner>/<repo>/<ref>/<path>
+		if len(parts) > 4 && parts[2] == "blob" {
+			return "https://raw.githubusercontent.com/" + strings.Join(append(parts[:2], parts[3:]...), "/")
+		}
+	case "gist.github.com":
+		// gist.github.com/<owner>/<id> -> gist.githubusercontent.com/<owner>/<id>/raw
+		if len(parts) == 2 {
+			return "https://gist.githubusercontent.com/" + strings.Join(parts, "/") + "/raw"
+		}
+	}
+	return rawURL
+}
+
+// sourceCachePath keys the cache by URL while keeping the file name, so its extension still tells the language
+func sourceCachePath(rawURL string) string {
+	h := fnv.New32a()
+	h.Write([]byte(rawURL))
+
+	name := "source"
+	if u, err := url.Parse(rawURL); err == nil {
+		if base := path.Base(u.Path); base != "." && base != "/" && base != "raw" {
+			name = base
+		}
+	}
+	return filepath.Join(sourceCacheDir(), fmt.Sprintf("%08x-%s", h.Sum32(), name))
+}
+
+// FetchSource downloads a source file, reusing the cached copy from an earlier download of the same URL
+func FetchSource(cfg *config.Config, rawURL string) (string, error) {
+	if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
+		return "", fmt.Errorf("not an http(s) URL: %s", rawURL)
+	}
+	rawURL = rawSourceURL(rawURL)
+
+	cachePath := sourceCachePath(rawURL)
+	if _, err := os.Stat(cachePath); err == nil {
+		return cachePath, nil
+	}
+
+	body, err := download(cfg, rawURL, MaxSourceSize)
+	if err != nil {
+		return "", err
+	}
+
+	if err := config.EnsureDir(sourceCacheDir()); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(cachePath, body, 0644); err != nil {
+		return "", err
+	}
+	return cachePath, nil
+}
+
+// download fetches rawURL within the configured timeout, refusing bodies larger than limit bytes
+func download(cfg *config.Config, rawURL string, limit int64) ([]byte, error) {
+	client := &http.Client{
+		Timeout: time.Duration(cfg.Network.TimeoutMs) * time.Millisecond,
+	}
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", rawURL, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("%s is larger than %d KB", rawURL, limit/1024)
+	}
+	return body, nil
+}
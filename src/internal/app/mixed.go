@@ -0,0 +1,79 @@
+package app
+
+import (
+	"fmt"
+	"math/rand"
+
+	"gti/src/internal"
+	"gti/src/internal/config"
+	"gti/src/internal/session"
+	"gti/src/internal/tui"
+)
+
+// mixedProvider draws each chunk from words, quotes or code at random, weighted by the [mixed] config
+type mixedProvider struct {
+	cfg    *config.Config
+	mixed  config.MixedConfig
+	words  int
+	quotes []session.Quote
+	rng    *rand.Rand
+}
+
+// newMixedProvider fetches the quotes a session might need up front, so no chunk waits on the network
+func newMixedProvider(cfg *config.Config, chunks int) (*mixedProvider, error) {
+	mixed := cfg.Mixed
+	if mixed.Words < 0 || mixed.Quotes < 0 || mixed.Code < 0 || mixed.Words+mixed.Quotes+mixed.Code == 0 {
+		return nil, fmt.Errorf("[mixed] weights must be 0 or more and at least one above 0")
+	}
+	if mixed.CodeLanguage != "" && mixed.Code > 0 {
+		if err := internal.ValidateCodeLanguage(mixed.CodeLanguage); err != nil {
+			return nil, err
+		}
+	}
+
+	p := &mixedProvider{
+		cfg:   cfg,
+		mixed: mixed,
+		rng:   rand.New(rand.NewSource(internal.Int63())),
+	}
+	_, words := cfg.ForMode("mixed")
+	p.words = words.Words(session.DefaultWordCount)
+	if mixed.Quotes > 0 {
+		p.quotes = FetchMultipleQuotes(cfg, chunks)
+	}
+	return p, nil
+}
+
+// NextChunk implements session.TextProvider
+func (p *mixedProvider) NextChunk() session.Chunk {
+	pick := p.rng.Intn(p.mixed.Words + p.mixed.Quotes + p.mixed.Code)
+	switch {
+	case pick < p.mixed.Words:
+		return session.Chunk{Text: internal.GenerateWordsDynamic(p.words, p.cfg.Language.Default)}
+	case pick < p.mixed.Words+p.mixed.Quotes:
+		q := p.quotes[p.rng.Intn(len(p.quotes))]
+		return session.Chunk{Text: q.Text, Author: q.Author}
+	default:
+		language := p.mixed.CodeLanguage
+		if language == "" {
+			languages := internal.GetSupportedCodeLanguages()
+			language = languages[p.rng.Intn(len(languages))]
+		}
+		return session.Chunk{Text: internal.GenerateCodeSnippet(language, ""), Language: language}
+	}
+}
+
+// StartMixed practices chunks drawn from a weighted mix of generated words, quotes and code snippets
+func StartMixed(chunks int) error {
+	cfg := config.GetConfig()
+
+	if chunks <= 0 {
+		chunks = max(cfg.Mixed.Chunks, 1)
+	}
+	provider, err := newMixedProvider(cfg, chunks)
+	if err != nil {
+		return err
+	}
+	sess := session.NewSession(cfg, "mixed", session.WithProvider(provider, chunks))
+	return runTUIModel(cfg, tui.ModelOptions{Session: sess})
+}
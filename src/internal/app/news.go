@@ -0,0 +1,261 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gti/src/internal"
+	"gti/src/internal/config"
+)
+
+const (
+	// MaxFeedSize caps how much of a feed is downloaded
+	MaxFeedSize = 2 * 1024 * 1024
+	// maxNewsWords keeps each story to a paragraph, cutting long summaries at a sentence end where one is near
+	maxNewsWords = 60
+	// newsPool is how many of the newest stories a session's stories are picked from
+	newsPool = 30
+)
+
+// NewsItem is one story of a feed, ready to type
+type NewsItem struct {
+	Text      string    `json:"text"`
+	Source    string    `json:"source"`
+	Published time.Time `json:"published"`
+}
+
+// cachedFeed is what was last fetched from one feed
+type cachedFeed struct {
+	Fetched time.Time  `json:"fetched"`
+	Items   []NewsItem `json:"items"`
+}
+
+// feedDocument reads RSS 2.0, RSS 1.0 and Atom alike; each fills in only its own fields
+type feedDocument struct {
+	Title   string `xml:"title"`
+	Channel struct {
+		Title string     `xml:"title"`
+		Items []feedItem `xml:"item"`
+	} `xml:"channel"`
+	// RSS 1.0 puts its items beside the channel rather than in it
+	Items   []feedItem `xml:"item"`
+	Entries []struct {
+		Title     string `xml:"title"`
+		Summary   string `xml:"summary"`
+		Content   string `xml:"content"`
+		Updated   string `xml:"updated"`
+		Published string `xml:"published"`
+	} `xml:"entry"`
+}
+
+type feedItem struct {
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+	PubDate     string `xml:"pubDate"`
+	Date        string `xml:"date"`
+}
+
+var feedTimeLayouts = []string{time.RFC1123Z, time.RFC1123, time.RFC822Z, time.RFC822, time.RFC3339,
+	"Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST", "2006-01-02"}
+
+func newsCachePath() string {
+	return filepath.Join(config.CacheDir, "news.json")
+}
+
+func loadNewsCache() map[string]cachedFeed {
+	feeds := make(map[string]cachedFeed)
+	if data, err := os.ReadFile(newsCachePath()); err == nil {
+		json.Unmarshal(data, &feeds)
+	}
+	return feeds
+}
+
+func saveNewsCache(feeds map[string]cachedFeed) error {
+	data, err := json.MarshalIndent(feeds, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := config.EnsureDir(config.CacheDir); err != nil {
+		return err
+	}
+	return os.WriteFile(newsCachePath(), data, 0644)
+}
+
+// LoadNews returns the stories of every configured feed, newest first. Feeds fetched within the
+// last cache_minutes are reused unless refresh is set, and a feed that cannot be fetched falls back
+// to its cached stories however old. It fails only when no feed has any story to give.
+func LoadNews(cfg *config.Config, refresh bool) ([]NewsItem, error) {
+	if len(cfg.News.Feeds) == 0 {
+		return nil, fmt.Errorf("no news feeds configured, add some to feeds under [news] in %s", config.ConfigFile)
+	}
+
+	cache := loadNewsCache()
+	maxAge := time.Duration(cfg.News.CacheMinutes) * time.Minute
+	var items []NewsItem
+	var errs []error
+	fetched := false
+	for _, feedURL := range cfg.News.Feeds {
+		cached, ok := cache[feedURL]
+		if !ok || refresh || time.Since(cached.Fetched) >= maxAge {
+			fresh, err := fetchFeed(cfg, feedURL)
+			if err == nil {
+				cached, ok = cachedFeed{Fetched: time.Now(), Items: fresh}, true
+				cache[feedURL] = cached
+				fetched = true
+			} else {
+				errs = append(errs, fmt.Errorf("%s: %w", feedURL, err))
+			}
+		}
+		if ok {
+			items = append(items, cached.Items...)
+		}
+	}
+	if fetched {
+		saveNewsCache(cache)
+	}
+
+	if len(items) == 0 {
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("could not load any news feed: %w", errors.Join(errs...))
+		}
+		return nil, fmt.Errorf("the news feeds have no stories")
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Published.After(items[j].Published)
+	})
+	return items, nil
+}
+
+// PickNews picks count different stories at random from the newest ones
+func PickNews(items []NewsItem, count int) []NewsItem {
+	pool := items[:min(len(items), max(newsPool, count))]
+	count = min(count, len(pool))
+	picked := make([]NewsItem, count)
+	for i, j := range internal.Perm(len(pool))[:count] {
+		picked[i] = pool[j]
+	}
+	return picked
+}
+
+func fetchFeed(cfg *config.Config, feedURL string) ([]NewsItem, error) {
+	if !strings.HasPrefix(feedURL, "http://") && !strings.HasPrefix(feedURL, "https://") {
+		return nil, fmt.Errorf("not an http(s) URL")
+	}
+	body, err := download(cfg, feedURL, MaxFeedSize)
+	if err != nil {
+		return nil, err
+	}
+	return parseFeed(body)
+}
+
+// parseFeed turns a feed into stories, each its headline followed by its summary
+func parseFeed(data []byte) ([]NewsItem, error) {
+	var doc feedDocument
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	// Feeds are routinely served with HTML entities such as &nbsp; that XML does not define
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = latin1Reader
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("not a readable RSS or Atom feed: %w", err)
+	}
+
+	source := strings.TrimSpace(doc.Channel.Title)
+	if source == "" {
+		source = strings.TrimSpace(doc.Title)
+	}
+	var items []NewsItem
+	add := func(title, summary, date string) {
+		if text := newsText(title, summary); text != "" {
+			items = append(items, NewsItem{Text: text, Source: source, Published: parseFeedTime(date)})
+		}
+	}
+	for _, item := range append(doc.Channel.Items, doc.Items...) {
+		date := item.PubDate
+		if date == "" {
+			date = item.Date
+		}
+		add(item.Title, item.Description, date)
+	}
+	for _, entry := range doc.Entries {
+		summary := entry.Summary
+		if summary == "" {
+			summary = entry.Content
+		}
+		date := entry.Published
+		if date == "" {
+			date = entry.Updated
+		}
+		add(entry.Title, summary, date)
+	}
+	return items, nil
+}
+
+// newsText joins a headline and its summary into one paragraph, dropping any markup in the summary
+func newsText(title, summary string) string {
+	title = strings.Join(strings.Fields(internal.NormalizeText(title)), " ")
+	summary = strings.Join(strings.Fields(internal.ExtractBookText(summary)), " ")
+	if title == "" {
+		return trimNews(summary)
+	}
+	if summary == "" || strings.EqualFold(summary, title) {
+		return title
+	}
+	if !strings.ContainsAny(title[len(title)-1:], ".!?") {
+		title += "."
+	}
+	return trimNews(title + " " + summary)
+}
+
+// trimNews cuts text to maxNewsWords, at the last sentence end in the second half of them if there is one
+func trimNews(text string) string {
+	words := strings.Fields(text)
+	if len(words) <= maxNewsWords {
+		return text
+	}
+	words = words[:maxNewsWords]
+	for i := len(words) - 1; i >= maxNewsWords/2; i-- {
+		if strings.ContainsAny(words[i][len(words[i])-1:], ".!?") {
+			return strings.Join(words[:i+1], " ")
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// latin1Reader decodes the single-byte encodings older feeds still declare; Windows-1252 is read as
+// Latin-1, which differs only in a few punctuation marks
+func latin1Reader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1", "latin-1", "windows-1252", "cp1252", "us-ascii":
+	default:
+		return nil, fmt.Errorf("unsupported feed encoding %s", charset)
+	}
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return strings.NewReader(string(runes)), nil
+}
+
+func parseFeedTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range feedTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
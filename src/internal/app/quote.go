@@ -3,6 +3,7 @@ package app
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"time"
@@ -21,7 +22,12 @@ func FetchQuote(cfg *config.Config) string {
 	return q.Text
 }
 
+// FetchQuoteWithAuthor takes a quote from the offline cache, going to the network only when the cache is empty
 func FetchQuoteWithAuthor(cfg *config.Config) session.Quote {
+	if cached := cachedQuotes(1); cached != nil {
+		return cached[0]
+	}
+
 	client := &http.Client{
 		Timeout: time.Duration(cfg.Network.TimeoutMs) * time.Millisecond,
 	}
@@ -67,6 +73,10 @@ func FetchMultipleQuotes(cfg *config.Config, count int) []session.Quote {
 		count = 10
 	}
 
+	if cached := cachedQuotes(count); cached != nil {
+		return cached
+	}
+
 	var quotes []session.Quote
 	client := &http.Client{
 		Timeout: time.Duration(cfg.Network.TimeoutMs) * time.Millisecond,
@@ -112,3 +122,25 @@ func FetchMultipleQuotes(cfg *config.Config, count int) []session.Quote {
 
 	return quotes
 }
+
+// CheckQuoteAPI asks the quote provider for one quote, returning why it could not be reached
+func CheckQuoteAPI(cfg *config.Config) error {
+	client := &http.Client{
+		Timeout: time.Duration(cfg.Network.TimeoutMs) * time.Millisecond,
+	}
+
+	resp, err := client.Get("https://zenquotes.io/api/random")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered %s", quoteProviderAuthor, resp.Status)
+	}
+	var quotes []QuoteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&quotes); err != nil || len(quotes) == 0 {
+		return fmt.Errorf("%s sent no quotes", quoteProviderAuthor)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"gti/src/internal"
+	"gti/src/internal/config"
+	"gti/src/internal/session"
+)
+
+const (
+	// quoteBatchURL returns 50 random quotes per request
+	quoteBatchURL = "https://zenquotes.io/api/quotes"
+	// quoteRequestInterval keeps prefetching within the provider's 5 requests per 30 seconds
+	quoteRequestInterval = 6 * time.Second
+	// quoteRateLimitWait is how long to back off after being rate limited when no Retry-After is given
+	quoteRateLimitWait = 30 * time.Second
+	// quoteMaxRetries gives up on a prefetch after this many rate limited or failed requests in a row
+	quoteMaxRetries = 3
+	// MaxCachedQuotes caps the offline cache; the oldest quotes make room for new ones
+	MaxCachedQuotes = 1000
+	// quoteProviderAuthor marks the provider's own notices, such as its rate limit message, returned as quotes
+	quoteProviderAuthor = "zenquotes.io"
+)
+
+func quoteCachePath() string {
+	return filepath.Join(config.CacheDir, "quotes.json")
+}
+
+// LoadCachedQuotes returns the quotes stored by earlier prefetches, oldest first
+func LoadCachedQuotes() ([]session.Quote, error) {
+	data, err := os.ReadFile(quoteCachePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var quotes []session.Quote
+	if err := json.Unmarshal(data, &quotes); err != nil {
+		return nil, fmt.Errorf("failed to read quote cache: %w", err)
+	}
+	return quotes, nil
+}
+
+func saveCachedQuotes(quotes []session.Quote) error {
+	if len(quotes) > MaxCachedQuotes {
+		quotes = quotes[len(quotes)-MaxCachedQuotes:]
+	}
+	data, err := json.MarshalIndent(quotes, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := config.EnsureDir(config.CacheDir); err != nil {
+		return err
+	}
+	return os.WriteFile(quoteCachePath(), data, 0644)
+}
+
+// cachedQuotes picks count different quotes from the offline cache, or returns nil when it holds fewer than that
+func cachedQuotes(count int) []session.Quote {
+	quotes, err := LoadCachedQuotes()
+	if err != nil || len(quotes) < count {
+		return nil
+	}
+	picked := make([]session.Quote, count)
+	for i, j := range internal.Perm(len(quotes))[:count] {
+		picked[i] = quotes[j]
+	}
+	return picked
+}
+
+// errRateLimited reports that the provider asked to slow down, and for how long
+type errRateLimited struct {
+	wait time.Duration
+}
+
+func (e errRateLimited) Error() string {
+	return fmt.Sprintf("rate limited, retrying in %s", e.wait)
+}
+
+// fetchQuoteBatch makes a single request for a batch of quotes
+func fetchQuoteBatch(client *http.Client) ([]session.Quote, error) {
+	resp, err := client.Get(quoteBatchURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait := quoteRateLimitWait
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		return nil, errRateLimited{wait: wait}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("quote provider returned %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var responses []QuoteResponse
+	if err := json.Unmarshal(body, &responses); err != nil {
+		return nil, fmt.Errorf("failed to parse quotes: %w", err)
+	}
+
+	var quotes []session.Quote
+	for _, r := range responses {
+		if r.Q == "" || r.A == quoteProviderAuthor {
+			continue
+		}
+		quotes = append(quotes, session.Quote{Text: r.Q, Author: r.A})
+	}
+	if len(quotes) == 0 && len(responses) > 0 {
+		// The provider answers an exhausted quota with a single notice instead of a 429
+		return nil, errRateLimited{wait: quoteRateLimitWait}
+	}
+	return quotes, nil
+}
+
+// PrefetchQuotes downloads count new quotes into the offline cache, pacing requests to stay within
+// the provider's rate limit and backing off when asked to. progress, if set, is told after every request.
+// Quotes fetched before an error are kept; the number added is returned either way.
+func PrefetchQuotes(cfg *config.Config, count int, progress func(added int, status string)) (int, error) {
+	quotes, err := LoadCachedQuotes()
+	if err != nil {
+		return 0, err
+	}
+	seen := make(map[string]bool, len(quotes))
+	for _, q := range quotes {
+		seen[q.Text] = true
+	}
+
+	client := &http.Client{
+		Timeout: time.Duration(cfg.Network.TimeoutMs) * time.Millisecond,
+	}
+
+	added, failures, staleBatches := 0, 0, 0
+	var lastErr error
+	for added < count {
+		if failures >= quoteMaxRetries {
+			break
+		}
+
+		batch, err := fetchQuoteBatch(client)
+		if err != nil {
+			failures++
+			lastErr = err
+			wait := quoteRequestInterval
+			if limited, ok := err.(errRateLimited); ok {
+				wait = limited.wait
+			}
+			if progress != nil {
+				progress(added, err.Error())
+			}
+			time.Sleep(wait)
+			continue
+		}
+		failures = 0
+
+		before := added
+		for _, q := range batch {
+			if added == count {
+				break
+			}
+			if seen[q.Text] {
+				continue
+			}
+			seen[q.Text] = true
+			quotes = append(quotes, q)
+			added++
+		}
+		if progress != nil {
+			progress(added, "")
+		}
+
+		// Batches that bring nothing new mean the provider's pool is exhausted
+		if added == before {
+			staleBatches++
+			if staleBatches >= quoteMaxRetries {
+				break
+			}
+		}
+
+		if added < count {
+			time.Sleep(quoteRequestInterval)
+		}
+	}
+
+	if added > 0 {
+		if err := saveCachedQuotes(quotes); err != nil {
+			return 0, err
+		}
+	}
+	if added < count && lastErr != nil {
+		return added, fmt.Errorf("stopped after %d quotes: %w", added, lastErr)
+	}
+	return added, nil
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gti/src/internal"
+	"gti/src/internal/config"
+)
+
+// MaxPageSize caps how much of a web page is downloaded; pages carry far more markup than text
+const MaxPageSize = 4 * 1024 * 1024
+
+func pageCachePath(rawURL string) string {
+	h := fnv.New32a()
+	h.Write([]byte(rawURL))
+	return filepath.Join(config.CacheDir, "pages", fmt.Sprintf("%08x.txt", h.Sum32()))
+}
+
+// FetchWebPage downloads a web page and saves its readable text to the cache, one paragraph per line,
+// returning the file so it can be practised like any custom file. A page fetched before is reused.
+func FetchWebPage(cfg *config.Config, rawURL string) (string, error) {
+	if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
+		return "", fmt.Errorf("not an http(s) URL: %s", rawURL)
+	}
+
+	cachePath := pageCachePath(rawURL)
+	if _, err := os.Stat(cachePath); err == nil {
+		return cachePath, nil
+	}
+
+	body, err := download(cfg, rawURL, MaxPageSize)
+	if err != nil {
+		return "", err
+	}
+	text := internal.ExtractReadableText(string(body))
+	if text == "" {
+		return "", fmt.Errorf("found no readable text on %s", rawURL)
+	}
+
+	if err := config.EnsureDir(filepath.Dir(cachePath)); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(cachePath, []byte(text), 0644); err != nil {
+		return "", err
+	}
+	return cachePath, nil
+}
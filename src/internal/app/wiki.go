@@ -0,0 +1,128 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"gti/src/internal"
+	"gti/src/internal/config"
+)
+
+// wikiUserAgent identifies gti to Wikipedia, whose API policy asks every client to say who it is
+const wikiUserAgent = "gti (https://github.com/developic/gti-cli)"
+
+// minWikiWords is how long a random article's summary should be; stubs are passed over for another article
+const minWikiWords = 25
+
+// wikiLanguages maps word list languages to their Wikipedia editions
+var wikiLanguages = map[string]string{
+	"english": "en", "spanish": "es", "french": "fr", "german": "de", "japanese": "ja",
+	"russian": "ru", "italian": "it", "portuguese": "pt", "chinese": "zh", "arabic": "ar",
+	"hindi": "hi", "korean": "ko", "dutch": "nl", "swedish": "sv", "czech": "cs",
+	"danish": "da", "finnish": "fi", "greek": "el", "hebrew": "he", "hungarian": "hu",
+	"norwegian": "no", "polish": "pl", "thai": "th", "turkish": "tr",
+}
+
+// parenthetical is a bracketed aside, checked for pronunciations nobody can type
+var parenthetical = regexp.MustCompile(`\s*\([^()]*\)`)
+
+// WikiArticle is the summary of one Wikipedia article
+type WikiArticle struct {
+	Title   string `json:"title"`
+	Type    string `json:"type"`
+	Extract string `json:"extract"`
+}
+
+// FetchWikiArticle fetches the summary of the article on topic, or of a random article when topic is
+// empty, from the Wikipedia edition of language, the first of them when it mixes several
+func FetchWikiArticle(cfg *config.Config, topic, language string) (WikiArticle, error) {
+	edition, ok := wikiLanguages[internal.Languages(language)[0]]
+	if !ok {
+		edition = "en"
+	}
+	api := fmt.Sprintf("https://%s.wikipedia.org/api/rest_v1/page/", edition)
+	client := &http.Client{
+		Timeout: time.Duration(cfg.Network.TimeoutMs) * time.Millisecond,
+	}
+
+	if topic != "" {
+		article, err := getWikiSummary(client, api+"summary/"+url.PathEscape(strings.ReplaceAll(topic, " ", "_")))
+		if err != nil {
+			return article, err
+		}
+		if article.Type == "disambiguation" {
+			return article, fmt.Errorf("'%s' could mean several articles, try a more specific topic", topic)
+		}
+		return article, nil
+	}
+
+	var best WikiArticle
+	for range 5 {
+		article, err := getWikiSummary(client, api+"random/summary")
+		if err != nil {
+			return best, err
+		}
+		if article.Type == "disambiguation" {
+			continue
+		}
+		if len(strings.Fields(article.Extract)) > len(strings.Fields(best.Extract)) {
+			best = article
+		}
+		if len(strings.Fields(best.Extract)) >= minWikiWords {
+			break
+		}
+	}
+	if best.Extract == "" {
+		return best, fmt.Errorf("Wikipedia returned no article text")
+	}
+	return best, nil
+}
+
+func getWikiSummary(client *http.Client, rawURL string) (WikiArticle, error) {
+	var article WikiArticle
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return article, err
+	}
+	req.Header.Set("User-Agent", wikiUserAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return article, fmt.Errorf("could not reach Wikipedia: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return article, fmt.Errorf("Wikipedia has no article on that topic")
+	default:
+		return article, fmt.Errorf("Wikipedia answered %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return article, err
+	}
+	if err := json.Unmarshal(body, &article); err != nil {
+		return article, err
+	}
+	article.Extract = cleanWikiText(article.Extract)
+	return article, nil
+}
+
+// cleanWikiText makes a summary typeable, dropping the bracketed pronunciations that open many articles
+func cleanWikiText(text string) string {
+	text = parenthetical.ReplaceAllStringFunc(text, func(aside string) string {
+		// IPA letters, length and stress marks
+		if strings.IndexFunc(aside, func(r rune) bool { return r >= 0x0250 && r <= 0x02FF }) >= 0 {
+			return ""
+		}
+		return aside
+	})
+	return strings.Join(strings.Fields(internal.NormalizeText(text)), " ")
+}
@@ -0,0 +1,100 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// boardRound is one round of a keyboard drill: what to pay attention to, and words that exercise it
+type boardRound struct {
+	guidance string
+	words    []string
+}
+
+var boardAliases = map[string]string{
+	"ortholinear": "ortho",
+	"columnar":    "ortho",
+}
+
+// boardDrills hold the transitions that change most when moving off a row-staggered board
+var boardDrills = map[string][]boardRound{
+	"ortho": {
+		{
+			"Bottom row, left hand: c sits straight below d and v below f, so drop down instead of reaching diagonally",
+			[]string{"civic", "cave", "vex", "cozy", "exact", "vivid", "cycle", "excess", "voice", "cover", "convex", "deck", "fixed", "zeal", "crave", "vacant", "ox", "cozier"},
+		},
+		{
+			"b is a left index reach and n, m sit straight below j and k; keep each column on one finger",
+			[]string{"bomb", "number", "ember", "nimble", "bench", "combine", "symbol", "mumble", "banner", "member", "nab", "bump", "humble", "numb", "mob", "minimum"},
+		},
+		{
+			"Same-column jumps from top to bottom row: ec, ce, rv, un, my and ny travel straight up and down",
+			[]string{"decide", "curve", "fun", "any", "many", "grace", "receive", "mince", "brown", "nerve", "unmoved", "myth", "nylon", "secret", "serve", "funny"},
+		},
+		{
+			"Thumb keys: if Space, Enter or Backspace moved to your thumbs, use the same thumb every time in these short bursts",
+			[]string{"a", "I", "an", "to", "of", "in", "is", "it", "on", "be", "at", "we", "so", "up", "go", "no", "do", "me", "my", "by"},
+		},
+	},
+	"split": {
+		{
+			"The centre columns: t, g and b belong to the left hand, y, h and n to the right, however far the halves are apart",
+			[]string{"baby", "bulb", "toy", "why", "nothing", "begin", "tight", "hybrid", "gently", "bathing", "hungry", "tiny", "bygone", "ghost", "thing", "beyond"},
+		},
+		{
+			"Hand alternation: these words bounce between halves, so let each hand get ready while the other types",
+			[]string{"they", "right", "both", "when", "firm", "laugh", "visit", "handy", "rich", "auto", "formal", "blend", "sign", "profit", "element", "rhythm"},
+		},
+		{
+			"Inner-column rolls: tg, gb, yh and hn reach inward, where split gaps cause the most misses",
+			[]string{"night", "bright", "fight", "nymph", "hyphen", "rugby", "height", "brought", "sightly", "johnny", "highway", "nightly", "thought", "eighty", "tonight", "weight"},
+		},
+		{
+			"Thumb keys: if Space, Enter or Backspace moved to your thumbs, use the same thumb every time in these short bursts",
+			[]string{"a", "I", "an", "to", "of", "in", "is", "it", "on", "be", "at", "we", "so", "up", "go", "no", "do", "me", "my", "by"},
+		},
+	},
+}
+
+// ValidateBoard checks if the keyboard type has a drill and returns error if not
+func ValidateBoard(board string) error {
+	if _, ok := boardDrills[canonicalBoard(board)]; !ok {
+		return fmt.Errorf("unsupported board '%s'", board)
+	}
+	return nil
+}
+
+// GetSupportedBoards returns a list of keyboard types with drills
+func GetSupportedBoards() []string {
+	boards := make([]string, 0, len(boardDrills))
+	for board := range boardDrills {
+		boards = append(boards, board)
+	}
+	sort.Strings(boards)
+	return boards
+}
+
+func canonicalBoard(board string) string {
+	board = strings.ToLower(board)
+	if alias, ok := boardAliases[board]; ok {
+		return alias
+	}
+	return board
+}
+
+// GenerateBoardRounds returns the text of each round of the board's drill along with the guidance shown during it
+func GenerateBoardRounds(board string, words int) ([]string, []string) {
+	drill := boardDrills[canonicalBoard(board)]
+	rounds := make([]string, len(drill))
+	guidance := make([]string, len(drill))
+	for i, round := range drill {
+		picked := make([]string, words)
+		for j := range picked {
+			picked[j] = round.words[rng.Intn(len(round.words))]
+		}
+		rounds[i] = strings.Join(picked, " ")
+		guidance[i] = round.guidance
+	}
+	return rounds, guidance
+}
@@ -0,0 +1,62 @@
+// Package book reads long texts for gti book, plain text, Markdown or EPUB, as a list of paragraphs,
+// and keeps a bookmark per file so reading resumes where it stopped.
+package book
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gti/src/internal"
+)
+
+var paragraphBreak = regexp.MustCompile(`\n[ \t]*\n`)
+
+// Load reads the paragraphs of a book file
+func Load(file string) ([]string, error) {
+	if file == internal.StdinFile {
+		return nil, fmt.Errorf("a book needs a file to keep its bookmark in, not standard input")
+	}
+
+	var text string
+	if strings.EqualFold(filepath.Ext(file), ".epub") {
+		var err error
+		if text, err = readEPUB(file); err != nil {
+			return nil, fmt.Errorf("could not read %s: %w", file, err)
+		}
+	} else {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		text = string(data)
+		if internal.IsMarkdownFile(file) {
+			text = internal.StripMarkdown(text)
+		}
+	}
+
+	paragraphs := Paragraphs(internal.NormalizeText(text))
+	if len(paragraphs) == 0 {
+		return nil, fmt.Errorf("%s has no text to practice", file)
+	}
+	return paragraphs, nil
+}
+
+// Paragraphs splits text at blank lines, joining the hard-wrapped lines of each paragraph the way
+// plain-text books are written. Text without any blank line has a paragraph on every line instead.
+func Paragraphs(text string) []string {
+	blocks := paragraphBreak.Split(text, -1)
+	if len(blocks) == 1 {
+		blocks = strings.Split(text, "\n")
+	}
+
+	var paragraphs []string
+	for _, block := range blocks {
+		if paragraph := strings.Join(strings.Fields(block), " "); paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	return paragraphs
+}
@@ -0,0 +1,85 @@
+package book
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gti/src/internal/config"
+)
+
+// Bookmark is how far through a book reading has got
+type Bookmark struct {
+	// Paragraph is the next paragraph to type, counting from 0; it equals Paragraphs once the book is finished
+	Paragraph  int       `json:"paragraph"`
+	Paragraphs int       `json:"paragraphs"`
+	Updated    time.Time `json:"updated"`
+}
+
+// Percent is how much of the book has been typed
+func (b Bookmark) Percent() float64 {
+	if b.Paragraphs == 0 {
+		return 0
+	}
+	return float64(b.Paragraph) / float64(b.Paragraphs) * 100
+}
+
+// Finished reports whether every paragraph has been typed
+func (b Bookmark) Finished() bool {
+	return b.Paragraphs > 0 && b.Paragraph >= b.Paragraphs
+}
+
+func bookmarksFile() string {
+	return filepath.Join(config.ConfigDir, "bookmarks.json")
+}
+
+// bookKey names a book by its absolute path, so it is found again from any directory
+func bookKey(file string) string {
+	if abs, err := filepath.Abs(file); err == nil {
+		return abs
+	}
+	return file
+}
+
+func loadBookmarks() map[string]Bookmark {
+	bookmarks := make(map[string]Bookmark)
+	if _, err := os.Stat(bookmarksFile()); err == nil {
+		config.LoadJSONData(bookmarksFile(), &bookmarks)
+	}
+	return bookmarks
+}
+
+// LoadBookmark returns the bookmark kept for file, if it has been read before
+func LoadBookmark(file string) (Bookmark, bool) {
+	b, ok := loadBookmarks()[bookKey(file)]
+	return b, ok
+}
+
+// SaveBookmark records that the book in file continues at paragraph next of total
+func SaveBookmark(file string, next, total int) error {
+	bookmarks := loadBookmarks()
+	bookmarks[bookKey(file)] = Bookmark{Paragraph: next, Paragraphs: total, Updated: time.Now()}
+	if err := config.EnsureDir(config.ConfigDir); err != nil {
+		return err
+	}
+	return config.SaveJSONData(bookmarksFile(), bookmarks)
+}
+
+// Shelved is a book with a bookmark
+type Shelved struct {
+	File string
+	Bookmark
+}
+
+// Shelf is every book with a bookmark, most recently read first
+func Shelf() []Shelved {
+	var shelf []Shelved
+	for file, b := range loadBookmarks() {
+		shelf = append(shelf, Shelved{File: file, Bookmark: b})
+	}
+	sort.Slice(shelf, func(i, j int) bool {
+		return shelf[i].Updated.After(shelf[j].Updated)
+	})
+	return shelf
+}
@@ -0,0 +1,109 @@
+package book
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"gti/src/internal"
+)
+
+// container is META-INF/container.xml, which says where the package document is
+type container struct {
+	Rootfiles []struct {
+		FullPath string `xml:"full-path,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+// packageDocument is the part of the OPF package document that lists the chapters in reading order
+type packageDocument struct {
+	Manifest []struct {
+		ID   string `xml:"id,attr"`
+		Href string `xml:"href,attr"`
+	} `xml:"manifest>item"`
+	Spine []struct {
+		IDRef  string `xml:"idref,attr"`
+		Linear string `xml:"linear,attr"`
+	} `xml:"spine>itemref"`
+}
+
+// readEPUB returns the text of an EPUB's chapters in reading order, skipping the ones marked as
+// outside it such as footnote pages
+func readEPUB(file string) (string, error) {
+	r, err := zip.OpenReader(file)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	files := make(map[string]*zip.File, len(r.File))
+	for _, f := range r.File {
+		files[f.Name] = f
+	}
+
+	var c container
+	if err := decodeXML(files, "META-INF/container.xml", &c); err != nil {
+		return "", err
+	}
+	if len(c.Rootfiles) == 0 {
+		return "", fmt.Errorf("no package document in META-INF/container.xml")
+	}
+	opf := c.Rootfiles[0].FullPath
+	var pkg packageDocument
+	if err := decodeXML(files, opf, &pkg); err != nil {
+		return "", err
+	}
+
+	hrefs := make(map[string]string, len(pkg.Manifest))
+	for _, item := range pkg.Manifest {
+		hrefs[item.ID] = item.Href
+	}
+	var chapters []string
+	for _, ref := range pkg.Spine {
+		href, ok := hrefs[ref.IDRef]
+		if !ok || ref.Linear == "no" {
+			continue
+		}
+		// Manifest paths are URLs relative to the package document
+		if unescaped, err := url.PathUnescape(href); err == nil {
+			href = unescaped
+		}
+		page, err := readZipFile(files, path.Join(path.Dir(opf), href))
+		if err != nil {
+			return "", err
+		}
+		if text := internal.ExtractBookText(page); text != "" {
+			chapters = append(chapters, text)
+		}
+	}
+	return strings.Join(chapters, "\n\n"), nil
+}
+
+func readZipFile(files map[string]*zip.File, name string) (string, error) {
+	f, ok := files[name]
+	if !ok {
+		return "", fmt.Errorf("%s is missing from the book", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	return string(data), err
+}
+
+func decodeXML(files map[string]*zip.File, name string, v interface{}) error {
+	data, err := readZipFile(files, name)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal([]byte(data), v); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// Package card renders a session's result as a small bordered card to share, as text or as a PNG image
+package card
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gti/src/internal/config"
+	"gti/src/internal/session"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Format is what a card is saved as
+type Format int
+
+const (
+	FormatText Format = iota
+	FormatANSI
+	FormatPNG
+)
+
+// FormatFor picks the format from a file's extension: .png, .ans or .ansi, and plain text otherwise
+func FormatFor(file string) Format {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".png":
+		return FormatPNG
+	case ".ans", ".ansi":
+		return FormatANSI
+	}
+	return FormatText
+}
+
+// line is one line of a card and the theme color it is drawn in; the big one stands out
+type line struct {
+	text  string
+	color string
+	big   bool
+}
+
+// lines lays out the card: the speed large as text allows, then accuracy, what was typed and when
+func lines(r *session.SessionRecord, colors config.ThemeColorsConfig) []line {
+	mode := r.Mode
+	// The PNG's bitmap font has no middle dot, so the parts are kept to ASCII
+	if duration := (time.Duration(r.DurationMs) * time.Millisecond).Round(time.Second); duration > 0 {
+		if duration%time.Minute == 0 {
+			mode += fmt.Sprintf(", %dm", int(duration.Minutes()))
+		} else {
+			mode += ", " + duration.String()
+		}
+	}
+	if r.Partial {
+		mode += " (partial)"
+	}
+	when := r.Timestamp
+	if when.IsZero() {
+		when = time.Now()
+	}
+	return []line{
+		{text: "gti typing test", color: colors.Accent},
+		{},
+		{text: fmt.Sprintf("%.0f WPM", r.WPM), color: colors.Correct, big: true},
+		{text: fmt.Sprintf("%.1f%% accuracy", r.Accuracy), color: colors.TextPrimary},
+		{},
+		{text: mode, color: colors.TextSecondary},
+		{text: when.Local().Format("2 Jan 2006"), color: colors.TextSecondary},
+	}
+}
+
+// Text renders the card in a rounded border, in the theme's colors when ansi is set
+func Text(r *session.SessionRecord, colors config.ThemeColorsConfig, ansi bool) string {
+	renderer := lipgloss.NewRenderer(io.Discard)
+	renderer.SetColorProfile(termenv.Ascii)
+	if ansi {
+		renderer.SetColorProfile(termenv.TrueColor)
+	}
+
+	var body []string
+	for _, l := range lines(r, colors) {
+		style := renderer.NewStyle().Foreground(lipgloss.Color(l.color))
+		if l.big {
+			style = style.Bold(true)
+		}
+		body = append(body, style.Render(l.text))
+	}
+	return renderer.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(colors.Border)).
+		Padding(1, 4).
+		Align(lipgloss.Center).
+		Render(strings.Join(body, "\n")) + "\n"
+}
+
+// Save writes the card to file in the format its extension asks for
+func Save(file string, r *session.SessionRecord, colors config.ThemeColorsConfig) error {
+	if dir := filepath.Dir(file); dir != "." {
+		if err := config.EnsureDir(dir); err != nil {
+			return err
+		}
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch FormatFor(file) {
+	case FormatPNG:
+		err = WritePNG(f, r, colors)
+	case FormatANSI:
+		_, err = io.WriteString(f, Text(r, colors, true))
+	default:
+		_, err = io.WriteString(f, Text(r, colors, false))
+	}
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// DefaultFile is where a card is saved when no file is given: a timestamped PNG in the Downloads
+// folder, beside the statistics exports
+func DefaultFile() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	name := fmt.Sprintf("gti_card_%s.png", time.Now().Format("2006-01-02_15-04-05"))
+	return filepath.Join(homeDir, "Downloads", name)
+}
@@ -0,0 +1,115 @@
+package card
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"gti/src/internal/config"
+	"gti/src/internal/session"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// pixelScale blows the 7x13 bitmap font up to a size that reads well once shared
+	pixelScale = 4
+	// speedScale draws the speed line twice as large again
+	speedScale = 2
+	// cardPadding is the space inside the border, in font pixels
+	cardPadding = 16
+)
+
+// WritePNG draws the card in the theme's colors as a PNG image
+func WritePNG(w io.Writer, r *session.SessionRecord, colors config.ThemeColorsConfig) error {
+	face := basicfont.Face7x13
+	cardLines := lines(r, colors)
+
+	width, height := 0, 0
+	for _, l := range cardLines {
+		scale := lineScale(l)
+		width = max(width, len([]rune(l.text))*face.Advance*scale)
+		height += face.Height * scale
+	}
+	width += 2 * cardPadding
+	height += 2 * cardPadding
+
+	img := image.NewRGBA(image.Rect(0, 0, width*pixelScale, height*pixelScale))
+	draw.Draw(img, img.Bounds(), &image.Uniform{parseColor(colors.Background)}, image.Point{}, draw.Src)
+	drawBorder(img, parseColor(colors.Border))
+
+	y := cardPadding
+	for _, l := range cardLines {
+		scale := lineScale(l)
+		if l.text != "" {
+			glyphs := renderLine(face, l.text)
+			x := (width - glyphs.Bounds().Dx()*scale) / 2
+			blit(img, glyphs, x*pixelScale, y*pixelScale, scale*pixelScale, parseColor(l.color))
+		}
+		y += face.Height * scale
+	}
+	return png.Encode(w, img)
+}
+
+func lineScale(l line) int {
+	if l.big {
+		return speedScale
+	}
+	return 1
+}
+
+// renderLine draws text in the bitmap font at its own size, as a mask
+func renderLine(face *basicfont.Face, text string) *image.Alpha {
+	mask := image.NewAlpha(image.Rect(0, 0, len([]rune(text))*face.Advance, face.Height))
+	d := font.Drawer{
+		Dst:  mask,
+		Src:  image.Opaque,
+		Face: face,
+		Dot:  fixed.P(0, face.Ascent),
+	}
+	d.DrawString(text)
+	return mask
+}
+
+// blit paints the set pixels of mask onto img at (x, y), each blown up to a square of scale pixels
+func blit(img *image.RGBA, mask *image.Alpha, x, y, scale int, c color.Color) {
+	bounds := mask.Bounds()
+	for my := bounds.Min.Y; my < bounds.Max.Y; my++ {
+		for mx := bounds.Min.X; mx < bounds.Max.X; mx++ {
+			if mask.AlphaAt(mx, my).A == 0 {
+				continue
+			}
+			square := image.Rect(x+mx*scale, y+my*scale, x+(mx+1)*scale, y+(my+1)*scale)
+			draw.Draw(img, square, &image.Uniform{c}, image.Point{}, draw.Src)
+		}
+	}
+}
+
+// drawBorder frames the card a little inside its edge, in the same weight as the font's strokes
+func drawBorder(img *image.RGBA, c color.Color) {
+	inset, thickness := cardPadding/2*pixelScale, pixelScale
+	b := img.Bounds().Inset(inset)
+	edges := []image.Rectangle{
+		image.Rect(b.Min.X, b.Min.Y, b.Max.X, b.Min.Y+thickness),
+		image.Rect(b.Min.X, b.Max.Y-thickness, b.Max.X, b.Max.Y),
+		image.Rect(b.Min.X, b.Min.Y, b.Min.X+thickness, b.Max.Y),
+		image.Rect(b.Max.X-thickness, b.Min.Y, b.Max.X, b.Max.Y),
+	}
+	for _, edge := range edges {
+		draw.Draw(img, edge, &image.Uniform{c}, image.Point{}, draw.Src)
+	}
+}
+
+// parseColor reads a #rrggbb theme color, falling back to white for anything else
+func parseColor(hex string) color.RGBA {
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(hex, "#%02x%02x%02x", &r, &g, &b); err != nil {
+		return color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	}
+	return color.RGBA{r, g, b, 0xFF}
+}
@@ -8,6 +8,7 @@ import (
 
 	"gti/src/internal"
 	"gti/src/internal/config"
+	"gti/src/internal/keymap"
 	"gti/src/internal/session"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -63,6 +64,7 @@ type BossResult struct {
 
 type GameModel struct {
 	config  *config.Config
+	keys    *keymap.Bindings
 	state   *GameState
 	sess    *session.Session
 	width   int
@@ -91,6 +93,7 @@ func NewGameModel(cfg *config.Config, levels []Level) GameModel {
 
 	model := GameModel{
 		config: cfg,
+		keys:   keymap.NewBindings(cfg.Keybindings),
 		state:  state,
 		sess:   sess,
 	}
@@ -179,7 +182,7 @@ Boss Rounds: %d/%d completed`,
 	)
 
 	if m.state.CurrentLevel < len(m.state.Levels)-1 {
-		content += "\n\nPress Enter to continue to next level..."
+		content += fmt.Sprintf("\n\nPress %s to continue to next level...", m.keys.Label(keymap.ActionNext))
 	} else {
 		content += "\n\nCongratulations! All levels completed!"
 	}
@@ -202,13 +205,14 @@ Words Typed: %d (Required: %d)
 
 Requirements not met. Try again!
 
-Press R to retry this level
-Press Q to quit`,
+Press %s to retry this level
+Press %s to quit`,
 		m.state.CurrentLevel+1,
 		m.calculateAccuracy(), requirements.MinAccuracy,
 		m.state.Mistakes, requirements.MaxMistakes,
 		m.state.TotalChars, level.MinChars,
 		m.state.WordsTyped, requirements.MinWords,
+		m.keys.Label(keymap.ActionRetry), m.keys.Label(keymap.ActionQuit),
 	)
 
 	return m.renderLevelDialog(content, "red")
@@ -218,7 +222,9 @@ func (m GameModel) viewHelp() string {
 	helpText := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(m.config.Theme.Colors.TextPrimary)).
 		Background(lipgloss.Color(m.config.Theme.Colors.Background)).
-		Render("Help overlay - Press ESC to close\n\nShortcuts:\nCtrl+Q: Quit confirmation\nCtrl+C: Force quit\nEsc: Restart level\nCtrl+H: Help\nBackspace: Delete\nLeft/Right: Navigate segments\n\nChallenge Mode:\nComplete levels with increasing difficulty\nEnter: Continue to next level\nR: Retry failed level")
+		Render(fmt.Sprintf("Help overlay - Press %s to close\n\nShortcuts:\n%s: Quit confirmation\n%s: Force quit\n%s: Restart level\n%s: Help\nBackspace: Delete\nLeft/Right: Navigate segments\n\nChallenge Mode:\nComplete levels with increasing difficulty\n%s: Continue to next level\n%s: Retry failed level",
+			m.keys.Label(keymap.ActionBack), m.keys.Label(keymap.ActionQuit), m.keys.Label(keymap.ActionForceQuit), m.keys.Label(keymap.ActionRestart),
+			m.keys.Label(keymap.ActionHelp), m.keys.Label(keymap.ActionNext), m.keys.Label(keymap.ActionRetry)))
 
 	return m.renderDialogBox(helpText, 2, 1, m.config.Theme.Colors.TextPrimary, true)
 }
@@ -237,7 +243,7 @@ func (m GameModel) viewQuit() string {
 func (m *GameModel) handleKey(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch m.mode {
 	case "help":
-		if key.String() == "esc" {
+		if m.keys.Is(key, keymap.ActionBack) {
 			m.mode = ""
 		}
 		return m, nil
@@ -248,27 +254,27 @@ func (m *GameModel) handleKey(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.mode = ""
 		return m, nil
 	default:
-		switch key.String() {
-		case "ctrl+c":
+		switch {
+		case m.keys.Is(key, keymap.ActionForceQuit):
 			return m, tea.Quit
-		case "ctrl+q":
+		case m.keys.Is(key, keymap.ActionQuit):
 			m.mode = "quit"
 			return m, nil
-		case "ctrl+h":
+		case m.keys.Is(key, keymap.ActionHelp):
 			m.mode = "help"
 			return m, nil
-		case "esc":
+		case m.keys.Is(key, keymap.ActionRestart):
 			return m.retryLevel()
 		}
 
 		switch m.state.Phase {
 		case "complete":
-			if key.String() == "enter" {
+			if m.keys.Is(key, keymap.ActionNext) {
 				return m.advanceLevel()
 			}
 			return m, nil
 		case "failed":
-			if key.String() == "r" {
+			if m.keys.Is(key, keymap.ActionRetry) {
 				return m.retryLevel()
 			}
 			return m, nil
@@ -284,8 +290,7 @@ func (m *GameModel) handleSessionComplete() (tea.Model, tea.Cmd) {
 
 	wordsInChunk := strings.Fields(m.sess.GetText())
 	m.state.WordsTyped += len(wordsInChunk)
-	m.state.Mistakes += m.sess.GetMistakes()
-	m.state.TotalChars += len(m.sess.TypedText())
+	m.syncTotals()
 
 	if m.state.Phase == "boss" {
 		var bossName string
@@ -326,6 +331,7 @@ func (m *GameModel) handleTick() (tea.Model, tea.Cmd) {
 	m.state.TimeLeft--
 	m.sess.RemainingTimeDisplay = m.state.TimeLeft
 	if m.state.TimeLeft <= 0 {
+		m.syncTotals()
 		level := m.state.Levels[m.state.CurrentLevel]
 		if level.BossRound != nil {
 			result := m.createBossResult(level.BossRound.Name, false)
@@ -348,23 +354,13 @@ func (m *GameModel) generateNextChunk() {
 	level := m.state.Levels[m.state.CurrentLevel]
 	chunkText := internal.GenerateWordsDynamic(level.ChunkSize, m.config.Language.Default)
 	m.sess.SetText(chunkText)
-	m.sess.ExternalMistakes = m.state.Mistakes
-	m.sess.Start()
+	m.sess.Resume()
 }
 
 func (m *GameModel) advanceLevel() (tea.Model, tea.Cmd) {
 	UpdateProgress(m.config, m.state.CurrentLevel)
 
-	record := &session.SessionRecord{
-		Mode:       "challenge",
-		Tier:       fmt.Sprintf("lv%d", m.state.CurrentLevel+1),
-		TextLength: len(m.sess.GetText()),
-		DurationMs: time.Since(m.state.StartTime).Milliseconds(),
-		WPM:        m.calculateWPM(),
-		CPM:        float64(m.state.WordsTyped) / time.Since(m.state.StartTime).Minutes() * 5,
-		Accuracy:   m.calculateAccuracy(),
-		Mistakes:   m.state.Mistakes,
-	}
+	record := session.NewResultsCalculator().BuildRecord(m.sess)
 	session.SaveSessionRecord(m.config, record)
 
 	m.state.CurrentLevel++
@@ -378,13 +374,24 @@ func (m *GameModel) advanceLevel() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// results summarizes the level so far through the shared session results pipeline
+func (m GameModel) results() session.Results {
+	return session.NewResultsCalculator().CalculateResults(m.sess)
+}
+
+// syncTotals copies the session's accumulated totals into the level state
+func (m *GameModel) syncTotals() {
+	results := m.results()
+	m.state.Mistakes = results.Mistakes
+	m.state.TotalChars = results.TotalChars
+}
+
 func (m GameModel) calculateWPM() float64 {
-	levelDuration := time.Since(m.state.LevelStartTime)
-	return session.CalculateWPM(m.state.TotalChars, levelDuration)
+	return m.results().WPM
 }
 
 func (m GameModel) calculateAccuracy() float64 {
-	return session.CalculateAccuracy(m.state.TotalChars, m.state.Mistakes)
+	return m.results().Accuracy
 }
 
 func (m GameModel) countCompletedBosses() int {
@@ -411,7 +418,7 @@ func (m *GameModel) startHiddenBossRound(boss BossRound) {
 	m.sess.SetText(bossText)
 	m.state.Phase = "boss"
 	m.state.TimeLeft = boss.TimeLimit
-	m.sess.Start()
+	m.sess.Resume()
 }
 
 func (m GameModel) tickTimer() tea.Cmd {
@@ -488,7 +495,7 @@ func (m *GameModel) resetLevelState(level Level) {
 		text = internal.GenerateWordsDynamic(level.ChunkSize, m.config.Language.Default)
 	}
 	m.sess.SetText(text)
-	m.sess.ExternalMistakes = m.state.Mistakes
+	m.sess.ResetTotals()
 	m.sess.SetTier(fmt.Sprintf("lv%d", m.state.CurrentLevel+1))
 	m.sess.Start()
 }
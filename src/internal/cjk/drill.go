@@ -0,0 +1,51 @@
+package cjk
+
+import "strings"
+
+// Unit is the smallest scored piece of a drill: one kana or one jamo and the keys that type it
+type Unit struct {
+	Glyph   string
+	Input   string
+	Cluster int
+}
+
+// Drill is a run of text shown as glyphs but typed as the ASCII input of its units.
+// Clusters are what gets displayed; a kana is its own cluster, a Hangul syllable groups its jamo.
+type Drill struct {
+	Name     string
+	UnitName string
+	Units    []Unit
+	Clusters []string
+	// OptionalTones lets the typist skip the tone numbers in the input, as pinyin is often typed without them
+	OptionalTones bool
+}
+
+// Text returns the keys that type the whole drill, which is what the session compares against
+func (d *Drill) Text() string {
+	var b strings.Builder
+	for _, u := range d.Units {
+		b.WriteString(u.Input)
+	}
+	return b.String()
+}
+
+// UnitStarts returns the byte offset in Text at which each unit begins
+func (d *Drill) UnitStarts() []int {
+	starts := make([]int, len(d.Units))
+	offset := 0
+	for i, u := range d.Units {
+		starts[i] = offset
+		offset += len(u.Input)
+	}
+	return starts
+}
+
+func (d *Drill) addSpace() {
+	d.Clusters = append(d.Clusters, " ")
+	d.Units = append(d.Units, Unit{Glyph: " ", Input: " ", Cluster: len(d.Clusters) - 1})
+}
+
+// IsSpace reports whether the unit separates words rather than being scored as a glyph
+func (u Unit) IsSpace() bool {
+	return u.Input == " "
+}
@@ -0,0 +1,64 @@
+package cjk
+
+import "gti/src/internal"
+
+const hangulBase = 0xAC00
+
+// Jamo in Unicode composition order, each with its compatibility glyph and dubeolsik (standard 2-set) keys
+var choseong = []struct{ jamo, keys string }{
+	{"ㄱ", "r"}, {"ㄲ", "R"}, {"ㄴ", "s"}, {"ㄷ", "e"}, {"ㄸ", "E"}, {"ㄹ", "f"}, {"ㅁ", "a"},
+	{"ㅂ", "q"}, {"ㅃ", "Q"}, {"ㅅ", "t"}, {"ㅆ", "T"}, {"ㅇ", "d"}, {"ㅈ", "w"}, {"ㅉ", "W"},
+	{"ㅊ", "c"}, {"ㅋ", "z"}, {"ㅌ", "x"}, {"ㅍ", "v"}, {"ㅎ", "g"},
+}
+
+var jungseong = []struct{ jamo, keys string }{
+	{"ㅏ", "k"}, {"ㅐ", "o"}, {"ㅑ", "i"}, {"ㅒ", "O"}, {"ㅓ", "j"}, {"ㅔ", "p"}, {"ㅕ", "u"},
+	{"ㅖ", "P"}, {"ㅗ", "h"}, {"ㅘ", "hk"}, {"ㅙ", "ho"}, {"ㅚ", "hl"}, {"ㅛ", "y"}, {"ㅜ", "n"},
+	{"ㅝ", "nj"}, {"ㅞ", "np"}, {"ㅟ", "nl"}, {"ㅠ", "b"}, {"ㅡ", "m"}, {"ㅢ", "ml"}, {"ㅣ", "l"},
+}
+
+// jongseong starts at index 1; index 0 means the syllable has no final consonant
+var jongseong = []struct{ jamo, keys string }{
+	{"", ""},
+	{"ㄱ", "r"}, {"ㄲ", "R"}, {"ㄳ", "rt"}, {"ㄴ", "s"}, {"ㄵ", "sw"}, {"ㄶ", "sg"}, {"ㄷ", "e"},
+	{"ㄹ", "f"}, {"ㄺ", "fr"}, {"ㄻ", "fa"}, {"ㄼ", "fq"}, {"ㄽ", "ft"}, {"ㄾ", "fx"}, {"ㄿ", "fv"},
+	{"ㅀ", "fg"}, {"ㅁ", "a"}, {"ㅂ", "q"}, {"ㅄ", "qt"}, {"ㅅ", "t"}, {"ㅆ", "T"}, {"ㅇ", "d"},
+	{"ㅈ", "w"}, {"ㅊ", "c"}, {"ㅋ", "z"}, {"ㅌ", "x"}, {"ㅍ", "v"}, {"ㅎ", "g"},
+}
+
+// Finals common enough to appear in generated syllables
+var commonJongseong = []int{1, 4, 8, 16, 17, 21}
+
+// composeSyllable returns the precomposed Hangul syllable for the given jamo indices
+func composeSyllable(initial, medial, final int) string {
+	return string(rune(hangulBase + (initial*len(jungseong)+medial)*len(jongseong) + final))
+}
+
+// GenerateHangul builds a drill of pseudo-words made of random syllables, scored per jamo
+func GenerateHangul(words int) *Drill {
+	drill := &Drill{Name: "hangul", UnitName: "jamo"}
+	for w := 0; w < words; w++ {
+		if w > 0 {
+			drill.addSpace()
+		}
+		syllables := internal.Intn(3) + 1
+		for k := 0; k < syllables; k++ {
+			initial := internal.Intn(len(choseong))
+			medial := internal.Intn(len(jungseong))
+			final := 0
+			if internal.Intn(10) < 3 {
+				final = commonJongseong[internal.Intn(len(commonJongseong))]
+			}
+
+			drill.Clusters = append(drill.Clusters, composeSyllable(initial, medial, final))
+			cluster := len(drill.Clusters) - 1
+			drill.Units = append(drill.Units,
+				Unit{Glyph: choseong[initial].jamo, Input: choseong[initial].keys, Cluster: cluster},
+				Unit{Glyph: jungseong[medial].jamo, Input: jungseong[medial].keys, Cluster: cluster})
+			if final > 0 {
+				drill.Units = append(drill.Units, Unit{Glyph: jongseong[final].jamo, Input: jongseong[final].keys, Cluster: cluster})
+			}
+		}
+	}
+	return drill
+}
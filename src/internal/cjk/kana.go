@@ -0,0 +1,78 @@
+package cjk
+
+import (
+	"fmt"
+
+	"gti/src/internal"
+)
+
+// hiragana lists each kana with its Hepburn romaji
+var hiragana = []struct{ kana, romaji string }{
+	{"あ", "a"}, {"い", "i"}, {"う", "u"}, {"え", "e"}, {"お", "o"},
+	{"か", "ka"}, {"き", "ki"}, {"く", "ku"}, {"け", "ke"}, {"こ", "ko"},
+	{"さ", "sa"}, {"し", "shi"}, {"す", "su"}, {"せ", "se"}, {"そ", "so"},
+	{"た", "ta"}, {"ち", "chi"}, {"つ", "tsu"}, {"て", "te"}, {"と", "to"},
+	{"な", "na"}, {"に", "ni"}, {"ぬ", "nu"}, {"ね", "ne"}, {"の", "no"},
+	{"は", "ha"}, {"ひ", "hi"}, {"ふ", "fu"}, {"へ", "he"}, {"ほ", "ho"},
+	{"ま", "ma"}, {"み", "mi"}, {"む", "mu"}, {"め", "me"}, {"も", "mo"},
+	{"や", "ya"}, {"ゆ", "yu"}, {"よ", "yo"},
+	{"ら", "ra"}, {"り", "ri"}, {"る", "ru"}, {"れ", "re"}, {"ろ", "ro"},
+	{"わ", "wa"}, {"を", "wo"}, {"ん", "n"},
+	{"が", "ga"}, {"ぎ", "gi"}, {"ぐ", "gu"}, {"げ", "ge"}, {"ご", "go"},
+	{"ざ", "za"}, {"じ", "ji"}, {"ず", "zu"}, {"ぜ", "ze"}, {"ぞ", "zo"},
+	{"だ", "da"}, {"で", "de"}, {"ど", "do"},
+	{"ば", "ba"}, {"び", "bi"}, {"ぶ", "bu"}, {"べ", "be"}, {"ぼ", "bo"},
+	{"ぱ", "pa"}, {"ぴ", "pi"}, {"ぷ", "pu"}, {"ぺ", "pe"}, {"ぽ", "po"},
+	{"きゃ", "kya"}, {"きゅ", "kyu"}, {"きょ", "kyo"},
+	{"しゃ", "sha"}, {"しゅ", "shu"}, {"しょ", "sho"},
+	{"ちゃ", "cha"}, {"ちゅ", "chu"}, {"ちょ", "cho"},
+	{"にゃ", "nya"}, {"ひゃ", "hya"}, {"みゃ", "mya"}, {"りゃ", "rya"},
+	{"ぎゃ", "gya"}, {"じゃ", "ja"}, {"じゅ", "ju"}, {"じょ", "jo"},
+}
+
+var kanaScripts = map[string]bool{
+	"hiragana": true,
+	"katakana": true,
+	"mixed":    true,
+}
+
+// toKatakana shifts hiragana into the katakana block, which mirrors it 0x60 code points later
+func toKatakana(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if r >= 0x3041 && r <= 0x3096 {
+			runes[i] = r + 0x60
+		}
+	}
+	return string(runes)
+}
+
+// ValidateKanaScript checks the kana script is supported and returns error if not
+func ValidateKanaScript(script string) error {
+	if !kanaScripts[script] {
+		return fmt.Errorf("unsupported kana script '%s' (supported: hiragana, katakana, mixed)", script)
+	}
+	return nil
+}
+
+// GenerateKana builds a drill of pseudo-words made of random kana, typed in romaji
+func GenerateKana(script string, words int) *Drill {
+	drill := &Drill{Name: script, UnitName: "kana"}
+	for w := 0; w < words; w++ {
+		if w > 0 {
+			drill.addSpace()
+		}
+		katakana := script == "katakana" || (script == "mixed" && internal.Intn(2) == 0)
+		length := internal.Intn(3) + 2
+		for k := 0; k < length; k++ {
+			entry := hiragana[internal.Intn(len(hiragana))]
+			glyph := entry.kana
+			if katakana {
+				glyph = toKatakana(glyph)
+			}
+			drill.Clusters = append(drill.Clusters, glyph)
+			drill.Units = append(drill.Units, Unit{Glyph: glyph, Input: entry.romaji, Cluster: len(drill.Clusters) - 1})
+		}
+	}
+	return drill
+}
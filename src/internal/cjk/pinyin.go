@@ -0,0 +1,35 @@
+package cjk
+
+// IsToneNumber reports whether the key is a pinyin tone number
+func IsToneNumber(key byte) bool {
+	return key >= '1' && key <= '5'
+}
+
+// GeneratePinyin builds a drill from Chinese words, one unit per character typed as its numbered pinyin.
+// Characters missing from the pinyin table are left out.
+func GeneratePinyin(words []string) *Drill {
+	drill := &Drill{Name: "pinyin", UnitName: "character", OptionalTones: true}
+	for _, word := range words {
+		var units []Unit
+		for _, r := range word {
+			reading, ok := pinyinTable[string(r)]
+			if !ok {
+				continue
+			}
+			units = append(units, Unit{Glyph: string(r), Input: reading})
+		}
+		if len(units) == 0 {
+			continue
+		}
+
+		if len(drill.Units) > 0 {
+			drill.addSpace()
+		}
+		for _, u := range units {
+			drill.Clusters = append(drill.Clusters, u.Glyph)
+			u.Cluster = len(drill.Clusters) - 1
+			drill.Units = append(drill.Units, u)
+		}
+	}
+	return drill
+}
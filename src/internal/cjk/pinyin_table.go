@@ -0,0 +1,45 @@
+package cjk
+
+// pinyinTable gives the everyday reading of each character in the bundled Chinese word list,
+// in numbered pinyin; neutral-tone readings carry no number
+var pinyinTable = map[string]string{
+	"一": "yi1", "万": "wan4", "三": "san1", "上": "shang4", "下": "xia4", "不": "bu4", "与": "yu3", "世": "shi4",
+	"业": "ye4", "东": "dong1", "两": "liang3", "个": "ge4", "中": "zhong1", "为": "wei4", "主": "zhu3", "之": "zhi1",
+	"也": "ye3", "书": "shu1", "了": "le", "事": "shi4", "二": "er4", "于": "yu2", "些": "xie1", "交": "jiao1",
+	"产": "chan3", "人": "ren2", "什": "shen2", "从": "cong2", "他": "ta1", "代": "dai4", "以": "yi3", "们": "men",
+	"件": "jian4", "任": "ren4", "会": "hui4", "但": "dan4", "位": "wei4", "体": "ti3", "作": "zuo4", "你": "ni3",
+	"使": "shi3", "信": "xin4", "做": "zuo4", "像": "xiang4", "儿": "er2", "先": "xian1", "全": "quan2", "公": "gong1",
+	"关": "guan1", "其": "qi2", "具": "ju4", "养": "yang3", "内": "nei4", "军": "jun1", "决": "jue2", "准": "zhun3",
+	"几": "ji3", "出": "chu1", "分": "fen1", "利": "li4", "到": "dao4", "制": "zhi4", "前": "qian2", "力": "li4",
+	"加": "jia1", "动": "dong4", "化": "hua4", "十": "shi2", "南": "nan2", "却": "que4", "原": "yuan2", "去": "qu4",
+	"又": "you4", "及": "ji2", "反": "fan3", "发": "fa1", "另": "ling4", "只": "zhi3", "可": "ke3", "各": "ge4",
+	"合": "he2", "同": "tong2", "名": "ming2", "后": "hou4", "向": "xiang4", "听": "ting1", "员": "yuan2", "和": "he2",
+	"商": "shang1", "回": "hui2", "因": "yin1", "团": "tuan2", "国": "guo2", "在": "zai4", "地": "di4", "场": "chang3",
+	"基": "ji1", "境": "jing4", "声": "sheng1", "处": "chu4", "外": "wai4", "多": "duo1", "大": "da4", "天": "tian1",
+	"头": "tou2", "她": "ta1", "好": "hao3", "如": "ru2", "始": "shi3", "子": "zi3", "存": "cun2", "学": "xue2",
+	"它": "ta1", "安": "an1", "完": "wan2", "定": "ding4", "实": "shi2", "家": "jia1", "对": "dui4", "导": "dao3",
+	"将": "jiang1", "小": "xiao3", "就": "jiu4", "层": "ceng2", "山": "shan1", "工": "gong1", "差": "cha4", "己": "ji3",
+	"已": "yi3", "常": "chang2", "年": "nian2", "并": "bing4", "应": "ying1", "度": "du4", "建": "jian4", "开": "kai1",
+	"强": "qiang2", "当": "dang1", "形": "xing2", "很": "hen3", "得": "de2", "心": "xin1", "快": "kuai4", "怎": "zen3",
+	"性": "xing4", "总": "zong3", "情": "qing2", "想": "xiang3", "意": "yi4", "感": "gan3", "成": "cheng2", "我": "wo3",
+	"或": "huo4", "战": "zhan4", "所": "suo3", "手": "shou3", "才": "cai2", "打": "da3", "找": "zhao3", "技": "ji4",
+	"把": "ba3", "报": "bao4", "拉": "la1", "持": "chi2", "提": "ti2", "收": "shou1", "改": "gai3", "放": "fang4",
+	"政": "zheng4", "教": "jiao4", "数": "shu4", "文": "wen2", "断": "duan4", "斯": "si1", "新": "xin1", "方": "fang1",
+	"无": "wu2", "日": "ri4", "时": "shi2", "明": "ming2", "是": "shi4", "显": "xian3", "更": "geng4", "最": "zui4",
+	"月": "yue4", "有": "you3", "服": "fu2", "期": "qi1", "本": "ben3", "机": "ji1", "来": "lai2", "果": "guo3",
+	"查": "cha2", "样": "yang4", "根": "gen1", "次": "ci4", "正": "zheng4", "此": "ci3", "步": "bu4", "比": "bi3",
+	"民": "min2", "气": "qi4", "水": "shui3", "没": "mei2", "油": "you2", "法": "fa3", "活": "huo2", "济": "ji4",
+	"海": "hai3", "清": "qing1", "点": "dian3", "热": "re4", "然": "ran2", "爱": "ai4", "物": "wu4", "特": "te4",
+	"状": "zhuang4", "现": "xian4", "理": "li3", "生": "sheng1", "用": "yong4", "由": "you2", "界": "jie4", "白": "bai2",
+	"百": "bai3", "的": "de", "直": "zhi2", "相": "xiang1", "看": "kan4", "真": "zhen1", "着": "zhe", "知": "zhi1",
+	"确": "que4", "示": "shi4", "神": "shen2", "种": "zhong3", "究": "jiu1", "立": "li4", "第": "di4", "等": "deng3",
+	"管": "guan3", "精": "jing1", "系": "xi4", "线": "xian4", "组": "zu3", "细": "xi4", "经": "jing1", "给": "gei3",
+	"统": "tong3", "美": "mei3", "群": "qun2", "老": "lao3", "者": "zhe3", "而": "er2", "联": "lian2", "育": "yu4",
+	"能": "neng2", "自": "zi4", "色": "se4", "花": "hua1", "行": "xing2", "表": "biao3", "被": "bei4", "装": "zhuang1",
+	"西": "xi1", "要": "yao4", "见": "jian4", "规": "gui1", "解": "jie3", "计": "ji4", "论": "lun4", "话": "hua4",
+	"该": "gai1", "说": "shuo1", "走": "zou3", "起": "qi3", "身": "shen1", "转": "zhuan3", "边": "bian1", "过": "guo4",
+	"运": "yun4", "近": "jin4", "还": "hai2", "这": "zhe4", "进": "jin4", "远": "yuan3", "通": "tong1", "造": "zao4",
+	"道": "dao4", "那": "na4", "部": "bu4", "都": "dou1", "里": "li3", "重": "zhong4", "量": "liang4", "金": "jin1",
+	"铁": "tie3", "错": "cuo4", "长": "chang2", "门": "men2", "问": "wen4", "间": "jian1", "院": "yuan4", "集": "ji2",
+	"面": "mian4", "题": "ti2", "马": "ma3", "验": "yan4", "高": "gao1",
+}
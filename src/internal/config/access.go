@@ -0,0 +1,231 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var colorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Keys lists the dotted path of every setting, in the order they appear in the config file
+func Keys(cfg *Config) []string {
+	var keys []string
+	var walk func(v reflect.Value, prefix string)
+	walk = func(v reflect.Value, prefix string) {
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).Tag.Get("toml") == "-" {
+				// Fields kept out of config.toml are not settings
+				continue
+			}
+			key := prefix + keyName(v.Type().Field(i))
+			switch v.Field(i).Kind() {
+			case reflect.Struct:
+				walk(v.Field(i), key+".")
+			case reflect.Map:
+				// Per-mode sections such as [modes.code] and presets such as [presets.sprint] are only edited in config.toml
+			default:
+				keys = append(keys, key)
+			}
+		}
+	}
+	walk(reflect.ValueOf(cfg).Elem(), "")
+	return keys
+}
+
+// Values returns every setting Keys lists, nested by section under the same names and keeping its
+// value's type, for printing the config as JSON
+func Values(cfg *Config) map[string]any {
+	var walk func(v reflect.Value) map[string]any
+	walk = func(v reflect.Value) map[string]any {
+		values := make(map[string]any)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).Tag.Get("toml") == "-" {
+				continue
+			}
+			key := keyName(v.Type().Field(i))
+			switch v.Field(i).Kind() {
+			case reflect.Struct:
+				values[key] = walk(v.Field(i))
+			case reflect.Map:
+			default:
+				values[key] = v.Field(i).Interface()
+			}
+		}
+		return values
+	}
+	return walk(reflect.ValueOf(cfg).Elem())
+}
+
+// CanonicalKey returns the path as Keys spells it, so "Theme.Colors.WordHighlight" becomes "theme.colors.word_highlight"
+func CanonicalKey(cfg *Config, path string) (string, error) {
+	for _, key := range Keys(cfg) {
+		if normalizeKey(key) == normalizeKey(path) {
+			return key, nil
+		}
+	}
+	if _, err := lookup(cfg, path); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("unknown config key '%s'", path)
+}
+
+// Get returns the value of the setting at a dotted path such as "theme.colors.accent"
+func Get(cfg *Config, path string) (string, error) {
+	field, err := lookup(cfg, path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(field.Interface()), nil
+}
+
+// Set parses value for the setting at a dotted path and stores it, rejecting values of the wrong type.
+// The change only lasts for this run until SaveConfig writes it out.
+func Set(cfg *Config, path, value string) error {
+	field, err := lookup(cfg, path)
+	if err != nil {
+		return err
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		if strings.HasPrefix(normalizeKey(path), "theme.colors.") && !isColor(value) {
+			return fmt.Errorf("%s must be a hex color like #ff00ff or a terminal color number 0-255", path)
+		}
+		if normalizeKey(path) == normalizeKey("theme.styles.mark_errors") && !isMarkErrors(value) {
+			return fmt.Errorf("%s must be %s, %s or %s", path, MarkErrorsNone, MarkErrorsUnderline, MarkErrorsStrikethrough)
+		}
+		if normalizeKey(path) == normalizeKey("keyboard.paste") && value != PasteReject && value != PasteMark {
+			return fmt.Errorf("%s must be %s or %s", path, PasteReject, PasteMark)
+		}
+		if normalizeKey(path) == normalizeKey("keyboard.accents") && value != AccentsExact && value != AccentsLoose && value != AccentsPartial {
+			return fmt.Errorf("%s must be %s, %s or %s", path, AccentsExact, AccentsLoose, AccentsPartial)
+		}
+		if normalizeKey(path) == normalizeKey("sync.backend") && value != "" && value != SyncGit && value != SyncS3 && value != SyncWebDAV {
+			return fmt.Errorf("%s must be %s, %s or %s, or empty to turn sync off", path, SyncGit, SyncS3, SyncWebDAV)
+		}
+		// Git would read a URL starting with - as one of its options
+		if normalizeKey(path) == normalizeKey("sync.url") && strings.HasPrefix(value, "-") {
+			return fmt.Errorf("%s must not start with -", path)
+		}
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false", path)
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("%s must be a whole number of 0 or more", path)
+		}
+		switch normalizeKey(path) {
+		case normalizeKey("sound.volume"), normalizeKey("tts.volume"):
+			if n > 100 {
+				return fmt.Errorf("%s must be between 0 and 100", path)
+			}
+		case normalizeKey("tts.rate"):
+			if n < 80 || n > 450 {
+				return fmt.Errorf("%s must be between 80 and 450 words per minute", path)
+			}
+		}
+		field.SetInt(int64(n))
+	default:
+		return fmt.Errorf("%s cannot be set from the command line", path)
+	}
+	return nil
+}
+
+// Validate checks every setting the way Set would, so values edited by hand into config.toml are held
+// to the same rules as those set from the command line
+func Validate(cfg *Config) []error {
+	var errs []error
+	for _, key := range Keys(cfg) {
+		field, err := lookup(cfg, key)
+		if err != nil {
+			continue
+		}
+		switch field.Kind() {
+		case reflect.String, reflect.Bool, reflect.Int:
+		default:
+			continue
+		}
+		value := fmt.Sprint(field.Interface())
+		if value == "" && strings.HasPrefix(key, "theme.colors.") {
+			// A color left out, as some built-in themes leave out word_highlight, is drawn in the terminal's own
+			continue
+		}
+		if err := Set(DefaultConfig(), key, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, name := range cfg.Streaks.RestDays {
+		if _, ok := ParseWeekday(name); !ok {
+			errs = append(errs, fmt.Errorf("streaks.rest_days holds '%s', which is not a weekday such as saturday or sat", name))
+		}
+	}
+	return errs
+}
+
+func lookup(cfg *Config, path string) (reflect.Value, error) {
+	v := reflect.ValueOf(cfg).Elem()
+	for _, part := range strings.Split(path, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("unknown config key '%s'", path)
+		}
+		found := false
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).Tag.Get("toml") == "-" {
+				continue
+			}
+			if normalizeKey(keyName(v.Type().Field(i))) == normalizeKey(part) {
+				v = v.Field(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, fmt.Errorf("unknown config key '%s'", path)
+		}
+	}
+	if v.Kind() == reflect.Struct || v.Kind() == reflect.Map {
+		return reflect.Value{}, fmt.Errorf("'%s' is a section, not a setting", path)
+	}
+	return v, nil
+}
+
+// keyName is the field's TOML key, with untagged CamelCase fields shown as snake_case
+func keyName(field reflect.StructField) string {
+	if tag := field.Tag.Get("toml"); tag != "" {
+		return tag
+	}
+	var b strings.Builder
+	for i, r := range field.Name {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// normalizeKey lets "wordhighlight", "word_highlight" and "WordHighlight" all name the same key
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", ""))
+}
+
+func isMarkErrors(value string) bool {
+	return value == MarkErrorsNone || value == MarkErrorsUnderline || value == MarkErrorsStrikethrough
+}
+
+func isColor(value string) bool {
+	if colorPattern.MatchString(value) {
+		return true
+	}
+	n, err := strconv.Atoi(value)
+	return err == nil && n >= 0 && n <= 255
+}
@@ -15,6 +15,26 @@ type Config struct {
 	Language LanguageConfig `toml:"language"`
 	Network  NetworkConfig  `toml:"network"`
 	History  HistoryConfig  `toml:"history"`
+	Code     CodeConfig     `toml:"code"`
+	Keyboard KeyboardConfig `toml:"keyboard"`
+	Results  ResultsConfig  `toml:"results"`
+	Kiosk    KioskConfig    `toml:"kiosk"`
+	Mixed    MixedConfig    `toml:"mixed"`
+	Sound    SoundConfig    `toml:"sound"`
+	TTS      TTSConfig      `toml:"tts"`
+	News     NewsConfig     `toml:"news"`
+	Idle     IdleConfig     `toml:"idle"`
+	Import   ImportConfig   `toml:"import"`
+	Hooks    HooksConfig    `toml:"hooks"`
+	Sync     SyncConfig     `toml:"sync"`
+	Streaks  StreaksConfig  `toml:"streaks"`
+	Tips     TipsConfig     `toml:"tips"`
+	// Keybindings maps each action to its keys, comma-separated, e.g. help = "ctrl+h,f1"
+	Keybindings KeybindingsConfig `toml:"keybindings"`
+	// Modes holds per-mode overrides, e.g. [modes.code], applied when a session is created
+	Modes map[string]ModeConfig `toml:"modes"`
+	// Presets holds named tests, e.g. [presets.sprint], started with gti run <name>
+	Presets map[string]PresetConfig `toml:"presets"`
 }
 
 type DisplayConfig struct {
@@ -22,6 +42,16 @@ type DisplayConfig struct {
 	CenterText      bool `toml:"center_text"`
 	ShowProgressBar bool `toml:"show_progress_bar"`
 	FPS             int  `toml:"fps"`
+	ShowGhost       bool `toml:"show_ghost"`
+	SyntaxHighlight bool `toml:"syntax_highlight"`
+	// ChunkSummary flashes a one-line summary of each finished chunk before the next one begins
+	ChunkSummary bool `toml:"chunk_summary"`
+	// StartMenu opens a home screen on bare `gti` instead of going straight into practice
+	StartMenu bool `toml:"start_menu"`
+	// RollingText shows prose three lines at a time, previous, current and next, scrolling as you type
+	RollingText bool `toml:"rolling_text"`
+	// ShowKeyboard starts sessions with the on-screen keyboard showing the next key and finger
+	ShowKeyboard bool `toml:"show_keyboard"`
 }
 
 type ThemeConfig struct {
@@ -48,8 +78,28 @@ type ThemeStylesConfig struct {
 	UnderlineCurrent bool `toml:"underline_current"`
 	DimPending       bool `toml:"dim_pending"`
 	BoldResults      bool `toml:"bold_results"`
+	// MarkErrors also marks mistyped characters by shape, for anyone who cannot tell the correct and
+	// incorrect colors apart: "underline", "strikethrough", or "none" for color alone
+	MarkErrors string `toml:"mark_errors"`
 }
 
+const (
+	PasteReject = "reject"
+	PasteMark   = "mark"
+)
+
+const (
+	AccentsExact   = "exact"
+	AccentsLoose   = "loose"
+	AccentsPartial = "partial"
+)
+
+const (
+	MarkErrorsNone          = "none"
+	MarkErrorsUnderline     = "underline"
+	MarkErrorsStrikethrough = "strikethrough"
+)
+
 type TimedConfig struct {
 	DefaultSeconds int `toml:"default_seconds"`
 }
@@ -62,9 +112,205 @@ type NetworkConfig struct {
 	TimeoutMs int `toml:"timeout_ms"`
 }
 
+type CodeConfig struct {
+	// AutoIndent skips a line's leading whitespace after Enter so indentation needn't be typed
+	AutoIndent bool `toml:"auto_indent"`
+	// SkipComments drops comment-only lines from snippets so only code is typed
+	SkipComments bool `toml:"skip_comments"`
+}
+
+type KeyboardConfig struct {
+	// Layout is the keyboard layout the cheat sheet describes: qwerty, uk, qwertz, azerty or spanish
+	Layout string `toml:"layout"`
+	// Emulate remaps keys typed on a US QWERTY keyboard to another layout, such as dvorak or colemak, "" for none
+	Emulate string `toml:"emulate"`
+	// EmulateOnce is set by --emulate for this run only, and is never saved
+	EmulateOnce string `toml:"-"`
+	// Paste is what happens to text pasted into a test: "reject" ignores it, "mark" types it but
+	// leaves the session out of the statistics
+	Paste string `toml:"paste"`
+	// Accents is how an accented letter may be typed: "exact" needs the accent, "loose" takes the
+	// bare letter too, e for é, and "partial" takes it but counts it as a mistake
+	Accents string `toml:"accents"`
+}
+
+// EmulatedLayout is the layout to emulate this run, "" for none
+func (k KeyboardConfig) EmulatedLayout() string {
+	if k.EmulateOnce != "" {
+		return k.EmulateOnce
+	}
+	return k.Emulate
+}
+
+type ResultsConfig struct {
+	// AutoDismissSeconds closes the results screen on its own after this long, 0 to wait for a key
+	AutoDismissSeconds int `toml:"auto_dismiss_seconds"`
+	// AutoChain starts the next test when the results are dismissed automatically, instead of exiting
+	AutoChain bool `toml:"auto_chain"`
+	// Percentiles compares speeds with other typists' on the results screen and in the statistics
+	Percentiles bool `toml:"percentiles"`
+	// PercentileTable is a file of speeds and the share of typists slower than each to compare with,
+	// "" for gti's own
+	PercentileTable string `toml:"percentile_table"`
+	// JSONOnce is set by --json-result for this run only, and is never saved
+	JSONOnce bool `toml:"-"`
+}
+
+type KioskConfig struct {
+	// Passcode is required to leave kiosk mode; kiosk mode will not start without one
+	Passcode string `toml:"passcode"`
+	// IdleSeconds returns to the attract screen after this long without a key press
+	IdleSeconds int `toml:"idle_seconds"`
+	// Seconds is the length of each visitor's timed test
+	Seconds int `toml:"seconds"`
+}
+
+type MixedConfig struct {
+	// Chunks is how many chunks a mixed session holds
+	Chunks int `toml:"chunks"`
+	// Words, Quotes and Code weigh the chance of each chunk coming from that source; 0 leaves a source out
+	Words  int `toml:"words"`
+	Quotes int `toml:"quotes"`
+	Code   int `toml:"code"`
+	// CodeLanguage is the language of code chunks, "" for a random one each time
+	CodeLanguage string `toml:"code_language"`
+}
+
+type SoundConfig struct {
+	// Enabled plays a sound on every key press, off by default
+	Enabled bool `toml:"enabled"`
+	// Volume runs from 0 to 100
+	Volume int `toml:"volume"`
+	// Pack is click, typewriter or soft, or the name of a folder holding key.wav and error.wav in the sounds folder
+	Pack string `toml:"pack"`
+	// Errors gives mistakes their own sound; when off they sound like any other key
+	Errors bool `toml:"errors"`
+}
+
+type TTSConfig struct {
+	// Engine is the speech engine that reads words aloud: auto, espeak-ng, espeak, spd-say, say or sapi
+	Engine string `toml:"engine"`
+	// Voice is the engine's own voice name, such as en-us for espeak or Samantha for say, "" for its default
+	Voice string `toml:"voice"`
+	// Rate is the speaking speed in words per minute
+	Rate int `toml:"rate"`
+	// Volume runs from 0 to 100
+	Volume int `toml:"volume"`
+}
+
+type NewsConfig struct {
+	// Feeds are the RSS or Atom feeds gti news takes headlines and summaries from
+	Feeds []string `toml:"feeds"`
+	// CacheMinutes is how long fetched feeds are reused before being fetched again
+	CacheMinutes int `toml:"cache_minutes"`
+}
+
+type IdleConfig struct {
+	// Seconds pauses a session after this long without a key press, and the idle time is not counted; 0 never pauses
+	Seconds int `toml:"seconds"`
+}
+
+// HooksConfig holds commands run by the shell when something happens in gti
+type HooksConfig struct {
+	// OnComplete runs after each session with the results as JSON on standard input and in GTI_*
+	// environment variables; "" runs nothing
+	OnComplete string `toml:"on_complete"`
+}
+
+// SyncConfig is where gti sync push and pull keep the history and progress files shared between machines
+type SyncConfig struct {
+	// Backend is git, s3 or webdav; "" leaves sync off
+	Backend string `toml:"backend"`
+	// URL is the Git remote, the WebDAV folder, or the S3 endpoint ("" for AWS in the region)
+	URL string `toml:"url"`
+	// Bucket and Region name the S3 bucket; the keys come from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	Bucket string `toml:"bucket"`
+	Region string `toml:"region"`
+	// Prefix is the folder the files are kept in, inside the repository, bucket or WebDAV folder
+	Prefix string `toml:"prefix"`
+	// Username signs in to WebDAV, with the password taken from GTI_SYNC_PASSWORD
+	Username string `toml:"username"`
+}
+
+const (
+	SyncGit    = "git"
+	SyncS3     = "s3"
+	SyncWebDAV = "webdav"
+)
+
+// StreaksConfig is which days without practice a streak survives
+type StreaksConfig struct {
+	// RestDays are weekdays that never break a streak, such as ["saturday", "sunday"]
+	RestDays []string `toml:"rest_days"`
+	// FreezesPerMonth is how many other missed days each calendar month a streak survives
+	FreezesPerMonth int `toml:"freezes_per_month"`
+}
+
+// TipsConfig is which tips show under the text while typing, and how often they change
+type TipsConfig struct {
+	Enabled bool `toml:"enabled"`
+	// File holds tips of your own, one per line, "" for tips.txt beside config.toml; tips under a
+	// [language] heading, such as [french] or [go], only show while typing that language
+	File string `toml:"file"`
+	// Builtin keeps gti's own tips alongside those from File
+	Builtin bool `toml:"builtin"`
+	// Advice shows advice on what the typing shows, such as many backspaces or missed symbols, in
+	// place of the tips while it applies
+	Advice bool `toml:"advice"`
+	// RotateSeconds is how long each tip shows before the next while typing, 0 for one tip per session
+	RotateSeconds int `toml:"rotate_seconds"`
+}
+
+// ImportConfig is how the text of a file practised with -c is cleaned up before it is typed
+type ImportConfig struct {
+	// Quotes turns curly quotes into straight ones
+	Quotes bool `toml:"quotes"`
+	// Dashes folds en and em dashes and minus signs into a hyphen, and an ellipsis into three dots
+	Dashes bool `toml:"dashes"`
+	// Whitespace turns tabs and no-break spaces into spaces and collapses runs of them; code keeps its indentation
+	Whitespace bool `toml:"whitespace"`
+	// Control strips control characters and invisible ones such as zero-width spaces and soft hyphens
+	Control bool `toml:"control"`
+}
+
+type KeybindingsConfig struct {
+	ForceQuit   string `toml:"force_quit"`
+	Quit        string `toml:"quit"`
+	Pause       string `toml:"pause"`
+	Help        string `toml:"help"`
+	Restart     string `toml:"restart"`
+	Repeat      string `toml:"repeat"`
+	Skip        string `toml:"skip"`
+	TTS         string `toml:"tts"`
+	CheatSheet  string `toml:"cheat_sheet"`
+	Back        string `toml:"back"`
+	Next        string `toml:"next"`
+	Retry       string `toml:"retry"`
+	SaveSnippet string `toml:"save_snippet"`
+	SaveCard    string `toml:"save_card"`
+	Rerun15     string `toml:"rerun_15"`
+	Rerun30     string `toml:"rerun_30"`
+	Rerun60     string `toml:"rerun_60"`
+	Rerun120    string `toml:"rerun_120"`
+	ScrollUp    string `toml:"scroll_up"`
+	ScrollDown  string `toml:"scroll_down"`
+	PageUp      string `toml:"page_up"`
+	PageDown    string `toml:"page_down"`
+	StatsQuit   string `toml:"stats_quit"`
+	StatsSwitch string `toml:"stats_switch"`
+	StatsPrev   string `toml:"stats_prev"`
+	StatsNext   string `toml:"stats_next"`
+	StatsExport string `toml:"stats_export"`
+	StatsUp     string `toml:"stats_up"`
+	StatsDown   string `toml:"stats_down"`
+}
+
 type HistoryConfig struct {
 	Enabled bool   `toml:"enabled"`
 	File    string `toml:"file"`
+	// Encrypt seals each session, the daily rollups and exports with the key 'gti encrypt' makes,
+	// so they can only be read with its passphrase
+	Encrypt bool `toml:"encrypt"`
 }
 
 func DefaultConfig() *Config {
@@ -74,6 +320,9 @@ func DefaultConfig() *Config {
 			CenterText:      true,
 			ShowProgressBar: true,
 			FPS:             60,
+			ShowGhost:       true,
+			SyntaxHighlight: true,
+			ChunkSummary:    true,
 		},
 
 		Theme: ThemeConfig{
@@ -94,6 +343,7 @@ func DefaultConfig() *Config {
 				UnderlineCurrent: true,
 				DimPending:       true,
 				BoldResults:      true,
+				MarkErrors:       MarkErrorsNone,
 			},
 		},
 		Timed: TimedConfig{
@@ -110,5 +360,93 @@ func DefaultConfig() *Config {
 			Enabled: true,
 			File:    filepath.Join(xdg.DataHome, "gti", "history.jsonl"),
 		},
+		Code: CodeConfig{
+			AutoIndent: true,
+		},
+		Keyboard: KeyboardConfig{
+			Layout:  "qwerty",
+			Paste:   PasteReject,
+			Accents: AccentsExact,
+		},
+		Kiosk: KioskConfig{
+			IdleSeconds: 60,
+			Seconds:     30,
+		},
+		Mixed: MixedConfig{
+			Chunks: 6,
+			Words:  60,
+			Quotes: 25,
+			Code:   15,
+		},
+		Sound: SoundConfig{
+			Volume: 50,
+			Pack:   "click",
+			Errors: true,
+		},
+		TTS: TTSConfig{
+			Engine: "auto",
+			Rate:   175,
+			Volume: 100,
+		},
+		News: NewsConfig{
+			Feeds: []string{
+				"https://feeds.bbci.co.uk/news/rss.xml",
+				"https://feeds.npr.org/1001/rss.xml",
+			},
+			CacheMinutes: 30,
+		},
+		Idle: IdleConfig{
+			Seconds: 30,
+		},
+		Import: ImportConfig{
+			Quotes:     true,
+			Dashes:     true,
+			Whitespace: true,
+			Control:    true,
+		},
+		Sync: SyncConfig{
+			Region: "us-east-1",
+			Prefix: "gti",
+		},
+		Results: ResultsConfig{
+			Percentiles: true,
+		},
+		Tips: TipsConfig{
+			Enabled:       true,
+			Builtin:       true,
+			Advice:        true,
+			RotateSeconds: 10,
+		},
+		Keybindings: KeybindingsConfig{
+			ForceQuit:   "ctrl+c",
+			Quit:        "ctrl+q",
+			Pause:       "ctrl+p",
+			Help:        "ctrl+h",
+			Restart:     "esc",
+			Repeat:      "ctrl+r",
+			Skip:        "tab",
+			TTS:         "ctrl+w",
+			CheatSheet:  "ctrl+k",
+			Back:        "esc",
+			Next:        "enter",
+			Retry:       "r",
+			SaveSnippet: "s,S",
+			SaveCard:    "c,C",
+			Rerun15:     "1",
+			Rerun30:     "2",
+			Rerun60:     "3",
+			Rerun120:    "4",
+			ScrollUp:    "up",
+			ScrollDown:  "down",
+			PageUp:      "pgup",
+			PageDown:    "pgdown",
+			StatsQuit:   "q,ctrl+c",
+			StatsSwitch: "s",
+			StatsPrev:   "h",
+			StatsNext:   "l",
+			StatsExport: "e",
+			StatsUp:     "up,k",
+			StatsDown:   "down,j",
+		},
 	}
 }
@@ -0,0 +1,81 @@
+package config
+
+import "strings"
+
+// ModeConfig overrides settings for the sessions of one mode, from a [modes.<name>] section such as
+// [modes.code] or [modes.timed]. Keys that are left out keep the global setting.
+type ModeConfig struct {
+	UnderlineCurrent *bool `toml:"underline_current"`
+	DimPending       *bool `toml:"dim_pending"`
+	BoldResults      *bool `toml:"bold_results"`
+	// ChunkWords is how many words each generated chunk holds
+	ChunkWords *int `toml:"chunk_words"`
+	// ChunkLines is how many lines of a code file each chunk holds when practising from --start
+	ChunkLines *int `toml:"chunk_lines"`
+	// StopOnError keeps the cursor on a mistyped character until the right key is struck
+	StopOnError *bool `toml:"stop_on_error"`
+	// Backspace set to false ignores Backspace, so every error stands
+	Backspace *bool `toml:"backspace"`
+}
+
+// modeFamilies lets one section cover every variant of a mode, e.g. [modes.code] for go-code and custom code files
+var modeFamilies = map[string]string{
+	"custom-timed": "custom",
+	"book":         "custom",
+	"custom-code":  "code",
+	"quotes":       "quote",
+	"words":        "timed",
+}
+
+// ModeSection names the [modes.<name>] section that applies to a session mode
+func ModeSection(mode string) string {
+	if family, ok := modeFamilies[mode]; ok {
+		return family
+	}
+	if strings.HasSuffix(mode, "-code") || mode == "snippet" {
+		return "code"
+	}
+	return mode
+}
+
+// ForMode returns the configuration for a session of the given mode along with the mode's overrides.
+// The returned config is a copy with the mode's theme styles applied, so the global config is never changed.
+func (c *Config) ForMode(mode string) (*Config, ModeConfig) {
+	// An exact section, e.g. [modes.words], wins over its family's
+	overrides, ok := c.Modes[mode]
+	if !ok {
+		overrides, ok = c.Modes[ModeSection(mode)]
+	}
+	if !ok {
+		return c, ModeConfig{}
+	}
+
+	resolved := *c
+	styles := &resolved.Theme.Styles
+	if overrides.UnderlineCurrent != nil {
+		styles.UnderlineCurrent = *overrides.UnderlineCurrent
+	}
+	if overrides.DimPending != nil {
+		styles.DimPending = *overrides.DimPending
+	}
+	if overrides.BoldResults != nil {
+		styles.BoldResults = *overrides.BoldResults
+	}
+	return &resolved, overrides
+}
+
+// Words is the mode's chunk size in words, or def when the mode does not set one
+func (m ModeConfig) Words(def int) int {
+	if m.ChunkWords != nil && *m.ChunkWords > 0 {
+		return *m.ChunkWords
+	}
+	return def
+}
+
+// Lines is the mode's chunk size in lines, or def when the mode does not set one
+func (m ModeConfig) Lines(def int) int {
+	if m.ChunkLines != nil && *m.ChunkLines > 0 {
+		return *m.ChunkLines
+	}
+	return def
+}
@@ -15,4 +15,10 @@ var (
 	DataDir    = filepath.Join(xdg.DataHome, AppName)
 	CacheDir   = filepath.Join(xdg.CacheHome, AppName)
 	ConfigFile = filepath.Join(ConfigDir, "config.toml")
+	// ThemesDir holds the user's own themes, in the same format as the built-in ones
+	ThemesDir = filepath.Join(ConfigDir, "themes")
+	// SoundsDir holds the user's own sound packs, one folder per pack
+	SoundsDir = filepath.Join(ConfigDir, "sounds")
+	// LayoutsDir holds the user's own keyboard layouts for emulation
+	LayoutsDir = filepath.Join(ConfigDir, "layouts")
 )
@@ -0,0 +1,36 @@
+package config
+
+import "sort"
+
+// PresetConfig is a named test from a [presets.<name>] section, such as [presets.sprint], started with
+// gti run <name>. Each key stands for the option of the same name; keys left out are not used.
+type PresetConfig struct {
+	// Seconds makes the preset a timed test of this length
+	Seconds int `toml:"seconds"`
+	// Words ends the test once this many words are typed
+	Words int `toml:"words"`
+	// Language is the language, or comma-separated languages, of the generated words
+	Language string `toml:"language"`
+	// Punctuation generates capitalized sentences with commas and periods instead of bare words
+	Punctuation bool `toml:"punctuation"`
+	// Rows, MinLen, MaxLen and FocusChars narrow the generated words as --rows, --min-len,
+	// --max-len and --focus-chars do
+	Rows       string `toml:"rows"`
+	MinLen     int    `toml:"min_len"`
+	MaxLen     int    `toml:"max_len"`
+	FocusChars string `toml:"focus_chars"`
+	// Bot races an opponent typing at this WPM
+	Bot float64 `toml:"bot"`
+	// Focus repeats each mistyped word until it is typed cleanly this many times in a row
+	Focus int `toml:"focus"`
+}
+
+// PresetNames lists the presets in the config file, alphabetically
+func (c *Config) PresetNames() []string {
+	names := make([]string, 0, len(c.Presets))
+	for name := range c.Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
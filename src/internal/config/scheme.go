@@ -0,0 +1,247 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// terminalPalette is the part of a terminal color scheme a gti theme is built from
+type terminalPalette struct {
+	name       string
+	background string
+	foreground string
+	cursor     string
+	selection  string
+	// muted is a dimmed foreground, when the scheme has one apart from its terminal colors
+	muted string
+	// ansi holds the 16 terminal colors: black, red, green, yellow, blue, magenta, cyan, white, then their bright versions
+	ansi [16]string
+}
+
+type base16Scheme map[string]string
+
+type alacrittyColors struct {
+	Primary struct {
+		Background string `toml:"background" yaml:"background"`
+		Foreground string `toml:"foreground" yaml:"foreground"`
+	} `toml:"primary" yaml:"primary"`
+	Cursor struct {
+		Cursor string `toml:"cursor" yaml:"cursor"`
+	} `toml:"cursor" yaml:"cursor"`
+	Selection struct {
+		Background string `toml:"background" yaml:"background"`
+	} `toml:"selection" yaml:"selection"`
+	Normal alacrittyANSI `toml:"normal" yaml:"normal"`
+	Bright alacrittyANSI `toml:"bright" yaml:"bright"`
+}
+
+type alacrittyANSI struct {
+	Black   string `toml:"black" yaml:"black"`
+	Red     string `toml:"red" yaml:"red"`
+	Green   string `toml:"green" yaml:"green"`
+	Yellow  string `toml:"yellow" yaml:"yellow"`
+	Blue    string `toml:"blue" yaml:"blue"`
+	Magenta string `toml:"magenta" yaml:"magenta"`
+	Cyan    string `toml:"cyan" yaml:"cyan"`
+	White   string `toml:"white" yaml:"white"`
+}
+
+type alacrittyScheme struct {
+	Colors alacrittyColors `toml:"colors" yaml:"colors"`
+}
+
+type weztermScheme struct {
+	Colors struct {
+		Foreground  string   `toml:"foreground"`
+		Background  string   `toml:"background"`
+		CursorBg    string   `toml:"cursor_bg"`
+		SelectionBg string   `toml:"selection_bg"`
+		ANSI        []string `toml:"ansi"`
+		Brights     []string `toml:"brights"`
+	} `toml:"colors"`
+	Metadata struct {
+		Name string `toml:"name"`
+	} `toml:"metadata"`
+}
+
+// ImportColorScheme converts a base16 (YAML), alacritty (TOML or YAML) or wezterm (TOML) color scheme
+// into theme colors. The name is the scheme's own, or the file name when it has none.
+func ImportColorScheme(path string) (string, ThemeColorsConfig, error) {
+	data, err := os.ReadFile(ExpandPath(path))
+	if err != nil {
+		return "", ThemeColorsConfig{}, err
+	}
+
+	var palette *terminalPalette
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		palette, err = parseTOMLScheme(data)
+	case ".yaml", ".yml":
+		palette, err = parseYAMLScheme(data)
+	default:
+		return "", ThemeColorsConfig{}, fmt.Errorf("unsupported color scheme %s: expected a .toml or .yaml file", path)
+	}
+	if err != nil {
+		return "", ThemeColorsConfig{}, fmt.Errorf("invalid color scheme %s: %w", path, err)
+	}
+
+	colors, err := palette.themeColors()
+	if err != nil {
+		return "", ThemeColorsConfig{}, fmt.Errorf("invalid color scheme %s: %w", path, err)
+	}
+	name := palette.name
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return ThemeName(name), colors, nil
+}
+
+// ThemeName turns a scheme name such as "Tomorrow Night" into a theme name such as "tomorrow-night"
+func ThemeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+func parseTOMLScheme(data []byte) (*terminalPalette, error) {
+	// wezterm keeps its colors flat under [colors]; alacritty nests them under [colors.primary] and friends
+	var wez weztermScheme
+	if _, err := toml.Decode(string(data), &wez); err == nil && wez.Colors.Background != "" {
+		p := &terminalPalette{
+			name:       wez.Metadata.Name,
+			background: wez.Colors.Background,
+			foreground: wez.Colors.Foreground,
+			cursor:     wez.Colors.CursorBg,
+			selection:  wez.Colors.SelectionBg,
+		}
+		copy(p.ansi[:8], wez.Colors.ANSI)
+		copy(p.ansi[8:], wez.Colors.Brights)
+		return p, nil
+	}
+
+	var ala alacrittyScheme
+	if _, err := toml.Decode(string(data), &ala); err != nil {
+		return nil, err
+	}
+	return ala.Colors.palette(), nil
+}
+
+func parseYAMLScheme(data []byte) (*terminalPalette, error) {
+	var ala alacrittyScheme
+	if err := yaml.Unmarshal(data, &ala); err == nil && ala.Colors.Primary.Background != "" {
+		return ala.Colors.palette(), nil
+	}
+
+	var scheme base16Scheme
+	if err := yaml.Unmarshal(data, &scheme); err != nil {
+		return nil, err
+	}
+	return scheme.palette()
+}
+
+func (c alacrittyColors) palette() *terminalPalette {
+	p := &terminalPalette{
+		background: c.Primary.Background,
+		foreground: c.Primary.Foreground,
+		cursor:     c.Cursor.Cursor,
+		selection:  c.Selection.Background,
+	}
+	for i, set := range []alacrittyANSI{c.Normal, c.Bright} {
+		copy(p.ansi[i*8:], []string{set.Black, set.Red, set.Green, set.Yellow, set.Blue, set.Magenta, set.Cyan, set.White})
+	}
+	return p
+}
+
+// palette maps the base16 slots onto terminal roles, following the base16 styling guidelines
+func (s base16Scheme) palette() (*terminalPalette, error) {
+	if s["base00"] == "" {
+		return nil, fmt.Errorf("not a base16, alacritty or wezterm scheme")
+	}
+	name := s["scheme"]
+	if name == "" {
+		name = s["name"]
+	}
+	return &terminalPalette{
+		name:       name,
+		background: s["base00"],
+		foreground: s["base05"],
+		cursor:     s["base0A"],
+		selection:  s["base02"],
+		muted:      s["base04"],
+		ansi: [16]string{
+			s["base00"], s["base08"], s["base0B"], s["base0A"], s["base0D"], s["base0E"], s["base0C"], s["base05"],
+			s["base03"], s["base08"], s["base0B"], s["base0A"], s["base0D"], s["base0E"], s["base0C"], s["base07"],
+		},
+	}, nil
+}
+
+// themeColors assigns the palette to gti's roles: typed text takes green and red, the cursor
+// marks the current character, and the dim bright-black shows what is still to be typed
+func (p *terminalPalette) themeColors() (ThemeColorsConfig, error) {
+	pick := func(candidates ...string) string {
+		for _, c := range candidates {
+			if hex := normalizeHex(c); hex != "" {
+				return hex
+			}
+		}
+		return ""
+	}
+
+	colors := ThemeColorsConfig{
+		Background:    pick(p.background),
+		TextPrimary:   pick(p.foreground, p.ansi[7]),
+		TextSecondary: pick(p.muted, p.ansi[7], p.foreground),
+		Correct:       pick(p.ansi[2], p.ansi[10]),
+		Incorrect:     pick(p.ansi[1], p.ansi[9]),
+		Current:       pick(p.cursor, p.ansi[3], p.foreground),
+		Pending:       pick(p.ansi[8], p.ansi[7]),
+		WordHighlight: pick(p.selection, p.ansi[8]),
+		Accent:        pick(p.ansi[4], p.ansi[12], p.ansi[6]),
+		Border:        pick(p.ansi[8], p.selection),
+		StatusBar:     pick(p.selection, p.ansi[0], p.background),
+	}
+	if colors.Background == "" || colors.TextPrimary == "" {
+		return ThemeColorsConfig{}, fmt.Errorf("no background or foreground color found")
+	}
+	if colors.Correct == "" || colors.Incorrect == "" {
+		return ThemeColorsConfig{}, fmt.Errorf("no green or red found among the terminal colors")
+	}
+	if colors.Pending == "" {
+		colors.Pending = colors.TextPrimary
+	}
+	if colors.Accent == "" {
+		colors.Accent = colors.Current
+	}
+	if colors.Border == "" {
+		colors.Border = colors.Pending
+	}
+	if colors.WordHighlight == "" {
+		colors.WordHighlight = colors.Border
+	}
+	return colors, nil
+}
+
+// normalizeHex accepts #rrggbb, 0xrrggbb and bare rrggbb (as base16 writes them), or "" when c is not a color
+func normalizeHex(c string) string {
+	c = strings.TrimSpace(c)
+	c = strings.TrimPrefix(strings.TrimPrefix(c, "0x"), "#")
+	if !colorPattern.MatchString("#" + c) {
+		return ""
+	}
+	if len(c) == 3 {
+		c = string([]byte{c[0], c[0], c[1], c[1], c[2], c[2]})
+	}
+	return "#" + strings.ToUpper(c)
+}
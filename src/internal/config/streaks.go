@@ -0,0 +1,33 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+// ParseWeekday reads a weekday by its English name or its first three letters, in any case
+func ParseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for full, day := range weekdays {
+		if name == full || len(name) == 3 && strings.HasPrefix(full, name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// RestWeekdays is the set of rest days, leaving out names that are not weekdays
+func (s StreaksConfig) RestWeekdays() map[time.Weekday]bool {
+	rest := make(map[time.Weekday]bool)
+	for _, name := range s.RestDays {
+		if day, ok := ParseWeekday(name); ok {
+			rest[day] = true
+		}
+	}
+	return rest
+}
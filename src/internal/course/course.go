@@ -0,0 +1,158 @@
+// Package course is the beginner typing course behind gti learn: an ordered curriculum that runs
+// from the home row to the most common words, each lesson passed on a speed and accuracy target.
+package course
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"gti/src/internal"
+	"gti/src/internal/keymap"
+	"gti/src/internal/lesson"
+)
+
+// commonWords is how far down the word list the common words lesson draws from; the lists open with their most frequent words
+const commonWords = 200
+
+// The symbols lesson wraps a word in a pair of brackets or quotes, follows it with punctuation,
+// or joins it to the next word with a symbol
+var (
+	wrapSymbols = [][2]string{{"(", ")"}, {"[", "]"}, {"{", "}"}, {"\"", "\""}, {"'", "'"}, {"<", ">"}}
+	tailSymbols = []string{".", ",", ";", ":", "!", "?"}
+	joinSymbols = []string{"-", "_", "/", "&", "+", "=", "@", "#", "*", "%", "$"}
+)
+
+// Lesson is one step of the course
+type Lesson struct {
+	Number int
+	Name   string
+	// About says what the lesson practises, shown while it is typed
+	About string
+	// MinWPM and MinAccuracy are the net speed and accuracy a run needs to pass the lesson
+	MinWPM      float64
+	MinAccuracy float64
+	text        func(words int, language string) string
+}
+
+// Curriculum lists the course's lessons in the order they are taken, with the letter rows taken from layout
+func Curriculum(layout string) []Lesson {
+	rows := keymap.KeyboardRows(layout)
+	home, top, bottom := letters(rows[2]), letters(rows[1]), letters(rows[3])
+	letterRows := func(keys, known []rune) func(int, string) string {
+		return lesson.Lesson{Keys: keys, Known: known}.Text
+	}
+
+	lessons := []Lesson{
+		{Name: "Home row", About: "rest your fingers on " + string(home) + " and type without looking down",
+			MinWPM: 10, MinAccuracy: 92, text: letterRows(home, home)},
+		{Name: "Top row", About: "reach up from the home row and come straight back",
+			MinWPM: 12, MinAccuracy: 92, text: letterRows(top, concat(home, top))},
+		{Name: "Bottom row", About: "reach down from the home row; every letter is now in play",
+			MinWPM: 14, MinAccuracy: 92, text: letterRows(bottom, concat(home, top, bottom))},
+		{Name: "Numbers", About: "reach the number row with the finger above each home key",
+			MinWPM: 10, MinAccuracy: 90, text: numberText},
+		{Name: "Symbols", About: "punctuation and brackets, shifted keys with the other hand's little finger",
+			MinWPM: 10, MinAccuracy: 90, text: symbolText},
+		{Name: "Capitals", About: "hold Shift with the little finger of the hand not typing the letter",
+			MinWPM: 16, MinAccuracy: 92, text: capitalText},
+		{Name: "Common words", About: "the most frequent words, typed as whole words rather than letters",
+			MinWPM: 25, MinAccuracy: 95, text: commonText},
+	}
+	for i := range lessons {
+		lessons[i].Number = i + 1
+	}
+	return lessons
+}
+
+// Text makes a run of the lesson of about the given number of words
+func (l Lesson) Text(words int, language string) string {
+	return l.text(words, language)
+}
+
+// Guidance is shown in place of the tips while the lesson is typed
+func (l Lesson) Guidance() string {
+	return fmt.Sprintf("Lesson %d, %s: %s (pass: %.0f wpm at %.0f%%)", l.Number, l.Name, l.About, l.MinWPM, l.MinAccuracy)
+}
+
+// letters keeps the letters of a keyboard row, leaving out its punctuation keys
+func letters(row string) []rune {
+	var keys []rune
+	for _, r := range row {
+		if unicode.IsLetter(r) {
+			keys = append(keys, r)
+		}
+	}
+	return keys
+}
+
+func concat(rows ...[]rune) []rune {
+	var all []rune
+	for _, r := range rows {
+		all = append(all, r...)
+	}
+	return all
+}
+
+func pickCommon(language string) string {
+	words := internal.GetWordList(language)
+	return words[internal.Intn(min(len(words), commonWords))]
+}
+
+// numberText mixes common words with numbers of one to four digits, about every other word
+func numberText(words int, language string) string {
+	picked := make([]string, words)
+	for i := range picked {
+		if internal.Intn(2) == 0 {
+			picked[i] = pickCommon(language)
+			continue
+		}
+		digits := 1 + internal.Intn(4)
+		n := internal.Intn(9) + 1
+		for j := 1; j < digits; j++ {
+			n = n*10 + internal.Intn(10)
+		}
+		picked[i] = fmt.Sprint(n)
+	}
+	return strings.Join(picked, " ")
+}
+
+// symbolText puts punctuation after, around or between common words
+func symbolText(words int, language string) string {
+	picked := make([]string, words)
+	for i := range picked {
+		w := pickCommon(language)
+		switch internal.Intn(4) {
+		case 0:
+			w += tailSymbols[internal.Intn(len(tailSymbols))]
+		case 1:
+			wrap := wrapSymbols[internal.Intn(len(wrapSymbols))]
+			w = wrap[0] + w + wrap[1]
+		case 2:
+			w += joinSymbols[internal.Intn(len(joinSymbols))] + pickCommon(language)
+		}
+		picked[i] = w
+	}
+	return strings.Join(picked, " ")
+}
+
+// capitalText capitalises about half the words, and always the first
+func capitalText(words int, language string) string {
+	picked := make([]string, words)
+	for i := range picked {
+		w := []rune(pickCommon(language))
+		if i == 0 || internal.Intn(2) == 0 {
+			w[0] = unicode.ToUpper(w[0])
+		}
+		picked[i] = string(w)
+	}
+	return strings.Join(picked, " ")
+}
+
+func commonText(words int, language string) string {
+	picked := make([]string, words)
+	for i := range picked {
+		picked[i] = pickCommon(language)
+	}
+	return strings.Join(picked, " ")
+}
@@ -0,0 +1,83 @@
+package course
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gti/src/internal/config"
+	"gti/src/internal/session"
+)
+
+// Progress is how far the course has got and the best passing run of each lesson
+type Progress struct {
+	Completed int         `json:"completed"`
+	Best      map[int]Run `json:"best"`
+}
+
+// Run is the net speed and accuracy of one run of a lesson
+type Run struct {
+	WPM      float64 `json:"wpm"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+func progressFile() string {
+	return filepath.Join(config.ConfigDir, "course_progress.json")
+}
+
+// LoadProgress reads the course progress, starting afresh when there is none
+func LoadProgress() *Progress {
+	progress := &Progress{}
+	if _, err := os.Stat(progressFile()); err == nil {
+		config.LoadJSONData(progressFile(), progress)
+	}
+	if progress.Best == nil {
+		progress.Best = make(map[int]Run)
+	}
+	return progress
+}
+
+func SaveProgress(progress *Progress) error {
+	return config.SaveJSONData(progressFile(), progress)
+}
+
+// BestRun returns the best passing run of lesson number n, if it has been passed
+func (p *Progress) BestRun(n int) (Run, bool) {
+	run, ok := p.Best[n]
+	return run, ok
+}
+
+// Next is the first lesson not yet passed, or the last lesson once all are
+func (p *Progress) Next(lessons []Lesson) int {
+	return min(p.Completed+1, len(lessons))
+}
+
+func (l Lesson) passed(run Run) bool {
+	return run.WPM >= l.MinWPM && run.Accuracy >= l.MinAccuracy
+}
+
+// Check grades runs of the lesson, marking it passed and keeping the run when it met both targets
+func Check(lessons []Lesson, l Lesson) session.RunCheck {
+	return func(results session.Results) string {
+		run := Run{WPM: results.NetWPM, Accuracy: results.Accuracy}
+		if !l.passed(run) {
+			return fmt.Sprintf("Lesson %d not passed yet: it needs %.0f wpm at %.0f%% accuracy, this run was %.0f wpm at %.1f%%",
+				l.Number, l.MinWPM, l.MinAccuracy, run.WPM, run.Accuracy)
+		}
+
+		progress := LoadProgress()
+		progress.Completed = max(progress.Completed, l.Number)
+		if best, ok := progress.BestRun(l.Number); !ok || run.WPM > best.WPM {
+			progress.Best[l.Number] = run
+		}
+		if err := SaveProgress(progress); err != nil {
+			return "Could not save course progress: " + err.Error()
+		}
+
+		if l.Number == len(lessons) {
+			return fmt.Sprintf("Lesson %d passed, the last one of the course!", l.Number)
+		}
+		next := lessons[l.Number]
+		return fmt.Sprintf("Lesson %d passed! Up next: lesson %d, %s", l.Number, next.Number, next.Name)
+	}
+}
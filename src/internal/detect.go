@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var codeExtensions = map[string]string{
+	".go":    "go",
+	".py":    "python",
+	".pyw":   "python",
+	".js":    "javascript",
+	".mjs":   "javascript",
+	".cjs":   "javascript",
+	".jsx":   "javascript",
+	".java":  "java",
+	".c":     "cpp",
+	".h":     "cpp",
+	".cc":    "cpp",
+	".cpp":   "cpp",
+	".cxx":   "cpp",
+	".hpp":   "cpp",
+	".rs":    "rust",
+	".ts":    "typescript",
+	".tsx":   "typescript",
+	".cs":    "csharp",
+	".rb":    "ruby",
+	".php":   "php",
+	".kt":    "kotlin",
+	".kts":   "kotlin",
+	".swift": "swift",
+	".sql":   "sql",
+	".sh":    "bash",
+	".bash":  "bash",
+	".zsh":   "bash",
+	".html":  "html",
+	".htm":   "html",
+	".css":   "css",
+}
+
+var shebangInterpreters = map[string]string{
+	"python":  "python",
+	"python2": "python",
+	"python3": "python",
+	"node":    "javascript",
+	"deno":    "typescript",
+	"ruby":    "ruby",
+	"php":     "php",
+	"sh":      "bash",
+	"bash":    "bash",
+	"zsh":     "bash",
+	"dash":    "bash",
+}
+
+// DetectCodeLanguage guesses the code language of a file from its extension, falling back to
+// its shebang line. It returns "" when the file doesn't look like code.
+func DetectCodeLanguage(filename string) string {
+	if language, exists := codeExtensions[strings.ToLower(filepath.Ext(filename))]; exists {
+		return language
+	}
+	return detectShebang(filename)
+}
+
+func detectShebang(filename string) string {
+	var r io.Reader
+	if filename == StdinFile {
+		data, err := ReadFile(filename)
+		if err != nil {
+			return ""
+		}
+		r = bytes.NewReader(data)
+	} else {
+		// Only the first line is needed, so a large file is not read whole
+		file, err := os.Open(filename)
+		if err != nil {
+			return ""
+		}
+		defer file.Close()
+		r = file
+	}
+
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		return ""
+	}
+	line := scanner.Text()
+	if !strings.HasPrefix(line, "#!") {
+		return ""
+	}
+
+	// "#!/usr/bin/env python3" names the interpreter after env, "#!/bin/bash" in the path itself
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") {
+				interpreter = field
+				break
+			}
+		}
+	}
+	return shebangInterpreters[interpreter]
+}
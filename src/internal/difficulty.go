@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Snippet difficulty tiers: short, idiomatic snippets are easy; long or symbol-heavy ones are hard
+const (
+	DifficultyEasy = "easy"
+	DifficultyHard = "hard"
+)
+
+const (
+	// hardSnippetLines is the length past which an untagged snippet counts as hard on its own
+	hardSnippetLines = 12
+	// hardSymbolRatio is the share of punctuation among non-space characters that makes a snippet hard
+	hardSymbolRatio = 0.3
+)
+
+type codeSnippet struct {
+	code       string
+	difficulty string
+}
+
+// ValidateCodeDifficulty checks the difficulty tier is supported; "" means any tier
+func ValidateCodeDifficulty(difficulty string) error {
+	if difficulty != "" && difficulty != DifficultyEasy && difficulty != DifficultyHard {
+		return fmt.Errorf("unsupported difficulty '%s' (supported: %s, %s)", difficulty, DifficultyEasy, DifficultyHard)
+	}
+	return nil
+}
+
+// headerDifficulty reads a "[easy]" or "[hard]" tag from the end of a snippet separator line
+func headerDifficulty(header string) string {
+	header = strings.TrimSpace(header)
+	for _, tier := range []string{DifficultyEasy, DifficultyHard} {
+		if strings.HasSuffix(header, "["+tier+"]") {
+			return tier
+		}
+	}
+	return ""
+}
+
+// classifyDifficulty tiers a snippet that carries no tag by its length and symbol density
+func classifyDifficulty(code string) string {
+	lines := strings.Count(code, "\n") + 1
+
+	var chars, symbols int
+	for _, r := range code {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		chars++
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			symbols++
+		}
+	}
+
+	if lines > hardSnippetLines || (chars > 0 && float64(symbols)/float64(chars) > hardSymbolRatio) {
+		return DifficultyHard
+	}
+	return DifficultyEasy
+}
+
+// snippetsOfDifficulty keeps the snippets in the requested tier, falling back to all of them when the tier is empty
+func snippetsOfDifficulty(snippets []codeSnippet, difficulty string) []codeSnippet {
+	if difficulty == "" {
+		return snippets
+	}
+	var matching []codeSnippet
+	for _, snippet := range snippets {
+		if snippet.difficulty == difficulty {
+			matching = append(matching, snippet)
+		}
+	}
+	if len(matching) == 0 {
+		return snippets
+	}
+	return matching
+}
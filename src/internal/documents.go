@@ -0,0 +1,267 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// docField is one key/value pair of a generated document; values are scalars, []docField objects or []interface{} arrays
+type docField struct {
+	key   string
+	value interface{}
+}
+
+var documentFormats = map[string]bool{
+	"json": true,
+	"yaml": true,
+	"toml": true,
+}
+
+var docServiceNames = []string{"api-gateway", "billing", "auth-service", "search", "notifications", "inventory", "ledger", "scheduler"}
+var docHosts = []string{"0.0.0.0", "127.0.0.1", "10.0.3.17", "db.internal", "cache.internal", "localhost"}
+var docRegions = []string{"us-east-1", "eu-west-2", "ap-south-1", "us-west-2"}
+var docLogLevels = []string{"debug", "info", "warn", "error"}
+var docFeatures = []string{"dark_mode", "beta_search", "rate_limiting", "audit_log", "webhooks", "sso", "metrics"}
+var docEnvKeys = []string{"DATABASE_URL", "REDIS_URL", "LOG_FORMAT", "SENTRY_DSN", "FEATURE_FLAGS", "TZ"}
+
+func pick(values []string) string {
+	return values[rng.Intn(len(values))]
+}
+
+func pickN(values []string, n int) []interface{} {
+	shuffled := append([]string{}, values...)
+	rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	if n > len(shuffled) {
+		n = len(shuffled)
+	}
+	out := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		out[i] = shuffled[i]
+	}
+	return out
+}
+
+var docSections = []func() docField{
+	func() docField {
+		return docField{"server", []docField{
+			{"host", pick(docHosts)},
+			{"port", 8000 + rng.Intn(1000)},
+			{"read_timeout_ms", (rng.Intn(10) + 1) * 500},
+			{"tls", []docField{
+				{"enabled", rng.Intn(2) == 0},
+				{"cert_file", "/etc/ssl/certs/" + pick(docServiceNames) + ".pem"},
+			}},
+		}}
+	},
+	func() docField {
+		return docField{"database", []docField{
+			{"driver", pick([]string{"postgres", "mysql", "sqlite"})},
+			{"dsn", fmt.Sprintf("postgres://app:%s@%s:5432/app?sslmode=disable", pick([]string{"s3cr3t", "hunter2", "p@ss"}), pick(docHosts))},
+			{"max_open_conns", rng.Intn(90) + 10},
+			{"replicas", []interface{}{
+				[]docField{{"host", pick(docHosts)}, {"weight", rng.Intn(5) + 1}},
+				[]docField{{"host", pick(docHosts)}, {"weight", rng.Intn(5) + 1}},
+			}},
+		}}
+	},
+	func() docField {
+		return docField{"logging", []docField{
+			{"level", pick(docLogLevels)},
+			{"format", pick([]string{"json", "text", "logfmt"})},
+			{"sample_rate", float64(rng.Intn(100)+1) / 100},
+		}}
+	},
+	func() docField {
+		return docField{"features", []docField{
+			{"enabled", pickN(docFeatures, rng.Intn(3)+2)},
+			{"rollout_percent", rng.Intn(101)},
+		}}
+	},
+	func() docField {
+		env := []docField{}
+		for _, key := range pickN(docEnvKeys, rng.Intn(2)+2) {
+			env = append(env, docField{key.(string), fmt.Sprintf("${%s:-%s}", key, pick([]string{"unset", "default", "prod"}))})
+		}
+		return docField{"deploy", []docField{
+			{"region", pick(docRegions)},
+			{"replicas", rng.Intn(8) + 1},
+			{"env", env},
+			{"ports", []interface{}{8080, 9090 + rng.Intn(10)}},
+		}}
+	},
+	func() docField {
+		return docField{"cache", []docField{
+			{"backend", pick([]string{"redis", "memcached", "memory"})},
+			{"ttl_seconds", (rng.Intn(12) + 1) * 300},
+			{"keys", []interface{}{"user:{id}", "session:{token}", "rate:{ip}"}},
+		}}
+	},
+}
+
+// generateDocumentTree builds a random service configuration with a few nested sections
+func generateDocumentTree() []docField {
+	fields := []docField{
+		{"name", pick(docServiceNames)},
+		{"version", fmt.Sprintf("%d.%d.%d", rng.Intn(4)+1, rng.Intn(20), rng.Intn(10))},
+		{"debug", rng.Intn(2) == 0},
+	}
+
+	order := rng.Perm(len(docSections))
+	count := rng.Intn(2) + 3
+	sort.Ints(order[:count])
+	for _, idx := range order[:count] {
+		fields = append(fields, docSections[idx]())
+	}
+	return fields
+}
+
+func GenerateDocument(format string) string {
+	tree := generateDocumentTree()
+	switch format {
+	case "yaml":
+		return strings.TrimRight(renderYAML(tree, 0), "\n")
+	case "toml":
+		return strings.TrimRight(renderTOML(tree, ""), "\n")
+	default:
+		return renderJSONObject(tree, 0)
+	}
+}
+
+func GenerateDocuments(count int, format string) string {
+	var docs []string
+	for i := 0; i < count; i++ {
+		docs = append(docs, GenerateDocument(format))
+	}
+	return strings.Join(docs, "\n\n")
+}
+
+func IsDocumentFormatSupported(format string) bool {
+	return documentFormats[format]
+}
+
+// ValidateDocumentFormat checks if the document format is supported and returns error if not
+func ValidateDocumentFormat(format string) error {
+	if !IsDocumentFormatSupported(format) {
+		return fmt.Errorf("unsupported document format '%s'", format)
+	}
+	return nil
+}
+
+// GetSupportedDocumentFormats returns a list of supported document formats
+func GetSupportedDocumentFormats() []string {
+	formats := make([]string, 0, len(documentFormats))
+	for format := range documentFormats {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+func scalarString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func renderJSONValue(value interface{}, depth int) string {
+	switch v := value.(type) {
+	case []docField:
+		return renderJSONObject(v, depth)
+	case []interface{}:
+		indent := strings.Repeat("  ", depth+1)
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = indent + renderJSONValue(item, depth+1)
+		}
+		return "[\n" + strings.Join(items, ",\n") + "\n" + strings.Repeat("  ", depth) + "]"
+	default:
+		return scalarString(v)
+	}
+}
+
+func renderJSONObject(fields []docField, depth int) string {
+	indent := strings.Repeat("  ", depth+1)
+	lines := make([]string, len(fields))
+	for i, f := range fields {
+		lines[i] = fmt.Sprintf("%s%q: %s", indent, f.key, renderJSONValue(f.value, depth+1))
+	}
+	return "{\n" + strings.Join(lines, ",\n") + "\n" + strings.Repeat("  ", depth) + "}"
+}
+
+func renderYAML(fields []docField, depth int) string {
+	var b strings.Builder
+	indent := strings.Repeat("  ", depth)
+	for _, f := range fields {
+		switch v := f.value.(type) {
+		case []docField:
+			fmt.Fprintf(&b, "%s%s:\n%s", indent, f.key, renderYAML(v, depth+1))
+		case []interface{}:
+			fmt.Fprintf(&b, "%s%s:\n", indent, f.key)
+			for _, item := range v {
+				if obj, ok := item.([]docField); ok {
+					nested := renderYAML(obj, depth+2)
+					// The first key of a list item sits on the dash line
+					fmt.Fprintf(&b, "%s  - %s", indent, strings.TrimPrefix(nested, strings.Repeat("  ", depth+2)))
+				} else {
+					fmt.Fprintf(&b, "%s  - %s\n", indent, scalarString(item))
+				}
+			}
+		default:
+			fmt.Fprintf(&b, "%s%s: %s\n", indent, f.key, scalarString(v))
+		}
+	}
+	return b.String()
+}
+
+func renderTOML(fields []docField, table string) string {
+	var b strings.Builder
+	var tables []docField
+	var arrayTables []docField
+
+	for _, f := range fields {
+		switch v := f.value.(type) {
+		case []docField:
+			tables = append(tables, f)
+		case []interface{}:
+			if len(v) > 0 {
+				if _, ok := v[0].([]docField); ok {
+					arrayTables = append(arrayTables, f)
+					continue
+				}
+			}
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = scalarString(item)
+			}
+			fmt.Fprintf(&b, "%s = [%s]\n", f.key, strings.Join(items, ", "))
+		default:
+			fmt.Fprintf(&b, "%s = %s\n", f.key, scalarString(v))
+		}
+	}
+
+	for _, t := range tables {
+		name := joinTableName(table, t.key)
+		fmt.Fprintf(&b, "\n[%s]\n%s", name, renderTOML(t.value.([]docField), name))
+	}
+	for _, t := range arrayTables {
+		name := joinTableName(table, t.key)
+		for _, item := range t.value.([]interface{}) {
+			fmt.Fprintf(&b, "\n[[%s]]\n%s", name, renderTOML(item.([]docField), name))
+		}
+	}
+	return b.String()
+}
+
+func joinTableName(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
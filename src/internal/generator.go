@@ -4,9 +4,11 @@ import (
 	"bufio"
 	"fmt"
 	"math/rand"
+	"sort"
 	"strings"
 	"sync"
-	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"gti/src/assets"
 )
@@ -54,10 +56,19 @@ var codeLanguageFiles = map[string]string{
 	"cpp":        "cpp.snippets",
 	"rust":       "rust.snippets",
 	"typescript": "typescript.snippets",
+	"csharp":     "csharp.snippets",
+	"ruby":       "ruby.snippets",
+	"php":        "php.snippets",
+	"kotlin":     "kotlin.snippets",
+	"swift":      "swift.snippets",
+	"sql":        "sql.snippets",
+	"bash":       "bash.snippets",
+	"html":       "html.snippets",
+	"css":        "css.snippets",
 }
 
 var loadedWords = make(map[string][]string)
-var loadedCodeSnippets = make(map[string][]string)
+var loadedCodeSnippets = make(map[string][]codeSnippet)
 var loadMutex sync.Mutex
 
 func loadWords(language string) []string {
@@ -97,14 +108,159 @@ func loadWords(language string) []string {
 	return words
 }
 
-func GenerateWord(language string) string {
-	rand.Seed(time.Now().UnixNano())
+// minRowWords is how many words a language needs on the chosen rows before it is practised with them;
+// with fewer, groups of the rows' letters are mixed in
+const minRowWords = 20
+
+var (
+	// rowKeys limits generated words to the keys of the rows chosen with --rows, nil for any word
+	rowKeys map[rune]bool
+	// rowWords caches each language's words as narrowed by the rows and the word filter
+	rowWords = make(map[string][]string)
+)
+
+// RestrictWordsToKeys makes generated words use only the given keys, from then on for this run
+func RestrictWordsToKeys(keys string) {
+	loadMutex.Lock()
+	defer loadMutex.Unlock()
+	rowKeys = make(map[rune]bool)
+	for _, r := range keys {
+		rowKeys[r] = true
+	}
+	rowWords = make(map[string][]string)
+}
+
+// WordFilter narrows generated words down for targeted practice
+type WordFilter struct {
+	// MinLen and MaxLen bound a word's length in characters, 0 for no bound
+	MinLen int
+	MaxLen int
+	// Chars keeps only the words containing at least one of its characters, "" for any word
+	Chars string
+}
+
+// keeps reports whether word passes the filter; letters match in either case
+func (f WordFilter) keeps(word string) bool {
+	length := utf8.RuneCountInString(word)
+	if (f.MinLen > 0 && length < f.MinLen) || (f.MaxLen > 0 && length > f.MaxLen) {
+		return false
+	}
+	return f.Chars == "" || strings.ContainsAny(strings.ToLower(word), strings.ToLower(f.Chars))
+}
+
+// wordFilter is the filter set with FilterWords, the zero filter keeping every word
+var wordFilter WordFilter
+
+// FilterWords makes generated words pass the filter, from then on for this run
+func FilterWords(f WordFilter) {
+	loadMutex.Lock()
+	defer loadMutex.Unlock()
+	wordFilter = f
+	rowWords = make(map[string][]string)
+}
+
+// FilteredWordCount is how many of the language's words pass the filter set with FilterWords
+func FilteredWordCount(language string) int {
+	count := 0
+	for _, w := range GetWordList(language) {
+		if wordFilter.keeps(w) {
+			count++
+		}
+	}
+	return count
+}
+
+// wordsFor returns the words generated text is drawn from: the language's list, less any word
+// that leaves the rows chosen with --rows or fails the filter set with FilterWords
+func wordsFor(language string) []string {
 	words := loadWords(language)
-	return words[rand.Intn(len(words))]
+	loadMutex.Lock()
+	defer loadMutex.Unlock()
+	if rowKeys == nil && wordFilter == (WordFilter{}) {
+		return words
+	}
+	if filtered, ok := rowWords[language]; ok {
+		return filtered
+	}
+
+	var filtered []string
+	for _, w := range words {
+		if wordFilter.keeps(w) && onRows(w) {
+			filtered = append(filtered, w)
+		}
+	}
+	if rowKeys != nil && len(filtered) < minRowWords {
+		filtered = append(filtered, keyGroups(minRowWords-len(filtered))...)
+	}
+	if len(filtered) == 0 {
+		// A filter no word passes is turned down before the test starts; this only keeps a stray
+		// caller from drawing from an empty list
+		filtered = words
+	}
+	rowWords[language] = filtered
+	return filtered
+}
+
+// onRows reports whether word is typed with the keys of the rows chosen with --rows alone
+func onRows(word string) bool {
+	if rowKeys == nil {
+		return true
+	}
+	for _, r := range strings.ToLower(word) {
+		if !rowKeys[r] {
+			return false
+		}
+	}
+	return true
+}
+
+// keyGroups makes count groups of two to five of the row keys, preferring letters when the rows have any
+func keyGroups(count int) []string {
+	var keys []rune
+	for r := range rowKeys {
+		if unicode.IsLetter(r) {
+			keys = append(keys, r)
+		}
+	}
+	if len(keys) == 0 {
+		for r := range rowKeys {
+			keys = append(keys, r)
+		}
+	}
+	groups := make([]string, count)
+	for i := range groups {
+		group := make([]rune, 2+rng.Intn(4))
+		for j := range group {
+			group[j] = keys[rng.Intn(len(keys))]
+		}
+		groups[i] = string(group)
+	}
+	return groups
+}
+
+// Languages splits a language setting into the languages it mixes: "english,spanish" interleaves the
+// words of both lists, for bilingual practice
+func Languages(language string) []string {
+	languages := strings.Split(language, ",")
+	for i := range languages {
+		languages[i] = strings.TrimSpace(languages[i])
+	}
+	return languages
+}
+
+// GenerateWord picks a word of the language, or of one of them at random when the setting mixes several
+func GenerateWord(language string) string {
+	if languages := Languages(language); len(languages) > 1 {
+		language = languages[rng.Intn(len(languages))]
+	}
+	words := wordsFor(language)
+	return words[rng.Intn(len(words))]
 }
 
 func GenerateWordsDynamic(count int, language string) string {
-	rand.Seed(time.Now().UnixNano())
+	if sentences {
+		return GenerateSentences(count, language)
+	}
 	var selected []string
 	for i := 0; i < count; i++ {
 		selected = append(selected, GenerateWord(language))
@@ -112,20 +268,68 @@ func GenerateWordsDynamic(count int, language string) string {
 	return strings.Join(selected, " ")
 }
 
+// GenerateWordsSeeded picks words from its own seeded source, so the same seed always gives the same text
+func GenerateWordsSeeded(count int, language string, seed int64) string {
+	rng := rand.New(rand.NewSource(seed))
+	languages := Languages(language)
+	selected := make([]string, count)
+	for i := range selected {
+		// A single language draws nothing for the choice, so its protocols give the text they always have
+		lang := languages[0]
+		if len(languages) > 1 {
+			lang = languages[rng.Intn(len(languages))]
+		}
+		words := loadWords(lang)
+		selected[i] = words[rng.Intn(len(words))]
+	}
+	return strings.Join(selected, " ")
+}
+
+// GetWordList returns the language's whole word list, or the lists of all the languages it mixes
+func GetWordList(language string) []string {
+	languages := Languages(language)
+	if len(languages) == 1 {
+		return loadWords(language)
+	}
+	var words []string
+	for _, lang := range languages {
+		words = append(words, loadWords(lang)...)
+	}
+	return words
+}
+
+// IsLanguageSupported reports whether there is a word list for the language, or for each of the
+// languages it mixes
 func IsLanguageSupported(language string) bool {
-	_, exists := languageFiles[language]
-	return exists
+	for _, lang := range Languages(language) {
+		if _, exists := languageFiles[lang]; !exists {
+			return false
+		}
+	}
+	return true
+}
+
+// GetSupportedLanguages returns the languages there are word lists for, in alphabetical order
+func GetSupportedLanguages() []string {
+	langs := make([]string, 0, len(languageFiles))
+	for lang := range languageFiles {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
 }
 
 // ValidateLanguage checks if language is supported and returns error if not
 func ValidateLanguage(language string) error {
-	if !IsLanguageSupported(language) {
-		return fmt.Errorf("unsupported language '%s'", language)
+	for _, lang := range Languages(language) {
+		if _, exists := languageFiles[lang]; !exists {
+			return fmt.Errorf("unsupported language '%s'", lang)
+		}
 	}
 	return nil
 }
 
-func loadCodeSnippets(language string) []string {
+func loadCodeSnippets(language string) []codeSnippet {
 	loadMutex.Lock()
 	defer loadMutex.Unlock()
 
@@ -144,12 +348,37 @@ func loadCodeSnippets(language string) []string {
 	data, err := assets.Code.ReadFile(filePath)
 	if err != nil {
 		// Return a simple default code snippet
-		return []string{"func main() {\n    fmt.Println(\"Hello, World!\")\n}"}
+		return []codeSnippet{{"func main() {\n    fmt.Println(\"Hello, World!\")\n}", DifficultyEasy}}
 	}
 
-	var snippets []string
+	snippets := parseSnippets(string(data))
+	snippets = append(snippets, loadPersonalSnippets(language)...)
+
+	if len(snippets) == 0 {
+		snippets = []codeSnippet{{"func main() {\n    fmt.Println(\"Hello, World!\")\n}", DifficultyEasy}}
+	}
+
+	loadedCodeSnippets[language] = snippets
+	return snippets
+}
+
+// parseSnippets splits a snippet pack into snippets, using lines starting with # as separators.
+// A separator ending in [easy] or [hard] tags the snippet after it; untagged snippets are classified.
+func parseSnippets(data string) []codeSnippet {
+	var snippets []codeSnippet
 	var currentSnippet strings.Builder
-	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	var difficulty string
+	scanner := bufio.NewScanner(strings.NewReader(data))
+
+	addSnippet := func() {
+		code := strings.TrimSuffix(currentSnippet.String(), "\n")
+		tier := difficulty
+		if tier == "" {
+			tier = classifyDifficulty(code)
+		}
+		snippets = append(snippets, codeSnippet{code, tier})
+		currentSnippet.Reset()
+	}
 
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -157,9 +386,9 @@ func loadCodeSnippets(language string) []string {
 		if strings.HasPrefix(strings.TrimSpace(line), "#") {
 			// Save previous snippet if it exists
 			if currentSnippet.Len() > 0 {
-				snippets = append(snippets, strings.TrimSuffix(currentSnippet.String(), "\n"))
-				currentSnippet.Reset()
+				addSnippet()
 			}
+			difficulty = headerDifficulty(line)
 		} else if strings.TrimSpace(line) != "" {
 			// Add non-empty lines to current snippet
 			currentSnippet.WriteString(line)
@@ -169,30 +398,23 @@ func loadCodeSnippets(language string) []string {
 
 	// Add the last snippet
 	if currentSnippet.Len() > 0 {
-		snippets = append(snippets, strings.TrimSuffix(currentSnippet.String(), "\n"))
+		addSnippet()
 	}
-
-	if len(snippets) == 0 {
-		snippets = []string{"func main() {\n    fmt.Println(\"Hello, World!\")\n}"}
-	}
-
-	loadedCodeSnippets[language] = snippets
 	return snippets
 }
 
-func GenerateCodeSnippet(language string) string {
-	rand.Seed(time.Now().UnixNano())
-	snippets := loadCodeSnippets(language)
-	return snippets[rand.Intn(len(snippets))]
+// GenerateCodeSnippet picks a snippet of the given difficulty tier, or of any tier when difficulty is ""
+func GenerateCodeSnippet(language string, difficulty string) string {
+	snippets := snippetsOfDifficulty(loadCodeSnippets(language), difficulty)
+	return snippets[rng.Intn(len(snippets))].code
 }
 
-func GenerateCodeSnippets(count int, language string) string {
-	rand.Seed(time.Now().UnixNano())
-	snippets := loadCodeSnippets(language)
+func GenerateCodeSnippets(count int, language string, difficulty string) string {
+	snippets := snippetsOfDifficulty(loadCodeSnippets(language), difficulty)
 	var selected []string
 
 	for i := 0; i < count && i < len(snippets); i++ {
-		selected = append(selected, snippets[rand.Intn(len(snippets))])
+		selected = append(selected, snippets[rng.Intn(len(snippets))].code)
 	}
 
 	return strings.Join(selected, "\n\n")
@@ -0,0 +1,149 @@
+package keymap
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gti/src/internal/config"
+)
+
+// Action is something a key can be bound to under [keybindings]
+type Action string
+
+const (
+	ActionForceQuit   Action = "force_quit"
+	ActionQuit        Action = "quit"
+	ActionPause       Action = "pause"
+	ActionHelp        Action = "help"
+	ActionRestart     Action = "restart"
+	ActionRepeat      Action = "repeat"
+	ActionSkip        Action = "skip"
+	ActionTTS         Action = "tts"
+	ActionCheatSheet  Action = "cheat_sheet"
+	ActionBack        Action = "back"
+	ActionNext        Action = "next"
+	ActionRetry       Action = "retry"
+	ActionSaveSnippet Action = "save_snippet"
+	ActionSaveCard    Action = "save_card"
+	ActionRerun15     Action = "rerun_15"
+	ActionRerun30     Action = "rerun_30"
+	ActionRerun60     Action = "rerun_60"
+	ActionRerun120    Action = "rerun_120"
+	ActionScrollUp    Action = "scroll_up"
+	ActionScrollDown  Action = "scroll_down"
+	ActionPageUp      Action = "page_up"
+	ActionPageDown    Action = "page_down"
+	ActionStatsQuit   Action = "stats_quit"
+	ActionStatsSwitch Action = "stats_switch"
+	ActionStatsPrev   Action = "stats_prev"
+	ActionStatsNext   Action = "stats_next"
+	ActionStatsExport Action = "stats_export"
+	ActionStatsUp     Action = "stats_up"
+	ActionStatsDown   Action = "stats_down"
+)
+
+// keyLabels spells out keys whose names read badly when merely capitalised
+var keyLabels = map[string]string{
+	" ":      "Space",
+	"pgup":   "PgUp",
+	"pgdown": "PgDn",
+	"up":     "↑",
+	"down":   "↓",
+}
+
+// Bindings resolves key presses to actions, so every screen honours the user's [keybindings]
+type Bindings struct {
+	keys map[Action][]string
+}
+
+func NewBindings(cfg config.KeybindingsConfig) *Bindings {
+	actions := map[Action]string{
+		ActionForceQuit:   cfg.ForceQuit,
+		ActionQuit:        cfg.Quit,
+		ActionPause:       cfg.Pause,
+		ActionHelp:        cfg.Help,
+		ActionRestart:     cfg.Restart,
+		ActionRepeat:      cfg.Repeat,
+		ActionSkip:        cfg.Skip,
+		ActionTTS:         cfg.TTS,
+		ActionCheatSheet:  cfg.CheatSheet,
+		ActionBack:        cfg.Back,
+		ActionNext:        cfg.Next,
+		ActionRetry:       cfg.Retry,
+		ActionSaveSnippet: cfg.SaveSnippet,
+		ActionSaveCard:    cfg.SaveCard,
+		ActionRerun15:     cfg.Rerun15,
+		ActionRerun30:     cfg.Rerun30,
+		ActionRerun60:     cfg.Rerun60,
+		ActionRerun120:    cfg.Rerun120,
+		ActionScrollUp:    cfg.ScrollUp,
+		ActionScrollDown:  cfg.ScrollDown,
+		ActionPageUp:      cfg.PageUp,
+		ActionPageDown:    cfg.PageDown,
+		ActionStatsQuit:   cfg.StatsQuit,
+		ActionStatsSwitch: cfg.StatsSwitch,
+		ActionStatsPrev:   cfg.StatsPrev,
+		ActionStatsNext:   cfg.StatsNext,
+		ActionStatsExport: cfg.StatsExport,
+		ActionStatsUp:     cfg.StatsUp,
+		ActionStatsDown:   cfg.StatsDown,
+	}
+
+	b := &Bindings{keys: make(map[Action][]string)}
+	for action, value := range actions {
+		b.keys[action] = ParseKeys(value)
+	}
+	return b
+}
+
+// ParseKeys splits a comma-separated binding into key names as bubbletea reports them
+func ParseKeys(value string) []string {
+	var keys []string
+	for _, key := range strings.Split(value, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		// Letters are case-sensitive on their own, but modifier and named keys are not
+		if len([]rune(key)) > 1 {
+			key = strings.ToLower(key)
+		}
+		if key == "space" {
+			key = " "
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Is reports whether the key press is bound to the action
+func (b *Bindings) Is(key tea.KeyMsg, action Action) bool {
+	pressed := key.String()
+	for _, k := range b.keys[action] {
+		if k == pressed {
+			return true
+		}
+	}
+	return false
+}
+
+// Label names the action's first key for hints and help text, e.g. "Ctrl+H"; unbound actions read "unbound"
+func (b *Bindings) Label(action Action) string {
+	keys := b.keys[action]
+	if len(keys) == 0 {
+		return "unbound"
+	}
+	if label, ok := keyLabels[keys[0]]; ok {
+		return label
+	}
+
+	parts := strings.Split(keys[0], "+")
+	for i, part := range parts {
+		if label, ok := keyLabels[part]; ok {
+			parts[i] = label
+		} else if part != "" {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, "+")
+}
@@ -0,0 +1,117 @@
+package keymap
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gti/src/assets"
+	"gti/src/internal/config"
+
+	"github.com/BurntSushi/toml"
+)
+
+// qwertyRows and qwertyShifted are what a US QWERTY keyboard types, the positions emulated layouts are given in
+var (
+	qwertyRows    = [4]string{"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./"}
+	qwertyShifted = [4]string{"~!@#$%^&*()_+", "QWERTYUIOP{}|", "ASDFGHJKL:\"", "ZXCVBNM<>?"}
+)
+
+// Emulation remaps keys typed on a US QWERTY keyboard to another layout, so it can be learned
+// without changing the system's layout
+type Emulation struct {
+	Name    string    `toml:"name"`
+	Rows    [4]string `toml:"rows"`
+	Shifted [4]string `toml:"shifted"`
+	remap   map[rune]rune
+}
+
+// LoadEmulation reads the named layout from the user's layouts folder, or else from the built-in ones
+func LoadEmulation(name string) (*Emulation, error) {
+	file := strings.ToLower(name) + ".toml"
+	data, err := os.ReadFile(filepath.Join(config.LayoutsDir, file))
+	if os.IsNotExist(err) {
+		data, err = assets.Layouts.ReadFile("layouts/" + file)
+		if err != nil {
+			return nil, fmt.Errorf("unknown layout '%s' to emulate (available: %s)", name, strings.Join(GetEmulations(), ", "))
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var e Emulation
+	if _, err := toml.Decode(string(data), &e); err != nil {
+		return nil, fmt.Errorf("invalid layout %s: %w", file, err)
+	}
+	if e.Name == "" {
+		e.Name = name
+	}
+	e.remap = make(map[rune]rune)
+	for row := range qwertyRows {
+		for _, pair := range [][2]string{{qwertyRows[row], e.Rows[row]}, {qwertyShifted[row], e.Shifted[row]}} {
+			from, to := []rune(pair[0]), []rune(pair[1])
+			if len(to) != len(from) {
+				return nil, fmt.Errorf("invalid layout %s: row %d has %d keys, a US QWERTY keyboard has %d", file, row+1, len(to), len(from))
+			}
+			for i := range from {
+				e.remap[from[i]] = to[i]
+			}
+		}
+	}
+	return &e, nil
+}
+
+// GetEmulations lists the layouts that can be emulated, built-in and the user's own
+func GetEmulations() []string {
+	seen := make(map[string]bool)
+	add := func(entries []fs.DirEntry) {
+		for _, entry := range entries {
+			if name, ok := strings.CutSuffix(entry.Name(), ".toml"); ok {
+				seen[name] = true
+			}
+		}
+	}
+	builtin, _ := assets.Layouts.ReadDir("layouts")
+	add(builtin)
+	own, _ := os.ReadDir(config.LayoutsDir)
+	add(own)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Remap turns what a US QWERTY keyboard typed into what the emulated layout would have
+func (e *Emulation) Remap(r rune) rune {
+	if to, ok := e.remap[r]; ok {
+		return to
+	}
+	return r
+}
+
+// Press finds where r is on the emulated layout
+func (e *Emulation) Press(r rune) (KeyPress, bool) {
+	switch r {
+	case ' ':
+		return KeyPress{Row: RowSpace, Finger: Thumb}, true
+	case '\n':
+		return KeyPress{Row: 2, Col: len([]rune(e.Rows[2])), Finger: RightPinky, Enter: true}, true
+	}
+	for row := range e.Rows {
+		for shifted, keys := range []string{e.Rows[row], e.Shifted[row]} {
+			for col, k := range []rune(keys) {
+				if k == r {
+					return KeyPress{Row: row, Col: col, Finger: FingerAt("qwerty", row, col), Shift: shifted == 1}, true
+				}
+			}
+		}
+	}
+	return KeyPress{}, false
+}
@@ -0,0 +1,176 @@
+package keymap
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Finger is the finger a key is pressed with when touch typing
+type Finger int
+
+const (
+	LeftPinky Finger = iota
+	LeftRing
+	LeftMiddle
+	LeftIndex
+	RightIndex
+	RightMiddle
+	RightRing
+	RightPinky
+	Thumb
+)
+
+var fingerNames = []string{"left pinky", "left ring", "left middle", "left index", "right index", "right middle", "right ring", "right pinky", "thumb"}
+
+func (f Finger) String() string {
+	return fingerNames[f]
+}
+
+// Left reports whether the finger is on the left hand
+func (f Finger) Left() bool {
+	return f <= LeftIndex
+}
+
+// RowSpace is the row of the space bar, below the four rows of keys
+const RowSpace = 4
+
+// keyboardRows are each layout's unshifted keys, number row first. ISO layouts have an extra key
+// left of the bottom row, which makes that row one key longer.
+var keyboardRows = map[string][4]string{
+	"qwerty":  {"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./"},
+	"uk":      {"`1234567890-=", "qwertyuiop[]", "asdfghjkl;'#", "\\zxcvbnm,./"},
+	"qwertz":  {"^1234567890ß´", "qwertzuiopü+", "asdfghjklöä#", "<yxcvbnm,.-"},
+	"azerty":  {"²&é\"'(-è_çà)=", "azertyuiop^$", "qsdfghjklmù*", "<wxcvbn,;:!"},
+	"spanish": {"º1234567890'¡", "qwertyuiop`+", "asdfghjklñ´ç", "<zxcvbnm,.-"},
+}
+
+// fingerColumns assigns fingers to the keys of each row by position, the same on every layout.
+// The bottom row is listed for ANSI boards; ISO boards put their extra key under the left pinky too.
+var fingerColumns = [4][]Finger{
+	{LeftPinky, LeftPinky, LeftRing, LeftMiddle, LeftIndex, LeftIndex, RightIndex, RightIndex, RightMiddle, RightRing, RightPinky, RightPinky, RightPinky},
+	{LeftPinky, LeftRing, LeftMiddle, LeftIndex, LeftIndex, RightIndex, RightIndex, RightMiddle, RightRing, RightPinky, RightPinky, RightPinky, RightPinky},
+	{LeftPinky, LeftRing, LeftMiddle, LeftIndex, LeftIndex, RightIndex, RightIndex, RightMiddle, RightRing, RightPinky, RightPinky, RightPinky},
+	{LeftPinky, LeftRing, LeftMiddle, LeftIndex, LeftIndex, RightIndex, RightIndex, RightMiddle, RightRing, RightPinky},
+}
+
+// KeyPress is how a character is typed: the key's place on the keyboard, the finger that presses it,
+// and whatever has to be held or pressed with it
+type KeyPress struct {
+	Row, Col int
+	Finger   Finger
+	// Shift and AltGr are held while the key is pressed
+	Shift, AltGr bool
+	// Enter is the Enter key, which sits at the end of the home row
+	Enter bool
+	// Keys spells out anything more involved, such as a dead key, as the cheat sheet would
+	Keys string
+}
+
+// KeyboardRows returns the layout's unshifted keys, number row first
+func KeyboardRows(layout string) [4]string {
+	rows, ok := keyboardRows[layout]
+	if !ok {
+		return keyboardRows["qwerty"]
+	}
+	return rows
+}
+
+// FingerAt is the finger for the key at row and col of the layout
+func FingerAt(layout string, row, col int) Finger {
+	fingers := fingerColumns[row]
+	if row == 3 && len([]rune(KeyboardRows(layout)[3])) > len(fingers) {
+		// The ISO key left of the bottom row shifts the rest one place right
+		if col == 0 {
+			return LeftPinky
+		}
+		col--
+	}
+	return fingers[min(col, len(fingers)-1)]
+}
+
+// Press finds how r is typed on the layout; ok is false when the layout has no key for it
+func Press(layout string, r rune) (KeyPress, bool) {
+	switch r {
+	case ' ':
+		return KeyPress{Row: RowSpace, Finger: Thumb}, true
+	case '\n':
+		return KeyPress{Row: 2, Col: len([]rune(KeyboardRows(layout)[2])), Finger: RightPinky, Enter: true}, true
+	}
+
+	if press, ok := findKey(layout, r); ok {
+		return press, true
+	}
+	if lower := unicode.ToLower(r); lower != r {
+		if press, ok := findKey(layout, lower); ok {
+			press.Shift = true
+			return press, true
+		}
+	}
+
+	// Anything else comes from the cheat sheet table, as modifiers plus a key, possibly after a dead key
+	keys, ok := Locate(layout, r)
+	if !ok || keys == NotOnLayout {
+		return KeyPress{}, false
+	}
+	key := keys
+	if before, _, found := strings.Cut(key, " ("); found {
+		key = before
+	}
+	if before, _, found := strings.Cut(key, " then "); found {
+		key = before
+	}
+	press := KeyPress{Keys: keys}
+	for {
+		if rest, found := strings.CutPrefix(key, "Shift+"); found && rest != "" {
+			press.Shift, key = true, rest
+		} else if rest, found := strings.CutPrefix(key, "AltGr+"); found && rest != "" {
+			press.AltGr, key = true, rest
+		} else {
+			break
+		}
+	}
+	base := []rune(strings.ToLower(key))
+	if len(base) != 1 {
+		return KeyPress{}, false
+	}
+	found, ok := findKey(layout, base[0])
+	if !ok {
+		return KeyPress{}, false
+	}
+	press.Row, press.Col, press.Finger = found.Row, found.Col, found.Finger
+	return press, true
+}
+
+// findKey looks for an unshifted key that types r
+func findKey(layout string, r rune) (KeyPress, bool) {
+	for row, keys := range KeyboardRows(layout) {
+		for col, k := range []rune(keys) {
+			if k == r {
+				return KeyPress{Row: row, Col: col, Finger: FingerAt(layout, row, col)}, true
+			}
+		}
+	}
+	return KeyPress{}, false
+}
+
+// rowNames name the rows --rows picks from, in KeyboardRows order
+var rowNames = []string{"number", "top", "home", "bottom"}
+
+// RowKeys returns the keys on the rows named in spec, joined with "+" as in "top+home"
+func RowKeys(rows [4]string, spec string) (string, error) {
+	var keys strings.Builder
+	for _, name := range strings.Split(strings.ToLower(spec), "+") {
+		row := -1
+		for i, n := range rowNames {
+			if strings.TrimSpace(name) == n {
+				row = i
+			}
+		}
+		if row < 0 {
+			return "", fmt.Errorf("unknown keyboard row '%s' (rows: %s, joined with +)", name, strings.Join(rowNames, ", "))
+		}
+		keys.WriteString(rows[row])
+	}
+	return keys.String(), nil
+}
@@ -0,0 +1,175 @@
+// Package keymap knows where hard-to-find characters live on common keyboard layouts,
+// and which keys the user has bound to gti's actions.
+package keymap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// NotOnLayout is shown for characters the layout has no key for
+const NotOnLayout = "not on this layout (use a compose key)"
+
+// MaxEntries keeps the cheat sheet to a glanceable size
+const MaxEntries = 16
+
+// Entry is one character and the keys that produce it
+type Entry struct {
+	Char string
+	Keys string
+}
+
+// shiftedNumbers lists what Shift plus the number row gives, for layouts that share it with US
+func shiftedNumbers(symbols string) map[rune]string {
+	keys := make(map[rune]string)
+	for i, r := range []rune(symbols) {
+		keys[r] = fmt.Sprintf("Shift+%d", (i+1)%10)
+	}
+	return keys
+}
+
+func merge(maps ...map[rune]string) map[rune]string {
+	merged := make(map[rune]string)
+	for _, m := range maps {
+		for r, keys := range m {
+			merged[r] = keys
+		}
+	}
+	return merged
+}
+
+// layouts only lists characters that take more than a plain or shifted letter key to reach
+var layouts = map[string]map[rune]string{
+	"qwerty": merge(shiftedNumbers("!@#$%^&*()"), map[rune]string{
+		'~': "Shift+` (left of 1)", '`': "` (left of 1)",
+		'_': "Shift+-", '+': "Shift+=",
+		'{': "Shift+[", '}': "Shift+]", '|': "Shift+\\", '\\': "\\ (above Enter)",
+		':': "Shift+;", '"': "Shift+'", '<': "Shift+,", '>': "Shift+.", '?': "Shift+/",
+	}),
+	"uk": merge(shiftedNumbers("!\"£$%^&*()"), map[rune]string{
+		'€': "AltGr+4", '@': "Shift+' (right of ;)", '#': "# (left of Enter)", '~': "Shift+# (left of Enter)",
+		'`': "` (left of 1)", '¬': "Shift+` (left of 1)", '\\': "\\ (left of Z)", '|': "Shift+\\ (left of Z)",
+		'_': "Shift+-", '+': "Shift+=", '{': "Shift+[", '}': "Shift+]",
+		':': "Shift+;", '<': "Shift+,", '>': "Shift+.", '?': "Shift+/",
+	}),
+	"qwertz": merge(shiftedNumbers("!\"§$%&/()="), map[rune]string{
+		'ß': "ß (right of 0)", '?': "Shift+ß", '\\': "AltGr+ß",
+		'{': "AltGr+7", '[': "AltGr+8", ']': "AltGr+9", '}': "AltGr+0",
+		'@': "AltGr+Q", '€': "AltGr+E", '~': "AltGr++", '|': "AltGr+< (left of Y)",
+		'ä': "ä (right of Ö)", 'ö': "ö (right of L)", 'ü': "ü (right of P)",
+		'+': "+ (right of Ü)", '*': "Shift++", '#': "# (right of Ä)", '\'': "Shift+#",
+		'<': "< (left of Y)", '>': "Shift+<", ';': "Shift+,", ':': "Shift+.",
+		'-': "- (right of .)", '_': "Shift+-", '^': "^ then Space (left of 1)", '°': "Shift+^",
+		'`': "Shift+´ then Space (right of ß)",
+	}),
+	"azerty": map[rune]string{
+		'1': "Shift+&", '2': "Shift+é", '3': "Shift+\"", '4': "Shift+'", '5': "Shift+(",
+		'6': "Shift+-", '7': "Shift+è", '8': "Shift+_", '9': "Shift+ç", '0': "Shift+à",
+		'~': "AltGr+é then Space", '#': "AltGr+\"", '{': "AltGr+'", '[': "AltGr+(", '|': "AltGr+-",
+		'`': "AltGr+è then Space", '\\': "AltGr+_", '@': "AltGr+à", ']': "AltGr+)", '}': "AltGr+=",
+		'€': "AltGr+E", '°': "Shift+)", '+': "Shift+=", '£': "Shift+$", '%': "Shift+ù", 'µ': "Shift+*",
+		'?': "Shift+,", '.': "Shift+;", '/': "Shift+:", '§': "Shift+!", '>': "Shift+<",
+		'ê': "^ then E", 'â': "^ then A", 'î': "^ then I", 'ô': "^ then O", 'û': "^ then U",
+		'ë': "Shift+^ then E", 'ï': "Shift+^ then I",
+	},
+	"spanish": merge(shiftedNumbers("!\"·$%&/()="), map[rune]string{
+		'ñ': "ñ (right of L)", '¡': "¡ (right of ')", '¿': "Shift+¡", '?': "Shift+' (right of 0)",
+		'|': "AltGr+1", '@': "AltGr+2", '#': "AltGr+3", '~': "AltGr+4", '€': "AltGr+E", '¬': "AltGr+6",
+		'[': "AltGr+` (right of P)", ']': "AltGr++", '{': "AltGr+´ (right of Ñ)", '}': "AltGr+ç",
+		'\\': "AltGr+º (left of 1)", 'º': "º (left of 1)", 'ª': "Shift+º",
+		'+': "+ (right of `)", '*': "Shift++", 'ç': "ç (right of ´)",
+		'á': "´ then A", 'é': "´ then E", 'í': "´ then I", 'ó': "´ then O", 'ú': "´ then U", 'ü': "Shift+´ then U",
+		'^': "Shift+` then Space", '_': "Shift+-", ';': "Shift+,", ':': "Shift+.", '<': "< (left of Z)", '>': "Shift+<",
+	}),
+}
+
+// languageChars are shown for a language even before they turn up in the text
+var languageChars = map[string]string{
+	"spanish":    "ñáéíóú¿¡",
+	"german":     "äöüß€",
+	"french":     "éèêàçù€",
+	"portuguese": "ãõçáéê",
+	"italian":    "àèéìòù",
+	"swedish":    "åäö",
+	"norwegian":  "æøå",
+	"danish":     "æøå",
+	"finnish":    "äö",
+	"polish":     "ąćęłńóśźż",
+	"czech":      "ěščřžýáíé",
+	"hungarian":  "áéíóöőúüű",
+	"turkish":    "çğıöşü",
+}
+
+// commonSymbols fill the sheet when the text has nothing unusual in it
+const commonSymbols = "{}[]@#~\\|€"
+
+// ValidateLayout checks the keyboard layout is known and returns error if not
+func ValidateLayout(layout string) error {
+	if _, exists := layouts[layout]; !exists {
+		return fmt.Errorf("unsupported keyboard layout '%s' (supported: %s)", layout, strings.Join(GetSupportedLayouts(), ", "))
+	}
+	return nil
+}
+
+// GetSupportedLayouts returns a list of supported keyboard layouts
+func GetSupportedLayouts() []string {
+	names := make([]string, 0, len(layouts))
+	for name := range layouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Locate tells where a character lives on the layout; ok is false when it needs no help finding
+func Locate(layout string, r rune) (keys string, ok bool) {
+	table, exists := layouts[layout]
+	if !exists {
+		table = layouts["qwerty"]
+	}
+	if keys, found := table[r]; found {
+		return keys, true
+	}
+	if lower := unicode.ToLower(r); lower != r {
+		if keys, found := table[lower]; found {
+			// A dead key is pressed as usual; it is the letter after it that takes Shift
+			if strings.Contains(keys, " then ") {
+				return strings.Replace(keys, " then ", " then Shift+", 1), true
+			}
+			return "Shift+" + keys, true
+		}
+	}
+	if r > unicode.MaxASCII {
+		return NotOnLayout, true
+	}
+	return "", false
+}
+
+// CheatSheet picks the hard-to-find characters of the text and language and says where each one is
+func CheatSheet(layout, language, text string) []Entry {
+	var entries []Entry
+	seen := make(map[rune]bool)
+	add := func(chars string) {
+		for _, r := range chars {
+			if seen[r] || len(entries) >= MaxEntries || unicode.IsSpace(r) {
+				continue
+			}
+			seen[r] = true
+			if keys, ok := Locate(layout, r); ok {
+				entries = append(entries, Entry{Char: string(r), Keys: keys})
+			}
+		}
+	}
+
+	add(text)
+	// A mixed language setting, such as english,spanish, adds the characters of each
+	for _, lang := range strings.Split(language, ",") {
+		add(languageChars[strings.TrimSpace(lang)])
+	}
+	if len(entries) == 0 {
+		add(commonSymbols)
+	}
+	return entries
+}
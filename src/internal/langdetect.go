@@ -0,0 +1,158 @@
+package internal
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// AutoLanguage is the --language value that picks the word list by sampling the text being typed
+const AutoLanguage = "auto"
+
+// scriptLanguages maps non-Latin scripts to the word list written in them
+var scriptLanguages = []struct {
+	table    *unicode.RangeTable
+	language string
+}{
+	{unicode.Arabic, "arabic"},
+	{unicode.Hebrew, "hebrew"},
+	{unicode.Hiragana, "japanese"},
+	{unicode.Katakana, "japanese"},
+	{unicode.Han, "chinese"},
+	{unicode.Hangul, "korean"},
+	{unicode.Thai, "thai"},
+	{unicode.Cyrillic, "russian"},
+	{unicode.Greek, "greek"},
+	{unicode.Devanagari, "hindi"},
+}
+
+var rtlLanguages = map[string]bool{
+	"arabic": true,
+	"hebrew": true,
+}
+
+var diacriticLanguages = map[string]bool{
+	"spanish":    true,
+	"french":     true,
+	"german":     true,
+	"italian":    true,
+	"portuguese": true,
+	"czech":      true,
+	"danish":     true,
+	"finnish":    true,
+	"hungarian":  true,
+	"norwegian":  true,
+	"polish":     true,
+	"swedish":    true,
+	"turkish":    true,
+}
+
+// IsRTLLanguage reports whether the language is written right to left
+func IsRTLLanguage(language string) bool {
+	// Mixed languages are laid out right to left only when all of them are written that way
+	for _, lang := range Languages(language) {
+		if !rtlLanguages[lang] {
+			return false
+		}
+	}
+	return true
+}
+
+// HasDiacritics reports whether the language's words, or those of any language it mixes, commonly
+// carry accented letters
+func HasDiacritics(language string) bool {
+	for _, lang := range Languages(language) {
+		if diacriticLanguages[lang] {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectLanguage picks the supported word list closest to the sample text. Non-Latin scripts
+// decide on their own; Latin text is matched by how many of its words appear in each list.
+func DetectLanguage(sample string) string {
+	scriptCounts := make(map[string]int)
+	letters := 0
+	for _, r := range sample {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, script := range scriptLanguages {
+			if unicode.Is(script.table, r) {
+				scriptCounts[script.language]++
+				break
+			}
+		}
+	}
+
+	// Kanji are shared with Chinese, so any kana makes the text Japanese
+	if scriptCounts["japanese"] > 0 {
+		scriptCounts["japanese"] += scriptCounts["chinese"]
+		delete(scriptCounts, "chinese")
+	}
+
+	bestScript, bestCount := "", 0
+	for language, count := range scriptCounts {
+		if count > bestCount {
+			bestScript, bestCount = language, count
+		}
+	}
+	if letters > 0 && bestCount*2 > letters {
+		return bestScript
+	}
+
+	return detectLatinLanguage(sample)
+}
+
+func detectLatinLanguage(sample string) string {
+	words := strings.FieldsFunc(strings.ToLower(sample), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+
+	languages := make([]string, 0, len(languageFiles))
+	for language := range languageFiles {
+		if language != "random" && language != "english" {
+			languages = append(languages, language)
+		}
+	}
+	sort.Strings(languages)
+
+	// English goes first so it wins ties, including text that matches nothing
+	best, bestScore := "english", -1
+	for _, language := range append([]string{"english"}, languages...) {
+		known := make(map[string]bool)
+		for _, word := range loadWords(language) {
+			known[strings.ToLower(word)] = true
+		}
+
+		score := 0
+		for _, word := range words {
+			if known[word] {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = language, score
+		}
+	}
+	return best
+}
+
+// DetectFileLanguage samples the start of a text file, or of standard input for "-", and picks the closest word list
+func DetectFileLanguage(filename string) (string, error) {
+	data, err := ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	const sampleSize = 4096
+	sample := string(data)
+	if IsMarkdownFile(filename) {
+		sample = StripMarkdown(sample)
+	}
+	if len(sample) > sampleSize {
+		sample = sample[:sampleSize]
+	}
+	return DetectLanguage(sample), nil
+}
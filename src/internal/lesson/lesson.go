@@ -0,0 +1,176 @@
+package lesson
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"gti/src/internal"
+	"gti/src/internal/keymap"
+)
+
+// position is a key's place on the keyboard, row 0 being the number row
+type position struct {
+	row, col int
+}
+
+// stage is one step of the lesson plan. Keys are given by position, so every layout is taught
+// in the same order: home row first, then the top and bottom rows, then the far keys.
+type stage struct {
+	name string
+	keys []position
+}
+
+func row(r int, cols ...int) []position {
+	keys := make([]position, len(cols))
+	for i, c := range cols {
+		keys[i] = position{r, c}
+	}
+	return keys
+}
+
+var stages = []stage{
+	{"Home row: index and middle fingers", row(2, 2, 3, 6, 7)},
+	{"Home row: ring and little fingers", row(2, 0, 1, 8, 9)},
+	{"Home row: index reaches", row(2, 4, 5)},
+	{"Top row: index fingers", row(1, 3, 4, 5, 6)},
+	{"Top row: middle and ring fingers", row(1, 1, 2, 7, 8)},
+	{"Top row: little fingers", row(1, 0, 9)},
+	{"Bottom row: index fingers", row(3, 3, 4, 5, 6)},
+	{"Bottom row: the rest", row(3, 0, 1, 2, 7, 8, 9)},
+	{"Far little-finger keys", append(row(2, 10), row(1, 10)...)},
+	{"Number row", row(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10)},
+}
+
+// Rounds is how many rounds of words a lesson run is made of
+const Rounds = 3
+
+// minDictionaryWords is how many dictionary words a lesson needs before it mixes them in; with fewer,
+// the keys are practised as letter groups alone
+const minDictionaryWords = 8
+
+// Lesson is one step of a layout's plan
+type Lesson struct {
+	Number int
+	Name   string
+	// Keys are the characters the lesson introduces and Fingers the finger for each
+	Keys    []rune
+	Fingers []keymap.Finger
+	// Known is every character typed in the lesson: its own keys and those of the lessons before it
+	Known []rune
+}
+
+// Plan is the lesson set of one layout
+type Plan struct {
+	Layout string
+	// Emulated marks a layout typed through emulation rather than set up in the system
+	Emulated bool
+	Lessons  []Lesson
+}
+
+// PlanFor builds the lessons of a keyboard layout (qwerty, uk, ...) or of a layout to emulate (colemak, ...)
+func PlanFor(layout string) (*Plan, error) {
+	plan := &Plan{Layout: strings.ToLower(layout)}
+	var rows [4]string
+	fingerLayout := plan.Layout
+	if keymap.ValidateLayout(plan.Layout) == nil {
+		rows = keymap.KeyboardRows(plan.Layout)
+	} else {
+		emulation, err := keymap.LoadEmulation(plan.Layout)
+		if err != nil {
+			return nil, fmt.Errorf("no lessons for '%s': it is neither a keyboard layout (%s) nor a layout to emulate (%s)",
+				layout, strings.Join(keymap.GetSupportedLayouts(), ", "), strings.Join(keymap.GetEmulations(), ", "))
+		}
+		// Emulated layouts are laid over a US QWERTY keyboard
+		rows, fingerLayout, plan.Emulated = emulation.Rows, "qwerty", true
+	}
+
+	seen := make(map[rune]bool)
+	var known []rune
+	for _, st := range stages {
+		l := Lesson{Number: len(plan.Lessons) + 1, Name: st.name}
+		for _, pos := range st.keys {
+			keys := []rune(rows[pos.row])
+			col := pos.col
+			if pos.row == 3 && len(keys) > 10 {
+				// ISO boards have an extra key left of the bottom row
+				col++
+			}
+			if col >= len(keys) || seen[keys[col]] || !unicode.IsPrint(keys[col]) {
+				continue
+			}
+			seen[keys[col]] = true
+			l.Keys = append(l.Keys, keys[col])
+			l.Fingers = append(l.Fingers, keymap.FingerAt(fingerLayout, pos.row, col))
+		}
+		if len(l.Keys) == 0 {
+			continue
+		}
+		known = append(known, l.Keys...)
+		l.Known = append([]rune(nil), known...)
+		plan.Lessons = append(plan.Lessons, l)
+	}
+	return plan, nil
+}
+
+// Keys lists every key the plan teaches
+func (p *Plan) Keys() []rune {
+	if len(p.Lessons) == 0 {
+		return nil
+	}
+	return p.Lessons[len(p.Lessons)-1].Known
+}
+
+// Guidance names the lesson's new keys and the finger for each, shown while it is typed
+func (l Lesson) Guidance() string {
+	keys := make([]string, len(l.Keys))
+	for i, k := range l.Keys {
+		keys[i] = fmt.Sprintf("%c (%s)", k, l.Fingers[i])
+	}
+	return fmt.Sprintf("Lesson %d, %s: %s", l.Number, l.Name, strings.Join(keys, ", "))
+}
+
+// Text makes a round of practice: dictionary words written with the known keys that use at least
+// one new key, mixed with letter groups that always do
+func (l Lesson) Text(words int, language string) string {
+	known := make(map[rune]bool, len(l.Known))
+	for _, k := range l.Known {
+		known[k] = true
+	}
+	isNew := make(map[rune]bool, len(l.Keys))
+	for _, k := range l.Keys {
+		isNew[k] = true
+	}
+
+	var dictionary []string
+	seen := make(map[string]bool)
+	for _, w := range internal.GetWordList(language) {
+		usesNew, fits := false, true
+		for _, r := range w {
+			if !known[r] {
+				fits = false
+				break
+			}
+			usesNew = usesNew || isNew[r]
+		}
+		if fits && usesNew && !seen[w] {
+			seen[w] = true
+			dictionary = append(dictionary, w)
+		}
+	}
+
+	picked := make([]string, words)
+	for i := range picked {
+		if len(dictionary) >= minDictionaryWords && internal.Intn(10) < 6 {
+			picked[i] = dictionary[internal.Intn(len(dictionary))]
+			continue
+		}
+		group := make([]rune, 3+internal.Intn(3))
+		for j := range group {
+			group[j] = l.Known[internal.Intn(len(l.Known))]
+		}
+		group[internal.Intn(len(group))] = l.Keys[internal.Intn(len(l.Keys))]
+		picked[i] = string(group)
+	}
+	return strings.Join(picked, " ")
+}
@@ -0,0 +1,131 @@
+package lesson
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gti/src/internal/config"
+	"gti/src/internal/session"
+)
+
+// A lesson is passed once each of its new keys has been typed at least MinPresses times in a run
+// with at least MinKeyAccuracy percent of them right. Speed does not count.
+const (
+	MinPresses     = 10
+	MinKeyAccuracy = 90.0
+)
+
+// Progress is how far each layout's lessons have got
+type Progress struct {
+	Layouts map[string]*LayoutProgress `json:"layouts"`
+}
+
+// LayoutProgress is the lessons passed on one layout and every press of its keys across lessons
+type LayoutProgress struct {
+	Completed int                        `json:"completed"`
+	Keys      map[string]session.KeyStat `json:"keys"`
+}
+
+func progressFile() string {
+	return filepath.Join(config.ConfigDir, "lesson_progress.json")
+}
+
+// LoadProgress reads the lesson progress, starting afresh when there is none
+func LoadProgress() *Progress {
+	progress := &Progress{}
+	if _, err := os.Stat(progressFile()); err == nil {
+		config.LoadJSONData(progressFile(), progress)
+	}
+	if progress.Layouts == nil {
+		progress.Layouts = make(map[string]*LayoutProgress)
+	}
+	return progress
+}
+
+func SaveProgress(progress *Progress) error {
+	return config.SaveJSONData(progressFile(), progress)
+}
+
+// Layout returns the progress on one layout, creating it on first use
+func (p *Progress) Layout(name string) *LayoutProgress {
+	lp := p.Layouts[name]
+	if lp == nil {
+		lp = &LayoutProgress{}
+		p.Layouts[name] = lp
+	}
+	if lp.Keys == nil {
+		lp.Keys = make(map[string]session.KeyStat)
+	}
+	return lp
+}
+
+// Covered counts the keys typed at least MinPresses times at MinKeyAccuracy over all lessons
+func (lp *LayoutProgress) Covered(keys []rune) int {
+	covered := 0
+	for _, k := range keys {
+		if keyPassed(lp.Keys[string(k)]) {
+			covered++
+		}
+	}
+	return covered
+}
+
+// Next is the first lesson not yet passed, or the last lesson once all are
+func (p *Plan) Next(progress *Progress) int {
+	return min(progress.Layout(p.Layout).Completed+1, len(p.Lessons))
+}
+
+func keyPassed(stat session.KeyStat) bool {
+	return stat.Typed >= MinPresses && keyAccuracy(stat) >= MinKeyAccuracy
+}
+
+func keyAccuracy(stat session.KeyStat) float64 {
+	return session.CalculateAccuracy(stat.Typed, stat.Errors)
+}
+
+// Check grades runs of the lesson: the presses of the plan's keys are added to the layout's
+// coverage, and the lesson is marked passed when every new key met the bar in the run
+func (p *Plan) Check(l Lesson) session.LessonCheck {
+	return func(keyStats map[string]session.KeyStat) string {
+		progress := LoadProgress()
+		lp := progress.Layout(p.Layout)
+		for _, k := range p.Keys() {
+			run, ok := keyStats[string(k)]
+			if !ok {
+				continue
+			}
+			total := lp.Keys[string(k)]
+			total.Typed += run.Typed
+			total.Errors += run.Errors
+			lp.Keys[string(k)] = total
+		}
+
+		var short []string
+		for _, k := range l.Keys {
+			stat := keyStats[string(k)]
+			if !keyPassed(stat) {
+				short = append(short, fmt.Sprintf("%c %d at %.0f%%", k, stat.Typed, keyAccuracy(stat)))
+			}
+		}
+		if len(short) == 0 {
+			lp.Completed = max(lp.Completed, l.Number)
+		}
+		if err := SaveProgress(progress); err != nil {
+			return "Could not save lesson progress: " + err.Error()
+		}
+
+		coverage := fmt.Sprintf("Key coverage on %s: %d/%d keys", p.Layout, lp.Covered(p.Keys()), len(p.Keys()))
+		switch {
+		case len(short) > 0:
+			return fmt.Sprintf("Lesson %d not passed yet: each new key needs %d presses at %.0f%% accuracy (%s)\n%s",
+				l.Number, MinPresses, MinKeyAccuracy, strings.Join(short, ", "), coverage)
+		case l.Number == len(p.Lessons):
+			return fmt.Sprintf("Lesson %d passed, the last one for %s!\n%s", l.Number, p.Layout, coverage)
+		default:
+			next := p.Lessons[l.Number]
+			return fmt.Sprintf("Lesson %d passed! Up next: lesson %d, %s\n%s", l.Number, next.Number, next.Name, coverage)
+		}
+	}
+}
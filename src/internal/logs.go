@@ -0,0 +1,185 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var logKinds = map[string]func(time.Time) string{
+	"app":    appLogBlock,
+	"access": accessLogBlock,
+	"system": systemLogBlock,
+	"trace":  stackTraceBlock,
+}
+
+var logLevels = []string{"INFO", "INFO", "INFO", "DEBUG", "WARN", "ERROR"}
+var logMethods = []string{"GET", "GET", "GET", "POST", "PUT", "DELETE", "PATCH"}
+var logPaths = []string{"/v1/users/%d", "/v1/invoices/%d", "/api/orders/%d/items", "/healthz", "/v2/search?q=%d", "/internal/jobs/%d/retry"}
+var logStatuses = []int{200, 200, 200, 201, 204, 301, 400, 401, 404, 409, 429, 500, 502, 503}
+var logUserAgents = []string{"curl/8.4.0", "Mozilla/5.0 (X11; Linux x86_64)", "Go-http-client/1.1", "python-requests/2.31.0", "kube-probe/1.29"}
+var logMessages = []string{
+	"request completed",
+	"cache miss, falling back to database",
+	"retrying upstream call",
+	"connection pool exhausted",
+	"slow query detected",
+	"job enqueued",
+	"token refreshed",
+	"circuit breaker opened",
+}
+var logErrors = []string{
+	"context deadline exceeded",
+	"connection reset by peer",
+	"dial tcp 10.0.3.17:5432: connect: connection refused",
+	"duplicate key value violates unique constraint \"users_email_key\"",
+	"unexpected EOF",
+}
+
+func hexID(n int) string {
+	const digits = "0123456789abcdef"
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = digits[rng.Intn(len(digits))]
+	}
+	return string(b)
+}
+
+func logPath() string {
+	path := pick(logPaths)
+	if strings.Contains(path, "%d") {
+		path = fmt.Sprintf(path, rng.Intn(99000)+1000)
+	}
+	return path
+}
+
+// advance moves a log clock forward by a few milliseconds to a couple of seconds
+func advance(t time.Time) time.Time {
+	return t.Add(time.Duration(rng.Intn(2500000)+3000) * time.Microsecond)
+}
+
+func appLogBlock(t time.Time) string {
+	service := pick(docServiceNames)
+	traceID := hexID(32)
+	var lines []string
+	for i := 0; i < rng.Intn(3)+3; i++ {
+		t = advance(t)
+		level := pick(logLevels)
+		line := fmt.Sprintf("%s %-5s [%s] %s trace_id=%s", t.Format("2006-01-02T15:04:05.000Z"), level, service, pick(logMessages), traceID)
+		switch level {
+		case "ERROR", "WARN":
+			line += fmt.Sprintf(" error=%q", pick(logErrors))
+		default:
+			line += fmt.Sprintf(" method=%s path=%s duration_ms=%d", pick(logMethods), logPath(), rng.Intn(900)+1)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func accessLogBlock(t time.Time) string {
+	var lines []string
+	for i := 0; i < rng.Intn(3)+3; i++ {
+		t = advance(t)
+		lines = append(lines, fmt.Sprintf("%d.%d.%d.%d - - [%s] \"%s %s HTTP/1.1\" %d %d \"-\" \"%s\"",
+			rng.Intn(223)+1, rng.Intn(256), rng.Intn(256), rng.Intn(254)+1,
+			t.Format("02/Jan/2006:15:04:05 -0700"), pick(logMethods), logPath(),
+			pickStatus(), rng.Intn(48000)+120, pick(logUserAgents)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func pickStatus() int {
+	return logStatuses[rng.Intn(len(logStatuses))]
+}
+
+func systemLogBlock(t time.Time) string {
+	node := fmt.Sprintf("node-%d", rng.Intn(12)+1)
+	pid := rng.Intn(30000) + 300
+	pod := fmt.Sprintf("%s-%s-%s", pick(docServiceNames), hexID(10), hexID(5))
+	var lines []string
+	for i := 0; i < rng.Intn(3)+3; i++ {
+		t = advance(t)
+		lines = append(lines, fmt.Sprintf("%s %s kubelet[%d]: E%s %d pod_workers.go:%d] \"Error syncing pod\" err=%q pod=\"default/%s\"",
+			t.Format("Jan _2 15:04:05"), node, pid, t.Format("0102 15:04:05.000000"), pid, rng.Intn(2000)+100,
+			pick(logErrors), pod))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func stackTraceBlock(t time.Time) string {
+	switch rng.Intn(3) {
+	case 0:
+		return fmt.Sprintf(`panic: runtime error: invalid memory address or nil pointer dereference
+[signal SIGSEGV: segmentation violation code=0x1 addr=0x%s pc=0x%s]
+
+goroutine %d [running]:
+main.(*Handler).ServeHTTP(0xc000%s, {0x%s, 0xc000%s}, 0xc000%s)
+    /app/internal/http/handler.go:%d +0x%s
+net/http.serverHandler.ServeHTTP({0xc000%s}, {0x%s, 0xc000%s}, 0xc000%s)
+    /usr/local/go/src/net/http/server.go:%d +0x%s`,
+			hexID(2), hexID(6), rng.Intn(900)+1, hexID(6), hexID(6), hexID(6), hexID(6), rng.Intn(300)+20, hexID(2),
+			hexID(6), hexID(6), hexID(6), hexID(6), rng.Intn(400)+3000, hexID(3))
+	case 1:
+		pkg := pick([]string{"billing", "orders", "payments", "inventory"})
+		return fmt.Sprintf(`%s ERROR [main] c.e.%s.OrderService - Failed to process order %d
+java.lang.IllegalStateException: Order %d already settled
+    at com.example.%s.OrderService.settle(OrderService.java:%d)
+    at com.example.%s.OrderController.checkout(OrderController.java:%d)
+    at java.base/jdk.internal.reflect.NativeMethodAccessorImpl.invoke0(Native Method)
+Caused by: java.sql.SQLException: %s
+    ... %d more`,
+			t.Format("2006-01-02 15:04:05.000"), pkg, rng.Intn(99000)+1000, rng.Intn(99000)+1000,
+			pkg, rng.Intn(400)+20, pkg, rng.Intn(200)+20, pick(logErrors), rng.Intn(40)+3)
+	default:
+		return fmt.Sprintf(`Traceback (most recent call last):
+  File "/srv/app/worker.py", line %d, in run
+    result = self.handle(job)
+  File "/srv/app/jobs/%s.py", line %d, in handle
+    payload = json.loads(job["body"])
+KeyError: 'body' (job_id=%s)`,
+			rng.Intn(200)+10, strings.ReplaceAll(pick(docServiceNames), "-", "_"), rng.Intn(120)+5, hexID(12))
+	}
+}
+
+// GenerateLogs returns blocks of log lines or stack traces of the given kind ("mixed" draws from all of them).
+// Timestamps, IDs and numbers are randomized so the text never repeats exactly.
+func GenerateLogs(count int, kind string) string {
+	kinds := GetSupportedLogKinds()
+	t := time.Now().Add(-time.Duration(rng.Intn(72)) * time.Hour).UTC()
+
+	var blocks []string
+	for i := 0; i < count; i++ {
+		k := kind
+		if k == "mixed" {
+			k = kinds[rng.Intn(len(kinds))]
+		}
+		blocks = append(blocks, logKinds[k](t))
+		t = t.Add(time.Duration(rng.Intn(600)+5) * time.Second)
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+func IsLogKindSupported(kind string) bool {
+	_, exists := logKinds[kind]
+	return exists || kind == "mixed"
+}
+
+// ValidateLogKind checks if the log kind is supported and returns error if not
+func ValidateLogKind(kind string) error {
+	if !IsLogKindSupported(kind) {
+		return fmt.Errorf("unsupported log kind '%s'", kind)
+	}
+	return nil
+}
+
+// GetSupportedLogKinds returns a list of supported log kinds
+func GetSupportedLogKinds() []string {
+	kinds := make([]string, 0, len(logKinds))
+	for kind := range logKinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
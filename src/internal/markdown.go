@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// escapedBase is where escaped ASCII characters are parked while the emphasis around them is removed
+const escapedBase = 0xE000
+
+var (
+	mdFence      = regexp.MustCompile("^\\s*(```+|~~~+)")
+	mdHeading    = regexp.MustCompile(`^\s{0,3}#{1,6}(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+	mdRule       = regexp.MustCompile(`^\s{0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,}|=+\s*|-+\s*)$`)
+	mdQuote      = regexp.MustCompile(`^\s{0,3}(?:>\s?)+`)
+	mdListItem   = regexp.MustCompile(`^\s*(?:[-*+]|\d{1,9}[.)])\s+(?:\[[ xX]\]\s+)?`)
+	mdTableRule  = regexp.MustCompile(`^\s*\|?(?:\s*:?-+:?\s*\|)+\s*(?::?-+:?\s*)?$`)
+	mdLinkDef    = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s+\S+`)
+	mdImage      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink       = regexp.MustCompile(`\[([^\]]+)\](?:\([^)]*\)|\[[^\]]*\])`)
+	mdAutolink   = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	mdHTMLTag    = regexp.MustCompile(`</?[a-zA-Z][^>]*>|<!--.*?-->`)
+	mdCodeSpan   = regexp.MustCompile("(`+)\\s?(.+?)\\s?(`+)")
+	mdStrong     = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|__(\S(?:.*?\S)?)__`)
+	mdEmphasis   = regexp.MustCompile(`\*(\S(?:[^*]*?\S)?)\*`)
+	mdUnderscore = regexp.MustCompile(`(^|[^\pL\pN_])_(\S(?:[^_]*?\S)?)_($|[^\pL\pN_])`)
+	mdStrike     = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+	mdEscape     = regexp.MustCompile("\\\\([\\\\`*_{}\\[\\]()#+\\-.!|>~<])")
+)
+
+// IsMarkdownFile reports whether name is a Markdown file by its extension
+func IsMarkdownFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".md", ".markdown", ".mdown", ".mkd":
+		return true
+	}
+	return false
+}
+
+// StripMarkdown turns Markdown into the plain text it reads as: heading markers, list bullets,
+// quote markers, emphasis, links and inline HTML go, leaving their text, and code blocks, rules
+// and front matter are dropped. Wrapped lines are joined, so each paragraph, heading, list item
+// and table row comes out on a line of its own with a blank line between them.
+func StripMarkdown(text string) string {
+	var blocks, paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, strings.Join(paragraph, " "))
+			paragraph = nil
+		}
+	}
+	emit := func(line string) {
+		flush()
+		if line = strings.TrimSpace(line); line != "" {
+			blocks = append(blocks, line)
+		}
+	}
+
+	lines := markdownLines(text)
+	fence := ""
+	for _, line := range lines {
+		if fence != "" {
+			if closesFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if m := mdFence.FindStringSubmatch(line); m != nil {
+			flush()
+			fence = m[1]
+			continue
+		}
+
+		line = mdQuote.ReplaceAllString(line, "")
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+		case mdRule.MatchString(line), mdLinkDef.MatchString(line), mdTableRule.MatchString(line):
+			flush()
+		case mdHeading.MatchString(line):
+			emit(stripInline(mdHeading.FindStringSubmatch(line)[1]))
+		case strings.HasPrefix(strings.TrimSpace(line), "|"):
+			emit(stripInline(tableRow(line)))
+		case mdListItem.MatchString(line):
+			flush()
+			paragraph = append(paragraph, stripInline(mdListItem.ReplaceAllString(line, "")))
+		default:
+			paragraph = append(paragraph, stripInline(line))
+		}
+	}
+	flush()
+	return strings.Join(blocks, "\n\n")
+}
+
+// MarkdownCode keeps only the fenced code blocks of a Markdown file, verbatim and separated by blank lines,
+// for practising the code of a README or tutorial
+func MarkdownCode(text string) string {
+	var blocks, block []string
+	fence := ""
+	for _, line := range markdownLines(text) {
+		if fence == "" {
+			if m := mdFence.FindStringSubmatch(line); m != nil {
+				fence = m[1]
+			}
+			continue
+		}
+		if closesFence(line, fence) {
+			if code := strings.Trim(strings.Join(block, "\n"), "\n"); code != "" {
+				blocks = append(blocks, code)
+			}
+			fence, block = "", nil
+			continue
+		}
+		block = append(block, line)
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// markdownLines splits text into lines, leaving out YAML or TOML front matter at the top
+func markdownLines(text string) []string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if len(lines) > 0 {
+		if marker := strings.TrimSpace(lines[0]); marker == "---" || marker == "+++" {
+			for i := 1; i < len(lines); i++ {
+				if end := strings.TrimSpace(lines[i]); end == marker || (marker == "---" && end == "...") {
+					return lines[i+1:]
+				}
+			}
+		}
+	}
+	return lines
+}
+
+// closesFence reports whether line ends a code block opened with fence: the same character, at least as many times, and nothing else
+func closesFence(line, fence string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, fence) && strings.Trim(line, fence[:1]) == ""
+}
+
+// tableRow joins the cells of a table row with spaces
+func tableRow(line string) string {
+	cells := strings.Split(strings.Trim(strings.TrimSpace(line), "|"), "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(cell)
+	}
+	return strings.Join(cells, " ")
+}
+
+// stripInline removes the inline markup of one line. Code spans keep their text as written,
+// so underscores and asterisks inside them are not mistaken for emphasis.
+func stripInline(line string) string {
+	line = mdImage.ReplaceAllString(line, "$1")
+	line = mdLink.ReplaceAllString(line, "$1")
+	line = mdAutolink.ReplaceAllString(line, "$1")
+
+	var out strings.Builder
+	last := 0
+	for _, span := range mdCodeSpan.FindAllStringSubmatchIndex(line, -1) {
+		// Only a closing run as long as the opening one ends the span
+		if span[3]-span[2] != span[7]-span[6] {
+			continue
+		}
+		out.WriteString(stripEmphasis(line[last:span[0]]))
+		out.WriteString(line[span[4]:span[5]])
+		last = span[1]
+	}
+	out.WriteString(stripEmphasis(line[last:]))
+	return strings.Join(strings.Fields(out.String()), " ")
+}
+
+// stripEmphasis removes inline HTML and emphasis. Escaped characters are set aside in the
+// private use area first, so \* stays a literal asterisk.
+func stripEmphasis(text string) string {
+	text = mdEscape.ReplaceAllStringFunc(text, func(escaped string) string {
+		return string(escapedBase + rune(escaped[1]))
+	})
+	text = mdHTMLTag.ReplaceAllString(text, "")
+	text = mdStrong.ReplaceAllString(text, "$1$2")
+	text = mdEmphasis.ReplaceAllString(text, "$1")
+	text = mdUnderscore.ReplaceAllString(text, "$1$2$3")
+	text = mdStrike.ReplaceAllString(text, "$1")
+	return strings.Map(func(r rune) rune {
+		if r >= escapedBase && r < escapedBase+128 {
+			return r - escapedBase
+		}
+		return r
+	}, text)
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// numberForms are the shapes digits take in everyday text; each returns one token
+var numberForms = []func() string{
+	func() string { return fmt.Sprint(rng.Intn(10000)) },
+	func() string { return fmt.Sprintf("%d%d%d%d", rng.Intn(10), rng.Intn(10), rng.Intn(10), rng.Intn(10)) },
+	func() string { return fmt.Sprintf("%d.%02d", rng.Intn(1000), rng.Intn(100)) },
+	func() string { return fmt.Sprintf("%02d:%02d", rng.Intn(24), rng.Intn(60)) },
+	func() string {
+		return fmt.Sprintf("%d-%02d-%02d", 1950+rng.Intn(90), rng.Intn(12)+1, rng.Intn(28)+1)
+	},
+	func() string { return fmt.Sprintf("%03d-%04d", rng.Intn(1000), rng.Intn(10000)) },
+	func() string { return fmt.Sprintf("%d%%", rng.Intn(101)) },
+	func() string { return fmt.Sprintf("$%d.%02d", rng.Intn(500), rng.Intn(100)) },
+	func() string { return fmt.Sprintf("v%d.%d.%d", rng.Intn(10), rng.Intn(30), rng.Intn(100)) },
+	func() string { return fmt.Sprintf("%d-%d", rng.Intn(50), 50+rng.Intn(50)) },
+	func() string { return fmt.Sprintf("%d,%03d", rng.Intn(999)+1, rng.Intn(1000)) },
+}
+
+// symbolForms are the bracket, operator and punctuation patterns of code and prose; w is a word to build around
+var symbolForms = []func(w string) string{
+	func(w string) string { return "(" + w + ")" },
+	func(w string) string { return "[" + w + "]" },
+	func(w string) string { return "{" + w + "}" },
+	func(w string) string { return "<" + w + ">" },
+	func(w string) string { return "\"" + w + "\"" },
+	func(w string) string { return "'" + w + "'" },
+	func(w string) string { return "`" + w + "`" },
+	func(w string) string { return w + "();" },
+	func(w string) string { return w + "[i]" },
+	func(w string) string { return "{" + w + ": 0}" },
+	func(w string) string { return w + "->next" },
+	func(w string) string { return "!" + w },
+	func(w string) string { return w + " != nil" },
+	func(w string) string { return "a && " + w },
+	func(w string) string { return w + " || b" },
+	func(w string) string { return "x <= " + w },
+	func(w string) string { return w + " >= y" },
+	func(w string) string { return w + " += 1" },
+	func(w string) string { return "#" + w },
+	func(w string) string { return "@" + w },
+	func(w string) string { return "$" + w },
+	func(w string) string { return "~/" + w + "/" },
+	func(w string) string { return w + "_id" },
+	func(w string) string { return w + " | sort" },
+	func(w string) string { return "*" + w },
+	func(w string) string { return "&" + w },
+	func(w string) string { return w + "^2" },
+	func(w string) string { return w + "?" },
+	func(w string) string { return w + "!" },
+	func(w string) string { return w + ";" },
+	func(w string) string { return w + ":" },
+	func(w string) string { return w + "\\n" },
+}
+
+// GenerateNumberDrill returns count tokens made mostly of digits: plain numbers, prices, times, dates,
+// percentages and version numbers, with about one short word in five to keep the hands moving
+func GenerateNumberDrill(count int, language string) string {
+	tokens := make([]string, count)
+	for i := range tokens {
+		if rng.Intn(5) == 0 {
+			tokens[i] = GenerateWord(language)
+			continue
+		}
+		tokens[i] = numberForms[rng.Intn(len(numberForms))]()
+	}
+	return strings.Join(tokens, " ")
+}
+
+// GenerateSymbolDrill returns count tokens that wrap, join or follow short words with brackets,
+// operators and punctuation
+func GenerateSymbolDrill(count int, language string) string {
+	tokens := make([]string, count)
+	for i := range tokens {
+		tokens[i] = symbolForms[rng.Intn(len(symbolForms))](GenerateWord(language))
+	}
+	return strings.Join(tokens, " ")
+}
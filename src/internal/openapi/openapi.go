@@ -0,0 +1,449 @@
+// Package openapi turns API specifications into client and handler snippets for code practice.
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gti/src/internal"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is the subset of an OpenAPI 3 document needed to generate snippets
+type Spec struct {
+	Servers []Server            `yaml:"servers"`
+	Paths   map[string]PathItem `yaml:"paths"`
+}
+
+type Server struct {
+	URL string `yaml:"url"`
+}
+
+type PathItem struct {
+	Parameters []Parameter `yaml:"parameters"`
+	Get        *Operation  `yaml:"get"`
+	Post       *Operation  `yaml:"post"`
+	Put        *Operation  `yaml:"put"`
+	Patch      *Operation  `yaml:"patch"`
+	Delete     *Operation  `yaml:"delete"`
+}
+
+type Operation struct {
+	OperationID string                 `yaml:"operationId"`
+	Summary     string                 `yaml:"summary"`
+	Parameters  []Parameter            `yaml:"parameters"`
+	RequestBody map[string]interface{} `yaml:"requestBody"`
+	Responses   map[string]interface{} `yaml:"responses"`
+}
+
+type Parameter struct {
+	Name     string `yaml:"name"`
+	In       string `yaml:"in"`
+	Required bool   `yaml:"required"`
+}
+
+// Endpoint is a single flattened operation of the spec
+type Endpoint struct {
+	Method      string
+	Path        string
+	Name        string
+	Summary     string
+	PathParams  []string
+	QueryParams []string
+	HasBody     bool
+	Status      string
+}
+
+var supportedLanguages = map[string]bool{
+	"go":         true,
+	"python":     true,
+	"javascript": true,
+	"typescript": true,
+}
+
+// LoadSpec reads a YAML or JSON OpenAPI document
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec Spec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+	}
+	if len(spec.Paths) == 0 {
+		return nil, fmt.Errorf("OpenAPI spec %s defines no paths", path)
+	}
+	return &spec, nil
+}
+
+// ValidateLanguage checks that snippets can be generated for the language
+func ValidateLanguage(language string) error {
+	if !supportedLanguages[language] {
+		return fmt.Errorf("OpenAPI snippets are not available for '%s' (supported: go, python, javascript, typescript)", language)
+	}
+	return nil
+}
+
+// BaseURL returns the first server URL of the spec, or a placeholder
+func (s *Spec) BaseURL() string {
+	if len(s.Servers) > 0 && s.Servers[0].URL != "" {
+		return strings.TrimSuffix(s.Servers[0].URL, "/")
+	}
+	return "https://api.example.com"
+}
+
+// Endpoints flattens the spec into a stable, sorted list of operations
+func (s *Spec) Endpoints() []Endpoint {
+	var paths []string
+	for path := range s.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var endpoints []Endpoint
+	for _, path := range paths {
+		item := s.Paths[path]
+		ops := []struct {
+			method string
+			op     *Operation
+		}{
+			{"GET", item.Get}, {"POST", item.Post}, {"PUT", item.Put},
+			{"PATCH", item.Patch}, {"DELETE", item.Delete},
+		}
+		for _, o := range ops {
+			if o.op == nil {
+				continue
+			}
+			endpoints = append(endpoints, newEndpoint(o.method, path, o.op, item.Parameters))
+		}
+	}
+	return endpoints
+}
+
+func newEndpoint(method, path string, op *Operation, shared []Parameter) Endpoint {
+	ep := Endpoint{
+		Method:  method,
+		Path:    path,
+		Name:    op.OperationID,
+		Summary: op.Summary,
+		HasBody: op.RequestBody != nil,
+		Status:  successStatus(op.Responses, method),
+	}
+	if ep.Name == "" {
+		ep.Name = deriveName(method, path)
+	}
+
+	for _, p := range append(append([]Parameter{}, shared...), op.Parameters...) {
+		switch p.In {
+		case "path":
+			ep.PathParams = append(ep.PathParams, p.Name)
+		case "query":
+			ep.QueryParams = append(ep.QueryParams, p.Name)
+		}
+	}
+	// Fall back to the placeholders in the path when parameters are not declared
+	if len(ep.PathParams) == 0 {
+		for _, seg := range strings.Split(path, "/") {
+			if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+				ep.PathParams = append(ep.PathParams, strings.Trim(seg, "{}"))
+			}
+		}
+	}
+	return ep
+}
+
+func successStatus(responses map[string]interface{}, method string) string {
+	var codes []string
+	for code := range responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	if len(codes) > 0 {
+		return codes[0]
+	}
+	if method == "POST" {
+		return "201"
+	}
+	return "200"
+}
+
+// deriveName builds an operation name such as getUsersById from the method and path
+func deriveName(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, seg := range strings.Split(path, "/") {
+		if seg == "" {
+			continue
+		}
+		if strings.HasPrefix(seg, "{") {
+			b.WriteString("By")
+			seg = strings.Trim(seg, "{}")
+		}
+		b.WriteString(upperFirst(seg))
+	}
+	return identifier(b.String())
+}
+
+// Snippets generates client and handler snippets for every endpoint, shuffled, limited to count
+func Snippets(spec *Spec, language string, count int) []string {
+	var snippets []string
+	for _, ep := range spec.Endpoints() {
+		snippets = append(snippets, clientSnippet(spec, ep, language), handlerSnippet(ep, language))
+	}
+
+	internal.Shuffle(len(snippets), func(i, j int) { snippets[i], snippets[j] = snippets[j], snippets[i] })
+	if count > 0 && count < len(snippets) {
+		snippets = snippets[:count]
+	}
+	return snippets
+}
+
+func clientSnippet(spec *Spec, ep Endpoint, language string) string {
+	base := spec.BaseURL()
+	name := identifier(ep.Name)
+
+	switch language {
+	case "python":
+		fn := snakeCase(name)
+		args := append([]string{"session"}, snakeAll(ep.PathParams)...)
+		if ep.HasBody {
+			args = append(args, "payload")
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "def %s(%s):\n", fn, strings.Join(args, ", "))
+		// Braces in the server URL would be read as f-string fields
+		pyBase := strings.NewReplacer("{", "{{", "}", "}}").Replace(base)
+		call := fmt.Sprintf("session.%s(f\"%s%s\"", strings.ToLower(ep.Method), pyBase, pathTemplate(ep.Path, "{", "}", snakeCase))
+		if ep.HasBody {
+			call += ", json=payload"
+		}
+		if len(ep.QueryParams) > 0 {
+			call += fmt.Sprintf(", params={%s}", pyParams(ep.QueryParams))
+		}
+		fmt.Fprintf(&b, "    resp = %s, timeout=10)\n", call)
+		b.WriteString("    resp.raise_for_status()\n")
+		b.WriteString("    return resp.json()")
+		return b.String()
+
+	case "javascript", "typescript":
+		params := lowerAll(ep.PathParams)
+		if ep.HasBody {
+			params = append(params, "payload")
+		}
+		sig := strings.Join(params, ", ")
+		if language == "typescript" {
+			typed := make([]string, len(params))
+			for i, p := range params {
+				if p == "payload" {
+					typed[i] = p + ": Record<string, unknown>"
+				} else {
+					typed[i] = p + ": string"
+				}
+			}
+			sig = strings.Join(typed, ", ")
+		}
+		var b strings.Builder
+		if language == "typescript" {
+			fmt.Fprintf(&b, "async function %s(%s): Promise<unknown> {\n", lowerFirst(name), sig)
+		} else {
+			fmt.Fprintf(&b, "async function %s(%s) {\n", lowerFirst(name), sig)
+		}
+		opts := fmt.Sprintf("method: \"%s\"", ep.Method)
+		if ep.HasBody {
+			opts += ", headers: { \"Content-Type\": \"application/json\" }, body: JSON.stringify(payload)"
+		}
+		fmt.Fprintf(&b, "    const res = await fetch(`%s%s`, { %s });\n", base, pathTemplate(ep.Path, "${", "}", lowerFirst), opts)
+		fmt.Fprintf(&b, "    if (!res.ok) throw new Error(`%s failed: ${res.status}`);\n", lowerFirst(name))
+		b.WriteString("    return res.json();\n}")
+		return b.String()
+
+	default:
+		args := []string{"client *http.Client"}
+		for _, p := range lowerAll(ep.PathParams) {
+			args = append(args, p+" string")
+		}
+		body := "nil"
+		if ep.HasBody {
+			args = append(args, "body io.Reader")
+			body = "body"
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "func %s(%s) (*http.Response, error) {\n", upperFirst(name), strings.Join(args, ", "))
+		fmt.Fprintf(&b, "    req, err := http.NewRequest(\"%s\", %s, %s)\n", ep.Method, goPath(base, ep.Path), body)
+		b.WriteString("    if err != nil {\n        return nil, err\n    }\n")
+		if ep.HasBody {
+			b.WriteString("    req.Header.Set(\"Content-Type\", \"application/json\")\n")
+		}
+		b.WriteString("    req.Header.Set(\"Accept\", \"application/json\")\n")
+		b.WriteString("    return client.Do(req)\n}")
+		return b.String()
+	}
+}
+
+func handlerSnippet(ep Endpoint, language string) string {
+	name := identifier(ep.Name)
+
+	switch language {
+	case "python":
+		fn := snakeCase(name)
+		var b strings.Builder
+		fmt.Fprintf(&b, "@app.route(\"%s\", methods=[\"%s\"])\n", pathTemplate(ep.Path, "<", ">", snakeCase), ep.Method)
+		fmt.Fprintf(&b, "def %s(%s):\n", fn, strings.Join(snakeAll(ep.PathParams), ", "))
+		if ep.HasBody {
+			b.WriteString("    payload = request.get_json()\n")
+		}
+		for _, q := range ep.QueryParams {
+			fmt.Fprintf(&b, "    %s = request.args.get(\"%s\")\n", snakeCase(q), q)
+		}
+		fmt.Fprintf(&b, "    return jsonify({}), %s", ep.Status)
+		return b.String()
+
+	case "javascript", "typescript":
+		var b strings.Builder
+		reqRes := "req, res"
+		if language == "typescript" {
+			reqRes = "req: Request, res: Response"
+		}
+		fmt.Fprintf(&b, "app.%s(\"%s\", async (%s) => {\n", strings.ToLower(ep.Method), pathTemplate(ep.Path, ":", "", lowerFirst), reqRes)
+		if len(ep.PathParams) > 0 {
+			fmt.Fprintf(&b, "    const { %s } = req.params;\n", strings.Join(lowerAll(ep.PathParams), ", "))
+		}
+		if len(ep.QueryParams) > 0 {
+			fmt.Fprintf(&b, "    const { %s } = req.query;\n", strings.Join(ep.QueryParams, ", "))
+		}
+		if ep.HasBody {
+			b.WriteString("    const payload = req.body;\n")
+		}
+		fmt.Fprintf(&b, "    res.status(%s).json({});\n});", ep.Status)
+		return b.String()
+
+	default:
+		var b strings.Builder
+		fmt.Fprintf(&b, "func handle%s(w http.ResponseWriter, r *http.Request) {\n", upperFirst(name))
+		for _, p := range ep.PathParams {
+			fmt.Fprintf(&b, "    %s := r.PathValue(\"%s\")\n", lowerFirst(identifier(p)), p)
+		}
+		for _, q := range ep.QueryParams {
+			fmt.Fprintf(&b, "    %s := r.URL.Query().Get(\"%s\")\n", lowerFirst(identifier(q)), q)
+		}
+		if ep.HasBody {
+			b.WriteString("    var payload map[string]any\n")
+			b.WriteString("    if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {\n")
+			b.WriteString("        http.Error(w, err.Error(), http.StatusBadRequest)\n        return\n    }\n")
+		}
+		b.WriteString("    w.Header().Set(\"Content-Type\", \"application/json\")\n")
+		fmt.Fprintf(&b, "    w.WriteHeader(%s)\n}", ep.Status)
+		return b.String()
+	}
+}
+
+// pathTemplate rewrites {param} placeholders using the given delimiters and name transform
+func pathTemplate(path, open, close string, transform func(string) string) string {
+	var parts []string
+	for _, seg := range strings.Split(path, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			seg = open + transform(identifier(strings.Trim(seg, "{}"))) + close
+		}
+		parts = append(parts, seg)
+	}
+	return strings.Join(parts, "/")
+}
+
+// goPath renders the base URL and path as a Go string concatenation expression
+func goPath(base, path string) string {
+	var parts []string
+	literal := base
+	for _, seg := range strings.Split(path, "/")[1:] {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			parts = append(parts, fmt.Sprintf("\"%s/\"", literal), lowerFirst(identifier(strings.Trim(seg, "{}"))))
+			literal = ""
+			continue
+		}
+		literal += "/" + seg
+	}
+	if literal != "" {
+		parts = append(parts, fmt.Sprintf("\"%s\"", literal))
+	}
+	return strings.Join(parts, "+")
+}
+
+func pyParams(names []string) string {
+	var pairs []string
+	for _, n := range names {
+		pairs = append(pairs, fmt.Sprintf("\"%s\": None", n))
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// identifier strips characters that are not valid in identifiers, camel-casing across separators
+func identifier(s string) string {
+	var b strings.Builder
+	upperNext := false
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if upperNext && b.Len() > 0 {
+				r = unicode.ToUpper(r)
+			}
+			b.WriteRune(r)
+			upperNext = false
+		} else {
+			upperNext = true
+		}
+	}
+	if b.Len() == 0 {
+		return "call"
+	}
+	return b.String()
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func lowerAll(names []string) []string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = lowerFirst(identifier(n))
+	}
+	return out
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func snakeAll(names []string) []string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = snakeCase(identifier(n))
+	}
+	return out
+}
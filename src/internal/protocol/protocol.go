@@ -0,0 +1,216 @@
+// Package protocol loads locked test protocols, so typing studies run every participant under identical conditions.
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gti/src/internal"
+	"gti/src/internal/config"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	LeniencyLenient = "lenient"
+	LeniencyStrict  = "strict"
+
+	BackspaceAllowed  = "allowed"
+	BackspaceDisabled = "disabled"
+
+	// DefaultWords is the length of the generated text when a protocol does not set one
+	DefaultWords = 50
+	// DefaultOutput is where results go, relative to the protocol file, when a protocol does not say
+	DefaultOutput = "results"
+)
+
+var safeName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Protocol is a test protocol file. Every parameter that affects a measurement lives here,
+// and nothing from the user's config or the command line can change it.
+type Protocol struct {
+	Name    string `toml:"name"`
+	Version string `toml:"version"`
+	// Seed fixes the generated text, so every participant types the same words
+	Seed     int64  `toml:"seed"`
+	Language string `toml:"language"`
+	Words    int    `toml:"words"`
+	// Seconds ends each run after this long; 0 runs until the text is typed
+	Seconds int `toml:"seconds"`
+	// Leniency is "lenient" to accept mistyped characters and move on, or "strict" to wait for the right key
+	Leniency string `toml:"leniency"`
+	// Backspace is "allowed" or "disabled"
+	Backspace string `toml:"backspace"`
+	// Output is the directory result files are written to, relative to the protocol file
+	Output string `toml:"output"`
+
+	path string
+	hash string
+}
+
+// Stamp identifies the protocol, participant and gti build in every result file
+type Stamp struct {
+	Name        string `json:"name"`
+	Version     string `json:"version,omitempty"`
+	File        string `json:"file"`
+	SHA256      string `json:"sha256"`
+	Seed        int64  `json:"seed"`
+	Language    string `json:"language"`
+	Words       int    `json:"words"`
+	Seconds     int    `json:"seconds"`
+	Leniency    string `json:"leniency"`
+	Backspace   string `json:"backspace"`
+	Participant string `json:"participant,omitempty"`
+	GTIVersion  string `json:"gti_version"`
+}
+
+// Load reads and validates a protocol file. Unknown keys are rejected rather than ignored,
+// so a misspelt parameter cannot silently fall back to a default.
+func Load(path string) (*Protocol, error) {
+	path = config.ExpandPath(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Protocol{}
+	meta, err := toml.Decode(string(data), p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid protocol %s: %w", path, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		sort.Strings(keys)
+		return nil, fmt.Errorf("invalid protocol %s: unknown keys %s", path, strings.Join(keys, ", "))
+	}
+	if !meta.IsDefined("seed") {
+		return nil, fmt.Errorf("invalid protocol %s: seed is required so the text can be reproduced", path)
+	}
+
+	hash := sha256.Sum256(data)
+	p.hash = hex.EncodeToString(hash[:])
+	if p.path, err = filepath.Abs(path); err != nil {
+		p.path = path
+	}
+
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid protocol %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// validate checks the parameters and fills in defaults for the optional ones
+func (p *Protocol) validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if p.Language == "" {
+		p.Language = "english"
+	}
+	if err := internal.ValidateLanguage(p.Language); err != nil {
+		return err
+	}
+	if p.Words < 0 || p.Seconds < 0 {
+		return fmt.Errorf("words and seconds cannot be negative")
+	}
+	if p.Words == 0 {
+		p.Words = DefaultWords
+	}
+
+	switch p.Leniency {
+	case "":
+		p.Leniency = LeniencyLenient
+	case LeniencyLenient, LeniencyStrict:
+	default:
+		return fmt.Errorf("leniency must be %q or %q", LeniencyLenient, LeniencyStrict)
+	}
+	switch p.Backspace {
+	case "":
+		p.Backspace = BackspaceAllowed
+	case BackspaceAllowed, BackspaceDisabled:
+	default:
+		return fmt.Errorf("backspace must be %q or %q", BackspaceAllowed, BackspaceDisabled)
+	}
+
+	if p.Output == "" {
+		p.Output = DefaultOutput
+	}
+	return nil
+}
+
+// Text generates the protocol's text; the same protocol always yields the same text
+func (p *Protocol) Text() string {
+	return internal.GenerateWordsSeeded(p.Words, p.Language, p.Seed)
+}
+
+// OutputDir is the absolute directory result files are written to
+func (p *Protocol) OutputDir() string {
+	output := config.ExpandPath(p.Output)
+	if filepath.IsAbs(output) {
+		return output
+	}
+	return filepath.Join(filepath.Dir(p.path), output)
+}
+
+// ResultName prefixes the result files of a participant's runs
+func (p *Protocol) ResultName(participant string) string {
+	name := p.Name
+	if participant != "" {
+		name += "-" + participant
+	}
+	return strings.Trim(safeName.ReplaceAllString(name, "_"), "_")
+}
+
+// StopOnError reports whether the cursor waits on mistyped characters
+func (p *Protocol) StopOnError() bool {
+	return p.Leniency == LeniencyStrict
+}
+
+// NoBackspace reports whether Backspace is disabled
+func (p *Protocol) NoBackspace() bool {
+	return p.Backspace == BackspaceDisabled
+}
+
+// Stamp describes the protocol for a participant's result files
+func (p *Protocol) Stamp(participant, gtiVersion string) Stamp {
+	return Stamp{
+		Name:        p.Name,
+		Version:     p.Version,
+		File:        p.path,
+		SHA256:      p.hash,
+		Seed:        p.Seed,
+		Language:    p.Language,
+		Words:       p.Words,
+		Seconds:     p.Seconds,
+		Leniency:    p.Leniency,
+		Backspace:   p.Backspace,
+		Participant: participant,
+		GTIVersion:  gtiVersion,
+	}
+}
+
+// LockedConfig is the configuration a protocol run uses: the defaults for everything that could affect
+// a measurement, keeping only the user's colours, key bindings and history settings
+func LockedConfig(user *config.Config, p *Protocol) *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.Theme = user.Theme
+	cfg.Keybindings = user.Keybindings
+	cfg.History = user.History
+	cfg.Language.Default = p.Language
+	// A ghost or an automatic next test would change what participants see from one run to the next
+	cfg.Display.ShowGhost = false
+	cfg.Results.AutoDismissSeconds = 0
+	cfg.Results.AutoChain = false
+	// The clock runs from the first key to the last; an idle pause would take time out of the measurement
+	cfg.Idle.Seconds = 0
+	return cfg
+}
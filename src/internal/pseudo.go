@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// pseudoOnsets, pseudoNuclei and pseudoCodas are the parts of an English-like syllable; the empty
+	// entries let a syllable start on its vowel or end on it
+	pseudoOnsets = []string{
+		"", "b", "c", "d", "f", "g", "h", "j", "k", "l", "m", "n", "p", "r", "s", "t", "v", "w", "z",
+		"bl", "br", "ch", "cl", "cr", "dr", "fl", "fr", "gl", "gr", "pl", "pr", "sh", "sk", "sl", "sn",
+		"sp", "st", "str", "th", "tr",
+	}
+	pseudoNuclei = []string{"a", "e", "i", "o", "u", "a", "e", "i", "o", "ai", "ea", "ee", "oa", "oo", "ou", "y"}
+	pseudoCodas  = []string{"", "", "", "b", "ck", "d", "g", "l", "m", "n", "nd", "ng", "nt", "p", "r", "rk", "s", "st", "t", "x"}
+
+	// randomLetters and randomSymbols are what random strings are made of
+	randomLetters = "abcdefghijklmnopqrstuvwxyz"
+	randomSymbols = "0123456789!@#$%^&*()-_=+[]{};:'\",.<>/?\\|`~"
+)
+
+const (
+	// maxPseudoSyllables is the most syllables a pseudo-word runs to
+	maxPseudoSyllables = 3
+	// minRandomLength and maxRandomLength bound the length of a random string
+	minRandomLength = 3
+	maxRandomLength = 8
+)
+
+// GeneratePseudoWords returns count made-up words built from English-like syllables: easy to say, so
+// they read fluently, but in no word list, so the fingers cannot lean on a remembered spelling
+func GeneratePseudoWords(count int) string {
+	words := make([]string, count)
+	for i := range words {
+		var b strings.Builder
+		for n := 1 + rng.Intn(maxPseudoSyllables); n > 0; n-- {
+			b.WriteString(pseudoOnsets[rng.Intn(len(pseudoOnsets))])
+			b.WriteString(pseudoNuclei[rng.Intn(len(pseudoNuclei))])
+			// Only the last syllable closes on a consonant, so clusters never pile up mid-word
+			if n == 1 {
+				b.WriteString(pseudoCodas[rng.Intn(len(pseudoCodas))])
+			}
+		}
+		words[i] = b.String()
+	}
+	return strings.Join(words, " ")
+}
+
+// GenerateRandomStrings returns count strings of random characters, each one a digit or symbol with
+// the given probability and a letter otherwise
+func GenerateRandomStrings(count int, symbols float64) string {
+	tokens := make([]string, count)
+	for i := range tokens {
+		token := make([]byte, minRandomLength+rng.Intn(maxRandomLength-minRandomLength+1))
+		for j := range token {
+			if rng.Float64() < symbols {
+				token[j] = randomSymbols[rng.Intn(len(randomSymbols))]
+			} else {
+				token[j] = randomLetters[rng.Intn(len(randomLetters))]
+			}
+		}
+		tokens[i] = string(token)
+	}
+	return strings.Join(tokens, " ")
+}
+
+// ValidateSymbolDensity checks that a symbol density is a percentage
+func ValidateSymbolDensity(percent int) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("symbol density must be between 0 and 100 percent, got %d", percent)
+	}
+	return nil
+}
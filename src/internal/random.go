@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// lockedSource lets every generator share one source, whichever goroutine asks
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+func (l *lockedSource) Int63() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.src.Int63()
+}
+
+func (l *lockedSource) Uint64() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.src.Uint64()
+}
+
+func (l *lockedSource) Seed(seed int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.src.Seed(seed)
+}
+
+var source = &lockedSource{src: rand.NewSource(time.Now().UnixNano()).(rand.Source64)}
+
+// rng is what the package's generators draw from. It is seeded once, at startup, so words picked in
+// a tight loop still differ
+var rng = rand.New(source)
+
+// Seed makes the generators repeatable: after it, the same calls in the same order give the same text
+func Seed(seed int64) {
+	source.Seed(seed)
+}
+
+// Intn draws a number in [0,n) from the shared source, for generators outside this package
+func Intn(n int) int {
+	return rng.Intn(n)
+}
+
+// Int63 draws a non-negative number from the shared source, for seeding a generator of its own
+func Int63() int64 {
+	return rng.Int63()
+}
+
+// Float64 draws a number in [0,1) from the shared source
+func Float64() float64 {
+	return rng.Float64()
+}
+
+// Perm is a random order of the numbers [0,n) drawn from the shared source
+func Perm(n int) []int {
+	return rng.Perm(n)
+}
+
+// Shuffle puts n items in a random order drawn from the shared source, swapping them with swap
+func Shuffle(n int, swap func(i, j int)) {
+	rng.Shuffle(n, swap)
+}
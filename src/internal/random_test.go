@@ -0,0 +1,18 @@
+package internal
+
+import "testing"
+
+func TestSeedRepeatsText(t *testing.T) {
+	Seed(42)
+	first := GenerateWordsDynamic(30, "english")
+	Seed(42)
+	second := GenerateWordsDynamic(30, "english")
+	if first != second {
+		t.Fatalf("the same seed gave different text:\n%q\n%q", first, second)
+	}
+
+	Seed(43)
+	if other := GenerateWordsDynamic(30, "english"); other == first {
+		t.Fatalf("a different seed gave the same text: %q", other)
+	}
+}
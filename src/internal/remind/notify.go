@@ -0,0 +1,41 @@
+// Package remind sends the desktop notification that today's practice streak is at risk, and
+// schedules gti to check for it each day with the system's own scheduler.
+package remind
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// Notify shows a desktop notification. The title and message are passed through the environment,
+// so nothing in them is ever parsed as a script.
+func Notify(title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript", "-e",
+			`display notification (system attribute "GTI_NOTIFY_MESSAGE") with title (system attribute "GTI_NOTIFY_TITLE")`)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command",
+			`Add-Type -AssemblyName System.Windows.Forms; `+
+				`$n = New-Object System.Windows.Forms.NotifyIcon; `+
+				`$n.Icon = [System.Drawing.SystemIcons]::Information; $n.Visible = $true; `+
+				`$n.ShowBalloonTip(10000, $env:GTI_NOTIFY_TITLE, $env:GTI_NOTIFY_MESSAGE, 'Info'); `+
+				`Start-Sleep -Seconds 10; $n.Dispose()`)
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return fmt.Errorf("notify-send is not installed: install libnotify (e.g. libnotify-bin) to get reminders")
+		}
+		cmd = exec.Command("notify-send", "--app-name=gti", "--", title, message)
+	}
+	cmd.Env = append(os.Environ(), "GTI_NOTIFY_TITLE="+title, "GTI_NOTIFY_MESSAGE="+message)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if len(out) > 0 {
+			return fmt.Errorf("could not show the notification: %s", out)
+		}
+		return fmt.Errorf("could not show the notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package remind
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// ParseTime reads a time of day such as 18:00 or 7:30
+func ParseTime(s string) (hour, minute int, err error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, 0, fmt.Errorf("--at takes a time of day such as 18:00, not '%s'", s)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// Next is the next time the clock reads hour:minute after now
+func Next(now time.Time, hour, minute int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// Install has the system's scheduler run 'gti remind check' every day at hour:minute, replacing
+// any reminder installed before, and says where it went
+func Install(hour, minute int) (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return installLaunchd(exe, hour, minute)
+	case "windows":
+		return installTask(exe, hour, minute)
+	default:
+		return installCron(exe, hour, minute)
+	}
+}
+
+// Remove takes out the scheduled reminder, reporting whether there was one
+func Remove() (bool, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return removeLaunchd()
+	case "windows":
+		return removeTask()
+	default:
+		return removeCron()
+	}
+}
+
+// cronTag ends the crontab line gti owns, so it can be found again without touching the user's own
+const cronTag = "# gti remind"
+
+// cronEnv are the variables notify-send needs to reach the desktop session, which cron does not pass on
+var cronEnv = []string{"DISPLAY", "WAYLAND_DISPLAY", "DBUS_SESSION_BUS_ADDRESS", "XDG_RUNTIME_DIR"}
+
+func installCron(exe string, hour, minute int) (string, error) {
+	lines, err := crontabLines()
+	if err != nil {
+		return "", err
+	}
+	lines = crontabLinesWithout(lines)
+	var command strings.Builder
+	for _, name := range cronEnv {
+		if value := os.Getenv(name); value != "" {
+			command.WriteString(name + "=" + shellQuote(value) + " ")
+		}
+	}
+	command.WriteString(shellQuote(exe) + " remind check")
+	// An unescaped % ends a cron command
+	line := fmt.Sprintf("%d %d * * * %s %s", minute, hour, strings.ReplaceAll(command.String(), "%", `\%`), cronTag)
+	if err := writeCrontab(append(lines, line)); err != nil {
+		return "", err
+	}
+	return "your crontab", nil
+}
+
+func removeCron() (bool, error) {
+	before, err := crontabLines()
+	if err != nil {
+		return false, err
+	}
+	lines := crontabLinesWithout(before)
+	if len(lines) == len(before) {
+		return false, nil
+	}
+	return true, writeCrontab(lines)
+}
+
+// crontabLines is the user's crontab, gti's reminder included
+func crontabLines() ([]string, error) {
+	if _, err := exec.LookPath("crontab"); err != nil {
+		return nil, fmt.Errorf("crontab is not installed: install cron, or run 'gti remind --daemon' from your session's startup programs")
+	}
+	out, err := exec.Command("crontab", "-l").Output()
+	if err != nil {
+		var exit *exec.ExitError
+		// crontab -l fails when there is no crontab yet; any other failure could lose the user's
+		// jobs if the crontab were written back without them
+		if !errors.As(err, &exit) || !bytes.Contains(exit.Stderr, []byte("no crontab for")) {
+			if exit != nil && len(bytes.TrimSpace(exit.Stderr)) > 0 {
+				return nil, fmt.Errorf("could not read the crontab: %s", bytes.TrimSpace(exit.Stderr))
+			}
+			return nil, fmt.Errorf("could not read the crontab: %w", err)
+		}
+		out = nil
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// crontabLinesWithout leaves gti's reminder out of the crontab
+func crontabLinesWithout(lines []string) []string {
+	var kept []string
+	for _, line := range lines {
+		if !strings.HasSuffix(line, cronTag) {
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
+
+func writeCrontab(lines []string) error {
+	cmd := exec.Command("crontab", "-")
+	cmd.Stdin = strings.NewReader(strings.Join(lines, "\n") + "\n")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("could not update the crontab: %s", bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+const launchdLabel = "com.developic.gti.remind"
+
+func launchdPlist() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "Library", "LaunchAgents", launchdLabel+".plist")
+}
+
+func installLaunchd(exe string, hour, minute int) (string, error) {
+	var escaped bytes.Buffer
+	xml.EscapeText(&escaped, []byte(exe))
+	plist := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+  <key>Label</key>
+  <string>%s</string>
+  <key>ProgramArguments</key>
+  <array>
+    <string>%s</string>
+    <string>remind</string>
+    <string>check</string>
+  </array>
+  <key>StartCalendarInterval</key>
+  <dict>
+    <key>Hour</key>
+    <integer>%d</integer>
+    <key>Minute</key>
+    <integer>%d</integer>
+  </dict>
+</dict>
+</plist>
+`, launchdLabel, escaped.String(), hour, minute)
+
+	path := launchdPlist()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	// A reminder loaded before is unloaded first, or launchd keeps its old time
+	exec.Command("launchctl", "unload", path).Run()
+	if err := os.WriteFile(path, []byte(plist), 0644); err != nil {
+		return "", err
+	}
+	if out, err := exec.Command("launchctl", "load", "-w", path).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("could not load %s: %s", path, bytes.TrimSpace(out))
+	}
+	return path, nil
+}
+
+func removeLaunchd() (bool, error) {
+	path := launchdPlist()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return false, nil
+	}
+	exec.Command("launchctl", "unload", path).Run()
+	return true, os.Remove(path)
+}
+
+const taskName = "gti remind"
+
+func installTask(exe string, hour, minute int) (string, error) {
+	at := fmt.Sprintf("%02d:%02d", hour, minute)
+	out, err := exec.Command("schtasks", "/Create", "/F", "/SC", "DAILY", "/TN", taskName,
+		"/TR", `"`+exe+`" remind check`, "/ST", at).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("could not create the scheduled task: %s", bytes.TrimSpace(out))
+	}
+	return "Task Scheduler, as '" + taskName + "'", nil
+}
+
+func removeTask() (bool, error) {
+	if exec.Command("schtasks", "/Query", "/TN", taskName).Run() != nil {
+		return false, nil
+	}
+	if out, err := exec.Command("schtasks", "/Delete", "/F", "/TN", taskName).CombinedOutput(); err != nil {
+		return false, fmt.Errorf("could not delete the scheduled task: %s", bytes.TrimSpace(out))
+	}
+	return true, nil
+}
@@ -0,0 +1,100 @@
+package remote
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gti/src/internal/config"
+)
+
+// gitStore keeps the files in a Git repository, through a fresh shallow clone for each push or pull.
+// Signing in is left to git, so SSH keys and credential helpers work as they do for any other clone.
+type gitStore struct {
+	dir    string
+	prefix string
+}
+
+func openGit(cfg *config.Config) (store, error) {
+	if cfg.Sync.URL == "" {
+		return nil, fmt.Errorf("set sync.url to the Git repository to sync with")
+	}
+	if strings.HasPrefix(cfg.Sync.URL, "-") {
+		return nil, fmt.Errorf("sync.url must not start with -: %s", cfg.Sync.URL)
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("sync.backend is git, but git is not installed")
+	}
+	dir, err := os.MkdirTemp("", "gti-sync-")
+	if err != nil {
+		return nil, err
+	}
+	s := &gitStore{dir: dir, prefix: filepath.FromSlash(strings.Trim(cfg.Sync.Prefix, "/"))}
+	if _, err := s.git(nil, "clone", "--quiet", "--depth", "1", "--", cfg.Sync.URL, "."); err != nil {
+		s.close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *gitStore) path(name string) string {
+	return filepath.Join(s.dir, s.prefix, name)
+}
+
+func (s *gitStore) get(name string) ([]byte, error) {
+	return readLocal(s.path(name))
+}
+
+func (s *gitStore) put(name string, data []byte) error {
+	return writeLocal(s.path(name), data, time.Time{})
+}
+
+func (s *gitStore) finish(message string) error {
+	if _, err := s.git(nil, "add", "--all"); err != nil {
+		return err
+	}
+	status, err := s.git(nil, "status", "--porcelain")
+	if err != nil {
+		return err
+	}
+	if status == "" {
+		return nil
+	}
+	var env []string
+	if email, _ := s.git(nil, "config", "user.email"); email == "" {
+		// A machine that has never committed still syncs, under gti's own name
+		env = []string{"GIT_AUTHOR_NAME=gti", "GIT_AUTHOR_EMAIL=gti@localhost", "GIT_COMMITTER_NAME=gti", "GIT_COMMITTER_EMAIL=gti@localhost"}
+	}
+	if _, err := s.git(env, "commit", "--quiet", "-m", message); err != nil {
+		return err
+	}
+	_, err = s.git(nil, "push", "--quiet", "origin", "HEAD")
+	return err
+}
+
+func (s *gitStore) close() {
+	os.RemoveAll(s.dir)
+}
+
+// git runs a git command in the clone with env added to the environment, returning its trimmed
+// output, or its error message when it fails
+func (s *gitStore) git(env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = s.dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Never stop to ask for a password, since sync runs from scripts as often as by hand
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
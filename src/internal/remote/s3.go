@@ -0,0 +1,176 @@
+package remote
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"gti/src/internal/config"
+)
+
+// s3Store keeps the files in an S3 bucket, or one of a service that speaks the same API such as
+// MinIO or Backblaze B2, addressing it by path so any endpoint works
+type s3Store struct {
+	client    *http.Client
+	endpoint  *url.URL
+	bucket    string
+	prefix    string
+	region    string
+	accessKey string
+	secretKey string
+	token     string
+}
+
+func openS3(cfg *config.Config) (store, error) {
+	if cfg.Sync.Bucket == "" {
+		return nil, fmt.Errorf("set sync.bucket to the S3 bucket to sync with")
+	}
+	region := cfg.Sync.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := cfg.Sync.URL
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("sync.url is not a valid S3 endpoint: %s", endpoint)
+	}
+	s := &s3Store{
+		client:    &http.Client{Timeout: time.Duration(cfg.Network.TimeoutMs) * time.Millisecond},
+		endpoint:  u,
+		bucket:    cfg.Sync.Bucket,
+		prefix:    strings.Trim(cfg.Sync.Prefix, "/"),
+		region:    region,
+		accessKey: os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		token:     os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if s.accessKey == "" || s.secretKey == "" {
+		return nil, fmt.Errorf("set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY to the keys for the S3 bucket")
+	}
+	return s, nil
+}
+
+// objectPath is the escaped path of the named object, as the request and its signature both use it
+func (s *s3Store) objectPath(name string) string {
+	key := name
+	if s.prefix != "" {
+		key = s.prefix + "/" + name
+	}
+	segments := []string{strings.TrimSuffix(s.endpoint.EscapedPath(), "/"), uriEncode(s.bucket)}
+	for _, part := range strings.Split(key, "/") {
+		segments = append(segments, uriEncode(part))
+	}
+	return strings.Join(segments, "/")
+}
+
+func (s *s3Store) do(method, name string, body []byte) (*http.Response, error) {
+	path := s.objectPath(name)
+	target := *s.endpoint
+	target.Path = ""
+	target.RawPath = ""
+	req, err := http.NewRequest(method, target.String()+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, path, body, time.Now().UTC())
+	return s.client.Do(req)
+}
+
+// sign adds an AWS Signature Version 4 to the request
+func (s *s3Store) sign(req *http.Request, path string, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payload := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(payload[:])
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := []string{req.URL.Host, payloadHash, amzDate}
+	if s.token != "" {
+		req.Header.Set("x-amz-security-token", s.token)
+		headers = append(headers, "x-amz-security-token")
+		values = append(values, s.token)
+	}
+	var canonicalHeaders strings.Builder
+	for i, h := range headers {
+		canonicalHeaders.WriteString(h + ":" + values[i] + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	canonicalRequest := strings.Join([]string{req.Method, path, "", canonicalHeaders.String(), signedHeaders, payloadHash}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	scope := day + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), day)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", s.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// uriEncode escapes everything but the characters S3 leaves unreserved, as signing requires
+func uriEncode(s string) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-._~", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func (s *s3Store) get(name string) ([]byte, error) {
+	resp, err := s.do(http.MethodGet, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("S3 GET %s: %s", name, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (s *s3Store) put(name string, data []byte) error {
+	resp, err := s.do(http.MethodPut, name, data)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("S3 PUT %s: %s", name, resp.Status)
+	}
+	return nil
+}
+
+func (s *s3Store) finish(message string) error {
+	return nil
+}
+
+func (s *s3Store) close() {}
@@ -0,0 +1,354 @@
+package remote
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gti/src/internal/config"
+	"gti/src/internal/vault"
+)
+
+// store is a place the synced files are kept, with each file named relative to the sync prefix
+type store interface {
+	// get returns the contents of the file, or nil when it has not been pushed yet
+	get(name string) ([]byte, error)
+	put(name string, data []byte) error
+	// finish makes the puts so far visible to other machines; it is called once, after the last put
+	finish(message string) error
+	// close releases anything the store holds, whether or not finish was called
+	close()
+}
+
+// manifestName is the remote file recording when each progress file was last pushed, and from where
+const manifestName = "manifest.json"
+
+type manifest struct {
+	Files map[string]fileStamp `json:"files"`
+}
+
+type fileStamp struct {
+	Updated time.Time `json:"updated"`
+	Machine string    `json:"machine"`
+}
+
+// historyName is the session history's name on the remote, wherever history.file keeps it locally
+const historyName = "history.jsonl"
+
+// progressFiles are the progress files kept in the config directory that are synced whole,
+// the most recently changed copy winning
+var progressFiles = []string{
+	"challenge_progress.json",
+	"lesson_progress.json",
+	"course_progress.json",
+	"bookmarks.json",
+}
+
+func open(cfg *config.Config) (store, error) {
+	switch cfg.Sync.Backend {
+	case config.SyncGit:
+		return openGit(cfg)
+	case config.SyncS3:
+		return openS3(cfg)
+	case config.SyncWebDAV:
+		return openWebDAV(cfg)
+	case "":
+		return nil, fmt.Errorf("sync is off: set sync.backend to %s, %s or %s and sync.url to where the files go", config.SyncGit, config.SyncS3, config.SyncWebDAV)
+	}
+	return nil, fmt.Errorf("unknown sync backend '%s': use %s, %s or %s", cfg.Sync.Backend, config.SyncGit, config.SyncS3, config.SyncWebDAV)
+}
+
+// Push merges the local session history into the remote one and uploads each progress file that
+// changed here more recently than on any other machine. It returns a line for each file it looked at.
+func Push(cfg *config.Config) ([]string, error) {
+	s, err := open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer s.close()
+
+	m, err := loadManifest(s)
+	if err != nil {
+		return nil, err
+	}
+	var report []string
+	changed := false
+
+	if cfg.History.Enabled {
+		local, err := readLocal(config.ExpandPath(cfg.History.File))
+		if err != nil {
+			return nil, err
+		}
+		remote, err := s.get(historyName)
+		if err != nil {
+			return nil, err
+		}
+		merged, added, err := mergeHistory(cfg, remote, local)
+		if err != nil {
+			return nil, err
+		}
+		if added > 0 {
+			if err := s.put(historyName, merged); err != nil {
+				return nil, err
+			}
+			changed = true
+		}
+		report = append(report, fmt.Sprintf("History: %s sent", plural(added, "new session")))
+	} else {
+		report = append(report, "History: skipped, history.enabled is off")
+	}
+
+	machine, _ := os.Hostname()
+	for _, name := range progressFiles {
+		path := filepath.Join(config.ConfigDir, name)
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		updated := info.ModTime().UTC().Truncate(time.Second)
+		stamp, pushed := m.Files[name]
+		switch {
+		case pushed && stamp.Updated.Equal(updated):
+			report = append(report, fmt.Sprintf("%s: up to date", name))
+			continue
+		case pushed && stamp.Updated.After(updated):
+			report = append(report, fmt.Sprintf("%s: kept the newer copy from %s, pull to get it", name, stamp.Machine))
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.put(name, data); err != nil {
+			return nil, err
+		}
+		m.Files[name] = fileStamp{Updated: updated, Machine: machine}
+		changed = true
+		report = append(report, fmt.Sprintf("%s: sent", name))
+	}
+
+	if !changed {
+		return report, nil
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.put(manifestName, data); err != nil {
+		return nil, err
+	}
+	if err := s.finish("gti sync push from " + machine); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Pull merges the remote session history into the local one and replaces each progress file that
+// was pushed from another machine more recently than it changed here. It returns a line for each file
+// it looked at.
+func Pull(cfg *config.Config) ([]string, error) {
+	s, err := open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer s.close()
+
+	m, err := loadManifest(s)
+	if err != nil {
+		return nil, err
+	}
+	var report []string
+
+	if cfg.History.Enabled {
+		path := config.ExpandPath(cfg.History.File)
+		local, err := readLocal(path)
+		if err != nil {
+			return nil, err
+		}
+		remote, err := s.get(historyName)
+		if err != nil {
+			return nil, err
+		}
+		merged, added, err := mergeHistory(cfg, local, remote)
+		if err != nil {
+			return nil, err
+		}
+		if added > 0 {
+			if err := writeLocal(path, merged, time.Time{}); err != nil {
+				return nil, err
+			}
+		}
+		report = append(report, fmt.Sprintf("History: %s received", plural(added, "new session")))
+	} else {
+		report = append(report, "History: skipped, history.enabled is off")
+	}
+
+	for _, name := range progressFiles {
+		stamp, pushed := m.Files[name]
+		if !pushed {
+			continue
+		}
+		path := filepath.Join(config.ConfigDir, name)
+		if info, err := os.Stat(path); err == nil {
+			updated := info.ModTime().UTC().Truncate(time.Second)
+			if updated.Equal(stamp.Updated) {
+				report = append(report, fmt.Sprintf("%s: up to date", name))
+				continue
+			}
+			if updated.After(stamp.Updated) {
+				report = append(report, fmt.Sprintf("%s: kept the newer copy here, push to send it", name))
+				continue
+			}
+		}
+		data, err := s.get(name)
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			report = append(report, fmt.Sprintf("%s: missing from the remote, push to send it again", name))
+			continue
+		}
+		// The file takes the time it was pushed, so it is not mistaken for a newer local change
+		if err := writeLocal(path, data, stamp.Updated); err != nil {
+			return nil, err
+		}
+		report = append(report, fmt.Sprintf("%s: received from %s", name, stamp.Machine))
+	}
+	return report, nil
+}
+
+func loadManifest(s store) (*manifest, error) {
+	m := &manifest{}
+	data, err := s.get(manifestName)
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		if err := json.Unmarshal(data, m); err != nil {
+			return nil, fmt.Errorf("the remote %s is damaged: %w", manifestName, err)
+		}
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]fileStamp)
+	}
+	return m, nil
+}
+
+// mergeHistory adds the sessions of other that base lacks, telling sessions apart by when they were
+// saved and in which mode, and returns the lines oldest first with how many were added. Encrypted
+// sessions are opened to be told apart, asking for the passphrase the first time; those sealed with
+// a key not kept here lead, in the order they were found. Lines are kept as written, so fields this
+// version of gti does not know survive the round trip, but sessions added in the clear are sealed
+// when history.encrypt is on.
+func mergeHistory(cfg *config.Config, base, other []byte) ([]byte, int, error) {
+	type line struct {
+		at   time.Time
+		data []byte
+	}
+	type key struct {
+		at   int64
+		mode string
+	}
+	seen := make(map[key]bool)
+	var lines []line
+	added := 0
+	for i, data := range [][]byte{base, other} {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, 1024*1024)
+		for scanner.Scan() {
+			var record struct {
+				Timestamp time.Time `json:"timestamp"`
+				Mode      string    `json:"mode"`
+			}
+			raw := append([]byte(nil), scanner.Bytes()...)
+			plain := raw
+			sealed := vault.IsSealed(raw)
+			if sealed {
+				plain = nil
+				if vault.HasKey(cfg) {
+					opened, err := vault.Open(cfg, raw)
+					if err != nil && !vault.Unlocked() {
+						return nil, 0, err
+					}
+					plain = opened
+				}
+			}
+			var k key
+			if plain == nil {
+				// A session sealed with a key not kept here can only be told apart by its sealed bytes
+				k = key{mode: string(raw)}
+			} else if json.Unmarshal(plain, &record) != nil {
+				continue
+			} else {
+				k = key{record.Timestamp.UnixNano(), record.Mode}
+			}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			if i == 1 && !sealed && cfg.History.Encrypt {
+				var err error
+				if raw, err = vault.Seal(cfg, raw); err != nil {
+					return nil, 0, err
+				}
+			}
+			lines = append(lines, line{record.Timestamp, raw})
+			if i == 1 {
+				added++
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].at.Before(lines[j].at) })
+	var merged bytes.Buffer
+	for _, l := range lines {
+		merged.Write(l.data)
+		merged.WriteByte('\n')
+	}
+	return merged.Bytes(), added, nil
+}
+
+// readLocal returns the file's contents, or nil when it does not exist
+func readLocal(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// writeLocal replaces the file through a temporary one, so a failed write never leaves half a file,
+// and dates it modified, unless modified is zero
+func writeLocal(path string, data []byte, modified time.Time) error {
+	if err := config.EnsureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	tmp := path + ".sync"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if !modified.IsZero() {
+		if err := os.Chtimes(tmp, modified, modified); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+	}
+	return os.Rename(tmp, path)
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,96 @@
+package remote
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"gti/src/internal/config"
+)
+
+// webdavStore keeps the files in a WebDAV folder, such as one on Nextcloud, signing in with basic auth
+type webdavStore struct {
+	client   *http.Client
+	base     string
+	username string
+	password string
+	// made is set once the prefix folder is known to exist
+	made bool
+}
+
+func openWebDAV(cfg *config.Config) (store, error) {
+	if cfg.Sync.URL == "" {
+		return nil, fmt.Errorf("set sync.url to the WebDAV folder to sync with")
+	}
+	base, err := url.JoinPath(cfg.Sync.URL, strings.Trim(cfg.Sync.Prefix, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("sync.url is not a valid URL: %w", err)
+	}
+	return &webdavStore{
+		client:   &http.Client{Timeout: time.Duration(cfg.Network.TimeoutMs) * time.Millisecond},
+		base:     strings.TrimSuffix(base, "/"),
+		username: cfg.Sync.Username,
+		password: os.Getenv("GTI_SYNC_PASSWORD"),
+	}, nil
+}
+
+func (s *webdavStore) do(method, target string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if s.username != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+	return s.client.Do(req)
+}
+
+func (s *webdavStore) get(name string) ([]byte, error) {
+	resp, err := s.do(http.MethodGet, s.base+"/"+name, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("WebDAV GET %s: %s", name, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (s *webdavStore) put(name string, data []byte) error {
+	if !s.made {
+		// MKCOL answers 405 when the folder is already there
+		resp, err := s.do("MKCOL", s.base+"/", nil)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 && resp.StatusCode != http.StatusMethodNotAllowed {
+			return fmt.Errorf("WebDAV could not create %s: %s", s.base, resp.Status)
+		}
+		s.made = true
+	}
+	resp, err := s.do(http.MethodPut, s.base+"/"+name, data)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("WebDAV PUT %s: %s", name, resp.Status)
+	}
+	return nil
+}
+
+func (s *webdavStore) finish(message string) error {
+	return nil
+}
+
+func (s *webdavStore) close() {}
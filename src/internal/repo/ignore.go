@@ -0,0 +1,104 @@
+package repo
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// skippedDirs are never practiced from, whatever .gitignore says
+var skippedDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// ignorePattern is one line of a .gitignore file, covering the common subset of its syntax
+type ignorePattern struct {
+	pattern  string
+	anchored bool
+	dirOnly  bool
+	negate   bool
+}
+
+func loadIgnorePatterns(root string) []ignorePattern {
+	file, err := os.Open(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var patterns []ignorePattern
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var p ignorePattern
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			p.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		p.pattern = line
+		patterns = append(patterns, p)
+	}
+	return patterns
+}
+
+// ignored applies the patterns in order, so a later negation can re-include a path
+func ignored(patterns []ignorePattern, rel string, isDir bool) bool {
+	result := false
+	for _, p := range patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		target := filepath.Base(rel)
+		if p.anchored {
+			target = rel
+		}
+		if matched, _ := filepath.Match(p.pattern, target); matched {
+			result = !p.negate
+		}
+	}
+	return result
+}
+
+// walkFiles lists the files under root by hand for trees that aren't git checkouts
+func walkFiles(root string) ([]string, error) {
+	patterns := loadIgnorePatterns(root)
+
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if skippedDirs[d.Name()] || ignored(patterns, rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !ignored(patterns, rel, false) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, err
+}
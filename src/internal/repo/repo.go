@@ -0,0 +1,249 @@
+// Package repo extracts function-sized snippets from a local source repository for code practice.
+package repo
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gti/src/internal"
+)
+
+const (
+	// MinSnippetLines and MaxSnippetLines bound what counts as a function-sized snippet
+	MinSnippetLines = 3
+	MaxSnippetLines = 25
+	// MaxLineLength drops snippets with lines too wide to type comfortably
+	MaxLineLength = 100
+	// MaxFileSize skips generated or vendored blobs that happen to have a code extension
+	MaxFileSize = 256 * 1024
+	tabWidth    = 4
+)
+
+// Snippet is one function or block taken from a source file
+type Snippet struct {
+	File     string
+	Language string
+	Code     string
+}
+
+var functionStart = regexp.MustCompile(`^\s*(@\w+\s+)*((export|pub(\(\w+\))?|public|private|protected|internal|static|async|override|final|abstract|open|suspend|default)\s+)*(def|func|function|fn|fun|sub|class|struct|impl|trait|interface|module)\b`)
+var cStyleStart = regexp.MustCompile(`^\s*[\w<>\[\],.*&:~]+(\s+[\w<>\[\],.*&:~]+)*\s*\([^;]*\)\s*(const\s*)?(throws\s+[\w., ]+)?\{\s*$`)
+var controlStart = regexp.MustCompile(`^\s*(\}\s*)?(if|else|for|foreach|while|switch|catch|do|try|return|using|lock)\b`)
+
+// Snippets walks the repository at root and returns up to count random function-sized snippets.
+// When language is "" the repository's most common supported language is used. The language
+// actually practiced is returned alongside the snippets.
+func Snippets(root string, language string, count int) ([]string, string, error) {
+	files, err := listFiles(root)
+	if err != nil {
+		return nil, "", err
+	}
+
+	byLanguage := make(map[string][]Snippet)
+	for _, file := range files {
+		lang := internal.DetectCodeLanguage(filepath.Join(root, file))
+		if lang == "" || (language != "" && lang != language) {
+			continue
+		}
+		data, err := readSource(filepath.Join(root, file))
+		if err != nil {
+			continue
+		}
+		byLanguage[lang] = append(byLanguage[lang], extract(file, lang, data)...)
+	}
+
+	if language == "" {
+		language = mostCommon(byLanguage)
+	}
+	snippets := byLanguage[language]
+	if len(snippets) == 0 {
+		if language == "" {
+			return nil, "", fmt.Errorf("no function-sized snippets found in %s", root)
+		}
+		return nil, "", fmt.Errorf("no %s snippets found in %s", language, root)
+	}
+
+	return pick(snippets, count), language, nil
+}
+
+// FileSnippets returns up to count random function-sized snippets from a single source file
+func FileSnippets(path string, language string, count int) ([]string, error) {
+	data, err := readSource(path)
+	if err != nil {
+		return nil, err
+	}
+	snippets := extract(filepath.Base(path), language, data)
+	if len(snippets) == 0 {
+		return nil, fmt.Errorf("no function-sized snippets found in %s", filepath.Base(path))
+	}
+	return pick(snippets, count), nil
+}
+
+// pick shuffles the snippets and returns the code of the first count of them
+func pick(snippets []Snippet, count int) []string {
+	internal.Shuffle(len(snippets), func(i, j int) { snippets[i], snippets[j] = snippets[j], snippets[i] })
+	if count > 0 && count < len(snippets) {
+		snippets = snippets[:count]
+	}
+	codes := make([]string, len(snippets))
+	for i, snippet := range snippets {
+		codes[i] = snippet.Code
+	}
+	return codes
+}
+
+// listFiles returns the files git would track in root, relative to it. Outside a git checkout,
+// or without git installed, the tree is walked by hand honouring the top-level .gitignore.
+func listFiles(root string) ([]string, error) {
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+
+	out, err := exec.Command("git", "-C", root, "ls-files", "--cached", "--others", "--exclude-standard", "-z").Output()
+	if err == nil {
+		var files []string
+		for _, file := range bytes.Split(out, []byte{0}) {
+			if len(file) > 0 {
+				files = append(files, string(file))
+			}
+		}
+		return files, nil
+	}
+	return walkFiles(root)
+}
+
+func readSource(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() || info.Size() > MaxFileSize {
+		return "", fmt.Errorf("%s: not a source file", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return "", fmt.Errorf("%s: binary file", path)
+	}
+	return string(data), nil
+}
+
+// extract finds blocks that open with a function, class or method signature and runs them
+// to the line that brings the indentation back to where the block started
+func extract(file, language, source string) []Snippet {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(strings.ReplaceAll(line, "\t", strings.Repeat(" ", tabWidth)), " ")
+	}
+
+	var snippets []Snippet
+	for i := 0; i < len(lines); i++ {
+		if !isBlockStart(lines[i]) {
+			continue
+		}
+		end := blockEnd(lines, i)
+		if end < 0 {
+			continue
+		}
+		if code, ok := snippetCode(lines[i : end+1]); ok {
+			snippets = append(snippets, Snippet{File: file, Language: language, Code: code})
+			i = end
+		}
+	}
+	return snippets
+}
+
+func isBlockStart(line string) bool {
+	if strings.TrimSpace(line) == "" || controlStart.MatchString(line) {
+		return false
+	}
+	return functionStart.MatchString(line) || cStyleStart.MatchString(line)
+}
+
+// blockEnd returns the index of the block's last line, or -1 when it runs too long
+func blockEnd(lines []string, start int) int {
+	indent := indentOf(lines[start])
+	last := start
+	for j := start + 1; j < len(lines); j++ {
+		if j-start >= MaxSnippetLines {
+			return -1
+		}
+		trimmed := strings.TrimSpace(lines[j])
+		if trimmed == "" {
+			continue
+		}
+		if indentOf(lines[j]) <= indent {
+			if isCloser(trimmed) {
+				return j
+			}
+			return last
+		}
+		last = j
+	}
+	return last
+}
+
+func isCloser(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "}") || strings.HasPrefix(trimmed, ")") || strings.HasPrefix(trimmed, "]") ||
+		trimmed == "end" || strings.HasPrefix(trimmed, "end ") || trimmed == "end;" || trimmed == "fi" || trimmed == "done"
+}
+
+// snippetCode drops blank lines and the block's own indentation, rejecting blocks that are too short or too wide
+func snippetCode(block []string) (string, bool) {
+	indent := indentOf(block[0])
+	var kept []string
+	for _, line := range block {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if indentOf(line) >= indent {
+			line = line[indent:]
+		}
+		if len(line) > MaxLineLength || !isPrintableASCII(line) {
+			return "", false
+		}
+		kept = append(kept, line)
+	}
+	if len(kept) < MinSnippetLines {
+		return "", false
+	}
+	return strings.Join(kept, "\n"), true
+}
+
+// isPrintableASCII keeps snippets typeable: the session compares input byte by byte
+func isPrintableASCII(line string) bool {
+	for i := 0; i < len(line); i++ {
+		if line[i] < ' ' || line[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func mostCommon(byLanguage map[string][]Snippet) string {
+	languages := make([]string, 0, len(byLanguage))
+	for language := range byLanguage {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	best := ""
+	for _, language := range languages {
+		if len(byLanguage[language]) > len(byLanguage[best]) {
+			best = language
+		}
+	}
+	return best
+}
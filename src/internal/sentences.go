@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// minSentenceWords and maxSentenceWords bound the length of a generated sentence
+	minSentenceWords = 4
+	maxSentenceWords = 12
+	// commaAfter is the fewest words a sentence runs before it may pause on a comma
+	commaAfter = 3
+)
+
+// sentences makes generated text come as sentences rather than a stream of words, set with --sentences
+var sentences bool
+
+// UseSentences makes generated words come as sentences, from then on for this run
+func UseSentences() {
+	sentences = true
+}
+
+// GenerateSentences strings count words of the language into sentences that read like prose: each
+// starts with a capital and ends with a period, or now and then a question mark, and the longer ones
+// pause on a comma
+func GenerateSentences(count int, language string) string {
+	var out []string
+	for count > 0 {
+		n := minSentenceWords + rng.Intn(maxSentenceWords-minSentenceWords+1)
+		if count-n < minSentenceWords {
+			// Too few words would be left for a sentence of their own, so this one takes them
+			n = count
+		}
+		out = append(out, sentence(n, language))
+		count -= n
+	}
+	return strings.Join(out, " ")
+}
+
+// sentence builds one sentence of n words
+func sentence(n int, language string) string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = GenerateWord(language)
+	}
+	words[0] = capitalize(words[0])
+
+	if n > 2*commaAfter && rng.Intn(2) == 0 {
+		at := commaAfter - 1 + rng.Intn(n-2*commaAfter+1)
+		words[at] += ","
+	}
+	end := "."
+	if rng.Intn(8) == 0 {
+		end = "?"
+	}
+	words[n-1] += end
+	return strings.Join(words, " ")
+}
+
+// capitalize upper-cases the first letter of word, leaving scripts without case as they are
+func capitalize(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	if r == utf8.RuneError {
+		return word
+	}
+	return string(unicode.ToUpper(r)) + word[size:]
+}
@@ -0,0 +1,50 @@
+package session
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"gti/src/internal/config"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// substitute stands in the typed text for the bytes of a character that a shorter or longer one was
+// typed in place of. The typed text keeps to the text byte for byte, and the ASCII substitute control
+// character never turns up in a text to be mistaken for a match.
+const substitute = "\x1a"
+
+// charAt returns the whole character of the text at pos, however many bytes it takes
+func (s *Session) charAt(pos int) string {
+	_, size := utf8.DecodeRuneInString(s.text[pos:])
+	return s.text[pos : pos+size]
+}
+
+// baseLetter returns the letter r is written with before any accent, e for é and N for Ñ; anything
+// without an accent comes back as it is
+func baseLetter(r rune) rune {
+	base, _ := utf8.DecodeRuneInString(norm.NFD.String(string(r)))
+	return base
+}
+
+// accentSlip reports whether typed is expected with its accent left off, in a run that takes that
+func (s *Session) accentSlip(typed, expected string) bool {
+	if s.config.Keyboard.Accents != config.AccentsLoose && s.config.Keyboard.Accents != config.AccentsPartial {
+		return false
+	}
+	t, _ := utf8.DecodeRuneInString(typed)
+	e, _ := utf8.DecodeRuneInString(expected)
+	return t != e && baseLetter(e) == t
+}
+
+// alignTyped is what goes into the typed text for char typed in place of expected: char itself when
+// the two are as long, and otherwise as much of char as fits, padded out with substitutes
+func alignTyped(char, expected string) string {
+	if len(char) == len(expected) {
+		return char
+	}
+	if len(char) > len(expected) {
+		return strings.Repeat(substitute, len(expected))
+	}
+	return char + strings.Repeat(substitute, len(expected)-len(char))
+}
@@ -0,0 +1,185 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"gti/src/internal/vault"
+)
+
+const (
+	// adviceMinChars is how much of a run must be typed before its own signals pick the advice
+	adviceMinChars = 40
+	// adviceEvery is how often the advice is looked at again when tips do not rotate
+	adviceEvery = 10 * time.Second
+
+	adviceBackspaceRate = 15.0
+	adviceLowAccuracy   = 90.0
+	// adviceKeyClassRate is the error rate on symbols or digits that points to their drill
+	adviceKeyClassRate    = 20.0
+	adviceKeyClassMinHits = 5
+	adviceWeakKeyErrors   = 3
+	adviceSlowdown        = 15.0
+
+	// storedAdviceSessions is how many of the latest sessions the stored key stats are summed over
+	storedAdviceSessions = 50
+	storedWeakKeyMinHits = 50
+	storedWeakKeyRate    = 8.0
+)
+
+// Advice picks a tip while typing from what the run shows so far, and from the keys missed most in
+// past sessions, showing it in place of the rotating tips. It is looked at again when the tip would
+// rotate, so it does not flicker with every key.
+type Advice struct {
+	advicePicked bool
+	adviceAt     adviceSlot
+	adviceText   string
+	// storedWeak are the keys most often missed in past sessions, worst first, loaded the first
+	// time advice is wanted
+	storedWeak       []weakKey
+	storedWeakLoaded bool
+}
+
+type adviceSlot struct {
+	start time.Time
+	slot  int
+}
+
+type weakKey struct {
+	key  string
+	rate float64
+}
+
+// currentAdvice is the advice for now, or "" when nothing stands out
+func (s *Session) currentAdvice() string {
+	if !s.config.Tips.Enabled || !s.config.Tips.Advice {
+		return ""
+	}
+	every := time.Duration(s.config.Tips.RotateSeconds) * time.Second
+	if every <= 0 {
+		every = adviceEvery
+	}
+	at := adviceSlot{s.startTime, int(s.duration / every)}
+	if !s.advicePicked || at != s.adviceAt {
+		s.advicePicked = true
+		s.adviceAt = at
+		s.adviceText = s.pickAdvice()
+	}
+	return s.adviceText
+}
+
+// pickAdvice goes through the signals, most pressing first
+func (s *Session) pickAdvice() string {
+	chars := s.totalChars + len(s.userInput)
+	if chars >= adviceMinChars {
+		if rate := float64(s.backspaceCount) / float64(chars) * 100; rate >= adviceBackspaceRate {
+			return fmt.Sprintf("You have backspaced %.0f%% of keys: slow down so each one lands first time", rate)
+		}
+		if CalculateAccuracy(chars, s.totalMistakes+s.mistakes) < adviceLowAccuracy {
+			return "Accuracy is slipping: ease off the pace until the mistakes stop"
+		}
+		if rate, ok := s.keyClassErrorRate(isSymbolKey); ok && rate >= adviceKeyClassRate {
+			return fmt.Sprintf("You miss %.0f%% of symbols: practise them with 'gti drill symbols'", rate)
+		}
+		if rate, ok := s.keyClassErrorRate(isDigitKey); ok && rate >= adviceKeyClassRate {
+			return fmt.Sprintf("You miss %.0f%% of digits: practise them with 'gti drill numbers'", rate)
+		}
+		if key, errors := s.weakestLiveKey(); errors >= adviceWeakKeyErrors {
+			return fmt.Sprintf("'%s' keeps slipping (%d misses): reach for it with the right finger", keyName(key), errors)
+		}
+		if whole := int(s.duration / time.Second); whole >= 2*fatigueMinSeconds && s.Fatigue() >= adviceSlowdown {
+			return "Your pace is dropping: relax your hands and shoulders and breathe"
+		}
+	}
+	for _, weak := range s.storedWeakKeys() {
+		if strings.Contains(s.text, weak.key) {
+			return fmt.Sprintf("Watch for '%s' here: you miss it %.0f%% of the time", keyName(weak.key), weak.rate)
+		}
+	}
+	return ""
+}
+
+// keyClassErrorRate is the error rate of this run on the keys in the class, if enough were typed
+func (s *Session) keyClassErrorRate(in func(string) bool) (float64, bool) {
+	var total KeyStat
+	for key, stat := range s.keyStats {
+		if in(key) {
+			total.Typed += stat.Typed
+			total.Errors += stat.Errors
+		}
+	}
+	if total.Typed < adviceKeyClassMinHits {
+		return 0, false
+	}
+	return float64(total.Errors) / float64(total.Typed) * 100, true
+}
+
+func isSymbolKey(key string) bool {
+	r, size := utf8.DecodeRuneInString(key)
+	return size == len(key) && (unicode.IsPunct(r) || unicode.IsSymbol(r))
+}
+
+func isDigitKey(key string) bool {
+	r, size := utf8.DecodeRuneInString(key)
+	return size == len(key) && unicode.IsDigit(r)
+}
+
+// weakestLiveKey is the key missed most in this run, and how often
+func (s *Session) weakestLiveKey() (string, int) {
+	worst, errors := "", 0
+	for key, stat := range s.keyStats {
+		if stat.Errors > errors || stat.Errors == errors && errors > 0 && key < worst {
+			worst, errors = key, stat.Errors
+		}
+	}
+	return worst, errors
+}
+
+// storedWeakKeys are the keys missed most often over the latest sessions. An encrypted history
+// that has not been unlocked is left unread, as there is no asking for the passphrase mid-session.
+func (s *Session) storedWeakKeys() []weakKey {
+	if s.storedWeakLoaded {
+		return s.storedWeak
+	}
+	s.storedWeakLoaded = true
+	if vault.HasKey(s.config) && !vault.Unlocked() {
+		return nil
+	}
+	records, err := LoadSessionRecords(s.config)
+	if err != nil {
+		return nil
+	}
+	records = CountedRecords(records)
+	if len(records) > storedAdviceSessions {
+		records = records[:storedAdviceSessions]
+	}
+
+	totals := make(map[string]KeyStat)
+	for _, r := range records {
+		for key, stat := range r.KeyStats {
+			total := totals[key]
+			total.Typed += stat.Typed
+			total.Errors += stat.Errors
+			totals[key] = total
+		}
+	}
+	for key, stat := range totals {
+		if stat.Typed < storedWeakKeyMinHits {
+			continue
+		}
+		if rate := float64(stat.Errors) / float64(stat.Typed) * 100; rate >= storedWeakKeyRate {
+			s.storedWeak = append(s.storedWeak, weakKey{key, rate})
+		}
+	}
+	sort.Slice(s.storedWeak, func(i, j int) bool {
+		if s.storedWeak[i].rate != s.storedWeak[j].rate {
+			return s.storedWeak[i].rate > s.storedWeak[j].rate
+		}
+		return s.storedWeak[i].key < s.storedWeak[j].key
+	})
+	return s.storedWeak
+}
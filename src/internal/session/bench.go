@@ -0,0 +1,120 @@
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"gti/src/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// BenchRun is a recorded run for gti bench to play back: the text, and each key press with its time
+// from the start, either as keystrokes in milliseconds, as replay files hold them, or as the events of
+// a JSON timing export, in microseconds. Keys are single characters, "\n" for Enter, or "backspace".
+type BenchRun struct {
+	Text string `json:"text"`
+	// Code types the text as code, with Enter skipping the next line's indentation
+	Code bool `json:"code,omitempty"`
+	// Seconds ends the run after this long, as a timed test does
+	Seconds     int  `json:"seconds,omitempty"`
+	StopOnError bool `json:"stop_on_error,omitempty"`
+	NoBackspace bool `json:"no_backspace,omitempty"`
+
+	Keystrokes []Keystroke   `json:"keystrokes,omitempty"`
+	Events     []TimingEvent `json:"events,omitempty"`
+}
+
+// benchKey is a key press of a recorded run, at its time from the start
+type benchKey struct {
+	at  time.Duration
+	key string
+}
+
+// presses lists the run's key presses, in the order they were made
+func (t BenchRun) presses() ([]benchKey, error) {
+	var keys []benchKey
+	for _, k := range t.Keystrokes {
+		keys = append(keys, benchKey{at: time.Duration(k.OffsetMs) * time.Millisecond, key: k.Key})
+	}
+	for _, e := range t.Events {
+		if e.Event == "key" {
+			keys = append(keys, benchKey{at: time.Duration(e.OffsetUs) * time.Microsecond, key: e.Key})
+		}
+	}
+	if len(t.Keystrokes) > 0 && len(t.Events) > 0 {
+		return nil, fmt.Errorf("a run holds keystrokes or events, not both")
+	}
+	for i := 1; i < len(keys); i++ {
+		if keys[i].at < keys[i-1].at {
+			return nil, fmt.Errorf("key %d is timed before the one ahead of it", i+1)
+		}
+	}
+	return keys, nil
+}
+
+// benchKeyMsg is the key press the terminal would have sent for key
+func benchKeyMsg(key string) tea.KeyMsg {
+	switch key {
+	case "backspace":
+		return tea.KeyMsg{Type: tea.KeyBackspace}
+	case "\n", "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+// Bench plays the run's key presses through a session whose clock is set to each one's time,
+// drawing nothing and saving nothing, and reports the results as --json-result would
+func Bench(cfg *config.Config, t BenchRun) (ResultReport, error) {
+	if t.Text == "" {
+		return ResultReport{}, fmt.Errorf("the run has no text")
+	}
+	keys, err := t.presses()
+	if err != nil {
+		return ResultReport{}, err
+	}
+
+	// Only the engine is measured: keys go in as recorded, whatever their pace, and nothing is kept
+	benchCfg := *cfg
+	benchCfg.History.Enabled = false
+	benchCfg.Sound.Enabled = false
+	benchCfg.Hooks.OnComplete = ""
+	benchCfg.Idle.Seconds = 0
+	benchCfg.Display.ChunkSummary = false
+	benchCfg.Display.ShowGhost = false
+	benchCfg.Keyboard.Paste = config.PasteMark
+	benchCfg.Keyboard.Emulate = ""
+	benchCfg.Keyboard.EmulateOnce = ""
+
+	mode := "bench"
+	if t.Code {
+		mode = "bench-code"
+	}
+	start := time.Now()
+	now := start
+	s := NewSession(&benchCfg, mode,
+		WithText(t.Text, nil, 0),
+		WithTimeLimit(t.Seconds),
+		WithInputPolicy(t.StopOnError, t.NoBackspace),
+		WithClock(func() time.Time { return now }))
+
+	s.Start()
+	for _, k := range keys {
+		now = start.Add(k.at)
+		s.UpdateTimer()
+		if s.completed {
+			break
+		}
+		s.HandleInput(benchKeyMsg(k.key))
+		if s.completed {
+			break
+		}
+	}
+	if !s.completed && t.Seconds > 0 {
+		// A timed test runs out its clock after the last key, as it would on screen
+		now = start.Add(time.Duration(t.Seconds) * time.Second)
+		s.UpdateTimer()
+	}
+	return NewResultsCalculator().BuildReport(s), nil
+}
@@ -0,0 +1,31 @@
+package session
+
+import "fmt"
+
+// Bookmark records where a book continues, given the index of the next paragraph to type
+type Bookmark func(next int)
+
+type BookState struct {
+	bookmark Bookmark
+}
+
+// EnableBookmark calls mark each time a paragraph is finished, so the book can be resumed there
+func (s *Session) EnableBookmark(mark Bookmark) {
+	s.bookmark = mark
+}
+
+// markBook passes the next paragraph to the bookmark once one is finished; a paragraph left half typed is typed again
+func (s *Session) markBook() {
+	if s.bookmark != nil {
+		s.bookmark(s.chunkIndex)
+	}
+}
+
+// bookLabel shows the paragraph being typed and how far through the whole book it is
+func (s *Session) bookLabel() string {
+	if s.bookmark == nil || len(s.allChunks) == 0 {
+		return ""
+	}
+	paragraph := min(s.chunkIndex+1, len(s.allChunks))
+	return fmt.Sprintf(" [%d/%d, %.1f%%]", paragraph, len(s.allChunks), s.calculateProgress())
+}
@@ -0,0 +1,115 @@
+package session
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// BotVariance is the standard deviation of the bot's per-second speed around its target
+	BotVariance = 0.08
+	// MaxBotWPM keeps the simulated opponent within humanly plausible speeds
+	MaxBotWPM = 300
+)
+
+// Bot is a simulated opponent that types at a target WPM with slight per-second variance
+type Bot struct {
+	WPM    float64
+	speeds []float64
+	rng    *rand.Rand
+}
+
+func NewBot(wpm float64) *Bot {
+	if wpm > MaxBotWPM {
+		wpm = MaxBotWPM
+	}
+	return &Bot{
+		WPM: wpm,
+		rng: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// speedAt returns the bot's typing speed in characters per second during the given second
+func (b *Bot) speedAt(second int) float64 {
+	for len(b.speeds) <= second {
+		factor := 1 + b.rng.NormFloat64()*BotVariance
+		if factor < 0.5 {
+			factor = 0.5
+		}
+		if factor > 1.5 {
+			factor = 1.5
+		}
+		b.speeds = append(b.speeds, b.WPM*CharsPerWord/60*factor)
+	}
+	return b.speeds[second]
+}
+
+// CharsAt returns how many characters the bot has typed after the given elapsed time
+func (b *Bot) CharsAt(elapsed time.Duration) int {
+	if elapsed <= 0 {
+		return 0
+	}
+	seconds := elapsed.Seconds()
+	whole := int(seconds)
+
+	var chars float64
+	for i := 0; i < whole; i++ {
+		chars += b.speedAt(i)
+	}
+	chars += b.speedAt(whole) * (seconds - float64(whole))
+	return int(chars)
+}
+
+// SetBot adds a simulated opponent racing at the given WPM
+func (s *Session) SetBot(wpm float64) {
+	if wpm <= 0 {
+		s.bot = nil
+		return
+	}
+	s.bot = NewBot(wpm)
+}
+
+func (s *Session) botElapsed() time.Duration {
+	if s.running {
+		return s.since(s.startTime)
+	}
+	return s.duration
+}
+
+// botPosition returns the bot's position within the current chunk, or -1 when it is elsewhere
+func (s *Session) botPosition() int {
+	if s.bot == nil || (!s.running && !s.completed) {
+		return -1
+	}
+	pos := s.bot.CharsAt(s.botElapsed()) - s.totalChars
+	if pos < 0 || pos >= len(s.text) {
+		return -1
+	}
+	return pos
+}
+
+// botLead returns how many characters the user is ahead of the bot (negative when behind)
+func (s *Session) botLead() int {
+	return s.totalChars + len(s.userInput) - s.bot.CharsAt(s.botElapsed())
+}
+
+func (s *Session) botStyle(style lipgloss.Style) lipgloss.Style {
+	return style.
+		Foreground(lipgloss.Color(s.config.Theme.Colors.Background)).
+		Background(lipgloss.Color(s.config.Theme.Colors.Current)).
+		Faint(false)
+}
+
+// BotSummary describes the outcome of the race against the bot, or "" when there was none
+func (s *Session) BotSummary() string {
+	if s.bot == nil {
+		return ""
+	}
+	if s.botLead() >= 0 {
+		return fmt.Sprintf("Bot (%.0f WPM): You win!", s.bot.WPM)
+	}
+	return fmt.Sprintf("Bot (%.0f WPM): Bot wins", s.bot.WPM)
+}
@@ -0,0 +1,155 @@
+package session
+
+import (
+	"gti/src/internal/config"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// cellKind is how a character of the text stands, as far as drawing it goes
+type cellKind uint8
+
+const (
+	// cellUnrendered is the zero value, so a fresh cache renders every cell it is asked for
+	cellUnrendered cellKind = iota
+	cellCorrect
+	cellIncorrect
+	cellCurrent
+	cellWord
+	cellPending
+)
+
+// cellState is everything a character's look depends on; a cell is restyled only when it changes
+type cellState struct {
+	kind cellKind
+	// pending is the color of untyped text, which code tints by token kind
+	pending string
+	ghost   bool
+	bot     bool
+}
+
+// CellCache holds each character of the text as last rendered, with the state it was rendered in.
+// Typing a key changes the state of only a few characters, the one typed, the cursor and perhaps
+// the word highlight, so only those are restyled on the next frame.
+type CellCache struct {
+	cellText  string
+	cellTheme config.ThemeConfig
+	cellCode  bool
+	states    []cellState
+	cells     []string
+
+	// styles holds the style of each state met so far; the handful every text uses are built as soon
+	// as the theme is, so typing never constructs a style, and a theme change starts them over
+	styleTheme      config.ThemeConfig
+	styles          map[cellState]lipgloss.Style
+	lineNumberStyle lipgloss.Style
+}
+
+// syncCells starts the cache over when the text, the theme or the way the text is drawn has changed
+func (s *Session) syncCells(code bool) {
+	s.syncStyles()
+	if s.cellText == s.text && s.cellTheme == s.config.Theme && s.cellCode == code && len(s.cells) == len(s.text) {
+		return
+	}
+	s.cellText = s.text
+	s.cellTheme = s.config.Theme
+	s.cellCode = code
+	s.states = make([]cellState, len(s.text))
+	s.cells = make([]string, len(s.text))
+}
+
+// styledCell returns glyph, the character at pos, rendered in state, reusing the last rendering
+// when the state has not changed since
+func (s *Session) styledCell(pos int, glyph string, state cellState) string {
+	if pos >= len(s.cells) {
+		return s.cellStyle(state).Render(glyph)
+	}
+	if s.states[pos] != state {
+		s.cells[pos] = s.cellStyle(state).Render(glyph)
+		s.states[pos] = state
+	}
+	return s.cells[pos]
+}
+
+// syncStyles builds the styles of the correct, incorrect, cursor, word and pending characters, and of
+// code's line numbers, whenever the theme is new
+func (s *Session) syncStyles() {
+	if s.styles != nil && s.styleTheme == s.config.Theme {
+		return
+	}
+	s.styleTheme = s.config.Theme
+	s.styles = make(map[cellState]lipgloss.Style)
+	for _, state := range []cellState{
+		{kind: cellCorrect},
+		{kind: cellIncorrect},
+		{kind: cellCurrent},
+		{kind: cellWord},
+		{kind: cellPending, pending: s.config.Theme.Colors.Pending},
+	} {
+		s.styles[state] = s.buildCellStyle(state)
+	}
+	s.lineNumberStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(s.config.Theme.Colors.TextSecondary))
+}
+
+// cellStyle is the style a character is drawn in for its state, built the first time the state is met
+func (s *Session) cellStyle(state cellState) lipgloss.Style {
+	if style, ok := s.styles[state]; ok {
+		return style
+	}
+	style := s.buildCellStyle(state)
+	s.styles[state] = style
+	return style
+}
+
+// buildCellStyle constructs the style for a state
+func (s *Session) buildCellStyle(state cellState) lipgloss.Style {
+	colors := s.config.Theme.Colors
+	style := lipgloss.NewStyle().Background(lipgloss.Color(colors.Background))
+	switch state.kind {
+	case cellCorrect:
+		style = style.Foreground(lipgloss.Color(colors.Correct))
+	case cellIncorrect:
+		style = s.incorrectStyle(style)
+	case cellCurrent:
+		style = style.Foreground(lipgloss.Color(colors.WordHighlight)).Faint(true)
+		if s.config.Theme.Styles.UnderlineCurrent {
+			style = style.Underline(true)
+		}
+	case cellWord:
+		style = style.Foreground(lipgloss.Color(colors.WordHighlight))
+	default:
+		style = style.Foreground(lipgloss.Color(state.pending))
+		if s.config.Theme.Styles.DimPending {
+			style = style.Faint(true)
+		}
+	}
+	if state.ghost {
+		style = s.ghostStyle(style)
+	}
+	if state.bot {
+		style = s.botStyle(style)
+	}
+	return style
+}
+
+// typedCellState is the state of the character at pos, glyph, as far as typing has reached. A
+// character of several bytes is one cell, holding the cursor while any of its bytes is next.
+func (s *Session) typedCellState(pos int, glyph string, ghostPos, botPos int) cellState {
+	end := pos + len(glyph)
+	var state cellState
+	switch {
+	case end <= s.position:
+		state.kind = cellIncorrect
+		if end <= len(s.userInput) && s.userInput[pos:end] == glyph {
+			state.kind = cellCorrect
+		}
+	case pos <= s.position:
+		state.kind = cellCurrent
+	default:
+		state.kind = cellPending
+	}
+	covers := func(p int) bool { return p >= pos && p < end }
+	state.ghost = covers(ghostPos) && state.kind != cellCurrent
+	state.bot = covers(botPos) && state.kind != cellCurrent
+	return state
+}
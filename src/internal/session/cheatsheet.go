@@ -0,0 +1,63 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+
+	"gti/src/internal/keymap"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// CheatSheetCellWidth is the room given to one character and its keys in the cheat sheet grid
+const CheatSheetCellWidth = 30
+
+// ToggleCheatSheet cycles what is shown under the text: where the text's hard-to-find characters are
+// on the keyboard layout, then the on-screen keyboard, then the usual tip
+func (s *Session) ToggleCheatSheet() {
+	switch {
+	case s.showCheatSheet:
+		s.showCheatSheet = false
+		s.showKeyboard = true
+	case s.showKeyboard:
+		s.showKeyboard = false
+	case s.emulation != nil:
+		// The cheat sheet describes the system's layout, which emulation bypasses
+		s.showKeyboard = true
+	default:
+		s.showCheatSheet = true
+	}
+	s.layoutDirty = true
+}
+
+func (s *Session) renderCheatSheet(width int) string {
+	language := ""
+	if !s.IsCodeMode() {
+		language = s.config.Language.Default
+	}
+	layout := s.keyboardLayout()
+	entries := keymap.CheatSheet(layout, language, s.text)
+
+	colors := s.config.Theme.Colors
+	charStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Accent)).Background(lipgloss.Color(colors.Background)).Bold(true)
+	keysStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.TextSecondary)).Background(lipgloss.Color(colors.Background))
+
+	columns := max(1, min(3, (width-4)/CheatSheetCellWidth))
+	var rows []string
+	for i := 0; i < len(entries); i += columns {
+		var cells []string
+		for _, entry := range entries[i:min(i+columns, len(entries))] {
+			cell := charStyle.Render(entry.Char) + keysStyle.Render("  "+entry.Keys)
+			cells = append(cells, keysStyle.Width(CheatSheetCellWidth).Render(cell))
+		}
+		// Pad short rows so the grid's columns line up once centered
+		rows = append(rows, keysStyle.Width(columns*CheatSheetCellWidth).Render(lipgloss.JoinHorizontal(lipgloss.Top, cells...)))
+	}
+
+	title := fmt.Sprintf("Keys on %s (%s for the keyboard)", layout, s.keys.Label(keymap.ActionCheatSheet))
+	lines := append([]string{s.renderCenteredText(title, colors.TextPrimary, width)}, rows...)
+	for i := 1; i < len(lines); i++ {
+		lines[i] = s.renderCenteredText(lines[i], colors.TextSecondary, width)
+	}
+	return strings.Join(lines, "\n")
+}
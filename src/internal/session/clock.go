@@ -0,0 +1,32 @@
+package session
+
+import "time"
+
+// Clock is where a session reads the time. Live sessions read the wall clock; gti bench sets it to the
+// time of each recorded key press, so a played back run is timed exactly as it was typed.
+type Clock struct {
+	clockNow func() time.Time
+}
+
+// WithClock makes the session read the time from now instead of the wall clock
+func WithClock(now func() time.Time) SessionOption {
+	return func(c *SessionConfig) {
+		c.Clock = now
+	}
+}
+
+func (s *Session) now() time.Time {
+	if s.clockNow != nil {
+		return s.clockNow()
+	}
+	return time.Now()
+}
+
+func (s *Session) since(t time.Time) time.Duration {
+	return s.now().Sub(t)
+}
+
+// playedBack reports whether the session is replaying a recorded run rather than being typed
+func (s *Session) playedBack() bool {
+	return s.clockNow != nil
+}
@@ -0,0 +1,28 @@
+package session
+
+import "strings"
+
+const (
+	// StructuralMistakeWeight is how many mistakes a wrong bracket, quote or separator counts as in document mode
+	StructuralMistakeWeight = 3
+	structuralChars         = "{}[]()\"':,="
+)
+
+// isStructural reports whether the expected character holds a document together: in document mode a
+// broken bracket or quote invalidates the whole file
+func (s *Session) isStructural(expected string) bool {
+	return s.mode == "document" && strings.Contains(structuralChars, expected)
+}
+
+// mistakeWeight returns how much a mistake at the expected character counts towards the score, more
+// than a typo for a structural one
+func (s *Session) mistakeWeight(expected string) int {
+	if s.isStructural(expected) {
+		return StructuralMistakeWeight
+	}
+	return 1
+}
+
+func (s *Session) GetStructuralErrors() int {
+	return s.structuralErrors
+}
@@ -0,0 +1,185 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+
+	"gti/src/internal/cjk"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type unitState int
+
+const (
+	unitPending unitState = iota
+	unitCurrent
+	unitCorrect
+	unitIncorrect
+)
+
+// WithDrill sets a kana, Hangul or pinyin drill, typed through its ASCII input but shown as glyphs
+func WithDrill(drill *cjk.Drill) SessionOption {
+	return func(c *SessionConfig) {
+		c.Drill = drill
+	}
+}
+
+// unitState compares the input typed so far against one unit of the drill
+func (s *Session) unitState(start int, unit cjk.Unit) unitState {
+	end := start + len(unit.Input)
+	if s.position < start {
+		return unitPending
+	}
+
+	typedEnd := min(s.position, end)
+	if typedEnd > len(s.userInput) {
+		typedEnd = len(s.userInput)
+	}
+	if typedEnd < start {
+		return unitPending
+	}
+	if s.userInput[start:typedEnd] != s.text[start:typedEnd] {
+		return unitIncorrect
+	}
+	if s.position < end {
+		return unitCurrent
+	}
+	return unitCorrect
+}
+
+// DrillScore returns how many glyph units have been typed in full and how many of those were right,
+// or for a listening drill how many words were heard and how many of those were typed right
+func (s *Session) DrillScore() (typed int, correct int) {
+	if s.listening {
+		return s.listenWords, s.listenCorrect
+	}
+	if s.drill == nil {
+		return 0, 0
+	}
+	if s.completed {
+		return s.drillUnits, s.drillCorrect
+	}
+	return s.scoreDrill()
+}
+
+// scoreDrill scores the drill from the input of the chunk in progress
+func (s *Session) scoreDrill() (typed int, correct int) {
+	for i, unit := range s.drill.Units {
+		start := s.unitStarts[i]
+		if unit.IsSpace() || s.position < start+len(unit.Input) {
+			continue
+		}
+		typed++
+		if s.unitState(start, unit) == unitCorrect {
+			correct++
+		}
+	}
+	return typed, correct
+}
+
+// DrillUnitName returns what a drill scores ("kana", "jamo", "character" or "word"), or "" outside drills
+func (s *Session) DrillUnitName() string {
+	if s.listening {
+		return "word"
+	}
+	if s.drill == nil {
+		return ""
+	}
+	return s.drill.UnitName
+}
+
+// optionalToneAt reports whether the key expected at pos is a tone number the typist may leave out
+func (s *Session) optionalToneAt(pos int) bool {
+	return s.drill != nil && s.drill.OptionalTones && pos < len(s.text) && cjk.IsToneNumber(s.text[pos])
+}
+
+// skipTone fills in the expected tone number when the typist moved on without it
+func (s *Session) skipTone(typed string) {
+	if !s.optionalToneAt(s.position) || cjk.IsToneNumber(typed[0]) {
+		return
+	}
+	s.userInput += string(s.text[s.position])
+	s.correctChars++
+	s.position++
+}
+
+// renderDrillContent shows the drill as glyphs, colouring each by the state of the units it is made of
+func (s *Session) renderDrillContent() string {
+	states := make([]unitState, len(s.drill.Clusters))
+	complete := make([]bool, len(s.drill.Clusters))
+	for i := range complete {
+		complete[i] = true
+	}
+
+	current := -1
+	for i, unit := range s.drill.Units {
+		state := s.unitState(s.unitStarts[i], unit)
+		c := unit.Cluster
+		if state != unitCorrect {
+			complete[c] = false
+		}
+		if state == unitIncorrect {
+			states[c] = unitIncorrect
+		} else if state == unitCurrent && states[c] != unitIncorrect {
+			states[c] = unitCurrent
+		}
+		if s.position == s.unitStarts[i] && current == -1 {
+			current = c
+			if states[c] != unitIncorrect {
+				states[c] = unitCurrent
+			}
+		}
+		if state == unitCurrent {
+			current = c
+		}
+	}
+
+	colors := s.config.Theme.Colors
+	var rendered strings.Builder
+	for c, glyph := range s.drill.Clusters {
+		style := lipgloss.NewStyle().Background(lipgloss.Color(colors.Background))
+		switch {
+		case states[c] == unitIncorrect:
+			style = s.incorrectStyle(style)
+		case states[c] == unitCurrent:
+			style = style.Foreground(lipgloss.Color(colors.WordHighlight))
+			if s.config.Theme.Styles.UnderlineCurrent {
+				style = style.Underline(true)
+			}
+		case complete[c]:
+			style = style.Foreground(lipgloss.Color(colors.Correct))
+		default:
+			style = style.Foreground(lipgloss.Color(colors.Pending))
+		}
+		rendered.WriteString(style.Render(glyph))
+	}
+
+	hint := s.drillHint(current)
+	if hint == "" {
+		return rendered.String()
+	}
+	return rendered.String() + "\n\n" + lipgloss.NewStyle().
+		Foreground(lipgloss.Color(colors.TextSecondary)).
+		Background(lipgloss.Color(colors.Background)).
+		Render(hint)
+}
+
+// drillHint spells out how to type the current glyph, jamo by jamo for Hangul
+func (s *Session) drillHint(cluster int) string {
+	if cluster < 0 || s.drill.Clusters[cluster] == " " {
+		return ""
+	}
+
+	var glyphs, inputs []string
+	for _, unit := range s.drill.Units {
+		if unit.Cluster == cluster {
+			glyphs = append(glyphs, unit.Glyph)
+			inputs = append(inputs, unit.Input)
+		}
+	}
+	if len(glyphs) == 1 {
+		return fmt.Sprintf("%s → %s", s.drill.Clusters[cluster], inputs[0])
+	}
+	return fmt.Sprintf("%s = %s → %s", s.drill.Clusters[cluster], strings.Join(glyphs, " "), strings.Join(inputs, " "))
+}
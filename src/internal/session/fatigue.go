@@ -0,0 +1,104 @@
+package session
+
+import (
+	"fmt"
+	"time"
+)
+
+// fatigueMinSeconds is the shortest run whose thirds are long enough to compare
+const fatigueMinSeconds = 15
+
+// fatigueNoticePercent is how far the final third must fall from, or rise over, the first before the
+// results screen calls the pace anything but steady
+const fatigueNoticePercent = 5.0
+
+// fatigueRecentSessions is how many of the latest measured sessions the trend sets against the rest
+const fatigueRecentSessions = 10
+
+// Fatigue is how much slower, in percent, the final third of the run was typed than the first,
+// negative when it sped up. Only whole seconds count, as the partial last one is too short to judge
+// a pace by. It is 0 for runs too short to tell.
+func (s *Session) Fatigue() float64 {
+	whole := min(len(s.samples), int(s.duration/time.Second))
+	if whole < fatigueMinSeconds {
+		return 0
+	}
+	third := whole / 3
+	first := meanSampleWPM(s.samples[:third])
+	last := meanSampleWPM(s.samples[whole-third : whole])
+	if first <= 0 {
+		return 0
+	}
+	return (first - last) / first * 100
+}
+
+func meanSampleWPM(samples []Sample) float64 {
+	sum := 0.0
+	for _, sample := range samples {
+		sum += sample.WPM
+	}
+	return sum / float64(len(samples))
+}
+
+// FatigueSummary says how the pace held up over the run, or "" when it was too short to tell
+func (s *Session) FatigueSummary() string {
+	if int(s.duration/time.Second) < fatigueMinSeconds {
+		return ""
+	}
+	fatigue := s.Fatigue()
+	switch {
+	case fatigue >= fatigueNoticePercent:
+		return fmt.Sprintf("Fatigue: you slowed %.0f%% in the final third", fatigue)
+	case fatigue <= -fatigueNoticePercent:
+		return fmt.Sprintf("Pace: you sped up %.0f%% in the final third", -fatigue)
+	}
+	return "Pace: steady from start to finish"
+}
+
+// FatigueTrend is how much sessions slowed towards their end on average, and whether that is getting
+// better, from the sessions that measured it
+type FatigueTrend struct {
+	Sessions int     `json:"sessions"`
+	Average  float64 `json:"average"`
+	// Recent averages the latest RecentSessions sessions and Earlier the ones before, both 0 until
+	// there are some of each
+	RecentSessions int     `json:"recent_sessions,omitempty"`
+	Recent         float64 `json:"recent,omitempty"`
+	Earlier        float64 `json:"earlier,omitempty"`
+}
+
+// AnalyzeFatigue averages the slowdown of the records, newest first as the history is loaded, that
+// measured one
+func AnalyzeFatigue(records []*SessionRecord) FatigueTrend {
+	var trend FatigueTrend
+	var recent, earlier []float64
+	for _, r := range records {
+		if r.Fatigue == 0 {
+			continue
+		}
+		if len(recent) < fatigueRecentSessions {
+			recent = append(recent, r.Fatigue)
+		} else {
+			earlier = append(earlier, r.Fatigue)
+		}
+	}
+	trend.Sessions = len(recent) + len(earlier)
+	if trend.Sessions == 0 {
+		return trend
+	}
+	trend.Average = (sum(recent) + sum(earlier)) / float64(trend.Sessions)
+	if len(earlier) > 0 {
+		trend.RecentSessions = len(recent)
+		trend.Recent = sum(recent) / float64(len(recent))
+		trend.Earlier = sum(earlier) / float64(len(earlier))
+	}
+	return trend
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
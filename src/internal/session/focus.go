@@ -0,0 +1,126 @@
+package session
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// MaxFocusWords caps how many problem words from one chunk are looped before moving on
+const MaxFocusWords = 3
+
+type Focus struct {
+	focusReps   int
+	focusQueue  []string
+	focusStreak int
+	focusResume string
+}
+
+// SetFocusReps loops each mistyped word until it is typed cleanly this many times in a row; 0 disables it
+func (s *Session) SetFocusReps(reps int) {
+	if reps < 0 {
+		reps = 0
+	}
+	s.focusReps = reps
+}
+
+func (s *Session) inFocusLoop() bool {
+	return len(s.focusQueue) > 0
+}
+
+// missedWords returns the words of the finished chunk that were typed with a mistake, in order and without repeats
+func (s *Session) missedWords() []string {
+	var missed []string
+	seen := make(map[string]bool)
+
+	start := -1
+	for i := 0; i <= len(s.text); i++ {
+		if i < len(s.text) && !unicode.IsSpace(rune(s.text[i])) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start < 0 {
+			continue
+		}
+		word := s.text[start:i]
+		end := min(i, len(s.userInput))
+		typed := ""
+		if start < end {
+			typed = s.userInput[start:end]
+		}
+		if typed != word && !seen[word] {
+			seen[word] = true
+			missed = append(missed, word)
+		}
+		start = -1
+	}
+
+	if len(missed) > MaxFocusWords {
+		missed = missed[:MaxFocusWords]
+	}
+	return missed
+}
+
+// startFocusLoop queues the missed words for looping, holding back the text that would have come next
+func (s *Session) startFocusLoop(missed []string) {
+	if s.focusReps == 0 || len(missed) == 0 || s.completed || s.IsCodeMode() || s.drill != nil {
+		return
+	}
+	s.focusQueue = missed
+	s.focusStreak = 0
+	s.focusResume = s.text
+	s.setFocusText(s.focusQueue[0])
+}
+
+// continueFocusLoop scores the rep just typed and moves to the next rep, word or back to the held text
+func (s *Session) continueFocusLoop() {
+	if s.mistakes == 0 {
+		s.focusStreak++
+	} else {
+		s.focusStreak = 0
+	}
+	s.foldChunk()
+	s.keystrokes = nil
+
+	if s.focusStreak >= s.focusReps {
+		s.focusQueue = s.focusQueue[1:]
+		s.focusStreak = 0
+	}
+	if len(s.focusQueue) > 0 {
+		s.setFocusText(s.focusQueue[0])
+		return
+	}
+	s.setFocusText(s.focusResume)
+	s.focusResume = ""
+}
+
+func (s *Session) setFocusText(text string) {
+	s.text = text
+	s.invalidateLineCache()
+	s.position = 0
+	s.userInput = ""
+	s.mistakes = 0
+	s.layoutDirty = true
+}
+
+// clearFocus drops any queued problem words and brings back the held text, as when the session restarts
+func (s *Session) clearFocus() {
+	if s.inFocusLoop() {
+		s.setFocusText(s.focusResume)
+	}
+	s.focusQueue = nil
+	s.focusStreak = 0
+	s.focusResume = ""
+}
+
+// focusLabel shows the clean-rep counter while a word is being looped
+func (s *Session) focusLabel() string {
+	if !s.inFocusLoop() {
+		return ""
+	}
+	if remaining := len(s.focusQueue) - 1; remaining > 0 {
+		return fmt.Sprintf(" [focus %d/%d, %d more]", s.focusStreak, s.focusReps, remaining)
+	}
+	return fmt.Sprintf(" [focus %d/%d]", s.focusStreak, s.focusReps)
+}
@@ -0,0 +1,71 @@
+package session
+
+import (
+	"strings"
+
+	"gti/src/internal"
+	"gti/src/internal/syntax"
+)
+
+// highlighterFor picks a lexer for code sessions, preferring what the custom file itself looks like
+func highlighterFor(sessionConfig SessionConfig) *syntax.Highlighter {
+	if sessionConfig.File != "" {
+		if language := internal.DetectCodeLanguage(sessionConfig.File); language != "" {
+			return syntax.ForLanguage(language)
+		}
+		if h := syntax.ForFile(sessionConfig.File); h != nil {
+			return h
+		}
+	}
+	if sessionConfig.Language != "" {
+		return syntax.ForLanguage(sessionConfig.Language)
+	}
+	return nil
+}
+
+// stripComments removes comment-only lines from code when the user has asked to skip them
+func (s *Session) stripComments() {
+	if s.highlighter == nil || !s.config.Code.SkipComments {
+		return
+	}
+	if stripped := strings.TrimSpace(s.highlighter.StripCommentLines(s.text)); stripped != "" {
+		s.text = stripped
+	}
+}
+
+// syntaxKinds returns the token kind of every byte of the text, or nil when highlighting is off
+func (s *Session) syntaxKinds() []syntax.Kind {
+	if s.highlighter == nil || !s.config.Display.SyntaxHighlight {
+		return nil
+	}
+
+	s.getCachedLines()
+	if s.cachedKinds == nil || s.kindsHash != s.textHash {
+		s.cachedKinds = s.highlighter.Kinds(s.text)
+		s.kindsHash = s.textHash
+	}
+	return s.cachedKinds
+}
+
+// pendingColor returns the theme colour for untyped text at pos, tinted by its token kind
+func (s *Session) pendingColor(kinds []syntax.Kind, pos int) string {
+	colors := s.config.Theme.Colors
+	if pos >= len(kinds) {
+		return colors.Pending
+	}
+
+	switch kinds[pos] {
+	case syntax.Keyword:
+		return colors.Accent
+	case syntax.String:
+		return colors.Border
+	case syntax.Number:
+		return colors.WordHighlight
+	case syntax.Comment:
+		return colors.TextSecondary
+	case syntax.Name:
+		return colors.TextPrimary
+	default:
+		return colors.Pending
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"gti/src/internal/config"
+	"gti/src/internal/vault"
 )
 
 type SessionRecord struct {
@@ -27,7 +28,60 @@ type SessionRecord struct {
 	CorrectedErrors   int     `json:"corrected_errors,omitempty"`
 	UncorrectedErrors int     `json:"uncorrected_errors,omitempty"`
 	BackspaceCount    int     `json:"backspace_count,omitempty"`
+	StructuralErrors  int     `json:"structural_errors,omitempty"`
 	AvgWordLength     float64 `json:"avg_word_length,omitempty"`
+	// Fatigue is how much slower, in percent, the final third was typed than the first
+	Fatigue float64 `json:"fatigue,omitempty"`
+
+	// Partial marks a session saved from the pause screen before it was finished
+	Partial bool `json:"partial,omitempty"`
+	// Pasted marks a session that took pasted text, which statistics leave out
+	Pasted bool `json:"pasted,omitempty"`
+
+	// KeyStats counts presses and misses per expected character, for the error heatmap
+	KeyStats map[string]KeyStat `json:"key_stats,omitempty"`
+}
+
+// KeyStat is how often a character was due and how often it was mistyped
+type KeyStat struct {
+	Typed  int `json:"n"`
+	Errors int `json:"e"`
+}
+
+// countKey tallies one press against the character the text expected
+func (s *Session) countKey(expected string, correct bool) {
+	if s.keyStats == nil {
+		s.keyStats = make(map[string]KeyStat)
+	}
+	stat := s.keyStats[expected]
+	stat.Typed++
+	if !correct {
+		stat.Errors++
+	}
+	s.keyStats[expected] = stat
+}
+
+// CountedRecords leaves out the sessions statistics should not count, those that took pasted text
+func CountedRecords(records []*SessionRecord) []*SessionRecord {
+	counted := make([]*SessionRecord, 0, len(records))
+	for _, r := range records {
+		if !r.Pasted {
+			counted = append(counted, r)
+		}
+	}
+	return counted
+}
+
+// FinishedRecords leaves out the sessions cut short from the pause screen, whose speed and accuracy
+// only cover the part of the test that was typed
+func FinishedRecords(records []*SessionRecord) []*SessionRecord {
+	finished := make([]*SessionRecord, 0, len(records))
+	for _, r := range records {
+		if !r.Partial {
+			finished = append(finished, r)
+		}
+	}
+	return finished
 }
 
 func SaveSessionRecord(cfg *config.Config, record *SessionRecord) error {
@@ -47,6 +101,11 @@ func SaveSessionRecord(cfg *config.Config, record *SessionRecord) error {
 	if err != nil {
 		return err
 	}
+	if cfg.History.Encrypt {
+		if data, err = vault.Seal(cfg, data); err != nil {
+			return err
+		}
+	}
 
 	_, err = file.WriteString(string(data) + "\n")
 	return err
@@ -69,9 +128,21 @@ func LoadSessionRecords(cfg *config.Config) ([]*SessionRecord, error) {
 
 	var records []*SessionRecord
 	scanner := bufio.NewScanner(file)
+	// Sealed lines, and records with many keys in their key stats, outgrow the default line limit
+	scanner.Buffer(nil, 1024*1024)
 	for scanner.Scan() {
+		line := scanner.Bytes()
+		if vault.IsSealed(line) {
+			if line, err = vault.Open(cfg, line); err != nil {
+				if !vault.Unlocked() {
+					return nil, err
+				}
+				// A session sealed with another machine's key is skipped like any unreadable line
+				continue
+			}
+		}
 		var record SessionRecord
-		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+		if err := json.Unmarshal(line, &record); err != nil {
 			continue
 		}
 		records = append(records, &record)
@@ -88,20 +159,68 @@ func LoadSessionRecords(cfg *config.Config) ([]*SessionRecord, error) {
 	return records, nil
 }
 
-func CalculateStreaks(validSessions []*SessionRecord) (int, int) {
-	if len(validSessions) == 0 {
-		return 0, 0
-	}
+// Streaks are the practice streaks of a history, under a streak policy
+type Streaks struct {
+	Current int
+	Longest int
+	// FreezesLeft is how many freezes this month still has, after those the current streak used
+	FreezesLeft int
+}
 
+// CalculateStreaks counts the days practised in a row as of now, where rest days and, up to the
+// monthly allowance, frozen days in between neither break a streak nor add to it. Today is never a
+// missed day, since there is still time to practise.
+func CalculateStreaks(validSessions []*SessionRecord, policy config.StreaksConfig, now time.Time) Streaks {
+	streaks := Streaks{FreezesLeft: policy.FreezesPerMonth}
 	dates := extractUniqueDates(validSessions)
 	if len(dates) == 0 {
-		return 0, 0
+		return streaks
 	}
 
-	currentStreak := calculateCurrentStreak(dates)
-	longestStreak := calculateLongestStreak(dates)
+	rest := policy.RestWeekdays()
+	frozen := make(map[string]int)
+	// bridge spends freezes on the missed days between two days, if there are enough to cover them all
+	bridge := func(from, to time.Time) bool {
+		need := make(map[string]int)
+		for day := from.AddDate(0, 0, 1); day.Before(to); day = day.AddDate(0, 0, 1) {
+			if !rest[day.Weekday()] {
+				need[day.Format("2006-01")]++
+			}
+		}
+		for month, n := range need {
+			if frozen[month]+n > policy.FreezesPerMonth {
+				return false
+			}
+		}
+		for month, n := range need {
+			frozen[month] += n
+		}
+		return true
+	}
 
-	return currentStreak, longestStreak
+	run := 0
+	var last time.Time
+	for _, date := range dates {
+		day, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			continue
+		}
+		if run > 0 && !bridge(last, day) {
+			run = 0
+		}
+		run++
+		last = day
+		if run > streaks.Longest {
+			streaks.Longest = run
+		}
+	}
+
+	today, _ := time.Parse("2006-01-02", now.Format("2006-01-02"))
+	if !last.After(today) && bridge(last, today) {
+		streaks.Current = run
+	}
+	streaks.FreezesLeft = max(0, policy.FreezesPerMonth-frozen[today.Format("2006-01")])
+	return streaks
 }
 
 func extractUniqueDates(sessions []*SessionRecord) []string {
@@ -118,63 +237,3 @@ func extractUniqueDates(sessions []*SessionRecord) []string {
 	sort.Strings(dates)
 	return dates
 }
-
-func calculateCurrentStreak(dates []string) int {
-	now := time.Now()
-	yesterday := now.AddDate(0, 0, -1)
-	todayStr := now.Format("2006-01-02")
-	yesterdayStr := yesterday.Format("2006-01-02")
-
-	hasRecentActivity := false
-	for _, date := range dates {
-		if date == todayStr || date == yesterdayStr {
-			hasRecentActivity = true
-			break
-		}
-	}
-
-	if !hasRecentActivity {
-		return 0
-	}
-
-	currentStreak := 0
-	for i := len(dates) - 1; i >= 0; i-- {
-		expectedDate := now.AddDate(0, 0, -(len(dates) - 1 - i)).Format("2006-01-02")
-		if dates[i] == expectedDate {
-			currentStreak++
-		} else {
-			break
-		}
-	}
-
-	return currentStreak
-}
-
-func calculateLongestStreak(dates []string) int {
-	if len(dates) == 0 {
-		return 0
-	}
-
-	longestStreak := 1
-	current := 1
-
-	for i := 1; i < len(dates); i++ {
-		prevDate, _ := time.Parse("2006-01-02", dates[i-1])
-		currDate, _ := time.Parse("2006-01-02", dates[i])
-
-		if currDate.Sub(prevDate).Hours() == 24 {
-			current++
-		} else {
-			if current > longestStreak {
-				longestStreak = current
-			}
-			current = 1
-		}
-	}
-
-	if current > longestStreak {
-		longestStreak = current
-	}
-
-	return longestStreak
-}
@@ -0,0 +1,85 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// hookInputWait is how long the results screen waits for the on_complete hook to take in the results
+const hookInputWait = 2 * time.Second
+
+type HookLog struct {
+	// hookResult says why the on_complete hook could not be run, "" when it ran or none is set
+	hookResult string
+}
+
+// runCompleteHook starts the on_complete command with the results on its standard input and in its
+// environment. It is not waited for, so a slow script never holds up the results screen, and its
+// output is discarded so it cannot draw over the screen.
+func (s *Session) runCompleteHook() {
+	command := s.config.Hooks.OnComplete
+	if command == "" {
+		return
+	}
+	report := NewResultsCalculator().BuildReport(s)
+	data, err := json.Marshal(report)
+	if err != nil {
+		s.hookResult = "Could not run the on_complete hook: " + err.Error()
+		return
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(),
+		"GTI_MODE="+report.Mode,
+		"GTI_COMPLETED="+strconv.FormatBool(report.Completed),
+		"GTI_WPM="+strconv.FormatFloat(report.WPM, 'f', 2, 64),
+		"GTI_NET_WPM="+strconv.FormatFloat(report.NetWPM, 'f', 2, 64),
+		"GTI_ACCURACY="+strconv.FormatFloat(report.Accuracy, 'f', 2, 64),
+		"GTI_MISTAKES="+strconv.Itoa(report.Mistakes),
+		"GTI_DURATION_SECONDS="+strconv.FormatFloat(report.DurationSeconds, 'f', 2, 64),
+	)
+
+	// The results are written through a pipe gti closes itself rather than by exec's copying
+	// goroutine, and waited for a moment, so they reach the hook even when gti exits straight after
+	// the session. A hook that never reads them only costs that moment.
+	stdin, input, err := os.Pipe()
+	if err != nil {
+		s.hookResult = "Could not run the on_complete hook: " + err.Error()
+		return
+	}
+	cmd.Stdin = stdin
+	if err := cmd.Start(); err != nil {
+		stdin.Close()
+		input.Close()
+		s.hookResult = fmt.Sprintf("Could not run the on_complete hook '%s': %v", command, err)
+		return
+	}
+	stdin.Close()
+	go cmd.Wait()
+
+	written := make(chan struct{})
+	go func() {
+		input.Write(append(data, '\n'))
+		input.Close()
+		close(written)
+	}()
+	select {
+	case <-written:
+	case <-time.After(hookInputWait):
+	}
+}
+
+// HookResultSummary says why the on_complete hook could not run, or "" when it ran or none is set
+func (s *Session) HookResultSummary() string {
+	return s.hookResult
+}
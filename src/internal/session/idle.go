@@ -0,0 +1,42 @@
+package session
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// IdleMsg says the session paused itself after going without a key press for [idle] seconds
+type IdleMsg struct{}
+
+type IdleWatch struct {
+	lastInput time.Time
+	// idle marks a pause the session took itself, rather than one asked for
+	idle bool
+}
+
+// touchInput restarts the idle clock
+func (s *Session) touchInput() {
+	s.lastInput = s.now()
+}
+
+// checkIdle pauses a running session that has gone [idle] seconds without a key press. The clock is
+// stopped at the last key, so the time spent away counts towards neither speed nor duration.
+func (s *Session) checkIdle() tea.Cmd {
+	limit := time.Duration(s.config.Idle.Seconds) * time.Second
+	if limit <= 0 || s.chunkSummary != "" || s.mode == DemoMode || s.since(s.lastInput) < limit {
+		return nil
+	}
+	s.Pause()
+	s.pausedAt = s.lastInput
+	s.duration = s.lastInput.Sub(s.startTime)
+	// Nothing was typed in the seconds sampled since, so they go with the rest of the idle time
+	s.samples = s.samples[:min(len(s.samples), int(s.duration/time.Second))]
+	s.idle = true
+	return func() tea.Msg { return IdleMsg{} }
+}
+
+// IsIdle reports whether the session is paused because nothing was typed for a while
+func (s *Session) IsIdle() bool {
+	return s.paused && s.idle
+}
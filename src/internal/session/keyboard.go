@@ -0,0 +1,154 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gti/src/internal/keymap"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// KeyboardMinHeight is the smallest terminal the on-screen keyboard is shown in; below it the tip line stays
+const KeyboardMinHeight = 18
+
+// keyboardLayout is the configured layout, or qwerty when it is unknown
+func (s *Session) keyboardLayout() string {
+	layout := s.config.Keyboard.Layout
+	if keymap.ValidateLayout(layout) != nil {
+		return "qwerty"
+	}
+	return layout
+}
+
+// nextPress is how the character at the cursor is typed; ok is false at the end of the text or when
+// the layout has no key for it
+func (s *Session) nextPress() (r rune, press keymap.KeyPress, ok bool) {
+	if s.position >= len(s.text) {
+		return 0, keymap.KeyPress{}, false
+	}
+	r, _ = utf8.DecodeRuneInString(s.text[s.position:])
+	if s.emulation != nil {
+		press, ok = s.emulation.Press(r)
+	} else {
+		press, ok = keymap.Press(s.keyboardLayout(), r)
+	}
+	return r, press, ok
+}
+
+// renderKeyboard draws the layout with the next key lit up, the other keys of the same finger picked
+// out, and a line saying which finger to use
+func (s *Session) renderKeyboard(width int) string {
+	layout := s.keyboardLayout()
+	r, press, ok := s.nextPress()
+
+	colors := s.config.Theme.Colors
+	bg := lipgloss.Color(colors.Background)
+	plain := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.TextSecondary)).Background(bg)
+	finger := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.WordHighlight)).Background(bg).Bold(true)
+	next := lipgloss.NewStyle().Foreground(bg).Background(lipgloss.Color(colors.Accent)).Bold(true)
+
+	cell := func(label string, lit, sameFinger bool) string {
+		switch {
+		case lit:
+			return next.Render(" " + label + " ")
+		case sameFinger:
+			return finger.Render(" " + label + " ")
+		default:
+			return plain.Render(" " + label + " ")
+		}
+	}
+	gap := plain.Render(" ")
+	// The opposite hand holds Shift, so a left-hand key lights the right Shift and the other way round
+	leftShift := ok && press.Shift && !press.Finger.Left()
+	rightShift := ok && press.Shift && press.Finger.Left()
+
+	rows := keymap.KeyboardRows(layout)
+	if s.emulation != nil {
+		// Emulated layouts are laid over a US QWERTY keyboard
+		layout, rows = "qwerty", s.emulation.Rows
+	}
+	iso := len([]rune(rows[3])) > 10
+	indents := []string{"", "  ", "   ", " "}
+	var lines []string
+	for row, keys := range rows {
+		var cells []string
+		if row == 3 {
+			shift := cell("⇧", leftShift, false)
+			if iso {
+				// ISO boards give the left Shift a narrow key beside the extra one
+				shift = plain.Render("⇧")
+				if leftShift {
+					shift = next.Render("⇧")
+				}
+			}
+			cells = append(cells, shift)
+		}
+		for col, k := range []rune(keys) {
+			lit := ok && !press.Enter && press.Row == row && press.Col == col
+			same := ok && press.Finger != keymap.Thumb && keymap.FingerAt(layout, row, col) == press.Finger
+			cells = append(cells, cell(string(k), lit, same))
+		}
+		switch row {
+		case 2:
+			cells = append(cells, cell("⏎", ok && press.Enter, false))
+		case 3:
+			cells = append(cells, cell("⇧", rightShift, false))
+		}
+		indent := indents[row]
+		if row == 3 && iso {
+			indent = ""
+		}
+		lines = append(lines, plain.Render(indent)+strings.Join(cells, gap))
+	}
+	altGr := plain.Render(" AltGr ")
+	if ok && press.AltGr {
+		altGr = next.Render(" AltGr ")
+	}
+	space := cell(strings.Repeat(" ", 9)+"space"+strings.Repeat(" ", 9), ok && press.Row == keymap.RowSpace, false)
+	lines = append(lines, plain.Render(strings.Repeat(" ", 14))+space+gap+altGr)
+
+	// Pad every row to the same width so the block stays square once centred
+	widest := 0
+	for _, line := range lines {
+		widest = max(widest, lipgloss.Width(line))
+	}
+	for i, line := range lines {
+		lines[i] = line + plain.Render(strings.Repeat(" ", widest-lipgloss.Width(line)))
+	}
+	block := lipgloss.PlaceHorizontal(width, lipgloss.Center, strings.Join(lines, "\n"), lipgloss.WithWhitespaceBackground(bg))
+	return block + "\n" + s.renderCenteredText(describePress(r, press, ok), colors.TextPrimary, width)
+}
+
+// describePress says which finger types the character, and with which modifiers
+func describePress(r rune, press keymap.KeyPress, ok bool) string {
+	switch {
+	case r == 0:
+		return ""
+	case !ok:
+		return fmt.Sprintf("Next: %q is not on this layout (use a compose key)", r)
+	case press.Enter:
+		return "Next: Enter, right pinky"
+	case press.Row == keymap.RowSpace:
+		return "Next: Space, either thumb"
+	}
+
+	var steps []string
+	if press.Shift {
+		shiftFinger := keymap.LeftPinky
+		if press.Finger.Left() {
+			shiftFinger = keymap.RightPinky
+		}
+		steps = append(steps, "hold Shift with the "+shiftFinger.String())
+	}
+	if press.AltGr {
+		steps = append(steps, "hold AltGr with the right thumb")
+	}
+	steps = append(steps, "press with the "+press.Finger.String())
+	hint := fmt.Sprintf("Next: %c, %s", r, strings.Join(steps, ", "))
+	if press.Keys != "" {
+		hint += " (" + press.Keys + ")"
+	}
+	return hint
+}
@@ -0,0 +1,42 @@
+package session
+
+// LessonCheck grades a finished lesson run from how often each character was due and mistyped,
+// and returns what the results screen should say about it
+type LessonCheck func(keyStats map[string]KeyStat) string
+
+// RunCheck grades a finished course run from its results, for lessons passed on speed and accuracy
+type RunCheck func(results Results) string
+
+type LessonLog struct {
+	lessonCheck  LessonCheck
+	runCheck     RunCheck
+	lessonResult string
+}
+
+// EnableLessonCheck grades every finished run with check, so lesson progress follows key coverage
+func (s *Session) EnableLessonCheck(check LessonCheck) {
+	s.lessonCheck = check
+}
+
+// EnableRunCheck grades every finished run with check, so course progress follows speed and accuracy
+func (s *Session) EnableRunCheck(check RunCheck) {
+	s.runCheck = check
+}
+
+// gradeLesson hands a finished run to the lesson or run check; runs saved from the pause screen do not count
+func (s *Session) gradeLesson() {
+	if s.partial {
+		return
+	}
+	if s.lessonCheck != nil {
+		s.lessonResult = s.lessonCheck(s.keyStats)
+	}
+	if s.runCheck != nil {
+		s.lessonResult = s.runCheck(NewResultsCalculator().CalculateResults(s))
+	}
+}
+
+// LessonResultSummary says how the lesson went, or "" outside lessons
+func (s *Session) LessonResultSummary() string {
+	return s.lessonResult
+}
@@ -0,0 +1,196 @@
+package session
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// WithListening reads each chunk aloud instead of showing it; what is typed is scored against it word by word
+func WithListening() SessionOption {
+	return func(c *SessionConfig) {
+		c.Listening = true
+	}
+}
+
+type Listening struct {
+	// listening hides the text and scores a free transcription of it once Enter is pressed
+	listening bool
+}
+
+// Transcript compares a typed transcription with the sentence that was read aloud
+type Transcript struct {
+	// Words is how many words the sentence has, Correct how many of them were typed right
+	Words   int
+	Correct int
+	// Missed were left out, Misheard pairs a sentence word with what was typed for it, Extra were typed but never said
+	Missed   []string
+	Misheard [][2]string
+	Extra    []string
+}
+
+// Errors counts the word edits that turn the transcription into the sentence
+func (t Transcript) Errors() int {
+	return len(t.Missed) + len(t.Misheard) + len(t.Extra)
+}
+
+// ScoreTranscript aligns typed with reference by the fewest word edits. Case and the punctuation
+// around words are ignored, since neither can be heard.
+func ScoreTranscript(reference, typed string) Transcript {
+	ref, got := transcriptWords(reference), transcriptWords(typed)
+	n, m := len(ref), len(got)
+
+	// cost[i][j] is the fewest edits between the first i sentence words and the first j typed ones
+	cost := make([][]int, n+1)
+	for i := range cost {
+		cost[i] = make([]int, m+1)
+		cost[i][0] = i
+	}
+	for j := 0; j <= m; j++ {
+		cost[0][j] = j
+	}
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			sub := cost[i-1][j-1]
+			if ref[i-1] != got[j-1] {
+				sub++
+			}
+			cost[i][j] = min(sub, min(cost[i-1][j], cost[i][j-1])+1)
+		}
+	}
+
+	t := Transcript{Words: n}
+	for i, j := n, m; i > 0 || j > 0; {
+		switch {
+		case i > 0 && j > 0 && ref[i-1] == got[j-1] && cost[i][j] == cost[i-1][j-1]:
+			t.Correct++
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && cost[i][j] == cost[i-1][j-1]+1:
+			t.Misheard = append(t.Misheard, [2]string{ref[i-1], got[j-1]})
+			i, j = i-1, j-1
+		case i > 0 && cost[i][j] == cost[i-1][j]+1:
+			t.Missed = append(t.Missed, ref[i-1])
+			i--
+		default:
+			t.Extra = append(t.Extra, got[j-1])
+			j--
+		}
+	}
+	// The walk runs from the end of the sentence, so put each list back in reading order
+	slices.Reverse(t.Missed)
+	slices.Reverse(t.Misheard)
+	slices.Reverse(t.Extra)
+	return t
+}
+
+func transcriptWords(text string) []string {
+	var words []string
+	for _, w := range strings.Fields(strings.ToLower(text)) {
+		w = strings.TrimFunc(w, func(r rune) bool { return unicode.IsPunct(r) || unicode.IsSymbol(r) })
+		if w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// sayListening reads the current sentence aloud
+func (s *Session) sayListening() {
+	if s.listening && s.running && !s.completed {
+		s.speech.Say(s.text)
+	}
+}
+
+// handleListeningInput collects the transcription, which is only checked once Enter is pressed
+func (s *Session) handleListeningInput(key tea.KeyMsg) tea.Cmd {
+	switch key.Type {
+	case tea.KeyEnter:
+		return s.checkTranscript()
+	case tea.KeyBackspace:
+		if len(s.userInput) > 0 && !s.noBackspace {
+			_, size := utf8.DecodeLastRuneInString(s.userInput)
+			s.userInput = s.userInput[:len(s.userInput)-size]
+			s.backspaceCount++
+			s.recordTiming("backspace", "")
+			s.sound.Key()
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		char := key.String()
+		s.userInput += char
+		s.recordTiming(char, "")
+		s.sound.Key()
+	}
+	if s.running {
+		s.duration = s.since(s.startTime)
+	}
+	s.layoutDirty = true
+	return nil
+}
+
+// checkTranscript scores the transcription of the current sentence and moves on to the next one.
+// Every character of a word missed, misheard or added counts as a mistake, so accuracy stays per character.
+func (s *Session) checkTranscript() tea.Cmd {
+	if strings.TrimSpace(s.userInput) == "" {
+		return nil
+	}
+	t := ScoreTranscript(s.text, s.userInput)
+	s.listenWords += t.Words
+	s.listenCorrect += t.Correct
+	for _, w := range t.Missed {
+		s.mistakes += utf8.RuneCountInString(w)
+	}
+	for _, pair := range t.Misheard {
+		s.mistakes += utf8.RuneCountInString(pair[0])
+	}
+	for _, w := range t.Extra {
+		s.mistakes += utf8.RuneCountInString(w)
+	}
+	s.correctChars += max(0, utf8.RuneCountInString(s.userInput)-s.mistakes)
+	summary := s.describeTranscript(t)
+
+	cmd := tea.Batch(s.handleChunkCompletion(), s.startChunkSummary(summary))
+	if s.chunkSummary == "" {
+		// Without a summary to wait for, the next sentence can be heard straight away
+		s.sayListening()
+	}
+	return cmd
+}
+
+// describeTranscript sums up a checked sentence, showing it in full when anything was wrong
+func (s *Session) describeTranscript(t Transcript) string {
+	line := fmt.Sprintf("sentence %d: %d/%d words", s.chunksDone+1, t.Correct, t.Words)
+	if t.Errors() == 0 {
+		return line + ", all right"
+	}
+	var notes []string
+	for _, pair := range t.Misheard {
+		notes = append(notes, fmt.Sprintf("'%s' for '%s'", pair[1], pair[0]))
+	}
+	for _, w := range t.Missed {
+		notes = append(notes, fmt.Sprintf("missed '%s'", w))
+	}
+	for _, w := range t.Extra {
+		notes = append(notes, fmt.Sprintf("extra '%s'", w))
+	}
+	if len(notes) > chunkSummaryWords {
+		notes = notes[:chunkSummaryWords]
+	}
+	return fmt.Sprintf("%s: %s\n%q", line, strings.Join(notes, ", "), s.text)
+}
+
+// renderListeningContent shows only what has been typed, as the sentence itself is heard rather than read
+func (s *Session) renderListeningContent(width int) string {
+	colors := s.config.Theme.Colors
+	base := lipgloss.NewStyle().Background(lipgloss.Color(colors.Background))
+	cursor := base.Foreground(lipgloss.Color(colors.WordHighlight)).Faint(true).Render("▏")
+	if s.userInput == "" {
+		prompt := base.Foreground(lipgloss.Color(colors.Pending)).Render("Type what you hear")
+		return cursor + prompt
+	}
+	return base.Foreground(lipgloss.Color(colors.TextPrimary)).Width(width).Render(s.userInput + cursor)
+}
@@ -0,0 +1,120 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// topMistakesShown is how many keys, words and substitutions the results screen lists
+const topMistakesShown = 5
+
+// substitution is a key pressed in place of the one the text expected
+type substitution struct {
+	expected string
+	typed    string
+}
+
+type MistakeLog struct {
+	substitutions map[substitution]int
+	mistypedWords map[string]int
+	// mistakeText and mistakeStarts count each word of a chunk once, however many of its letters go wrong
+	mistakeText   string
+	mistakeStarts map[int]bool
+}
+
+// recordMistake notes that typed was pressed where the text at the cursor expected expected
+func (s *Session) recordMistake(typed, expected string) {
+	if s.substitutions == nil {
+		s.substitutions = make(map[substitution]int)
+		s.mistypedWords = make(map[string]int)
+	}
+	s.substitutions[substitution{expected: expected, typed: typed}]++
+
+	if s.mistakeText != s.text {
+		s.mistakeText = s.text
+		s.mistakeStarts = make(map[int]bool)
+	}
+	if s.position >= len(s.text) || unicode.IsSpace(rune(s.text[s.position])) {
+		return
+	}
+	start, end := s.position, s.position
+	for start > 0 && !unicode.IsSpace(rune(s.text[start-1])) {
+		start--
+	}
+	for end < len(s.text) && !unicode.IsSpace(rune(s.text[end])) {
+		end++
+	}
+	if !s.mistakeStarts[start] {
+		s.mistakeStarts[start] = true
+		s.mistypedWords[s.text[start:end]]++
+	}
+}
+
+// keyName shows whitespace keys by name, as a bare space or newline would not show up in a list
+func keyName(key string) string {
+	switch key {
+	case " ":
+		return "space"
+	case "\n":
+		return "enter"
+	case "\t":
+		return "tab"
+	}
+	return key
+}
+
+// counted is one entry of a top mistakes list
+type counted struct {
+	label string
+	count int
+}
+
+// topCounted formats the most frequent entries, most frequent first and alphabetically among equals
+func topCounted(entries []counted) string {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		return entries[i].label < entries[j].label
+	})
+	if len(entries) > topMistakesShown {
+		entries = entries[:topMistakesShown]
+	}
+	parts := make([]string, len(entries))
+	for i, e := range entries {
+		parts[i] = fmt.Sprintf("%s ×%d", e.label, e.count)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// MistakeSummary lists the keys and words mistyped most often in the run, and the keys most often
+// pressed in place of others, for the results screen
+func (s *Session) MistakeSummary() string {
+	if len(s.substitutions) == 0 {
+		return ""
+	}
+
+	var keys []counted
+	for key, stat := range s.keyStats {
+		if stat.Errors > 0 {
+			keys = append(keys, counted{keyName(key), stat.Errors})
+		}
+	}
+	var words []counted
+	for word, n := range s.mistypedWords {
+		words = append(words, counted{word, n})
+	}
+	var swaps []counted
+	for sub, n := range s.substitutions {
+		swaps = append(swaps, counted{keyName(sub.typed) + " for " + keyName(sub.expected), n})
+	}
+
+	lines := []string{"Top mistakes:", "  Keys:  " + topCounted(keys)}
+	if len(words) > 0 {
+		lines = append(lines, "  Words: "+topCounted(words))
+	}
+	lines = append(lines, "  Typed: "+topCounted(swaps))
+	return strings.Join(lines, "\n")
+}
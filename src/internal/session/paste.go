@@ -0,0 +1,106 @@
+package session
+
+import (
+	"time"
+
+	"gti/src/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// pasteBurst characters arriving within pasteWindow are faster than anyone types, so they were pasted
+	pasteBurst  = 8
+	pasteWindow = 30 * time.Millisecond
+)
+
+type PasteGuard struct {
+	arrivals []time.Time
+	// pasted marks a run that took pasted text, which is kept out of the statistics
+	pasted bool
+	// pasteAttempts counts the pastes caught in this run, rejected or not
+	pasteAttempts int
+	pasteNotice   string
+}
+
+// keyRunes is how many characters a key message types
+func keyRunes(key tea.KeyMsg) int {
+	switch key.Type {
+	case tea.KeyRunes:
+		return len(key.Runes)
+	case tea.KeySpace, tea.KeyEnter, tea.KeyTab:
+		return 1
+	}
+	return 0
+}
+
+// isPaste reports whether key carries pasted text: a bracketed paste, or the last of a burst of
+// characters too quick to have been typed. A few characters in one message are not enough, as an
+// input method commits a whole CJK word at once; every character of a message counts towards a burst.
+func (s *Session) isPaste(key tea.KeyMsg) bool {
+	n := keyRunes(key)
+	if n == 0 {
+		return false
+	}
+	if key.Paste {
+		return true
+	}
+
+	now := s.now()
+	for range n {
+		s.arrivals = append(s.arrivals, now)
+	}
+	if len(s.arrivals) > pasteBurst {
+		s.arrivals = s.arrivals[len(s.arrivals)-pasteBurst:]
+	}
+	return len(s.arrivals) == pasteBurst && now.Sub(s.arrivals[0]) < pasteWindow
+}
+
+// guardPaste deals with pasted text as keyboard.paste says, rejecting it or typing it one character
+// at a time and marking the run. It reports whether key has been dealt with.
+func (s *Session) guardPaste(key tea.KeyMsg) (bool, tea.Cmd) {
+	if !s.isPaste(key) {
+		if s.pasteNotice != "" && keyRunes(key) > 0 {
+			s.pasteNotice = ""
+			s.layoutDirty = true
+		}
+		return false, nil
+	}
+
+	s.pasteAttempts++
+	if s.config.Keyboard.Paste != config.PasteMark {
+		s.pasteNotice = "Pasted text is ignored: type it instead"
+		s.layoutDirty = true
+		return true, nil
+	}
+	s.pasted = true
+	if keyRunes(key) < 2 {
+		return false, nil
+	}
+	return true, s.typeEach(key)
+}
+
+// typeEach types a message of several characters, a paste or a word an input method committed, one
+// character at a time as the keys that would type them
+func (s *Session) typeEach(key tea.KeyMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, r := range key.Runes {
+		single := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		switch r {
+		case '\r', '\n':
+			single = tea.KeyMsg{Type: tea.KeyEnter}
+		case ' ':
+			single = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}}
+		}
+		cmds = append(cmds, s.typeKey(single))
+	}
+	return tea.Batch(cmds...)
+}
+
+// PasteResultSummary notes on the results screen that the run is left out of the statistics
+func (s *Session) PasteResultSummary() string {
+	if !s.pasted {
+		return ""
+	}
+	return "Pasted text was typed in this run, so it is left out of your statistics"
+}
@@ -0,0 +1,154 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gti/src/assets"
+	"gti/src/internal/config"
+)
+
+// PercentilePoint is one line of a percentile table: a speed, and the percentage of typists slower
+type PercentilePoint struct {
+	WPM     float64
+	Percent float64
+}
+
+// PercentileTable places a speed among typists, its points in order of speed
+type PercentileTable []PercentilePoint
+
+// PercentileRank is where a speed stands among typists
+type PercentileRank struct {
+	WPM     float64 `json:"wpm"`
+	Percent float64 `json:"percent"`
+}
+
+type PercentileLog struct {
+	// percentileResult places the finished run among typists, or says why it could not be
+	percentileResult string
+}
+
+// LoadPercentiles reads the table results.percentile_table names, or gti's own when it names none
+func LoadPercentiles(cfg *config.Config) (PercentileTable, error) {
+	if cfg.Results.PercentileTable == "" {
+		data, err := assets.Percentiles.ReadFile("percentiles/typists.txt")
+		if err != nil {
+			return nil, err
+		}
+		return ParsePercentiles(data)
+	}
+	path := config.ExpandPath(cfg.Results.PercentileTable)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	table, err := ParsePercentiles(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return table, nil
+}
+
+// ParsePercentiles reads a percentile table: one speed in WPM and the percentage of typists slower
+// than it per line, faster speeds further down, with blank lines and lines starting with # left out
+func ParsePercentiles(data []byte) (PercentileTable, error) {
+	var table PercentileTable
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: want a speed and a percentage, got %q", n, line)
+		}
+		wpm, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil || wpm <= 0 {
+			return nil, fmt.Errorf("line %d: %q is not a speed", n, fields[0])
+		}
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(fields[1], "%"), 64)
+		if err != nil || percent < 0 || percent > 100 {
+			return nil, fmt.Errorf("line %d: %q is not a percentage from 0 to 100", n, fields[1])
+		}
+		if last := len(table) - 1; last >= 0 && (wpm <= table[last].WPM || percent < table[last].Percent) {
+			return nil, fmt.Errorf("line %d: speeds and percentages must rise down the table", n)
+		}
+		table = append(table, PercentilePoint{wpm, percent})
+	}
+	if len(table) < 2 {
+		return nil, fmt.Errorf("a percentile table needs at least two lines")
+	}
+	return table, nil
+}
+
+// Percentile is the percentage of typists slower than wpm, read off the straight line between the
+// table's points on either side. It runs down to 0% at 0 WPM, and stays at the last point's
+// percentage above it.
+func (t PercentileTable) Percentile(wpm float64) float64 {
+	if len(t) == 0 || wpm <= 0 {
+		return 0
+	}
+	below := PercentilePoint{}
+	for _, point := range t {
+		if wpm <= point.WPM {
+			return below.Percent + (wpm-below.WPM)/(point.WPM-below.WPM)*(point.Percent-below.Percent)
+		}
+		below = point
+	}
+	return below.Percent
+}
+
+// Rank places wpm among typists
+func (t PercentileTable) Rank(wpm float64) PercentileRank {
+	return PercentileRank{WPM: wpm, Percent: t.Percentile(wpm)}
+}
+
+// String says where the speed stands, to a whole percent except at the very top
+func (r PercentileRank) String() string {
+	if r.Percent >= 99 {
+		return fmt.Sprintf("faster than ~%.1f%% of typists", r.Percent)
+	}
+	return fmt.Sprintf("faster than ~%.0f%% of typists", r.Percent)
+}
+
+// AverageNetWPM averages the net speed of the records that measured one, as older records did not
+func AverageNetWPM(records []*SessionRecord) float64 {
+	var net []float64
+	for _, r := range records {
+		if r.NetWPM > 0 {
+			net = append(net, r.NetWPM)
+		}
+	}
+	if len(net) == 0 {
+		return 0
+	}
+	return sum(net) / float64(len(net))
+}
+
+// comparePercentile places the finished run's net speed among typists for the results screen
+func (s *Session) comparePercentile() {
+	s.percentileResult = ""
+	if !s.config.Results.Percentiles {
+		return
+	}
+	table, err := LoadPercentiles(s.config)
+	if err != nil {
+		s.percentileResult = "Could not compare with other typists: " + err.Error()
+		return
+	}
+	wpm := NewResultsCalculator().CalculateResults(s).NetWPM
+	if wpm <= 0 {
+		return
+	}
+	s.percentileResult = fmt.Sprintf("Compared: %s", table.Rank(wpm))
+}
+
+// PercentileSummary places the run among typists, or is "" when that is turned off or nothing was typed
+func (s *Session) PercentileSummary() string {
+	return s.percentileResult
+}
@@ -0,0 +1,119 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"time"
+
+	"gti/src/internal/config"
+)
+
+// ProtocolResult is the result file of a run under a research protocol. Header is the protocol's
+// own stamp, so every file says exactly which parameters produced it.
+type ProtocolResult struct {
+	Protocol interface{}     `json:"protocol"`
+	Started  time.Time       `json:"started"`
+	Finished time.Time       `json:"finished"`
+	Partial  bool            `json:"partial,omitempty"`
+	TextHash string          `json:"text_sha256"`
+	Text     string          `json:"text"`
+	Typed    string          `json:"typed"`
+	Results  ProtocolMetrics `json:"results"`
+}
+
+// ProtocolMetrics are the measures of a protocol run, in the units researchers report them in
+type ProtocolMetrics struct {
+	DurationMs        int64              `json:"duration_ms"`
+	Chars             int                `json:"chars"`
+	WPM               float64            `json:"wpm"`
+	NetWPM            float64            `json:"net_wpm"`
+	AdjustedWPM       float64            `json:"adjusted_wpm"`
+	CPM               float64            `json:"cpm"`
+	Accuracy          float64            `json:"accuracy"`
+	Mistakes          int                `json:"mistakes"`
+	CorrectedErrors   int                `json:"corrected_errors"`
+	UncorrectedErrors int                `json:"uncorrected_errors"`
+	Backspaces        int                `json:"backspaces"`
+	KeyStats          map[string]KeyStat `json:"key_stats,omitempty"`
+}
+
+type ProtocolLog struct {
+	// stopOnError keeps the cursor on a mistyped character until the right key is struck
+	stopOnError bool
+	// noBackspace ignores Backspace, so every error stands
+	noBackspace    bool
+	protocolDir    string
+	protocolName   string
+	protocolHeader interface{}
+	protocolResult string
+}
+
+// WithInputPolicy sets how strictly input is taken: stopOnError holds the cursor on mistakes
+// and noBackspace disables correcting them
+func WithInputPolicy(stopOnError, noBackspace bool) SessionOption {
+	return func(c *SessionConfig) {
+		c.StopOnError = stopOnError
+		c.NoBackspace = noBackspace
+	}
+}
+
+// EnableProtocolResult writes a ProtocolResult stamped with header into dir whenever a run ends.
+// Files are named after name and the run's start time, so repeated runs never overwrite each other.
+// A run can not be paused, as time away would go unseen in the result; pausing ends it as partial.
+func (s *Session) EnableProtocolResult(dir, name string, header interface{}) {
+	s.protocolDir = config.ExpandPath(dir)
+	s.protocolName = name
+	s.protocolHeader = header
+	s.endOnPause = true
+}
+
+// writeProtocolResult saves the protocol result file, remembering the outcome for the results screen.
+// It runs before the chunk is folded into the totals so the typed text is still at hand.
+func (s *Session) writeProtocolResult() {
+	if s.protocolDir == "" {
+		return
+	}
+
+	results := NewResultsCalculator().CalculateResults(s)
+	hash := sha256.Sum256([]byte(s.text))
+	result := ProtocolResult{
+		Protocol: s.protocolHeader,
+		Started:  s.startTime,
+		Finished: s.startTime.Add(results.Duration),
+		Partial:  s.partial,
+		TextHash: hex.EncodeToString(hash[:]),
+		Text:     s.text,
+		Typed:    s.userInput,
+		Results: ProtocolMetrics{
+			DurationMs:        results.Duration.Milliseconds(),
+			Chars:             results.TotalChars,
+			WPM:               results.WPM,
+			NetWPM:            results.NetWPM,
+			AdjustedWPM:       results.AdjustedWPM,
+			CPM:               results.CPM,
+			Accuracy:          results.Accuracy,
+			Mistakes:          results.Mistakes,
+			CorrectedErrors:   results.CorrectedErrors,
+			UncorrectedErrors: results.UncorrectedErrors,
+			Backspaces:        results.BackspaceCount,
+			KeyStats:          s.keyStats,
+		},
+	}
+
+	path := filepath.Join(s.protocolDir, s.protocolName+"-"+s.startTime.Format("20060102-150405")+".json")
+	if err := config.EnsureDir(s.protocolDir); err != nil {
+		s.protocolResult = "Could not write protocol result: " + err.Error()
+		return
+	}
+	if err := config.SaveJSONData(path, result); err != nil {
+		s.protocolResult = "Could not write protocol result: " + err.Error()
+		return
+	}
+	s.protocolResult = "Protocol result saved to " + path
+}
+
+// ProtocolResultSummary says where the protocol result went, or "" outside protocol runs
+func (s *Session) ProtocolResultSummary() string {
+	return s.protocolResult
+}
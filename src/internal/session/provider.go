@@ -0,0 +1,71 @@
+package session
+
+import (
+	"strings"
+
+	"gti/src/internal"
+	"gti/src/internal/syntax"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Chunk is one piece of text handed to a session by a TextProvider
+type Chunk struct {
+	Text   string
+	Author string
+	// Language is the programming language of a code chunk, "" for prose
+	Language string
+}
+
+// TextProvider supplies a session's text one chunk at a time, so a session is not tied to a single source
+type TextProvider interface {
+	NextChunk() Chunk
+}
+
+type Provided struct {
+	provider TextProvider
+	// codeChunk marks the chunk being typed as source code, rendered and typed like code mode
+	codeChunk bool
+}
+
+// WithProvider draws chunks of text from provider until chunks of them have been typed
+func WithProvider(provider TextProvider, chunks int) SessionOption {
+	return func(c *SessionConfig) {
+		c.Provider = provider
+		c.MaxChunks = chunks
+	}
+}
+
+// nextProvidedChunk replaces the text with the provider's next chunk
+func (s *Session) nextProvidedChunk() {
+	chunk := s.nextChunk()
+	s.providedChunk = chunk
+	s.runChunks = append(s.runChunks, chunk)
+	s.text = chunk.Text
+	s.author = chunk.Author
+	s.language = chunk.Language
+	s.codeChunk = chunk.Language != ""
+	s.highlighter = nil
+	s.rtl = !s.codeChunk && internal.IsRTLLanguage(s.config.Language.Default)
+	if s.codeChunk {
+		s.highlighter = syntax.ForLanguage(chunk.Language)
+		s.stripComments()
+	}
+	s.text = strings.TrimRight(s.text, "\n")
+	s.invalidateLineCache()
+	s.scrollOffset = 0
+	s.layoutDirty = true
+}
+
+// handleProvidedCompletion moves on to the provider's next chunk, or ends the session after the last one
+func (s *Session) handleProvidedCompletion() tea.Cmd {
+	s.totalChunks++
+	s.foldChunk()
+
+	if s.totalChunks >= s.maxChunks {
+		return s.completeSession()
+	}
+	s.nextProvidedChunk()
+	s.position = 0
+	return nil
+}
@@ -0,0 +1,80 @@
+package session
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// RunReplay remembers the text of a run chunk by chunk, so it can be typed again exactly as it was
+type RunReplay struct {
+	// runFirst is the text the run started on, runIndex its place in a custom file or quote list and
+	// runPage the chunks on the first page of grouped practice
+	runFirst string
+	runIndex int
+	runPage  int
+	// runTexts are the texts generated for the run's later chunks, and runChunks every chunk a
+	// provider gave the run, in the order they were typed
+	runTexts  []string
+	runChunks []Chunk
+	// replayTexts and replayChunks are what a repeat has still to give back before anything new
+	replayTexts  []string
+	replayChunks []Chunk
+	// providedChunk is the provider's chunk being typed
+	providedChunk Chunk
+}
+
+// markRun notes the text a run starts on; every later chunk is noted as it is made
+func (s *Session) markRun() {
+	s.runFirst, s.runIndex, s.runPage = s.text, s.chunkIndex, s.currentPageChunks
+	s.runTexts = nil
+	s.runChunks = nil
+	if s.provider != nil {
+		s.runChunks = []Chunk{s.providedChunk}
+	}
+}
+
+// nextText is the text of the run's next chunk: while repeating, the one typed there before, and
+// otherwise what generate makes
+func (s *Session) nextText(generate func() string) string {
+	var text string
+	if len(s.replayTexts) > 0 {
+		text, s.replayTexts = s.replayTexts[0], s.replayTexts[1:]
+	} else {
+		text = generate()
+	}
+	s.runTexts = append(s.runTexts, text)
+	return text
+}
+
+// nextChunk is the provider's next chunk, or while repeating the one typed there before
+func (s *Session) nextChunk() Chunk {
+	if len(s.replayChunks) > 0 {
+		chunk := s.replayChunks[0]
+		s.replayChunks = s.replayChunks[1:]
+		return chunk
+	}
+	return s.provider.NextChunk()
+}
+
+// Repeat starts the run over on exactly the text it was typed on, chunk for chunk, where Restart
+// only goes back to the start of the chunk on screen
+func (s *Session) Repeat() tea.Cmd {
+	index, page := s.runIndex, s.runPage
+	s.clearFocus()
+	s.replayTexts = slices.Clone(s.runTexts)
+	if s.provider != nil {
+		s.replayChunks = slices.Clone(s.runChunks)
+		s.nextProvidedChunk()
+	} else {
+		s.text = s.runFirst
+	}
+	s.invalidateLineCache()
+	s.scrollOffset = 0
+	s.layoutDirty = true
+	s.currentPageChunks = page
+
+	cmd := s.Restart()
+	s.chunkIndex, s.runIndex = index, index
+	return cmd
+}
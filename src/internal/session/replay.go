@@ -0,0 +1,136 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gti/src/internal/config"
+)
+
+// Keystroke is a single recorded key press within one chunk of text
+type Keystroke struct {
+	OffsetMs int64  `json:"t"`
+	Key      string `json:"k"`
+	Position int    `json:"p"`
+}
+
+// Replay is the recorded keystroke stream of a completed chunk
+type Replay struct {
+	Mode       string      `json:"mode"`
+	TextHash   uint32      `json:"text_hash"`
+	TextLength int         `json:"text_length"`
+	DurationMs int64       `json:"duration_ms"`
+	WPM        float64     `json:"wpm"`
+	Recorded   time.Time   `json:"recorded"`
+	Keystrokes []Keystroke `json:"keystrokes"`
+}
+
+// PositionAt returns how far into the text the replay was after the given elapsed time
+func (r *Replay) PositionAt(elapsed time.Duration) int {
+	ms := elapsed.Milliseconds()
+	pos := 0
+	for _, k := range r.Keystrokes {
+		if k.OffsetMs > ms {
+			break
+		}
+		pos = k.Position
+	}
+	return pos
+}
+
+func replayDir() string {
+	return filepath.Join(config.DataDir, "replays")
+}
+
+func replayPath(mode string, textHash uint32) string {
+	return filepath.Join(replayDir(), fmt.Sprintf("%s-%08x.json", mode, textHash))
+}
+
+// LoadBestReplay loads the fastest recorded run for the given mode and text
+func LoadBestReplay(mode string, textHash uint32) (*Replay, error) {
+	path := replayPath(mode, textHash)
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	var replay Replay
+	if err := config.LoadJSONData(path, &replay); err != nil {
+		return nil, err
+	}
+	return &replay, nil
+}
+
+// SaveReplayIfBest stores the replay when it beats the previously recorded best run
+func SaveReplayIfBest(replay *Replay) error {
+	if best, err := LoadBestReplay(replay.Mode, replay.TextHash); err == nil && best.DurationMs <= replay.DurationMs {
+		return nil
+	}
+
+	if err := config.EnsureDir(replayDir()); err != nil {
+		return err
+	}
+	return config.SaveJSONData(replayPath(replay.Mode, replay.TextHash), replay)
+}
+
+// recordKeystroke appends a key press to the current chunk's recording
+func (s *Session) recordKeystroke(key string) {
+	now := s.now()
+	if len(s.keystrokes) == 0 {
+		s.chunkStartTime = now
+	}
+	s.keystrokes = append(s.keystrokes, Keystroke{
+		OffsetMs: now.Sub(s.chunkStartTime).Milliseconds(),
+		Key:      key,
+		Position: s.position,
+	})
+}
+
+// finishChunkReplay saves the recording of a fully typed chunk and starts a fresh one
+func (s *Session) finishChunkReplay() {
+	defer func() { s.keystrokes = nil }()
+
+	// A played back run is not a new best run of its own
+	if len(s.keystrokes) == 0 || s.position < len(s.text) || s.playedBack() {
+		return
+	}
+
+	durationMs := s.keystrokes[len(s.keystrokes)-1].OffsetMs
+	if durationMs <= 0 {
+		return
+	}
+
+	replay := &Replay{
+		Mode:       s.mode,
+		TextHash:   s.computeTextHash(),
+		TextLength: len(s.text),
+		DurationMs: durationMs,
+		WPM:        CalculateWPM(len(s.text), time.Duration(durationMs)*time.Millisecond),
+		Recorded:   s.now(),
+		Keystrokes: s.keystrokes,
+	}
+	SaveReplayIfBest(replay)
+}
+
+// ghostPosition returns where the best recorded run was at this point in the current chunk, or -1
+func (s *Session) ghostPosition() int {
+	if !s.config.Display.ShowGhost {
+		return -1
+	}
+
+	// The text is compared rather than hashed, as hashing all of a long text each frame would add up
+	if !s.ghostLoaded || s.ghostText != s.text {
+		s.ghost, _ = LoadBestReplay(s.mode, s.computeTextHash())
+		s.ghostText = s.text
+		s.ghostLoaded = true
+	}
+	if s.ghost == nil {
+		return -1
+	}
+
+	if len(s.keystrokes) == 0 {
+		return 0
+	}
+	return s.ghost.PositionAt(s.since(s.chunkStartTime))
+}
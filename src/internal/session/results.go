@@ -5,11 +5,38 @@ import (
 )
 
 type Results struct {
-	WPM      float64
-	CPM      float64
-	Accuracy float64
-	Mistakes int
-	Duration time.Duration
+	WPM      float64       `json:"wpm"`
+	CPM      float64       `json:"cpm"`
+	Accuracy float64       `json:"accuracy"`
+	Mistakes int           `json:"mistakes"`
+	Duration time.Duration `json:"-"`
+
+	TotalChars        int     `json:"total_chars"`
+	NetWPM            float64 `json:"net_wpm"`
+	AdjustedWPM       float64 `json:"adjusted_wpm"`
+	CorrectedErrors   int     `json:"corrected_errors"`
+	UncorrectedErrors int     `json:"uncorrected_errors"`
+	BackspaceCount    int     `json:"backspace_count"`
+	StructuralErrors  int     `json:"structural_errors"`
+	AvgWordLength     float64 `json:"avg_word_length"`
+	// Fatigue is how much slower, in percent, the final third was typed than the first
+	Fatigue float64 `json:"fatigue,omitempty"`
+
+	// Kana and Hangul drills score whole glyphs; UnitName says which
+	UnitName     string `json:"unit_name,omitempty"`
+	Units        int    `json:"units,omitempty"`
+	CorrectUnits int    `json:"correct_units,omitempty"`
+}
+
+// ResultReport is a session's results as --json-result prints them for scripts
+type ResultReport struct {
+	Mode string `json:"mode"`
+	// Completed is false when the session was quit before its text or time ran out
+	Completed       bool    `json:"completed"`
+	Partial         bool    `json:"partial,omitempty"`
+	Pasted          bool    `json:"pasted,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Results
 }
 
 func CalculateWPM(totalChars int, duration time.Duration) float64 {
@@ -48,6 +75,10 @@ func CalculateAccuracy(totalChars int, mistakes int) float64 {
 	if totalChars == 0 {
 		return 100.0
 	}
+	// Weighted mistakes can outnumber the characters typed
+	if mistakes > totalChars {
+		return 0
+	}
 	return float64(totalChars-mistakes) / float64(totalChars) * 100
 }
 
@@ -57,41 +88,85 @@ func NewResultsCalculator() *ResultsCalculator {
 	return &ResultsCalculator{}
 }
 
-func (rc *ResultsCalculator) CalculateResults(session *Session, mode string) Results {
-	var mistakes int
-
-	switch mode {
-	case "challenge":
-
-		mistakes = session.GetTotalMistakes()
-		if session.GetMistakes() > 0 {
-			mistakes += session.GetMistakes()
-		}
-	case "practice":
-
-		mistakes = session.GetTotalMistakes()
-	default:
-
-		mistakes = session.GetTotalMistakes() + session.GetMistakes()
-	}
-
+// CalculateResults computes the summary of a session from its accumulated totals plus the chunk in progress.
+// Every mode goes through here so that new metrics show up everywhere at once.
+func (rc *ResultsCalculator) CalculateResults(session *Session) Results {
 	totalChars := session.GetTotalChars() + len(session.TypedText())
-
-	wpm := CalculateWPM(totalChars, session.GetDuration())
+	mistakes := session.GetTotalMistakes() + session.GetMistakes()
+	duration := session.GetDuration()
 
 	cpm := 0.0
-	if session.GetDuration() > 0 {
-		minutes := session.GetDuration().Minutes()
-		cpm = float64(totalChars) / minutes
+	if duration > 0 {
+		cpm = float64(totalChars) / duration.Minutes()
 	}
 
-	accuracy := CalculateAccuracy(totalChars, mistakes)
+	units, correctUnits := session.DrillScore()
 
 	return Results{
-		WPM:      wpm,
+		WPM:      CalculateWPM(totalChars, duration),
 		CPM:      cpm,
-		Accuracy: accuracy,
+		Accuracy: CalculateAccuracy(totalChars, mistakes),
 		Mistakes: mistakes,
-		Duration: session.GetDuration(),
+		Duration: duration,
+
+		TotalChars:        totalChars,
+		NetWPM:            CalculateNetWPM(totalChars, session.GetUncorrectedErrors(), duration),
+		AdjustedWPM:       CalculateAdjustedWPM(session.GetCorrectChars(), session.GetAvgWordLength(), duration),
+		CorrectedErrors:   session.GetCorrectedErrors(),
+		UncorrectedErrors: session.GetUncorrectedErrors(),
+		BackspaceCount:    session.GetBackspaceCount(),
+		StructuralErrors:  session.GetStructuralErrors(),
+		AvgWordLength:     session.GetAvgWordLength(),
+		Fatigue:           session.Fatigue(),
+
+		UnitName:     session.DrillUnitName(),
+		Units:        units,
+		CorrectUnits: correctUnits,
+	}
+}
+
+// BuildReport describes the session's results for --json-result, whether or not it was finished
+func (rc *ResultsCalculator) BuildReport(session *Session) ResultReport {
+	results := rc.CalculateResults(session)
+	return ResultReport{
+		Mode:            session.GetMode(),
+		Completed:       session.completed && !session.partial,
+		Partial:         session.partial,
+		Pasted:          session.pasted,
+		DurationSeconds: results.Duration.Seconds(),
+		Results:         results,
+	}
+}
+
+// BuildRecord turns the session's results into a history record
+func (rc *ResultsCalculator) BuildRecord(session *Session) *SessionRecord {
+	results := rc.CalculateResults(session)
+
+	textLength := len(session.GetText())
+	if results.TotalChars > textLength {
+		textLength = results.TotalChars
+	}
+
+	return &SessionRecord{
+		Mode:              session.GetMode(),
+		Tier:              session.GetTier(),
+		TextLength:        textLength,
+		DurationMs:        results.Duration.Milliseconds(),
+		WPM:               results.WPM,
+		CPM:               results.CPM,
+		Accuracy:          results.Accuracy,
+		Mistakes:          results.Mistakes,
+		QuoteAuthor:       session.author,
+		NetWPM:            results.NetWPM,
+		AdjustedWPM:       results.AdjustedWPM,
+		CorrectedErrors:   results.CorrectedErrors,
+		UncorrectedErrors: results.UncorrectedErrors,
+		BackspaceCount:    results.BackspaceCount,
+		StructuralErrors:  results.StructuralErrors,
+		AvgWordLength:     results.AvgWordLength,
+		Fatigue:           results.Fatigue,
+		KeyStats:          session.keyStats,
+		Partial:           session.partial,
+		Pasted:            session.pasted,
 	}
 }
@@ -0,0 +1,172 @@
+package session
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gti/src/internal/config"
+	"gti/src/internal/vault"
+)
+
+// rollupVersion is bumped whenever DailyRollup changes meaning, so old rollups are rebuilt rather than misread
+const rollupVersion = 2
+
+// DailyRollup sums one day of sessions. Rollups outlive the raw history, so lifetime totals
+// and trends stay accurate even after records are pruned or the history format changes.
+// Sessions, time and characters cover every session; the speed and accuracy sums only those
+// that were finished, counted by Finished.
+type DailyRollup struct {
+	Date         string  `json:"date"`
+	Sessions     int     `json:"sessions"`
+	DurationMs   int64   `json:"duration_ms"`
+	Chars        int     `json:"chars"`
+	Finished     int     `json:"finished"`
+	Mistakes     int     `json:"mistakes"`
+	WPMSum       float64 `json:"wpm_sum"`
+	AccuracySum  float64 `json:"accuracy_sum"`
+	PeakWPM      float64 `json:"peak_wpm"`
+	BestAccuracy float64 `json:"best_accuracy"`
+}
+
+type rollupTable struct {
+	Version int `json:"version"`
+	// Snapshot is the day the table was last brought up to date
+	Snapshot string        `json:"snapshot"`
+	Days     []DailyRollup `json:"days"`
+}
+
+// AvgWPM is the day's mean WPM across its finished sessions
+func (d DailyRollup) AvgWPM() float64 {
+	if d.Finished == 0 {
+		return 0
+	}
+	return d.WPMSum / float64(d.Finished)
+}
+
+// add folds one session into the day's sums
+func (d *DailyRollup) add(r *SessionRecord) {
+	d.Sessions++
+	d.DurationMs += r.DurationMs
+	d.Chars += r.TextLength
+	// A session cut short only measured part of a test
+	if r.Partial {
+		return
+	}
+	d.Finished++
+	d.Mistakes += r.Mistakes
+	d.WPMSum += r.WPM
+	d.AccuracySum += r.Accuracy
+	d.PeakWPM = math.Max(d.PeakWPM, r.WPM)
+	d.BestAccuracy = math.Max(d.BestAccuracy, r.Accuracy)
+}
+
+// RollupPath is where the daily rollups are kept, beside the history file
+func RollupPath(cfg *config.Config) string {
+	return filepath.Join(filepath.Dir(config.ExpandPath(cfg.History.File)), "rollups.json")
+}
+
+func loadRollupTable(cfg *config.Config) rollupTable {
+	var table rollupTable
+	data, err := vault.ReadFile(cfg, RollupPath(cfg))
+	if err != nil || json.Unmarshal(data, &table) != nil || table.Version != rollupVersion {
+		return rollupTable{Version: rollupVersion}
+	}
+	return table
+}
+
+// rollupDays sums the records per local calendar day
+func rollupDays(records []*SessionRecord) map[string]*DailyRollup {
+	days := make(map[string]*DailyRollup)
+	for _, r := range CountedRecords(records) {
+		date := r.Timestamp.Local().Format("2006-01-02")
+		if days[date] == nil {
+			days[date] = &DailyRollup{Date: date}
+		}
+		days[date].add(r)
+	}
+	return days
+}
+
+// SnapshotRollups brings the rollup table up to date with every finished day in the history.
+// It only reads the history on the first call of each day, so calling it on every launch is cheap.
+// A day already rolled up is only replaced by a recount that holds at least as many sessions,
+// so pruned records never shrink the totals.
+func SnapshotRollups(cfg *config.Config) error {
+	if !cfg.History.Enabled {
+		return nil
+	}
+	if vault.HasKey(cfg) && !vault.Unlocked() {
+		// Sealed sessions wait until something that reads the history asks for the passphrase
+		return nil
+	}
+	today := time.Now().Format("2006-01-02")
+	table := loadRollupTable(cfg)
+	if table.Snapshot == today {
+		return nil
+	}
+
+	records, err := LoadSessionRecords(cfg)
+	if err != nil {
+		return err
+	}
+	days := make(map[string]DailyRollup, len(table.Days))
+	for _, d := range table.Days {
+		days[d.Date] = d
+	}
+	for date, d := range rollupDays(records) {
+		// Today is still being typed; it is rolled up once it is over
+		if date >= today {
+			continue
+		}
+		if old, ok := days[date]; !ok || d.Sessions >= old.Sessions {
+			days[date] = *d
+		}
+	}
+
+	table.Snapshot = today
+	table.Days = sortedDays(days)
+	path := RollupPath(cfg)
+	if err := config.EnsureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	// Write beside the table and rename, so a crash mid-write cannot lose the only copy of pruned days
+	tmp := path + ".tmp"
+	data, err := json.MarshalIndent(table, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := vault.WriteFile(cfg, tmp, append(data, '\n')); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// LifetimeDays merges the rollups with the raw records, day by day, taking whichever source holds
+// more sessions for each day. It covers days whose records have been pruned as well as today.
+func LifetimeDays(cfg *config.Config, records []*SessionRecord) []DailyRollup {
+	days := make(map[string]DailyRollup)
+	for _, d := range loadRollupTable(cfg).Days {
+		days[d.Date] = d
+	}
+	for date, d := range rollupDays(records) {
+		if old, ok := days[date]; !ok || d.Sessions >= old.Sessions {
+			days[date] = *d
+		}
+	}
+	return sortedDays(days)
+}
+
+func sortedDays(days map[string]DailyRollup) []DailyRollup {
+	sorted := make([]DailyRollup, 0, len(days))
+	for _, d := range days {
+		sorted = append(sorted, d)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Date < sorted[j].Date
+	})
+	return sorted
+}
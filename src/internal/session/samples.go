@@ -0,0 +1,47 @@
+package session
+
+import "time"
+
+// Sample is one second of a run, as charted on the results screen
+type Sample struct {
+	// WPM is the raw speed over this second alone
+	WPM float64
+	// Accuracy is the accuracy of the whole run up to the end of this second
+	Accuracy float64
+	// Errors counts the mistakes made during this second
+	Errors int
+}
+
+// sampleSeconds records a sample for every whole second that has passed since the last one
+func (s *Session) sampleSeconds() {
+	for len(s.samples) < int(s.duration/time.Second) {
+		s.takeSample(time.Second)
+	}
+}
+
+// sampleRemainder records the seconds left unsampled when the run ends, the last one possibly partial
+func (s *Session) sampleRemainder() {
+	s.sampleSeconds()
+	if rest := s.duration - time.Duration(len(s.samples))*time.Second; rest >= 100*time.Millisecond {
+		s.takeSample(rest)
+	}
+}
+
+func (s *Session) takeSample(span time.Duration) {
+	chars := s.totalChars + len(s.userInput)
+	mistakes := s.totalMistakes + s.mistakes
+	// Backspacing can leave fewer characters than the last sample saw
+	typed := max(chars-s.sampledChars, 0)
+	s.samples = append(s.samples, Sample{
+		WPM:      CalculateWPM(typed, span),
+		Accuracy: CalculateAccuracy(chars, mistakes),
+		Errors:   max(mistakes-s.sampledMistakes, 0),
+	})
+	s.sampledChars = chars
+	s.sampledMistakes = mistakes
+}
+
+// GetSamples returns the per-second samples of the run so far
+func (s *Session) GetSamples() []Sample {
+	return s.samples
+}
@@ -0,0 +1,80 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+
+	"gti/src/internal/config"
+	"gti/src/internal/vault"
+)
+
+// ResealHistory rewrites the history and the daily rollups sealed or in the clear, as
+// history.encrypt now says, and returns how many sessions it rewrote. Sessions sealed with another
+// machine's key are kept as they are. Sealed data is opened with the passphrase, asked for if needed.
+func ResealHistory(cfg *config.Config) (int, error) {
+	path := config.ExpandPath(cfg.History.File)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	var out bytes.Buffer
+	count := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if vault.IsSealed(line) {
+			opened, err := vault.Open(cfg, line)
+			if err != nil {
+				if !vault.Unlocked() {
+					return 0, err
+				}
+				out.Write(line)
+				out.WriteByte('\n')
+				continue
+			}
+			line = opened
+		}
+		if cfg.History.Encrypt {
+			if line, err = vault.Seal(cfg, line); err != nil {
+				return 0, err
+			}
+		}
+		out.Write(line)
+		out.WriteByte('\n')
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	if data != nil {
+		// Written beside the history and renamed, so a failure never leaves it half converted
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, out.Bytes(), 0644); err != nil {
+			return 0, err
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return 0, err
+		}
+	}
+
+	rollups, err := vault.ReadFile(cfg, RollupPath(cfg))
+	if err == nil {
+		tmp := RollupPath(cfg) + ".tmp"
+		if err := vault.WriteFile(cfg, tmp, rollups); err != nil {
+			return 0, err
+		}
+		if err := os.Rename(tmp, RollupPath(cfg)); err != nil {
+			return 0, err
+		}
+	} else if !os.IsNotExist(err) {
+		return 0, err
+	}
+	return count, nil
+}
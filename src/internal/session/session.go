@@ -3,18 +3,23 @@ package session
 import (
 	"fmt"
 	"hash/fnv"
-	"os"
-	"os/exec"
-	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"gti/src/internal"
+	"gti/src/internal/cjk"
 	"gti/src/internal/config"
+	"gti/src/internal/keymap"
+	"gti/src/internal/sound"
+	"gti/src/internal/syntax"
+	"gti/src/internal/tts"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
 )
 
 const (
@@ -23,9 +28,12 @@ const (
 	MaxQuoteCount           = 10
 	CharsPerWord            = 5.0
 	DefaultTimedSeconds     = 60
+	// DefaultChunkLines is how many lines of a code file each chunk holds when practising from --start
+	DefaultChunkLines       = 6
 
 	// Rendering constants
-	RenderWindowSize        = 200
+	// RenderWindowLines is how many wrapped lines of prose are shown around the cursor
+	RenderWindowLines       = 5
 	ScrollSpeed             = 3
 	MinScrollIncrement      = 1
 	ScrollOverlap           = 1
@@ -44,23 +52,11 @@ const (
 	WordLengthEstimate      = 5.5
 )
 
-var Tips = []string{
-	"Keep your fingers on the home row (ASDF for left, JKL; for right)",
-	"Look at the screen, not your keyboard while typing",
-	"Use all your fingers, not just your index fingers",
-	"Practice regularly to build muscle memory",
-	"Focus on accuracy first, speed will come naturally",
-	"Take breaks to avoid fatigue and maintain focus",
-	"Breathe steadily and stay relaxed while typing",
-	"Maintain proper posture with feet flat on the floor",
-	"Keep your wrists straight and at a comfortable height",
-	"Practice difficult letter combinations separately",
-	"Use the correct finger for each key to avoid bad habits",
-	"Start slow and gradually increase your typing speed",
-	"Minimize mistakes by focusing on the next character",
-	"Rest your hands when not typing to prevent strain",
-	"Practice typing common words and phrases regularly",
-}
+// NewlineMarker is drawn where code expects Enter
+const NewlineMarker = "⏎"
+
+// DemoMode marks self-playing sessions, which are never saved to history
+const DemoMode = "demo"
 
 type SessionCompleteMsg struct{}
 type TimerTickMsg struct{}
@@ -91,6 +87,12 @@ type Timing struct {
 	timer      *time.Timer
 	running    bool
 	completed  bool
+	paused     bool
+	pausedAt   time.Time
+	// partial marks a run saved before its text or time ran out
+	partial bool
+	// wordTarget ends the run once this many words are typed, 0 for no target
+	wordTarget int
 }
 
 type TextData struct {
@@ -98,14 +100,27 @@ type TextData struct {
 	author     string
 	userInput  string
 	allChunks  []string
+	roundTips  []string
+	// tips rotate under the text while typing, starting from tipOffset
+	tips       []string
+	tipOffset  int
+	sourceFile string
+	drill      *cjk.Drill
+	unitStarts []int
 }
 
 type UIState struct {
 	layoutDirty           bool
 	showContext           bool
+	showCheatSheet        bool
+	showKeyboard          bool
+	rtl                   bool
+	diacritics            bool
 	ttsUnavailableMessage string
 	RemainingTimeDisplay  int
-	ExternalMistakes      int
+	keys                  *keymap.Bindings
+	// emulation remaps what is typed to the layout being learned; nil types as the keyboard does
+	emulation *keymap.Emulation
 }
 
 type Scrolling struct {
@@ -116,6 +131,25 @@ type Scrolling struct {
 type Performance struct {
 	cachedLines []string
 	textHash    uint32
+	cachedKinds []syntax.Kind
+	kindsHash   uint32
+	// linesText is the text the cached lines were split from; comparing it with the text costs nothing
+	// while the text is unchanged, where hashing it again would read all of it on every frame
+	linesText  string
+	lineStarts []int
+	// wrapStarts is where each line of prose starts, for wrappedText wrapped to wrapWidth
+	wrapStarts  []int
+	wrappedText string
+	wrapWidth   int
+}
+
+type Recording struct {
+	keystrokes     []Keystroke
+	chunkStartTime time.Time
+	ghost          *Replay
+	ghostText      string
+	ghostLoaded    bool
+	bot            *Bot
 }
 
 type Statistics struct {
@@ -123,7 +157,16 @@ type Statistics struct {
 	correctedErrors   int
 	uncorrectedErrors int
 	correctChars      int
+	structuralErrors  int
+	drillUnits        int
+	drillCorrect      int
+	listenWords       int
+	listenCorrect     int
 	avgWordLength     float64
+	keyStats          map[string]KeyStat
+	samples           []Sample
+	sampledChars      int
+	sampledMistakes   int
 }
 
 type SessionConfig struct {
@@ -138,18 +181,59 @@ type SessionConfig struct {
 	QuoteList    []Quote
 	Language     string
 	CodeCount    int
+	Difficulty   string
 	File         string
 	Start        int
+	Drill        *cjk.Drill
+	RoundTips    []string
+	StopOnError  bool
+	NoBackspace  bool
+	Provider     TextProvider
+	Listening    bool
+	WordTarget   int
+	// QuoteSource fetches count fresh quotes for the next test, nil to type the same ones again
+	QuoteSource func(count int) []Quote
+	// Clock replaces the wall clock, for playing back recorded runs
+	Clock func() time.Time
 }
 
 // NewSessionWithOptions creates a session using the unified SessionConfig
 func NewSessionWithOptions(cfg *config.Config, sessionConfig SessionConfig) *Session {
+	cfg, overrides := cfg.ForMode(sessionConfig.Mode)
 	session := &Session{
-		config: cfg,
-		mode:   sessionConfig.Mode,
-		tier:   sessionConfig.Tier,
+		config:   cfg,
+		mode:     sessionConfig.Mode,
+		tier:     sessionConfig.Tier,
+		language: sessionConfig.Language,
+		setup:    sessionConfig,
+		// A mode's chunk sizes are fixed here, so later chunks match the first
+		chunkWords: overrides.Words(0),
+		chunkLines: overrides.Lines(DefaultChunkLines),
+	}
+	session.keys = keymap.NewBindings(cfg.Keybindings)
+	session.showKeyboard = cfg.Display.ShowKeyboard
+	if name := cfg.Keyboard.EmulatedLayout(); name != "" {
+		// An unknown layout is reported at startup, and typing then goes through as is
+		session.emulation, _ = keymap.LoadEmulation(name)
+	}
+	// A missing audio player is reported once at startup, so the session simply stays silent
+	session.sound, _ = sound.For(cfg.Sound)
+	session.speech, session.speechErr = tts.For(cfg.TTS)
+	session.sourceFile = sessionConfig.File
+	session.listening = sessionConfig.Listening
+	session.wordTarget = sessionConfig.WordTarget
+	session.clockNow = sessionConfig.Clock
+	if !session.IsCodeMode() {
+		session.rtl = internal.IsRTLLanguage(cfg.Language.Default)
+		session.diacritics = internal.HasDiacritics(cfg.Language.Default)
+	}
+	if session.language == "" && sessionConfig.File != "" {
+		session.language = internal.DetectCodeLanguage(sessionConfig.File)
+	}
+
+	if session.IsCodeMode() {
+		session.highlighter = highlighterFor(sessionConfig)
 	}
-
 	// Set text and related fields based on configuration
 	session.setTextFromConfig(sessionConfig)
 
@@ -167,15 +251,45 @@ func NewSessionWithOptions(cfg *config.Config, sessionConfig SessionConfig) *Ses
 	if len(sessionConfig.AllChunks) > 0 {
 		session.allChunks = sessionConfig.AllChunks
 	}
+	session.roundTips = sessionConfig.RoundTips
+	session.stopOnError = sessionConfig.StopOnError
+	session.noBackspace = sessionConfig.NoBackspace
+	if overrides.StopOnError != nil && !sessionConfig.StopOnError {
+		session.stopOnError = *overrides.StopOnError
+	}
+	if overrides.Backspace != nil && !sessionConfig.NoBackspace {
+		session.noBackspace = !*overrides.Backspace
+	}
+	if session.IsCodeMode() {
+		session.loadTips(session.language)
+	} else {
+		session.loadTips(cfg.Language.Default)
+	}
 
 	session.calculateAvgWordLength()
 	return session
 }
 
+// wordsPerChunk is the mode's configured chunk size, or def when it has none
+func (s *Session) wordsPerChunk(def int) int {
+	if s.chunkWords > 0 {
+		return s.chunkWords
+	}
+	return def
+}
+
 // setTextFromConfig sets the text and related fields based on the session configuration
 func (s *Session) setTextFromConfig(sessionConfig SessionConfig) {
 	// Set text and related fields based on configuration
-	if sessionConfig.Text != "" {
+	if sessionConfig.Provider != nil {
+		s.provider = sessionConfig.Provider
+		s.maxChunks = sessionConfig.MaxChunks
+		s.nextProvidedChunk()
+	} else if sessionConfig.Drill != nil {
+		s.drill = sessionConfig.Drill
+		s.unitStarts = s.drill.UnitStarts()
+		s.text = s.drill.Text()
+	} else if sessionConfig.Text != "" {
 		s.text = sessionConfig.Text
 		s.author = sessionConfig.Author
 	} else if sessionConfig.File != "" {
@@ -183,7 +297,7 @@ func (s *Session) setTextFromConfig(sessionConfig SessionConfig) {
 		if sessionConfig.Mode == "code" || sessionConfig.Mode == "custom-code" {
 			if sessionConfig.Start == 1 {
 				// For code mode with default start, load entire file as one snippet
-				text, err := loadTextFromFile(sessionConfig.File)
+				text, err := loadTextFromFile(sessionConfig.File, true, s.config.Import)
 				if err != nil {
 					s.text = config.DefaultPracticeText
 				} else {
@@ -191,8 +305,8 @@ func (s *Session) setTextFromConfig(sessionConfig SessionConfig) {
 				}
 			} else {
 				// For code mode with custom start, load lines in chunks of 6 starting from specified chunk
-				paragraphs := loadParagraphs(sessionConfig.File)
-				linesPerChunk := 6
+				paragraphs := loadParagraphs(sessionConfig.File, true, s.config.Import)
+				linesPerChunk := s.chunkLines
 				chunkIndex := sessionConfig.Start - 1 // 0-based chunk index
 				if chunkIndex < 0 {
 					chunkIndex = 0
@@ -220,7 +334,7 @@ func (s *Session) setTextFromConfig(sessionConfig SessionConfig) {
 			}
 		} else {
 			// For other modes, split into paragraphs
-			paragraphs := loadParagraphs(sessionConfig.File)
+			paragraphs := loadParagraphs(sessionConfig.File, false, s.config.Import)
 			s.text = getParagraphAtStart(paragraphs, sessionConfig.Start)
 			s.allChunks = paragraphs
 			s.chunkIndex = sessionConfig.Start - 1
@@ -242,10 +356,10 @@ func (s *Session) setTextFromConfig(sessionConfig SessionConfig) {
 		}
 	} else if sessionConfig.Language != "" && sessionConfig.CodeCount > 0 {
 		// Generate multiple code snippets
-		s.text = internal.GenerateCodeSnippets(sessionConfig.CodeCount, sessionConfig.Language)
+		s.text = internal.GenerateCodeSnippets(sessionConfig.CodeCount, sessionConfig.Language, sessionConfig.Difficulty)
 	} else if sessionConfig.Language != "" {
 		// Generate single code snippet
-		s.text = internal.GenerateCodeSnippet(sessionConfig.Language)
+		s.text = internal.GenerateCodeSnippet(sessionConfig.Language, sessionConfig.Difficulty)
 		if !strings.Contains(sessionConfig.Mode, "code") {
 			s.mode = sessionConfig.Language + "-code"
 		}
@@ -255,14 +369,14 @@ func (s *Session) setTextFromConfig(sessionConfig SessionConfig) {
 			pageSize := 3
 			var currentPageChunks int
 			if sessionConfig.MaxChunks <= 1 || !isGroupMode {
-				s.text = internal.GenerateWordsDynamic(16, s.config.Language.Default)
+				s.text = internal.GenerateWordsDynamic(s.wordsPerChunk(16), s.config.Language.Default)
 				pageSize = 1
 				currentPageChunks = 1
 			} else {
 				currentPageChunks = min(pageSize, sessionConfig.MaxChunks)
 				var chunks []string
 				for i := 0; i < currentPageChunks; i++ {
-					chunks = append(chunks, internal.GenerateWordsDynamic(17, s.config.Language.Default))
+					chunks = append(chunks, internal.GenerateWordsDynamic(s.wordsPerChunk(17), s.config.Language.Default))
 				}
 				s.text = strings.Join(chunks, "\n\n")
 			}
@@ -274,25 +388,44 @@ func (s *Session) setTextFromConfig(sessionConfig SessionConfig) {
 			// Default text generation based on mode
 			switch sessionConfig.Mode {
 			case "words":
-				s.text = internal.GenerateWordsDynamic(DefaultWordCount, s.config.Language.Default)
+				s.text = internal.GenerateWordsDynamic(s.wordsPerChunk(DefaultWordCount), s.config.Language.Default)
 				s.timeLimit = time.Duration(DefaultTimedSeconds) * time.Second
 			case "timed":
-				s.text = internal.GenerateWordsDynamic(DefaultWordCount, s.config.Language.Default)
+				s.text = internal.GenerateWordsDynamic(s.wordsPerChunk(DefaultWordCount), s.config.Language.Default)
 				s.timeLimit = time.Duration(s.config.Timed.DefaultSeconds) * time.Second
 			case "practice":
-				s.text = internal.GenerateWordsDynamic(DefaultWordCount, s.config.Language.Default)
+				s.text = internal.GenerateWordsDynamic(s.nextChunkWords(), s.config.Language.Default)
 			case "quote":
 				s.text = config.DefaultPracticeText
 			default:
 				s.text = config.DefaultPracticeText
 			}
 		}
+	s.stripComments()
 }
 
 type Session struct {
-	config *config.Config
-	mode   string
-	tier   string
+	config      *config.Config
+	mode        string
+	tier        string
+	language    string
+	highlighter *syntax.Highlighter
+	// chunkWords overrides the words per generated chunk for this mode, 0 for the built-in sizes
+	chunkWords int
+	// chunkLines is how many lines of a code file each chunk holds when practising from a start chunk
+	chunkLines int
+	// setup is what the session was created from, so a next test can be generated the same way
+	setup SessionConfig
+	// sound plays key press and mistake sounds; nil when sound is off
+	sound *sound.Player
+	// speech reads the next word aloud while the context view is on; nil when no speech engine was found
+	speech *tts.Speaker
+	// speechErr says why speech is unavailable, shown when the context view is turned on
+	speechErr error
+	// unrecorded keeps the session out of the history and away from the completion hook
+	unrecorded bool
+	// endOnPause stops the run for good where it would pause, for runs whose clock must never stop
+	endOnPause bool
 
 	SessionState
 	TextData
@@ -301,28 +434,37 @@ type Session struct {
 	Scrolling
 	Performance
 	Statistics
-}
-
-// saveRecord saves a session record with the given mistakes count
-func (s *Session) saveRecord(mistakes int) {
-	record := &SessionRecord{
-		Mode:              s.mode,
-		Tier:              s.tier,
-		TextLength:        len(s.text),
-		DurationMs:        s.duration.Milliseconds(),
-		WPM:               s.CalculateWPM(),
-		CPM:               s.CalculateCPM(),
-		Accuracy:          s.CalculateAccuracy(),
-		Mistakes:          mistakes,
-		QuoteAuthor:       s.author,
-		NetWPM:            CalculateNetWPM(s.totalChars+len(s.userInput), s.GetUncorrectedErrors(), s.duration),
-		AdjustedWPM:       CalculateAdjustedWPM(s.GetCorrectChars(), s.GetAvgWordLength(), s.duration),
-		CorrectedErrors:   s.GetCorrectedErrors(),
-		UncorrectedErrors: s.GetUncorrectedErrors(),
-		BackspaceCount:    s.GetBackspaceCount(),
-		AvgWordLength:     s.GetAvgWordLength(),
-	}
-	SaveSessionRecord(s.config, record)
+	Recording
+	Focus
+	TimingLog
+	ProtocolLog
+	StandardLog
+	LessonLog
+	BookState
+	PasteGuard
+	WordTiming
+	MistakeLog
+	IdleWatch
+	Listening
+	ChunkSummary
+	Provided
+	CellCache
+	RunReplay
+	Clock
+	HookLog
+	Advice
+	PercentileLog
+}
+
+// Unrecorded leaves the session out of the history and does not run the completion hook for it,
+// for sessions typed by whoever walks up to a kiosk
+func (s *Session) Unrecorded() {
+	s.unrecorded = true
+}
+
+// saveRecord records the finished session in the history file
+func (s *Session) saveRecord() {
+	SaveSessionRecord(s.config, NewResultsCalculator().BuildRecord(s))
 }
 
 // Unified session creation with options pattern
@@ -349,7 +491,8 @@ func NewSession(cfg *config.Config, mode string, opts ...SessionOption) *Session
 	default:
 		// Check if it's a code mode
 		if strings.Contains(mode, "code") || mode == "snippet" {
-			if config.Language == "" {
+			// Custom files get their language detected in NewSessionWithOptions
+			if config.Language == "" && config.File == "" {
 				config.Language = extractLanguageFromMode(mode)
 			}
 		}
@@ -383,6 +526,13 @@ func WithQuotes(quoteList []Quote) SessionOption {
 	}
 }
 
+// WithQuoteSource lets the next test fetch fresh quotes instead of typing the same ones again
+func WithQuoteSource(source func(count int) []Quote) SessionOption {
+	return func(c *SessionConfig) {
+		c.QuoteSource = source
+	}
+}
+
 // WithChunkLimit sets maximum chunks for practice
 func WithChunkLimit(maxChunks int) SessionOption {
 	return func(c *SessionConfig) {
@@ -410,6 +560,13 @@ func WithCodeCount(count int) SessionOption {
 	}
 }
 
+// WithDifficulty restricts generated code snippets to one difficulty tier
+func WithDifficulty(difficulty string) SessionOption {
+	return func(c *SessionConfig) {
+		c.Difficulty = difficulty
+	}
+}
+
 // WithTimeLimit sets time limit
 func WithTimeLimit(seconds int) SessionOption {
 	return func(c *SessionConfig) {
@@ -419,6 +576,13 @@ func WithTimeLimit(seconds int) SessionOption {
 	}
 }
 
+// WithRoundTips shows a piece of guidance in place of the tips while each chunk is typed
+func WithRoundTips(tips []string) SessionOption {
+	return func(c *SessionConfig) {
+		c.RoundTips = tips
+	}
+}
+
 // WithText sets custom text directly
 func WithText(text string, allChunks []string, chunkIndex int) SessionOption {
 	return func(c *SessionConfig) {
@@ -437,6 +601,15 @@ func extractLanguageFromMode(mode string) string {
 		"cpp":        "cpp",
 		"rust":       "rust",
 		"typescript": "typescript",
+		"csharp":     "csharp",
+		"ruby":       "ruby",
+		"php":        "php",
+		"kotlin":     "kotlin",
+		"swift":      "swift",
+		"sql":        "sql",
+		"bash":       "bash",
+		"html":       "html",
+		"css":        "css",
 	}
 
 	for key, lang := range languageMap {
@@ -482,17 +655,28 @@ func NewSessionTimed(cfg *config.Config, mode string, text string, allChunks []s
 
 
 
-func loadTextFromFile(file string) (string, error) {
-	data, err := os.ReadFile(file)
+// loadTextFromFile reads a text file, or standard input when file is "-". Markdown is stripped to its
+// plain text, or for code practice cut down to its code blocks when it has any.
+func loadTextFromFile(file string, code bool, clean config.ImportConfig) (string, error) {
+	data, err := internal.ReadFile(file)
 	if err != nil {
 		return "", err
 	}
-	return string(data), nil
+	text := internal.CleanImported(string(data), clean, code)
+	if !internal.IsMarkdownFile(file) {
+		return text, nil
+	}
+	if code {
+		if blocks := internal.MarkdownCode(text); blocks != "" {
+			return blocks, nil
+		}
+	}
+	return internal.StripMarkdown(text), nil
 }
 
 // loadFileContent provides unified file loading with fallback
 func loadFileContent(filePath string, fallback string) string {
-	data, err := os.ReadFile(filePath)
+	data, err := internal.ReadFile(filePath)
 	if err != nil {
 		return fallback
 	}
@@ -534,12 +718,8 @@ func splitTextIntoParagraphs(text string) []string {
 	return result
 }
 
-func LoadParagraphs(file string) []string {
-	text, err := loadTextFromFile(file)
-	if err != nil {
-		text = config.DefaultPracticeText
-	}
-	return splitTextIntoParagraphs(text)
+func LoadParagraphs(file string, clean config.ImportConfig) []string {
+	return loadParagraphs(file, false, clean)
 }
 
 func GetParagraphAtStart(paragraphs []string, start int) string {
@@ -556,8 +736,12 @@ func GetParagraphAtStart(paragraphs []string, start int) string {
 	return paragraphs[startIndex]
 }
 
-func loadParagraphs(file string) []string {
-	return LoadParagraphs(file)
+func loadParagraphs(file string, code bool, clean config.ImportConfig) []string {
+	text, err := loadTextFromFile(file, code, clean)
+	if err != nil {
+		text = config.DefaultPracticeText
+	}
+	return splitTextIntoParagraphs(text)
 }
 
 func getParagraphAtStart(paragraphs []string, start int) string {
@@ -578,37 +762,17 @@ func max(a, b int) int {
 	return b
 }
 
-func speak(word string) {
-	go func() {
-		switch runtime.GOOS {
-		case "linux":
-			exec.Command("espeak", word).Run()
-		case "darwin":
-			exec.Command("say", word).Run()
-		case "windows":
-			exec.Command("powershell", "-c", "Add-Type -AssemblyName System.Speech; (New-Object System.Speech.Synthesis.SpeechSynthesizer).Speak('"+word+"')").Run()
-		}
-	}()
-}
-
-func ttsAvailable() bool {
-	switch runtime.GOOS {
-	case "linux":
-		_, err := exec.LookPath("espeak")
-		return err == nil
-	case "darwin":
-		_, err := exec.LookPath("say")
-		return err == nil
-	case "windows":
-		return true
-	default:
-		return false
-	}
-}
-
 func (s *Session) Start() tea.Cmd {
-	s.startTime = time.Now()
+	s.startTime = s.now()
 	s.running = true
+	s.paused = false
+	s.partial = false
+	s.pasted = false
+	s.pasteAttempts = 0
+	s.touchInput()
+	s.markRun()
+	s.timingMarker("start")
+	s.sayListening()
 	return s.tickTimer()
 }
 
@@ -616,18 +780,142 @@ func (s *Session) Restart() tea.Cmd {
 	s.userInput = ""
 	s.position = 0
 	s.mistakes = 0
-	s.totalMistakes = 0
-	s.totalChars = 0
 	s.totalChunks = 0
 	s.chunkIndex = 0
-	s.duration = 0
 	s.completed = false
+	if s.listening && len(s.allChunks) > 0 {
+		// The sentences are heard again from the first
+		s.text = s.allChunks[0]
+	}
+	s.ResetTotals()
+	s.clearFocus()
 	return s.Start()
 }
 
+// NextTest starts a fresh run on newly generated text or fresh quotes, keeping the session's mode and
+// options. Sessions on fixed text, such as custom files, simply start over. The run left behind is
+// not recorded.
+func (s *Session) NextTest() tea.Cmd {
+	s.clearFocus()
+	// The words typed so far go first, so a word target sizes the new text from zero
+	s.ResetTotals()
+	if s.setup.QuoteSource != nil {
+		s.setup.QuoteList = s.setup.QuoteSource(len(s.setup.QuoteList))
+	}
+	if s.mode == "timed" && s.setup.Text != "" {
+		// A timed test is handed the words generated for it, so the next one needs new words
+		s.setup.Text = internal.GenerateWordsDynamic(s.wordsPerChunk(DefaultWordCount), s.config.Language.Default)
+	}
+	s.setTextFromConfig(s.setup)
+	s.invalidateLineCache()
+	s.scrollOffset = 0
+	s.layoutDirty = true
+	return s.Restart()
+}
+
+// ResetTotals clears everything accumulated across chunks so a new run starts from zero
+func (s *Session) ResetTotals() {
+	s.totalChars = 0
+	s.totalMistakes = 0
+	s.duration = 0
+	s.Statistics = Statistics{avgWordLength: s.avgWordLength}
+	s.keystrokes = nil
+	s.timingEvents = nil
+	s.timingResult = ""
+	s.protocolResult = ""
+	s.standardResult = ""
+	s.lessonResult = ""
+	s.hookResult = ""
+	s.percentileResult = ""
+	s.ChunkSummary = ChunkSummary{}
+	s.WordTiming = WordTiming{}
+	s.MistakeLog = MistakeLog{}
+}
+
+// Resume continues a session on freshly set text without resetting its clock or totals
+func (s *Session) Resume() {
+	s.running = true
+	s.completed = false
+}
+
+// Pause stops the clock and input until Unpause; finished or already paused sessions are left alone
+func (s *Session) Pause() {
+	if !s.running || s.completed {
+		return
+	}
+	s.endChunkSummary()
+	s.duration = s.since(s.startTime)
+	s.running = false
+	s.paused = true
+	s.pausedAt = s.now()
+}
+
+// Unpause restarts the clock where Pause stopped it, so the time spent paused does not count
+func (s *Session) Unpause() tea.Cmd {
+	if !s.paused {
+		return nil
+	}
+	pausedFor := s.since(s.pausedAt)
+	s.startTime = s.startTime.Add(pausedFor)
+	if !s.chunkStartTime.IsZero() {
+		s.chunkStartTime = s.chunkStartTime.Add(pausedFor)
+	}
+	s.shiftWordMark(pausedFor)
+	s.paused = false
+	s.idle = false
+	s.running = true
+	s.touchInput()
+	s.sayListening()
+	return s.tickTimer()
+}
+
+func (s *Session) IsPaused() bool {
+	return s.paused
+}
+
+// SavePartial ends a paused session early, recording what was typed so far as a partial session
+func (s *Session) SavePartial() {
+	if !s.paused {
+		return
+	}
+	// Move the clock past the pause so end markers in the timing export line up with the typing
+	s.startTime = s.startTime.Add(s.since(s.pausedAt))
+	s.paused = false
+	s.idle = false
+	s.partial = true
+	s.finish()
+}
+
+// EndsOnPause reports whether pausing ends the run instead, as under a research protocol
+func (s *Session) EndsOnPause() bool {
+	return s.endOnPause
+}
+
+// Stop ends a running session early, recording what was typed so far as a partial session
+func (s *Session) Stop() tea.Cmd {
+	if !s.running || s.completed {
+		return nil
+	}
+	s.endChunkSummary()
+	s.duration = s.since(s.startTime)
+	s.partial = true
+	return s.finish()
+}
+
+// KeepsOnQuit reports whether quitting from the pause screen saves the session as partial: timed tests
+// that have had something typed, whose practice time should count even when the test is cut short
+func (s *Session) KeepsOnQuit() bool {
+	return s.paused && s.timeLimit > 0 && s.totalChars+len(s.userInput) > 0
+}
+
 func (s *Session) ToggleContext() {
-	if !s.showContext && !ttsAvailable() {
-		s.ttsUnavailableMessage = "Linux users must install espeak-ng to use TTS."
+	if s.listening {
+		// A listening drill always speaks, so the key hears the sentence again
+		s.sayListening()
+		return
+	}
+	if !s.showContext && s.speech == nil {
+		s.ttsUnavailableMessage = "TTS is unavailable: " + s.speechErr.Error()
 		s.layoutDirty = true
 		return
 	}
@@ -635,7 +923,7 @@ func (s *Session) ToggleContext() {
 	if s.showContext {
 		next := s.getNextWord()
 		if next != "" {
-			speak(next)
+			s.speech.Say(next)
 		}
 	}
 	s.ttsUnavailableMessage = ""
@@ -666,42 +954,131 @@ func (s *Session) getNextWord() string {
 }
 
 func (s *Session) HandleInput(key tea.KeyMsg) tea.Cmd {
-	if !s.running || s.completed {
+	if !s.running || s.completed || s.chunkSummary != "" {
+		return nil
+	}
+	s.touchInput()
+	if handled, cmd := s.guardPaste(key); handled {
+		return cmd
+	}
+
+	if s.emulation != nil && key.Type == tea.KeyRunes && !key.Alt {
+		runes := make([]rune, len(key.Runes))
+		for i, r := range key.Runes {
+			runes[i] = s.emulation.Remap(r)
+		}
+		key.Runes = runes
+	}
+	if key.Type == tea.KeyRunes && len(key.Runes) > 1 {
+		return s.typeEach(key)
+	}
+	return s.typeKey(key)
+}
+
+// typeKey types what key carries into the text
+func (s *Session) typeKey(key tea.KeyMsg) tea.Cmd {
+	if !s.running || s.completed || s.chunkSummary != "" {
 		return nil
 	}
+	if s.listening {
+		return s.handleListeningInput(key)
+	}
 
 	switch key.Type {
 	case tea.KeyBackspace:
-		if len(s.userInput) > 0 {
+		if len(s.userInput) > 0 && !s.noBackspace {
 			s.backspaceCount++
-			removedChar := s.userInput[len(s.userInput)-1]
-			s.userInput = s.userInput[:len(s.userInput)-1]
+			// A character of several bytes is taken back whole
+			_, size := utf8.DecodeLastRuneInString(s.text[:s.position])
+			size = max(1, min(size, len(s.userInput)))
+			removed := s.userInput[len(s.userInput)-size:]
+			s.userInput = s.userInput[:len(s.userInput)-size]
 			if s.position > 0 {
-				s.position--
-				if removedChar != s.text[s.position] {
+				s.position -= size
+				if removed != s.text[s.position:s.position+size] {
 					s.correctedErrors++
 					s.uncorrectedErrors--
 				}
+				s.untimeWord()
 			}
+			s.recordTiming("backspace", "")
+			s.recordKeystroke("backspace")
+			s.sound.Key()
 		}
 	default:
 		char := key.String()
-		if len(char) == 1 {
-			s.userInput += char
+		// Enter types the line breaks of code; elsewhere the text has none to type
+		if key.Type == tea.KeyEnter && s.IsCodeMode() {
+			char = "\n"
+		}
+		single := utf8.RuneCountInString(char) == 1
+		if single && s.stopOnError && s.position < len(s.text) && char != s.charAt(s.position) && !s.accentSlip(char, s.charAt(s.position)) {
+			// The mistake is counted, but the cursor waits for the right key
+			expectedChar := s.charAt(s.position)
+			s.countKey(expectedChar, false)
+			s.mistakes += s.mistakeWeight(expectedChar)
+			if s.isStructural(expectedChar) {
+				s.structuralErrors++
+			}
+			s.recordMistake(char, expectedChar)
+			s.recordTiming(char, expectedChar)
+			s.sound.Error()
+		} else if single {
+			s.skipTone(char)
+			typed := char
+			correct := true
+			autoIndent := false
+			expectedChar := ""
 			if s.position < len(s.text) {
-				expectedChar := string(s.text[s.position])
-				if char == expectedChar {
+				expectedChar = s.charAt(s.position)
+				slip := s.accentSlip(char, expectedChar)
+				correct = char == expectedChar || (slip && s.config.Keyboard.Accents == config.AccentsLoose)
+				s.countKey(expectedChar, correct)
+				switch {
+				case correct:
+					typed = expectedChar
 					s.correctChars++
-				} else {
-					s.mistakes++
+					autoIndent = char == "\n" && s.IsCodeMode() && s.config.Code.AutoIndent
+				case slip:
+					// Partial credit: the letter is taken, but the missing accent costs accuracy
+					typed = expectedChar
+					s.mistakes += s.mistakeWeight(expectedChar)
+					if s.isStructural(expectedChar) {
+						s.structuralErrors++
+					}
+					s.recordMistake(char, expectedChar)
+				default:
+					typed = alignTyped(char, expectedChar)
+					s.mistakes += s.mistakeWeight(expectedChar)
+					if s.isStructural(expectedChar) {
+						s.structuralErrors++
+					}
 					s.uncorrectedErrors++
+					s.recordMistake(char, expectedChar)
 				}
 			}
-			s.position++
+			s.userInput += typed
+			if correct {
+				s.sound.Key()
+			} else {
+				s.sound.Error()
+			}
+			s.recordTiming(char, expectedChar)
+			typedAt := s.position
+			s.position += len(typed)
+			if autoIndent {
+				s.skipIndentation()
+			}
+			if s.position == len(s.text)-1 {
+				// Nothing follows the last tone number to move on to, so let the text finish without it
+				s.skipTone(char)
+			}
+			s.timeWord(typedAt)
+			s.recordKeystroke(char)
 			if char == " " && s.showContext {
 				next := s.getNextWord()
 				if next != "" {
-					speak(next)
+					s.speech.Say(next)
 				}
 			}
 		}
@@ -709,31 +1086,59 @@ func (s *Session) HandleInput(key tea.KeyMsg) tea.Cmd {
 
 	// Update duration for smooth stats updates
 	if s.running {
-		s.duration = time.Since(s.startTime)
+		s.duration = s.since(s.startTime)
 	}
 
 	// Check for completion conditions
+	if s.wordTargetReached() {
+		return s.completeSession()
+	}
 	if s.position >= len(s.text) {
-		if s.mode == "practice" && s.maxChunks > 0 {
-			return s.handlePracticeCompletion()
-		} else if s.mode == "custom" || s.mode == "quotes" {
-			return s.handleChunkCompletion()
+		if s.inFocusLoop() {
+			s.continueFocusLoop()
+			return nil
+		}
+		s.finishChunkReplay()
+		missed := s.missedWords()
+		summary := s.describeChunk(missed)
+
+		var cmd tea.Cmd
+		if s.provider != nil {
+			cmd = tea.Batch(s.handleProvidedCompletion(), s.startChunkSummary(summary))
+		} else if s.mode == "practice" && s.maxChunks > 0 {
+			cmd = tea.Batch(s.handlePracticeCompletion(), s.startChunkSummary(summary))
+		} else if s.mode == "custom" || s.mode == "quotes" || s.mode == "board" || s.mode == "lesson" || s.mode == "learn" || s.mode == "book" || s.mode == "wiki" || s.mode == "news" {
+			cmd = tea.Batch(s.handleChunkCompletion(), s.startChunkSummary(summary))
 		} else if s.mode == "timed" || s.mode == "words" || (s.mode == "practice" && s.maxChunks == 0) {
 			s.handleContinuousCompletion()
+		} else if s.wordTarget > 0 && s.IsCodeMode() {
+			s.handleSnippetCompletion()
 		} else {
-			return s.handleDefaultCompletion()
+			cmd = s.handleDefaultCompletion()
 		}
+		s.startFocusLoop(missed)
+		return cmd
 	}
 
 	return nil
 }
 
+// skipIndentation fills in the leading whitespace of the new line as if it had been typed
+func (s *Session) skipIndentation() {
+	for s.position < len(s.text) && (s.text[s.position] == ' ' || s.text[s.position] == '\t') {
+		s.userInput += string(s.text[s.position])
+		s.correctChars++
+		s.position++
+	}
+}
+
 // handleContinuousCompletion handles completion for modes that continue indefinitely
 func (s *Session) handleContinuousCompletion() {
-	s.totalChars += len(s.userInput)
-	s.totalMistakes += s.mistakes
+	s.foldChunk()
 
-	s.text = internal.GenerateWordsDynamic(DefaultWordCount, s.config.Language.Default)
+	s.text = s.nextText(func() string {
+		return internal.GenerateWordsDynamic(s.nextChunkWords(), s.config.Language.Default)
+	})
 	s.invalidateLineCache()
 	s.position = 0
 	s.userInput = ""
@@ -744,19 +1149,20 @@ func (s *Session) handleContinuousCompletion() {
 // handlePracticeCompletion handles completion for practice mode with chunk limits
 func (s *Session) handlePracticeCompletion() tea.Cmd {
 	if s.isGroupMode {
-		s.totalChars += len(s.userInput)
-		s.totalMistakes += s.mistakes
+		s.foldChunk()
 		s.totalChunks += s.currentPageChunks
 
 		if s.totalChunks >= s.maxChunks {
 			return s.completeSession()
 		} else {
 			s.currentPageChunks = min(s.pageSize, s.maxChunks-s.totalChunks)
-			var chunks []string
-			for i := 0; i < s.currentPageChunks; i++ {
-				chunks = append(chunks, internal.GenerateWordsDynamic(DefaultWordCount, s.config.Language.Default))
-			}
-			s.text = strings.Join(chunks, "\n\n")
+			s.text = s.nextText(func() string {
+				var chunks []string
+				for i := 0; i < s.currentPageChunks; i++ {
+					chunks = append(chunks, internal.GenerateWordsDynamic(s.wordsPerChunk(DefaultWordCount), s.config.Language.Default))
+				}
+				return strings.Join(chunks, "\n\n")
+			})
 			s.position = 0
 			s.userInput = ""
 			s.mistakes = 0
@@ -764,13 +1170,14 @@ func (s *Session) handlePracticeCompletion() tea.Cmd {
 		}
 	} else {
 		s.totalChunks++
-		s.totalChars += len(s.userInput)
-		s.totalMistakes += s.mistakes
+		s.foldChunk()
 
 		if s.totalChunks >= s.maxChunks {
 			return s.completeSession()
 		} else {
-			s.text = internal.GenerateWordsDynamic(DefaultWordCount, s.config.Language.Default)
+			s.text = s.nextText(func() string {
+				return internal.GenerateWordsDynamic(s.wordsPerChunk(DefaultWordCount), s.config.Language.Default)
+			})
 			s.position = 0
 			s.userInput = ""
 			s.mistakes = 0
@@ -783,13 +1190,14 @@ func (s *Session) handlePracticeCompletion() tea.Cmd {
 // handleChunkCompletion handles completion for custom and quotes modes
 func (s *Session) handleChunkCompletion() tea.Cmd {
 	s.chunkIndex++
-	s.totalChars += len(s.userInput)
-	s.totalMistakes += s.mistakes
+	s.foldChunk()
+	s.markBook()
 
 	if s.chunkIndex >= len(s.allChunks) {
 		return s.completeSession()
 	} else {
 		s.text = s.allChunks[s.chunkIndex]
+		s.stripComments()
 		s.invalidateLineCache()
 		s.position = 0
 		s.userInput = ""
@@ -801,45 +1209,56 @@ func (s *Session) handleChunkCompletion() tea.Cmd {
 
 // handleDefaultCompletion handles completion for all other modes
 func (s *Session) handleDefaultCompletion() tea.Cmd {
+	return s.completeSession()
+}
+
+// foldChunk moves the chunk in progress into the session totals; calling it twice is harmless, as
+// the second call finds nothing left to fold
+func (s *Session) foldChunk() {
+	// A drill is scored from the typed text, which the first call clears
+	if s.drill != nil && s.userInput != "" {
+		s.drillUnits, s.drillCorrect = s.scoreDrill()
+	}
 	s.totalChars += len(s.userInput)
 	s.totalMistakes += s.mistakes
-	return s.completeSession()
+	s.userInput = ""
+	s.mistakes = 0
 }
 
 func (s *Session) completeSession() tea.Cmd {
+	s.duration = s.since(s.startTime)
+	return s.finish()
+}
+
+// finish ends the session and records it; challenge records are saved per level by the game
+func (s *Session) finish() tea.Cmd {
+	s.writeProtocolResult()
+	s.writeStandardResult()
+	s.foldChunk()
+	s.sampleRemainder()
+	s.gradeLesson()
+	s.comparePercentile()
 	s.completed = true
 	s.running = false
-	s.duration = time.Since(s.startTime)
-	if s.mode != "challenge" {
-		s.saveRecord(s.totalMistakes)
+	if s.mode != "challenge" && s.mode != DemoMode && !s.unrecorded {
+		s.saveRecord()
+		s.runCompleteHook()
 	}
-	s.mistakes = 0
+	s.exportTiming()
 	return func() tea.Msg { return SessionCompleteMsg{} }
 }
 
 func (s *Session) UpdateTimer() tea.Cmd {
 	if s.running {
-		s.duration = time.Since(s.startTime)
-		if s.timeLimit > 0 && s.duration >= s.timeLimit {
-			s.completed = true
-			s.running = false
+		s.duration = s.since(s.startTime)
+		s.sampleSeconds()
+		if s.timeLimit > 0 && s.duration >= s.timeLimit && s.chunkSummary == "" {
 			s.duration = s.timeLimit
-
-			mistakes := s.mistakes
-			if s.mode == "challenge" {
-				mistakes = s.totalMistakes
-			}
-			if s.mode == "timed" || s.mode == "words" || s.mode == "practice" {
-				mistakes = s.totalMistakes + s.mistakes
-			}
-
-			s.saveRecord(mistakes)
-			s.mistakes = 0
-
-			return func() tea.Msg { return SessionCompleteMsg{} }
+			return s.finish()
+		}
+		if cmd := s.checkIdle(); cmd != nil {
+			return cmd
 		}
-
-
 
 		return s.tickTimer()
 	}
@@ -854,11 +1273,21 @@ func (s *Session) tickTimer() tea.Cmd {
 
 func (s *Session) View(width, height int) string {
 	status := s.renderStatus(width)
-	textArea := s.renderText(width, height)
 	tipOrContext := s.renderTip(width)
 	if s.showContext {
 		tipOrContext = s.renderContext(width)
 	}
+	if s.showCheatSheet {
+		tipOrContext = s.renderCheatSheet(width)
+	}
+	if s.showKeyboard && height >= KeyboardMinHeight {
+		tipOrContext = s.renderKeyboard(width)
+	}
+	// A multi-line cheat sheet or keyboard takes its extra lines from the text area
+	textArea := s.renderText(width, height-(lipgloss.Height(tipOrContext)-1))
+	if s.chunkSummary != "" {
+		textArea = s.renderChunkSummary(width, lipgloss.Height(textArea))
+	}
 	hint := s.renderHint(width)
 
 	var content string
@@ -884,7 +1313,9 @@ func (s *Session) View(width, height int) string {
 }
 
 func (s *Session) calculateProgress() float64 {
-	if s.isGroupMode {
+	if s.wordTarget > 0 {
+		return float64(s.wordsTyped()) / float64(s.wordTarget) * 100
+	} else if s.isGroupMode {
 		return float64(s.totalChunks)/float64(s.maxChunks)*100 + float64(s.position)/float64(len(s.text))*float64(s.currentPageChunks)/float64(s.maxChunks)*100
 	} else if s.maxChunks > 0 {
 		completedChunks := s.totalChunks
@@ -904,12 +1335,14 @@ func (s *Session) renderStatus(width int) string {
 	if s.tier != "" {
 		mode += " (" + s.tier + ")"
 	}
+	mode += s.focusLabel()
+	mode += s.bookLabel()
 	timer := "00:00"
 	if s.running {
 		if s.mode == "challenge" {
 			timer = fmt.Sprintf("%ds", s.RemainingTimeDisplay)
 		} else {
-			elapsed := time.Since(s.startTime)
+			elapsed := s.since(s.startTime)
 			timer = elapsed.Truncate(time.Second).String()
 		}
 	}
@@ -917,10 +1350,8 @@ func (s *Session) renderStatus(width int) string {
 	accuracy := s.CalculateAccuracy()
 
 	mistakes := s.mistakes
-	if s.mode == "practice" && s.maxChunks > 0 {
+	if (s.mode == "practice" && s.maxChunks > 0) || s.mode == "challenge" {
 		mistakes = s.totalMistakes + s.mistakes
-	} else if s.mode == "challenge" && s.ExternalMistakes > 0 {
-		mistakes = s.ExternalMistakes + s.mistakes
 	}
 
 	progress := s.calculateProgress()
@@ -929,6 +1360,12 @@ func (s *Session) renderStatus(width int) string {
 	if width >= 80 {
 
 		statusText = fmt.Sprintf("Mode: %s | Timer: %s | WPM: %.1f | Accuracy: %.1f%% | Mistakes: %d | Progress: %.1f%%", mode, timer, wpm, accuracy, mistakes, progress)
+		if ghostPos := s.ghostPosition(); ghostPos >= 0 {
+			statusText += fmt.Sprintf(" | Ghost: %+d", s.position-ghostPos)
+		}
+		if s.bot != nil {
+			statusText += fmt.Sprintf(" | Bot: %+d", s.botLead())
+		}
 	} else if width >= 60 {
 
 		statusText = fmt.Sprintf("%s | %s | %.1f WPM | %.1f%% | %d mistakes", mode, timer, wpm, accuracy, mistakes)
@@ -983,51 +1420,148 @@ func (s *Session) findCurrentWordBoundaries() (int, int) {
 	return start, end - 1
 }
 
-func (s *Session) renderTextContent() string {
+// renderTextContent renders the text wrapped to width, showing at most height lines. Prose is wrapped
+// at word boundaries over the whole text, so a line only changes when the cursor moves onto another one.
+func (s *Session) renderTextContent(width, height int) string {
 	// Check if this is code mode
-	isCodeMode := strings.Contains(s.mode, "code") || s.mode == "snippet"
+	isCodeMode := s.IsCodeMode()
 
 	if isCodeMode {
 		return s.renderCodeContent()
 	}
+	if s.drill != nil {
+		return s.renderDrillContent()
+	}
+	if s.listening {
+		return s.renderListeningContent(width)
+	}
 
-	// Optimized rendering: only render a window around current position for performance
-	windowSize := RenderWindowSize // characters before and after current position
-	start := max(0, s.position-windowSize)
-	end := min(len(s.text), s.position+windowSize)
+	// Only the lines around the cursor are rendered, keeping one line of what was typed in view, so
+	// however long the text, a frame costs no more than a screenful
+	starts := s.wrappedLines(width)
+	cursorLine := sort.SearchInts(starts, s.position+1) - 1
+	lines := max(min(height, RenderWindowLines), 1)
+	first := max(0, min(cursorLine-1, len(starts)-lines))
+	last := min(first+lines, len(starts))
+	if s.config.Display.RollingText {
+		// Always the previous, current and next lines, so the cursor stays on the middle row
+		// and the text scrolls up a line at a time; missing lines are left blank
+		first, last = cursorLine-1, cursorLine+2
+	}
 
-	// Original word-based rendering for non-code modes
 	wordStart, wordEnd := s.findCurrentWordBoundaries()
-
-	var rendered strings.Builder
-	for i := start; i < end; i++ {
-		char := rune(s.text[i])
-		style := lipgloss.NewStyle().Background(lipgloss.Color(s.config.Theme.Colors.Background))
-		if i < s.position {
-			if i < len(s.userInput) && rune(s.userInput[i]) == char {
-				style = style.Foreground(lipgloss.Color(s.config.Theme.Colors.Correct))
+	ghostPos := s.ghostPosition()
+	botPos := s.botPosition()
+	s.syncCells(false)
+
+	var rendered []string
+	for line := first; line < last; line++ {
+		if line < 0 || line >= len(starts) {
+			rendered = append(rendered, "")
+			continue
+		}
+		end := len(s.text)
+		if line+1 < len(starts) {
+			end = starts[line+1]
+		}
+		var b strings.Builder
+		for i := starts[line]; i < end; {
+			_, size := utf8.DecodeRuneInString(s.text[i:])
+			glyph := s.text[i : i+size]
+			char := glyph
+			if char == "\n" {
+				// The line break is typed like any character, so it needs a cell to show the cursor on
+				char = " "
+			}
+			b.WriteString(s.styledCell(i, char, s.proseCellState(i, glyph, wordStart, wordEnd, ghostPos, botPos)))
+			i += size
+		}
+		rendered = append(rendered, b.String())
+	}
+	if s.config.Display.RollingText {
+		// A fixed width keeps the text from shifting sideways as longer and shorter lines scroll in
+		align := lipgloss.Left
+		if s.rtl {
+			align = lipgloss.Right
+		}
+		return lipgloss.NewStyle().
+			Width(width).
+			Align(align).
+			Background(lipgloss.Color(s.config.Theme.Colors.Background)).
+			Render(strings.Join(rendered, "\n"))
+	}
+	return strings.Join(rendered, "\n")
+}
+
+// wrapText breaks the text into lines at most width cells wide and returns where each line starts.
+// Lines break after the last space that fits, keeping the space at the end of its line so the cursor
+// can sit on it, and mid-word only when a word is longer than a whole line, as runs of Chinese or
+// Japanese, which have no spaces, usually are. Wide characters take two cells.
+func wrapText(text string, width int) []int {
+	starts := []int{0}
+	if width < 1 {
+		return starts
+	}
+	lineStart, lastSpace, column := 0, -1, 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		cells := cellWidth(r)
+		if column+cells > width && i > lineStart {
+			if lastSpace >= lineStart {
+				lineStart = lastSpace + 1
 			} else {
-				style = style.Foreground(lipgloss.Color(s.config.Theme.Colors.Incorrect))
+				lineStart = i
 			}
-		} else if i == s.position {
-			style = style.Foreground(lipgloss.Color(s.config.Theme.Colors.WordHighlight)).Faint(true)
-			if s.config.Theme.Styles.UnderlineCurrent {
-				style = style.Underline(true)
+			starts = append(starts, lineStart)
+			column = 0
+			for _, c := range text[lineStart:i] {
+				column += cellWidth(c)
 			}
-		} else {
-			if i >= wordStart && i <= wordEnd {
-				style = style.Foreground(lipgloss.Color(s.config.Theme.Colors.WordHighlight))
-			} else {
-				style = style.Foreground(lipgloss.Color(s.config.Theme.Colors.Pending))
-				if s.config.Theme.Styles.DimPending {
-					style = style.Faint(true)
-				}
+		}
+		column += cells
+		switch r {
+		case ' ':
+			lastSpace = i
+		case '\n':
+			lineStart, lastSpace, column = i+1, -1, 0
+			if lineStart < len(text) {
+				starts = append(starts, lineStart)
 			}
 		}
-		rendered.WriteString(style.Render(string(char)))
+		i += size
+	}
+	return starts
+}
+
+// cellWidth is how many terminal cells a character of the text takes. ASCII, the line break and tab
+// included, is drawn one cell to a character.
+func cellWidth(r rune) int {
+	if r < utf8.RuneSelf {
+		return 1
+	}
+	return runewidth.RuneWidth(r)
+}
+
+// proseCellState is the state of the character at i of non-code text; the rest of the word being
+// typed is highlighted
+func (s *Session) proseCellState(i int, glyph string, wordStart, wordEnd, ghostPos, botPos int) cellState {
+	state := s.typedCellState(i, glyph, ghostPos, botPos)
+	if state.kind == cellPending {
+		if i >= wordStart && i <= wordEnd {
+			state.kind = cellWord
+		} else {
+			state.pending = s.config.Theme.Colors.Pending
+		}
 	}
+	return state
+}
 
-	return rendered.String()
+// ghostStyle marks the character where the best previous run was at this moment
+func (s *Session) ghostStyle(style lipgloss.Style) lipgloss.Style {
+	return style.
+		Foreground(lipgloss.Color(s.config.Theme.Colors.Background)).
+		Background(lipgloss.Color(s.config.Theme.Colors.Accent)).
+		Faint(false)
 }
 
 func (s *Session) renderCodeContent() string {
@@ -1060,9 +1594,13 @@ func (s *Session) renderCodeContent() string {
 	var renderedLines []string
 
 	// Track global character position
+	ghostPos := s.ghostPosition()
+	botPos := s.botPosition()
+	kinds := s.syntaxKinds()
+	s.syncCells(true)
 	globalPos := 0
-	for i := 0; i < startLine; i++ {
-		globalPos += len(lines[i]) + 1 // +1 for newline
+	if startLine < len(s.lineStarts) {
+		globalPos = s.lineStarts[startLine]
 	}
 
 	for lineIdx := startLine; lineIdx < endLine && lineIdx < len(lines); lineIdx++ {
@@ -1074,36 +1612,17 @@ func (s *Session) renderCodeContent() string {
 		if showLineNumbers {
 			lineNum := strconv.Itoa(lineIdx + lineNumOffset)
 			lineNumPadded := fmt.Sprintf("%*s", lineNumWidth, lineNum)
-			lineStr.WriteString(lipgloss.NewStyle().
-				Foreground(lipgloss.Color(s.config.Theme.Colors.TextSecondary)).
-				Render(lineNumPadded + " "))
+			lineStr.WriteString(s.lineNumberStyle.Render(lineNumPadded + " "))
 		}
 
 		// Apply character-level typing colors
 		for charIdx, char := range line {
-			currentGlobalPos := globalPos + charIdx
-
-			style := lipgloss.NewStyle().Background(lipgloss.Color(s.config.Theme.Colors.Background))
-
-			if currentGlobalPos < s.position {
-				if currentGlobalPos < len(s.userInput) && rune(s.userInput[currentGlobalPos]) == char {
-					style = style.Foreground(lipgloss.Color(s.config.Theme.Colors.Correct))
-				} else {
-					style = style.Foreground(lipgloss.Color(s.config.Theme.Colors.Incorrect))
-				}
-			} else if currentGlobalPos == s.position {
-				style = style.Foreground(lipgloss.Color(s.config.Theme.Colors.WordHighlight)).Faint(true)
-				if s.config.Theme.Styles.UnderlineCurrent {
-					style = style.Underline(true)
-				}
-			} else {
-				style = style.Foreground(lipgloss.Color(s.config.Theme.Colors.Pending))
-				if s.config.Theme.Styles.DimPending {
-					style = style.Faint(true)
-				}
-			}
+			lineStr.WriteString(s.styledCell(globalPos+charIdx, string(char), s.codeCellState(globalPos+charIdx, string(char), kinds, ghostPos, botPos)))
+		}
 
-			lineStr.WriteString(style.Render(string(char)))
+		// Show the line break itself so Enter has something to aim at
+		if lineIdx < len(lines)-1 {
+			lineStr.WriteString(s.styledCell(globalPos+len(line), NewlineMarker, s.codeCellState(globalPos+len(line), "\n", kinds, ghostPos, botPos)))
 		}
 
 		renderedLines = append(renderedLines, lineStr.String())
@@ -1113,6 +1632,27 @@ func (s *Session) renderCodeContent() string {
 	return strings.Join(renderedLines, "\n")
 }
 
+// incorrectStyle colours a mistyped character, and marks it by shape too when mark_errors asks for it
+func (s *Session) incorrectStyle(style lipgloss.Style) lipgloss.Style {
+	style = style.Foreground(lipgloss.Color(s.config.Theme.Colors.Incorrect))
+	switch s.config.Theme.Styles.MarkErrors {
+	case config.MarkErrorsUnderline:
+		return style.Underline(true)
+	case config.MarkErrorsStrikethrough:
+		return style.Strikethrough(true)
+	}
+	return style
+}
+
+// codeCellState is the state of one character of code; untyped code is tinted by its token kind
+func (s *Session) codeCellState(pos int, glyph string, kinds []syntax.Kind, ghostPos, botPos int) cellState {
+	state := s.typedCellState(pos, glyph, ghostPos, botPos)
+	if state.kind == cellPending {
+		state.pending = s.pendingColor(kinds, pos)
+	}
+	return state
+}
+
 // autoScrollToCurrentPosition automatically scrolls to keep the current typing position visible
 func (s *Session) autoScrollToCurrentPosition(lines []string) {
 	if len(lines) == 0 || s.visibleLines <= 0 {
@@ -1120,16 +1660,7 @@ func (s *Session) autoScrollToCurrentPosition(lines []string) {
 	}
 
 	// Find which line contains the current position
-	currentLine := 0
-	charCount := 0
-	for i, line := range lines {
-		lineLen := len(line) + 1 // +1 for newline
-		if charCount+lineLen > s.position {
-			currentLine = i
-			break
-		}
-		charCount += lineLen
-	}
+	currentLine := max(0, sort.SearchInts(s.lineStarts, s.position+1)-1)
 
 	// Calculate target scroll position to keep current line visible
 	var targetScroll int
@@ -1229,8 +1760,6 @@ func (s *Session) ScrollDownPage() {
 }
 
 func (s *Session) renderText(width, height int) string {
-	content := s.renderTextContent()
-
 	var textHeight int
 	if height >= 6 {
 		textHeight = height - 4
@@ -1247,7 +1776,7 @@ func (s *Session) renderText(width, height int) string {
 	}
 
 	// Update visible lines for scrolling (only for code mode)
-	isCodeMode := strings.Contains(s.mode, "code") || s.mode == "snippet"
+	isCodeMode := s.IsCodeMode()
 	if isCodeMode {
 		// Limit visible lines to 6 for better UX with long code files
 		maxVisibleLines := 6
@@ -1258,9 +1787,16 @@ func (s *Session) renderText(width, height int) string {
 		}
 	}
 
+	// Wrap to the widest box calculateDynamicWidth allows, less its padding, so lipgloss never has to
+	content := s.renderTextContent(min(80, width-4)-2, textHeight)
 	dynamicWidth := s.calculateDynamicWidth(content, width)
+	align := lipgloss.Left
+	if s.rtl {
+		align = lipgloss.Right
+	}
 	styledContent := lipgloss.NewStyle().
 		Width(dynamicWidth).
+		Align(align).
 		Background(lipgloss.Color(s.config.Theme.Colors.Background)).
 		Render(content)
 	return lipgloss.Place(width, textHeight, lipgloss.Center, lipgloss.Center, styledContent,
@@ -1280,24 +1816,42 @@ func (s *Session) renderTip(width int) string {
 	if s.ttsUnavailableMessage != "" {
 		return s.renderCenteredText(s.ttsUnavailableMessage, s.config.Theme.Colors.TextPrimary, width)
 	}
-
-	if len(Tips) == 0 {
-		return ""
+	if s.pasteNotice != "" {
+		return s.renderCenteredText(s.pasteNotice, s.config.Theme.Colors.Incorrect, width)
 	}
 
-	tipIndex := int(s.position) % len(Tips)
-	tip := "💡 " + Tips[tipIndex]
+	if s.listening {
+		tip := fmt.Sprintf("Sentence %d/%d: type what you hear, then press Enter", s.chunkIndex+1, len(s.allChunks))
+		return s.renderCenteredText(tip, s.config.Theme.Colors.Accent, width)
+	}
+	if s.chunkIndex < len(s.roundTips) {
+		tip := fmt.Sprintf("Round %d/%d: %s", s.chunkIndex+1, len(s.roundTips), s.roundTips[s.chunkIndex])
+		return s.renderCenteredText(tip, s.config.Theme.Colors.Accent, width)
+	}
 
-	return s.renderCenteredText(tip, s.config.Theme.Colors.Accent, width)
+	tip := s.currentAdvice()
+	if tip == "" {
+		tip = s.currentTip()
+	}
+	if tip == "" {
+		return ""
+	}
+	return s.renderCenteredText("💡 "+tip, s.config.Theme.Colors.Accent, width)
 }
 
 func (s *Session) renderHint(width int) string {
-	isCodeMode := strings.Contains(s.mode, "code") || s.mode == "snippet"
+	isCodeMode := s.IsCodeMode()
 	var hint string
-	if isCodeMode {
-		hint = "↑↓: Scroll | PgUp/PgDn: Page | Esc: Restart | Ctrl+H: Help | Ctrl+Q: Quit"
+	keys := s.keys
+	common := fmt.Sprintf("%s: Restart | %s: Help", keys.Label(keymap.ActionRestart), keys.Label(keymap.ActionHelp))
+	if s.listening {
+		hint = fmt.Sprintf("%s | %s: Hear again | %s: Pause", common, keys.Label(keymap.ActionTTS), keys.Label(keymap.ActionPause))
+	} else if isCodeMode {
+		hint = fmt.Sprintf("%s%s: Scroll | %s/%s: Page | %s | %s: Keys | %s: Pause",
+			keys.Label(keymap.ActionScrollUp), keys.Label(keymap.ActionScrollDown), keys.Label(keymap.ActionPageUp), keys.Label(keymap.ActionPageDown),
+			common, keys.Label(keymap.ActionCheatSheet), keys.Label(keymap.ActionPause))
 	} else {
-		hint = "Esc: Restart | Ctrl+H: Help | Ctrl+W: TTS | Ctrl+Q: Quit"
+		hint = fmt.Sprintf("%s | %s: TTS | %s: Keys | %s: Pause", common, keys.Label(keymap.ActionTTS), keys.Label(keymap.ActionCheatSheet), keys.Label(keymap.ActionPause))
 	}
 	return s.renderCenteredText(hint, s.config.Theme.Colors.TextSecondary, width)
 }
@@ -1308,14 +1862,15 @@ func (s *Session) renderContext(width int) string {
 
 func (s *Session) GetResults() string {
 	calculator := NewResultsCalculator()
-	results := calculator.CalculateResults(s, s.GetMode())
+	results := calculator.CalculateResults(s)
 
 	return fmt.Sprintf("Results\n\nWPM: %.1f\nCPM: %.1f\nAccuracy: %.1f%%\nDuration: %.2fs\nMistakes: %d\n\nPress Enter or Esc to exit", results.WPM, results.CPM, results.Accuracy, results.Duration.Seconds(), results.Mistakes)
 }
 
 func (s *Session) ViewTextOnly(width, height int) string {
-	content := s.renderTextContent()
 	textHeight := height - 2
+	// The padding takes two columns on each side of the width-4 box
+	content := s.renderTextContent(width-8, textHeight)
 
 	paddedContent := lipgloss.NewStyle().
 		PaddingLeft(2).
@@ -1349,10 +1904,7 @@ func (s *Session) CalculateAccuracy() float64 {
 	totalChars := s.totalChars + len(s.userInput)
 	totalMistakes := s.totalMistakes + s.mistakes
 
-	if totalChars == 0 {
-		return 100.0
-	}
-	return float64(totalChars-totalMistakes) / float64(totalChars) * 100
+	return CalculateAccuracy(totalChars, totalMistakes)
 }
 
 
@@ -1391,14 +1943,31 @@ func (s *Session) SetText(text string) {
 
 // getCachedLines returns cached lines, computing them if necessary
 func (s *Session) getCachedLines() []string {
-	currentHash := s.computeTextHash()
-	if s.textHash != currentHash || s.cachedLines == nil {
+	if s.linesText != s.text || s.cachedLines == nil {
 		s.cachedLines = strings.Split(s.text, "\n")
-		s.textHash = currentHash
+		s.textHash = s.computeTextHash()
+		s.linesText = s.text
+		s.lineStarts = make([]int, len(s.cachedLines))
+		pos := 0
+		for i, line := range s.cachedLines {
+			s.lineStarts[i] = pos
+			pos += len(line) + 1 // +1 for newline
+		}
 	}
 	return s.cachedLines
 }
 
+// wrappedLines returns where each line of the text starts once wrapped to width, wrapping the text
+// again only when it or the width has changed
+func (s *Session) wrappedLines(width int) []int {
+	if s.wrapStarts == nil || s.wrappedText != s.text || s.wrapWidth != width {
+		s.wrapStarts = wrapText(s.text, width)
+		s.wrappedText = s.text
+		s.wrapWidth = width
+	}
+	return s.wrapStarts
+}
+
 // computeTextHash computes a simple hash of the text for cache invalidation
 func (s *Session) computeTextHash() uint32 {
 	h := fnv.New32a()
@@ -1410,6 +1979,8 @@ func (s *Session) computeTextHash() uint32 {
 func (s *Session) invalidateLineCache() {
 	s.cachedLines = nil
 	s.textHash = 0
+	s.cachedKinds = nil
+	s.wrapStarts = nil
 }
 
 func (s *Session) GetMistakes() int {
@@ -1428,6 +1999,28 @@ func (s *Session) GetDuration() time.Duration {
 	return s.duration
 }
 
+// IsCodeMode reports whether the text is multi-line source that should be rendered and scrolled like code
+func (s *Session) IsCodeMode() bool {
+	return strings.Contains(s.mode, "code") || s.mode == "snippet" || s.mode == "document" || s.mode == "logs" || s.codeChunk
+}
+
+// IsCustomCode reports whether the session is typing code loaded from the user's own file
+func (s *Session) IsCustomCode() bool {
+	return s.IsCodeMode() && s.sourceFile != ""
+}
+
+func (s *Session) GetLanguage() string {
+	return s.language
+}
+
+func (s *Session) GetPosition() int {
+	return s.position
+}
+
+func (s *Session) GetTimeLimit() time.Duration {
+	return s.timeLimit
+}
+
 func (s *Session) GetMode() string {
 	return s.mode
 }
@@ -1444,6 +2037,7 @@ func (s *Session) ResetForNewText() {
 	s.position = 0
 	s.userInput = ""
 	s.mistakes = 0
+	s.keystrokes = nil
 	s.completed = false
 	s.layoutDirty = true
 }
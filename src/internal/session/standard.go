@@ -0,0 +1,72 @@
+package session
+
+import "time"
+
+// StandardRun is a finished run of the standard test, handed to whatever signs and saves it
+type StandardRun struct {
+	Started  time.Time
+	Finished time.Time
+	Text     string
+	Typed    string
+	Results  ProtocolMetrics
+}
+
+type StandardLog struct {
+	standardSave   func(StandardRun) (string, error)
+	standardResult string
+}
+
+// EnableStandardResult hands every run that ends to save, which returns where the signed result went.
+// The test's clock never stops, so pausing ends the run, unsigned.
+func (s *Session) EnableStandardResult(save func(StandardRun) (string, error)) {
+	s.standardSave = save
+	s.endOnPause = true
+}
+
+// writeStandardResult signs and saves the standard test result, remembering the outcome for the
+// results screen. Runs cut short or that had text pasted into them are not certified at all.
+func (s *Session) writeStandardResult() {
+	if s.standardSave == nil {
+		return
+	}
+	switch {
+	case s.partial:
+		s.standardResult = "The test was stopped early, so no result file was signed"
+		return
+	case s.pasteAttempts > 0:
+		s.standardResult = "Text was pasted during the test, so no result file was signed"
+		return
+	}
+
+	results := NewResultsCalculator().CalculateResults(s)
+	path, err := s.standardSave(StandardRun{
+		Started:  s.startTime,
+		Finished: s.startTime.Add(results.Duration),
+		Text:     s.text,
+		Typed:    s.userInput,
+		Results: ProtocolMetrics{
+			DurationMs:        results.Duration.Milliseconds(),
+			Chars:             results.TotalChars,
+			WPM:               results.WPM,
+			NetWPM:            results.NetWPM,
+			AdjustedWPM:       results.AdjustedWPM,
+			CPM:               results.CPM,
+			Accuracy:          results.Accuracy,
+			Mistakes:          results.Mistakes,
+			CorrectedErrors:   results.CorrectedErrors,
+			UncorrectedErrors: results.UncorrectedErrors,
+			Backspaces:        results.BackspaceCount,
+			KeyStats:          s.keyStats,
+		},
+	})
+	if err != nil {
+		s.standardResult = "Could not sign the result: " + err.Error()
+		return
+	}
+	s.standardResult = "Signed result saved to " + path
+}
+
+// StandardResultSummary says where the signed result went, or "" outside the standard test
+func (s *Session) StandardResultSummary() string {
+	return s.standardResult
+}
@@ -0,0 +1,95 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// ChunkSummaryDuration is how long the summary of a finished chunk shows before the next one begins
+	ChunkSummaryDuration = 2 * time.Second
+	// chunkSummaryWords caps the missed words named in a summary
+	chunkSummaryWords = 3
+)
+
+// ChunkSummaryDoneMsg ends the summary of chunk number chunk, unless a later one has replaced it
+type ChunkSummaryDoneMsg struct {
+	chunk int
+}
+
+type ChunkSummary struct {
+	// chunkSummary is the line shown between chunks, "" while typing
+	chunkSummary string
+	summaryStart time.Time
+	chunksDone   int
+}
+
+// describeChunk sums up the chunk just typed in one line, before its input is folded into the totals
+func (s *Session) describeChunk(missed []string) string {
+	elapsed := s.since(s.chunkStartTime)
+	if s.chunkStartTime.IsZero() || elapsed <= 0 {
+		elapsed = s.duration
+	}
+	line := fmt.Sprintf("chunk %d: %.0fwpm", s.chunksDone+1, CalculateWPM(len(s.userInput), elapsed))
+
+	switch s.mistakes {
+	case 0:
+		return line + ", no errors"
+	case 1:
+		line += ", 1 error"
+	default:
+		line += fmt.Sprintf(", %d errors", s.mistakes)
+	}
+	if len(missed) > chunkSummaryWords {
+		missed = missed[:chunkSummaryWords]
+	}
+	if len(missed) > 0 {
+		line += ": '" + strings.Join(missed, "', '") + "'"
+	}
+	return line
+}
+
+// startChunkSummary shows the summary in place of the text and holds input until ChunkSummaryDuration passes.
+// The clock keeps running meanwhile but the summary's time is given back when it ends.
+func (s *Session) startChunkSummary(summary string) tea.Cmd {
+	s.chunksDone++
+	if !s.config.Display.ChunkSummary || s.completed {
+		return nil
+	}
+	s.chunkSummary = summary
+	s.summaryStart = s.now()
+	s.layoutDirty = true
+
+	chunk := s.chunksDone
+	return tea.Tick(ChunkSummaryDuration, func(time.Time) tea.Msg {
+		return ChunkSummaryDoneMsg{chunk: chunk}
+	})
+}
+
+// EndChunkSummary lets the next chunk begin once its summary has been shown
+func (s *Session) EndChunkSummary(msg ChunkSummaryDoneMsg) {
+	if msg.chunk == s.chunksDone {
+		s.endChunkSummary()
+		s.sayListening()
+	}
+}
+
+func (s *Session) endChunkSummary() {
+	if s.chunkSummary == "" {
+		return
+	}
+	s.startTime = s.startTime.Add(s.since(s.summaryStart))
+	s.chunkSummary = ""
+	s.layoutDirty = true
+}
+
+// renderChunkSummary fills the text area with the summary line
+func (s *Session) renderChunkSummary(width, height int) string {
+	line := s.renderCenteredText(s.chunkSummary, s.config.Theme.Colors.TextSecondary, width)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, line,
+		lipgloss.WithWhitespaceBackground(lipgloss.Color(s.config.Theme.Colors.Background)))
+}
@@ -0,0 +1,88 @@
+package session
+
+import "time"
+
+// timeOfDayMinSessions is how many sessions an hour or weekday needs before it can be named the
+// fastest or most accurate, so one lucky session does not decide when to practise
+const timeOfDayMinSessions = 3
+
+// TimeBucket is how the sessions started in one hour of the day, or on one weekday, went
+type TimeBucket struct {
+	Label       string  `json:"label"`
+	Sessions    int     `json:"sessions"`
+	AvgWPM      float64 `json:"avg_wpm"`
+	AvgAccuracy float64 `json:"avg_accuracy"`
+}
+
+// TimeOfDay buckets sessions by the hour and the weekday they started, in the time zone they were
+// saved in, and names the best of each where enough sessions tell them apart
+type TimeOfDay struct {
+	// Hours has one bucket per hour, midnight first
+	Hours []TimeBucket `json:"hours"`
+	// Weekdays has one bucket per day, Monday first
+	Weekdays []TimeBucket `json:"weekdays"`
+
+	FastestHour         *TimeBucket `json:"fastest_hour,omitempty"`
+	MostAccurateHour    *TimeBucket `json:"most_accurate_hour,omitempty"`
+	FastestWeekday      *TimeBucket `json:"fastest_weekday,omitempty"`
+	MostAccurateWeekday *TimeBucket `json:"most_accurate_weekday,omitempty"`
+}
+
+// AnalyzeTimeOfDay works out when the sessions went fastest and most accurately. Pass only
+// sessions long enough to count, as the short ones would skew the averages.
+func AnalyzeTimeOfDay(records []*SessionRecord) TimeOfDay {
+	t := TimeOfDay{
+		Hours:    make([]TimeBucket, 24),
+		Weekdays: make([]TimeBucket, 7),
+	}
+	for hour := range t.Hours {
+		t.Hours[hour].Label = time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC).Format("15:04")
+	}
+	for i := range t.Weekdays {
+		t.Weekdays[i].Label = time.Weekday((i + 1) % 7).String()[:3]
+	}
+
+	for _, r := range records {
+		for _, b := range []*TimeBucket{&t.Hours[r.Timestamp.Hour()], &t.Weekdays[(int(r.Timestamp.Weekday())+6)%7]} {
+			b.Sessions++
+			b.AvgWPM += r.WPM
+			b.AvgAccuracy += r.Accuracy
+		}
+	}
+	for _, buckets := range [][]TimeBucket{t.Hours, t.Weekdays} {
+		for i := range buckets {
+			if n := float64(buckets[i].Sessions); n > 0 {
+				buckets[i].AvgWPM /= n
+				buckets[i].AvgAccuracy /= n
+			}
+		}
+	}
+
+	wpm := func(b TimeBucket) float64 { return b.AvgWPM }
+	accuracy := func(b TimeBucket) float64 { return b.AvgAccuracy }
+	t.FastestHour = bestBucket(t.Hours, wpm)
+	t.MostAccurateHour = bestBucket(t.Hours, accuracy)
+	t.FastestWeekday = bestBucket(t.Weekdays, wpm)
+	t.MostAccurateWeekday = bestBucket(t.Weekdays, accuracy)
+	return t
+}
+
+// bestBucket is the bucket scoring highest among those with enough sessions, or nil when fewer than
+// two have enough to compare
+func bestBucket(buckets []TimeBucket, score func(TimeBucket) float64) *TimeBucket {
+	var best *TimeBucket
+	compared := 0
+	for i := range buckets {
+		if buckets[i].Sessions < timeOfDayMinSessions {
+			continue
+		}
+		compared++
+		if best == nil || score(buckets[i]) > score(*best) {
+			best = &buckets[i]
+		}
+	}
+	if compared < 2 {
+		return nil
+	}
+	return best
+}
@@ -0,0 +1,186 @@
+package session
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gti/src/internal/config"
+	"gti/src/internal/vault"
+)
+
+// TimingEvent is one key press or sync marker in a keystroke timing export
+type TimingEvent struct {
+	OffsetUs   int64  `json:"t_us"`
+	Event      string `json:"event"`
+	Key        string `json:"key,omitempty"`
+	Expected   string `json:"expected,omitempty"`
+	Correct    bool   `json:"correct"`
+	IntervalUs int64  `json:"interval_us"`
+}
+
+// TimingExport is the JSON form of a keystroke timing export, for keyboard review tooling
+type TimingExport struct {
+	Mode    string        `json:"mode"`
+	Started time.Time     `json:"started"`
+	WPM     float64       `json:"wpm"`
+	Summary TimingSummary `json:"summary"`
+	Events  []TimingEvent `json:"events"`
+}
+
+// TimingSummary describes how evenly keys were struck; a low deviation means consistent actuation
+type TimingSummary struct {
+	Keystrokes       int     `json:"keystrokes"`
+	MeanIntervalUs   float64 `json:"mean_interval_us"`
+	StdDevIntervalUs float64 `json:"stddev_interval_us"`
+}
+
+type TimingLog struct {
+	timingPath    string
+	timingMarkers bool
+	timingEvents  []TimingEvent
+	timingResult  string
+}
+
+// EnableTimingExport records every key press with microsecond offsets and writes them to path when
+// the session ends (JSON for .json files, CSV otherwise). With markers, the terminal bell rings at the
+// start and end and both are logged, so an audio recording of the keyboard can be lined up with the log.
+func (s *Session) EnableTimingExport(path string, markers bool) {
+	s.timingPath = config.ExpandPath(path)
+	s.timingMarkers = markers
+}
+
+func (s *Session) timingOffset() int64 {
+	return s.since(s.startTime).Microseconds()
+}
+
+// recordTiming logs a key press against the character that was expected at that point
+func (s *Session) recordTiming(key, expected string) {
+	if s.timingPath == "" {
+		return
+	}
+	event := TimingEvent{
+		OffsetUs: s.timingOffset(),
+		Event:    "key",
+		Key:      key,
+		Expected: expected,
+		Correct:  key == expected,
+	}
+	for i := len(s.timingEvents) - 1; i >= 0; i-- {
+		if s.timingEvents[i].Event == "key" {
+			event.IntervalUs = event.OffsetUs - s.timingEvents[i].OffsetUs
+			break
+		}
+	}
+	s.timingEvents = append(s.timingEvents, event)
+}
+
+// timingMarker rings the bell and logs a sync point for aligning audio recordings
+func (s *Session) timingMarker(name string) {
+	if s.timingPath == "" || !s.timingMarkers {
+		return
+	}
+	fmt.Fprint(os.Stderr, "\a")
+	s.timingEvents = append(s.timingEvents, TimingEvent{OffsetUs: s.timingOffset(), Event: "marker", Key: name})
+}
+
+func (s *Session) timingSummary() TimingSummary {
+	var intervals []float64
+	summary := TimingSummary{}
+	for _, event := range s.timingEvents {
+		if event.Event != "key" {
+			continue
+		}
+		summary.Keystrokes++
+		if event.IntervalUs > 0 {
+			intervals = append(intervals, float64(event.IntervalUs))
+		}
+	}
+	if len(intervals) == 0 {
+		return summary
+	}
+
+	var sum float64
+	for _, interval := range intervals {
+		sum += interval
+	}
+	summary.MeanIntervalUs = sum / float64(len(intervals))
+
+	var variance float64
+	for _, interval := range intervals {
+		variance += (interval - summary.MeanIntervalUs) * (interval - summary.MeanIntervalUs)
+	}
+	summary.StdDevIntervalUs = math.Sqrt(variance / float64(len(intervals)))
+	return summary
+}
+
+// exportTiming writes the recorded events, remembering the outcome for the results screen
+func (s *Session) exportTiming() {
+	if s.timingPath == "" {
+		return
+	}
+	s.timingMarker("end")
+
+	if err := config.EnsureDir(filepath.Dir(s.timingPath)); err != nil {
+		s.timingResult = "Could not export keystroke timing: " + err.Error()
+		return
+	}
+
+	var data []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(s.timingPath), ".json") {
+		data, err = json.MarshalIndent(TimingExport{
+			Mode:    s.mode,
+			Started: s.startTime,
+			WPM:     s.CalculateWPM(),
+			Summary: s.timingSummary(),
+			Events:  s.timingEvents,
+		}, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = s.timingCSV()
+	}
+	path := s.timingPath
+	if err == nil {
+		if s.config.History.Encrypt {
+			// Key timings are practice data too, so an encrypted history keeps them sealed
+			path += ".sealed"
+		}
+		err = vault.WriteFile(s.config, path, data)
+	}
+	if err != nil {
+		s.timingResult = "Could not export keystroke timing: " + err.Error()
+		return
+	}
+	s.timingResult = "Keystroke timing saved to " + path
+}
+
+func (s *Session) timingCSV() ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	w.Write([]string{"t_us", "event", "key", "expected", "correct", "interval_us"})
+	for _, event := range s.timingEvents {
+		w.Write([]string{
+			strconv.FormatInt(event.OffsetUs, 10),
+			event.Event,
+			event.Key,
+			event.Expected,
+			strconv.FormatBool(event.Correct),
+			strconv.FormatInt(event.IntervalUs, 10),
+		})
+	}
+	w.Flush()
+	return b.Bytes(), w.Error()
+}
+
+// TimingExportSummary says where the keystroke timing went, or "" when none was recorded
+func (s *Session) TimingExportSummary() string {
+	return s.timingResult
+}
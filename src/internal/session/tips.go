@@ -0,0 +1,99 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gti/src/internal"
+	"gti/src/internal/config"
+)
+
+var Tips = []string{
+	"Keep your fingers on the home row (ASDF for left, JKL; for right)",
+	"Look at the screen, not your keyboard while typing",
+	"Use all your fingers, not just your index fingers",
+	"Practice regularly to build muscle memory",
+	"Focus on accuracy first, speed will come naturally",
+	"Take breaks to avoid fatigue and maintain focus",
+	"Breathe steadily and stay relaxed while typing",
+	"Maintain proper posture with feet flat on the floor",
+	"Keep your wrists straight and at a comfortable height",
+	"Practice difficult letter combinations separately",
+	"Use the correct finger for each key to avoid bad habits",
+	"Start slow and gradually increase your typing speed",
+	"Minimize mistakes by focusing on the next character",
+	"Rest your hands when not typing to prevent strain",
+	"Practice typing common words and phrases regularly",
+}
+
+// DiacriticsTip is mixed into the tips for languages whose words carry accented letters
+const DiacriticsTip = "This language uses accented letters: enable dead keys or a compose key for your layout, or set accents = \"loose\" under [keyboard] to type the bare letters"
+
+// TipsPath is the file tips.file names, or tips.txt beside config.toml when it names none
+func TipsPath(cfg *config.Config) string {
+	if cfg.Tips.File == "" {
+		return filepath.Join(config.ConfigDir, "tips.txt")
+	}
+	return config.ExpandPath(cfg.Tips.File)
+}
+
+// loadTips gathers the tips a session shows while typing language: the accented letters tip first
+// where it applies, then gti's own unless tips.builtin is off, then those of the tips file. A tips
+// file that cannot be read adds none.
+func (s *Session) loadTips(language string) {
+	s.tips = nil
+	if !s.config.Tips.Enabled {
+		return
+	}
+	if s.diacritics {
+		s.tips = append(s.tips, DiacriticsTip)
+	}
+	if s.config.Tips.Builtin {
+		s.tips = append(s.tips, Tips...)
+	}
+	if data, err := os.ReadFile(TipsPath(s.config)); err == nil {
+		s.tips = append(s.tips, ParseTips(data, language)...)
+	}
+	// The accented letters tip leads; otherwise each session starts somewhere new
+	if !s.diacritics && len(s.tips) > 0 {
+		s.tipOffset = internal.Intn(len(s.tips))
+	}
+}
+
+// ParseTips reads a tips file: one tip per line, with blank lines and lines starting with # left
+// out. Tips after a [language] heading only apply to that language, any case, until the next
+// heading; [all] goes back to tips for every language.
+func ParseTips(data []byte, language string) []string {
+	var tips []string
+	applies := true
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			heading := strings.TrimSpace(line[1 : len(line)-1])
+			applies = strings.EqualFold(heading, "all") || strings.EqualFold(heading, language)
+		case applies:
+			tips = append(tips, line)
+		}
+	}
+	return tips
+}
+
+// currentTip is the tip showing now: it changes every tips.rotate_seconds of typing, and not at all
+// when that is 0
+func (s *Session) currentTip() string {
+	if len(s.tips) == 0 {
+		return ""
+	}
+	index := s.tipOffset
+	if rotate := time.Duration(s.config.Tips.RotateSeconds) * time.Second; rotate > 0 {
+		index += int(s.duration / rotate)
+	}
+	return s.tips[index%len(s.tips)]
+}
@@ -0,0 +1,165 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// wordSpeedShown is how many of the slowest and of the fastest words the results screen lists
+const wordSpeedShown = 3
+
+// WordSpeed is how fast one word was typed, over every time it came up
+type WordSpeed struct {
+	Word  string
+	WPM   float64
+	Times int
+}
+
+// wordTime is one typing of a word, from the key press that ended the word before it to the one
+// that ended it, so the space after a word counts towards it as it does for the whole run
+type wordTime struct {
+	word    string
+	start   int
+	from    time.Time
+	elapsed time.Duration
+	chars   int
+}
+
+type WordTiming struct {
+	wordTimes []wordTime
+	// wordText is the text the word boundaries refer to; a new chunk, even one repeating the text
+	// before, starts the words over
+	wordText string
+	wordFrom int
+	wordMark time.Time
+	// wordLead is set while the mark is the word's own first key, whose typing time is unknown
+	wordLead bool
+}
+
+// timeWord is called after each key typed into the text, having typed the character at typedAt, and
+// closes the word in progress when that character ends it
+func (s *Session) timeWord(typedAt int) {
+	now := s.now()
+	if s.wordText != s.text || typedAt < s.wordFrom {
+		s.wordText = s.text
+		s.wordFrom = 0
+		s.wordMark = time.Time{}
+	}
+	if s.wordMark.IsZero() {
+		// The first word of a chunk is timed from its first key, there being no word before it to end
+		s.wordMark = now
+		s.wordLead = true
+	}
+	if typedAt >= len(s.text) || (!unicode.IsSpace(rune(s.text[typedAt])) && s.position < len(s.text)) {
+		return
+	}
+
+	end := min(s.position, len(s.text))
+	if word := strings.TrimSpace(s.text[s.wordFrom:end]); word != "" {
+		chars := len(word)
+		if unicode.IsSpace(rune(s.text[typedAt])) {
+			chars++
+		}
+		if s.wordLead {
+			chars--
+		}
+		s.wordTimes = append(s.wordTimes, wordTime{
+			word:    word,
+			start:   s.wordFrom,
+			from:    s.wordMark,
+			elapsed: now.Sub(s.wordMark),
+			chars:   chars,
+		})
+	}
+	s.wordFrom = end
+	s.wordMark = now
+	s.wordLead = false
+}
+
+// untimeWord reopens the words that Backspace went back into
+func (s *Session) untimeWord() {
+	if s.wordText != s.text {
+		return
+	}
+	for s.position < s.wordFrom {
+		n := len(s.wordTimes)
+		if n == 0 || s.wordTimes[n-1].start > s.position {
+			s.wordFrom = s.position
+			return
+		}
+		last := s.wordTimes[n-1]
+		s.wordTimes = s.wordTimes[:n-1]
+		s.wordFrom = last.start
+		s.wordMark = last.from
+		s.wordLead = last.start == 0
+	}
+}
+
+// shiftWordMark moves the word in progress's start past a pause, so the time paused does not count
+func (s *Session) shiftWordMark(pausedFor time.Duration) {
+	if !s.wordMark.IsZero() {
+		s.wordMark = s.wordMark.Add(pausedFor)
+	}
+}
+
+// WordSpeeds returns the speed of every word typed in the run, each word's typings taken together,
+// slowest first
+func (s *Session) WordSpeeds() []WordSpeed {
+	type total struct {
+		elapsed time.Duration
+		chars   int
+		times   int
+	}
+	totals := make(map[string]*total)
+	var order []string
+	for _, w := range s.wordTimes {
+		t, ok := totals[w.word]
+		if !ok {
+			t = &total{}
+			totals[w.word] = t
+			order = append(order, w.word)
+		}
+		t.elapsed += w.elapsed
+		t.chars += w.chars
+		t.times++
+	}
+
+	speeds := make([]WordSpeed, 0, len(order))
+	for _, word := range order {
+		t := totals[word]
+		if t.elapsed <= 0 {
+			continue
+		}
+		speeds = append(speeds, WordSpeed{Word: word, WPM: CalculateWPM(t.chars, t.elapsed), Times: t.times})
+	}
+	sort.SliceStable(speeds, func(i, j int) bool {
+		return speeds[i].WPM < speeds[j].WPM
+	})
+	return speeds
+}
+
+// WordSpeedSummary lists the slowest and fastest words of the run for the results screen, once there
+// are enough different words for the two lists not to overlap
+func (s *Session) WordSpeedSummary() string {
+	speeds := s.WordSpeeds()
+	shown := min(wordSpeedShown, len(speeds)/2)
+	if shown == 0 {
+		return ""
+	}
+
+	list := func(speeds []WordSpeed) string {
+		parts := make([]string, len(speeds))
+		for i, w := range speeds {
+			parts[i] = fmt.Sprintf("%s %.0f", w.Word, w.WPM)
+		}
+		return strings.Join(parts, ", ")
+	}
+	fastest := make([]WordSpeed, shown)
+	for i := range fastest {
+		fastest[i] = speeds[len(speeds)-1-i]
+	}
+	return fmt.Sprintf("Slowest words (wpm): %s\nFastest words (wpm): %s", list(speeds[:shown]), list(fastest))
+}
@@ -0,0 +1,47 @@
+package session
+
+import "gti/src/internal"
+
+// WithWordTarget ends the session once this many words have been typed, however long it takes
+func WithWordTarget(words int) SessionOption {
+	return func(c *SessionConfig) {
+		c.WordTarget = words
+	}
+}
+
+// wordsTyped is how many words of the run have been finished so far
+func (s *Session) wordsTyped() int {
+	return len(s.wordTimes)
+}
+
+// wordTargetReached reports whether a run with a word target has typed all its words
+func (s *Session) wordTargetReached() bool {
+	return s.wordTarget > 0 && s.wordsTyped() >= s.wordTarget
+}
+
+// nextChunkWords is how many words the next chunk of generated text holds: the chunk size, cut short
+// so a word target ends on the last letter of a chunk rather than on the space after its last word
+func (s *Session) nextChunkWords() int {
+	n := s.wordsPerChunk(DefaultWordCount)
+	if s.wordTarget > 0 {
+		n = max(1, min(n, s.wordTarget-s.wordsTyped()))
+	}
+	return n
+}
+
+// handleSnippetCompletion moves on to a fresh snippet when code practice with a word target runs out
+// of code before the words are typed
+func (s *Session) handleSnippetCompletion() {
+	s.foldChunk()
+
+	s.text = s.nextText(func() string {
+		return internal.GenerateCodeSnippet(s.setup.Language, s.setup.Difficulty)
+	})
+	s.stripComments()
+	s.invalidateLineCache()
+	s.position = 0
+	s.userInput = ""
+	s.mistakes = 0
+	s.scrollOffset = 0
+	s.layoutDirty = true
+}
@@ -0,0 +1,391 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gti/src/internal/config"
+)
+
+// PersonalSnippet is one snippet in the user's library, with the tags recorded for it in the index
+type PersonalSnippet struct {
+	Language string
+	Name     string
+	Code     string
+	Tags     []string
+	Added    time.Time
+}
+
+// snippetMeta is what the index remembers about a personal snippet beyond its code
+type snippetMeta struct {
+	Tags  []string  `json:"tags,omitempty"`
+	Added time.Time `json:"added"`
+}
+
+// packEntry is one "# header" section of a personal pack file
+type packEntry struct {
+	header string
+	code   string
+}
+
+// PersonalSnippetDir is where user-saved snippet packs live, one file per language
+func PersonalSnippetDir() string {
+	return filepath.Join(config.DataDir, "snippets")
+}
+
+func personalSnippetPath(language string) string {
+	return filepath.Join(PersonalSnippetDir(), language+".snippets")
+}
+
+func snippetIndexPath() string {
+	return filepath.Join(PersonalSnippetDir(), "index.json")
+}
+
+func snippetKey(language, name string) string {
+	return language + "/" + name
+}
+
+// loadPersonalSnippets reads the user's pack for a language; a missing pack is simply empty
+func loadPersonalSnippets(language string) []codeSnippet {
+	entries, err := readPersonalPack(language)
+	if err != nil {
+		return nil
+	}
+	var snippets []codeSnippet
+	for _, entry := range entries {
+		tier := headerDifficulty(entry.header)
+		if tier == "" {
+			tier = classifyDifficulty(entry.code)
+		}
+		snippets = append(snippets, codeSnippet{entry.code, tier})
+	}
+	return snippets
+}
+
+// SavePersonalSnippet appends a named snippet to the user's pack for the language,
+// so it is drilled alongside the built-in snippets from then on.
+func SavePersonalSnippet(language, name, code string) error {
+	return AddPersonalSnippet(language, name, code, nil)
+}
+
+// AddPersonalSnippet saves a named snippet with tags; an "easy" or "hard" tag also sets the tier --difficulty uses
+func AddPersonalSnippet(language, name, code string, tags []string) error {
+	if err := ValidateCodeLanguage(language); err != nil {
+		return err
+	}
+	name = cleanSnippetName(name)
+	if name == "" {
+		return fmt.Errorf("snippet name cannot be empty")
+	}
+	if strings.TrimSpace(code) == "" {
+		return fmt.Errorf("snippet is empty")
+	}
+
+	entries, err := readPersonalPack(language)
+	if err != nil {
+		return err
+	}
+	if findPackEntry(entries, name) >= 0 {
+		return fmt.Errorf("a %s snippet named '%s' already exists", language, name)
+	}
+
+	tags = normalizeTags(tags)
+	entries = append(entries, packEntry{header: snippetHeader(name, tags), code: strings.TrimRight(code, "\n")})
+	if err := writePersonalPack(language, entries); err != nil {
+		return err
+	}
+
+	index := loadSnippetIndex()
+	index[snippetKey(language, name)] = &snippetMeta{Tags: tags, Added: time.Now()}
+	return saveSnippetIndex(index)
+}
+
+// ListPersonalSnippets returns the user's snippets for a language, or for every language when it is "",
+// keeping only those carrying the tag when one is given
+func ListPersonalSnippets(language, tag string) ([]PersonalSnippet, error) {
+	languages := []string{language}
+	if language == "" {
+		languages = nil
+		files, err := filepath.Glob(filepath.Join(PersonalSnippetDir(), "*.snippets"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			languages = append(languages, strings.TrimSuffix(filepath.Base(file), ".snippets"))
+		}
+		sort.Strings(languages)
+	}
+
+	index := loadSnippetIndex()
+	var snippets []PersonalSnippet
+	for _, lang := range languages {
+		entries, err := readPersonalPack(lang)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			snippet := personalSnippet(lang, entry, index)
+			if tag == "" || hasTag(snippet.Tags, strings.ToLower(tag)) {
+				snippets = append(snippets, snippet)
+			}
+		}
+	}
+	return snippets, nil
+}
+
+// GetPersonalSnippet looks up one of the user's snippets by language and name
+func GetPersonalSnippet(language, name string) (PersonalSnippet, error) {
+	entries, err := readPersonalPack(language)
+	if err != nil {
+		return PersonalSnippet{}, err
+	}
+	i := findPackEntry(entries, cleanSnippetName(name))
+	if i < 0 {
+		return PersonalSnippet{}, fmt.Errorf("no %s snippet named '%s'", language, name)
+	}
+	return personalSnippet(language, entries[i], loadSnippetIndex()), nil
+}
+
+// RemovePersonalSnippet deletes a snippet from the user's pack and the index
+func RemovePersonalSnippet(language, name string) error {
+	name = cleanSnippetName(name)
+	entries, err := readPersonalPack(language)
+	if err != nil {
+		return err
+	}
+	i := findPackEntry(entries, name)
+	if i < 0 {
+		return fmt.Errorf("no %s snippet named '%s'", language, name)
+	}
+	if err := writePersonalPack(language, append(entries[:i], entries[i+1:]...)); err != nil {
+		return err
+	}
+
+	index := loadSnippetIndex()
+	delete(index, snippetKey(language, name))
+	return saveSnippetIndex(index)
+}
+
+// TagPersonalSnippet adds tags to a snippet, or takes them away when remove is set
+func TagPersonalSnippet(language, name string, tags []string, remove bool) error {
+	name = cleanSnippetName(name)
+	entries, err := readPersonalPack(language)
+	if err != nil {
+		return err
+	}
+	i := findPackEntry(entries, name)
+	if i < 0 {
+		return fmt.Errorf("no %s snippet named '%s'", language, name)
+	}
+
+	index := loadSnippetIndex()
+	key := snippetKey(language, name)
+	meta := index[key]
+	if meta == nil {
+		// Snippets saved before the index existed have no entry yet
+		meta = &snippetMeta{Tags: personalSnippet(language, entries[i], index).Tags}
+		index[key] = meta
+	}
+
+	tags = normalizeTags(tags)
+	if remove {
+		var kept []string
+		for _, tag := range meta.Tags {
+			if !hasTag(tags, tag) {
+				kept = append(kept, tag)
+			}
+		}
+		meta.Tags = kept
+	} else {
+		meta.Tags = normalizeTags(append(meta.Tags, tags...))
+	}
+
+	entries[i].header = snippetHeader(name, meta.Tags)
+	if err := writePersonalPack(language, entries); err != nil {
+		return err
+	}
+	return saveSnippetIndex(index)
+}
+
+func personalSnippet(language string, entry packEntry, index map[string]*snippetMeta) PersonalSnippet {
+	name := snippetEntryName(entry.header)
+	snippet := PersonalSnippet{Language: language, Name: name, Code: entry.code}
+	if meta := index[snippetKey(language, name)]; meta != nil {
+		snippet.Tags = append(snippet.Tags, meta.Tags...)
+		snippet.Added = meta.Added
+	}
+	// A tier written into the pack by hand counts as a tag too
+	if tier := headerDifficulty(entry.header); tier != "" && !hasTag(snippet.Tags, tier) {
+		snippet.Tags = normalizeTags(append(snippet.Tags, tier))
+	}
+	return snippet
+}
+
+// packCodePrefix starts every code line of a personal pack, so code lines that begin with # or are
+// blank, common in Python, shell and C, are never mistaken for headers or separators
+const packCodePrefix = "|"
+
+// readPersonalPack reads the sections of a language's pack file
+func readPersonalPack(language string) ([]packEntry, error) {
+	if err := ValidateCodeLanguage(language); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(personalSnippetPath(language))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parsePack(string(data)), nil
+}
+
+// parsePack splits a pack into "# header" sections and the code lines written under each. Packs
+// saved before code lines were prefixed are split the way parseSnippets splits the built-in ones.
+func parsePack(data string) []packEntry {
+	lines := strings.Split(data, "\n")
+	prefixed := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, packCodePrefix) {
+			prefixed = true
+			break
+		}
+	}
+
+	var entries []packEntry
+	var code []string
+	header := ""
+	flush := func() {
+		if len(code) > 0 {
+			entries = append(entries, packEntry{header: header, code: strings.Join(code, "\n")})
+		}
+		code = nil
+	}
+	for _, line := range lines {
+		switch {
+		case prefixed && strings.HasPrefix(line, packCodePrefix):
+			line = strings.TrimPrefix(line, packCodePrefix)
+			code = append(code, strings.TrimPrefix(line, " "))
+		case strings.HasPrefix(strings.TrimSpace(line), "#"):
+			flush()
+			header = strings.TrimSpace(line)
+		case !prefixed && strings.TrimSpace(line) != "":
+			code = append(code, line)
+		}
+	}
+	flush()
+	return entries
+}
+
+// formatPack writes the sections of a pack, each code line behind packCodePrefix
+func formatPack(entries []packEntry) string {
+	var b strings.Builder
+	for _, entry := range entries {
+		b.WriteString(entry.header + "\n")
+		for _, line := range strings.Split(entry.code, "\n") {
+			if line == "" {
+				b.WriteString(packCodePrefix + "\n")
+			} else {
+				b.WriteString(packCodePrefix + " " + line + "\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func writePersonalPack(language string, entries []packEntry) error {
+	if err := config.EnsureDir(PersonalSnippetDir()); err != nil {
+		return err
+	}
+	if err := os.WriteFile(personalSnippetPath(language), []byte(formatPack(entries)), 0644); err != nil {
+		return err
+	}
+
+	loadMutex.Lock()
+	delete(loadedCodeSnippets, language)
+	loadMutex.Unlock()
+	return nil
+}
+
+func findPackEntry(entries []packEntry, name string) int {
+	for i, entry := range entries {
+		if snippetEntryName(entry.header) == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// snippetEntryName is a pack header without its leading # and difficulty tag
+func snippetEntryName(header string) string {
+	name := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(header), "#"))
+	if tier := headerDifficulty(name); tier != "" {
+		name = strings.TrimSpace(strings.TrimSuffix(name, "["+tier+"]"))
+	}
+	return name
+}
+
+// snippetHeader writes the pack header for a snippet, carrying its difficulty tag if it has one
+func snippetHeader(name string, tags []string) string {
+	for _, tier := range []string{DifficultyEasy, DifficultyHard} {
+		if hasTag(tags, tier) {
+			return fmt.Sprintf("# %s [%s]", name, tier)
+		}
+	}
+	return "# " + name
+}
+
+func cleanSnippetName(name string) string {
+	return strings.TrimSpace(strings.ReplaceAll(name, "\n", " "))
+}
+
+// normalizeTags lowercases, dedupes and sorts tags; a snippet is never both easy and hard, so the last one wins
+func normalizeTags(tags []string) []string {
+	var out []string
+	tier := ""
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || hasTag(out, tag) {
+			continue
+		}
+		if tag == DifficultyEasy || tag == DifficultyHard {
+			tier = tag
+			continue
+		}
+		out = append(out, tag)
+	}
+	if tier != "" {
+		out = append(out, tier)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func loadSnippetIndex() map[string]*snippetMeta {
+	index := map[string]*snippetMeta{}
+	if err := config.LoadJSONData(snippetIndexPath(), &index); err != nil {
+		return map[string]*snippetMeta{}
+	}
+	return index
+}
+
+func saveSnippetIndex(index map[string]*snippetMeta) error {
+	if err := config.EnsureDir(PersonalSnippetDir()); err != nil {
+		return err
+	}
+	return config.SaveJSONData(snippetIndexPath(), index)
+}
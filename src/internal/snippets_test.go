@@ -0,0 +1,29 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPackRoundTrip(t *testing.T) {
+	entries := []packEntry{
+		{header: "# greet [easy]", code: "def greet(name):\n    # say hello\n\n    print(name)"},
+		{header: "# guard", code: "#ifndef GUARD_H\n#define GUARD_H\n\n  int x;\n#endif"},
+	}
+
+	got := parsePack(formatPack(entries))
+	if !reflect.DeepEqual(got, entries) {
+		t.Fatalf("round trip changed the pack:\ngot  %q\nwant %q", got, entries)
+	}
+}
+
+func TestParseLegacyPack(t *testing.T) {
+	got := parsePack("# first\nfmt.Println(1)\n\n# second [hard]\nx := 2\ny := 3\n")
+	want := []packEntry{
+		{header: "# first", code: "fmt.Println(1)"},
+		{header: "# second [hard]", code: "x := 2\ny := 3"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,166 @@
+// Package sound plays key press and mistake sounds through the system's audio player
+package sound
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"gti/src/internal/config"
+)
+
+// players is how many sounds may play at once. At most one more waits its turn; key presses arriving
+// beyond that are dropped, so the sound never lags behind the typing.
+const players = 3
+
+// Player plays the sounds of one pack. A nil Player is silent, so callers need not check whether sound is on.
+type Player struct {
+	keyFile   string
+	errorFile string
+	command   []string
+	queue     chan string
+}
+
+var (
+	shared     *Player
+	sharedCfg  config.SoundConfig
+	sharedLock sync.Mutex
+)
+
+// For returns the player for cfg, reusing the previous one while the settings are unchanged, or nil
+// when sound is off. The pack is written out to the cache on first use.
+func For(cfg config.SoundConfig) (*Player, error) {
+	if !cfg.Enabled || cfg.Volume == 0 {
+		return nil, nil
+	}
+	sharedLock.Lock()
+	defer sharedLock.Unlock()
+	if shared != nil && sharedCfg == cfg {
+		return shared, nil
+	}
+
+	command, err := playerCommand()
+	if err != nil {
+		return nil, err
+	}
+	keyFile, errorFile, err := preparePack(cfg.Pack, cfg.Volume)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Errors {
+		errorFile = keyFile
+	}
+
+	p := &Player{
+		keyFile:   keyFile,
+		errorFile: errorFile,
+		command:   command,
+		queue:     make(chan string, 1),
+	}
+	for i := 0; i < players; i++ {
+		go p.run()
+	}
+	shared, sharedCfg = p, cfg
+	return p, nil
+}
+
+// Key plays the key press sound
+func (p *Player) Key() {
+	if p != nil {
+		p.play(p.keyFile)
+	}
+}
+
+// Error plays the mistake sound
+func (p *Player) Error() {
+	if p != nil {
+		p.play(p.errorFile)
+	}
+}
+
+// play hands the sound to an idle worker without waiting, so the key press is never held up
+func (p *Player) play(file string) {
+	select {
+	case p.queue <- file:
+	default:
+	}
+}
+
+func (p *Player) run() {
+	for file := range p.queue {
+		args := append(append([]string{}, p.command[1:]...), file)
+		if runtime.GOOS == "windows" {
+			args = []string{"-c", fmt.Sprintf("(New-Object Media.SoundPlayer '%s').PlaySync()", file)}
+		}
+		exec.Command(p.command[0], args...).Run()
+	}
+}
+
+// playerCommand finds a command line audio player for this system
+func playerCommand() ([]string, error) {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		candidates = [][]string{{"paplay"}, {"pw-play"}, {"aplay", "-q"}}
+	case "darwin":
+		candidates = [][]string{{"afplay"}}
+	case "windows":
+		candidates = [][]string{{"powershell", "-c"}}
+	}
+	for _, c := range candidates {
+		if _, err := exec.LookPath(c[0]); err == nil {
+			return c, nil
+		}
+	}
+	if runtime.GOOS == "linux" {
+		return nil, fmt.Errorf("no audio player found, install pulseaudio-utils, pipewire or alsa-utils to hear typing sounds")
+	}
+	return nil, fmt.Errorf("typing sounds are not supported on %s", runtime.GOOS)
+}
+
+// preparePack writes the pack's sounds at the given volume to the cache and returns their paths.
+// Built-in packs are synthesized; any other pack is read from a folder in config.SoundsDir.
+// They are written afresh each time, so edits to a user's pack are picked up on the next run.
+func preparePack(pack string, volume int) (string, string, error) {
+	dir := filepath.Join(config.CacheDir, "sounds", fmt.Sprintf("%s-%d", pack, volume))
+	keyFile, errorFile := filepath.Join(dir, "key.wav"), filepath.Join(dir, "error.wav")
+
+	var key, mistake []byte
+	if builtin, ok := packs[pack]; ok {
+		key = encodeWAV(scaleSamples(builtin.key(), volume))
+		mistake = encodeWAV(scaleSamples(builtin.mistake(), volume))
+	} else {
+		var err error
+		if key, err = loadPackSound(pack, "key.wav", volume); err != nil {
+			return "", "", err
+		}
+		if mistake, err = loadPackSound(pack, "error.wav", volume); err != nil {
+			return "", "", err
+		}
+	}
+
+	if err := config.EnsureDir(dir); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(keyFile, key, 0644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(errorFile, mistake, 0644); err != nil {
+		return "", "", err
+	}
+	return keyFile, errorFile, nil
+}
+
+func loadPackSound(pack, name string, volume int) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(config.SoundsDir, pack, name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("unknown sound pack '%s': use click, typewriter or soft, or add %s", pack, filepath.Join(config.SoundsDir, pack, name))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return scaleWAV(data, volume), nil
+}
@@ -0,0 +1,155 @@
+package sound
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/rand"
+)
+
+// sampleRate is the rate of the built-in sounds, plenty for clicks and beeps
+const sampleRate = 22050
+
+// builtinPack synthesizes a pack's two sounds as samples from -1 to 1
+type builtinPack struct {
+	key     func() []float64
+	mistake func() []float64
+}
+
+var packs = map[string]builtinPack{
+	// click is a crisp, short switch click over a low thock
+	"click": {
+		key: func() []float64 {
+			return mix(noise(0.018, 0.004, 1), tone(2600, 0.012, 0.003, 0.4), tone(180, 0.03, 0.01, 0.5))
+		},
+		mistake: func() []float64 {
+			return mix(square(160, 0.09, 0.05, 0.35), noise(0.02, 0.005, 2))
+		},
+	},
+	// typewriter is a heavier strike, with a bell for mistakes
+	"typewriter": {
+		key: func() []float64 {
+			return mix(noise(0.035, 0.008, 3), tone(120, 0.05, 0.015, 0.7))
+		},
+		mistake: func() []float64 {
+			return mix(tone(1760, 0.35, 0.12, 0.5), tone(3520, 0.2, 0.05, 0.2))
+		},
+	},
+	// soft is a gentle, rounded tap
+	"soft": {
+		key: func() []float64 {
+			return tone(520, 0.035, 0.01, 0.6)
+		},
+		mistake: func() []float64 {
+			return mix(tone(260, 0.12, 0.05, 0.5), tone(247, 0.12, 0.05, 0.5))
+		},
+	},
+}
+
+// tone is a sine wave fading out with the given decay time
+func tone(freq, seconds, decay, gain float64) []float64 {
+	samples := make([]float64, int(seconds*sampleRate))
+	for i := range samples {
+		t := float64(i) / sampleRate
+		samples[i] = gain * math.Sin(2*math.Pi*freq*t) * math.Exp(-t/decay)
+	}
+	return samples
+}
+
+// square is a square wave fading out, harsher than a tone
+func square(freq, seconds, decay, gain float64) []float64 {
+	samples := tone(freq, seconds, decay, gain)
+	for i, v := range samples {
+		t := float64(i) / sampleRate
+		samples[i] = math.Copysign(gain*math.Exp(-t/decay), v)
+	}
+	return samples
+}
+
+// noise is a burst of white noise fading out; the seed keeps every run sounding the same
+func noise(seconds, decay float64, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	samples := make([]float64, int(seconds*sampleRate))
+	for i := range samples {
+		t := float64(i) / sampleRate
+		samples[i] = (rng.Float64()*2 - 1) * math.Exp(-t/decay)
+	}
+	return samples
+}
+
+// mix adds the sounds together, as long as the longest of them
+func mix(sounds ...[]float64) []float64 {
+	var mixed []float64
+	for _, s := range sounds {
+		for len(mixed) < len(s) {
+			mixed = append(mixed, 0)
+		}
+		for i, v := range s {
+			mixed[i] += v
+		}
+	}
+	return mixed
+}
+
+// scaleSamples applies the volume and turns the samples into 16-bit PCM
+func scaleSamples(samples []float64, volume int) []int16 {
+	pcm := make([]int16, len(samples))
+	gain := float64(volume) / 100
+	for i, v := range samples {
+		pcm[i] = int16(math.Max(-1, math.Min(1, v*gain)) * math.MaxInt16)
+	}
+	return pcm
+}
+
+// encodeWAV wraps mono 16-bit samples in a WAV file
+func encodeWAV(pcm []int16) []byte {
+	var b bytes.Buffer
+	dataSize := uint32(len(pcm) * 2)
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, 36+dataSize)
+	b.WriteString("WAVEfmt ")
+	// PCM format, mono, 16 bits per sample
+	for _, field := range []any{uint32(16), uint16(1), uint16(1), uint32(sampleRate), uint32(sampleRate * 2), uint16(2), uint16(16)} {
+		binary.Write(&b, binary.LittleEndian, field)
+	}
+	b.WriteString("data")
+	binary.Write(&b, binary.LittleEndian, dataSize)
+	binary.Write(&b, binary.LittleEndian, pcm)
+	return b.Bytes()
+}
+
+// scaleWAV applies the volume to a 16-bit PCM WAV file. Files in any other format are returned as they
+// are, to play at their own volume.
+func scaleWAV(data []byte, volume int) []byte {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return data
+	}
+	scaled := append([]byte{}, data...)
+	pcm16 := false
+	for offset := 12; offset+8 <= len(scaled); {
+		id := string(scaled[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(scaled[offset+4 : offset+8]))
+		body := offset + 8
+		end := min(body+size, len(scaled))
+		switch id {
+		case "fmt ":
+			if end-body >= 16 {
+				format := binary.LittleEndian.Uint16(scaled[body:])
+				bits := binary.LittleEndian.Uint16(scaled[body+14:])
+				pcm16 = format == 1 && bits == 16
+			}
+		case "data":
+			if !pcm16 {
+				return data
+			}
+			for i := body; i+1 < end; i += 2 {
+				v := int16(binary.LittleEndian.Uint16(scaled[i:]))
+				binary.LittleEndian.PutUint16(scaled[i:], uint16(int16(int(v)*volume/100)))
+			}
+			return scaled
+		}
+		// Chunks are padded to an even length
+		offset = body + size + size%2
+	}
+	return data
+}
@@ -0,0 +1,207 @@
+package standard
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+	"unicode/utf8"
+
+	"gti/src/internal/config"
+	"gti/src/internal/session"
+)
+
+// Result is what a signed result file vouches for
+type Result struct {
+	Format     string                  `json:"format"`
+	GTIVersion string                  `json:"gti_version"`
+	Seconds    int                     `json:"seconds"`
+	Started    time.Time               `json:"started"`
+	Finished   time.Time               `json:"finished"`
+	TextHash   string                  `json:"text_sha256"`
+	Typed      string                  `json:"typed"`
+	Results    session.ProtocolMetrics `json:"results"`
+}
+
+// Certificate is the result file: the result exactly as it was signed, with the signature and the
+// public key that checks it
+type Certificate struct {
+	Result    json.RawMessage `json:"result"`
+	PublicKey string          `json:"public_key"`
+	Signature string          `json:"signature"`
+}
+
+// KeyPath is where the key results are signed with is kept, beside config.toml
+func KeyPath() string {
+	return filepath.Join(config.ConfigDir, "signing.key")
+}
+
+// loadKey reads the signing key, creating one the first time a result is signed
+func loadKey() (ed25519.PrivateKey, error) {
+	path := KeyPath()
+	data, err := os.ReadFile(path)
+	if err == nil {
+		seed, err := base64.StdEncoding.DecodeString(string(data))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("%s is not a gti signing key", path)
+		}
+		return ed25519.NewKeyFromSeed(seed), nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.EnsureDir(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key.Seed())), 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Fingerprint is a short form of a public key, for telling keys apart at a glance
+func Fingerprint(key ed25519.PublicKey) string {
+	hash := sha256.Sum256(key)
+	return hex.EncodeToString(hash[:8])
+}
+
+// Save signs the run and writes its result file into dir, returning the file's path
+func Save(run session.StandardRun, dir, gtiVersion string) (string, error) {
+	key, err := loadKey()
+	if err != nil {
+		return "", err
+	}
+	result, err := json.Marshal(Result{
+		Format:     Format,
+		GTIVersion: gtiVersion,
+		Seconds:    Seconds,
+		Started:    run.Started,
+		Finished:   run.Finished,
+		TextHash:   TextHash(run.Text),
+		Typed:      run.Typed,
+		Results:    run.Results,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	dir = config.ExpandPath(dir)
+	if err := config.EnsureDir(dir); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "gti_standard_"+run.Started.Format("2006-01-02_15-04-05")+".json")
+	err = config.SaveJSONData(path, Certificate{
+		Result:    result,
+		PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, result)),
+	})
+	return path, err
+}
+
+// Verify checks a result file: that it was signed by the key it carries and not changed since, and
+// that it is a standard test result whose numbers follow from what was typed. It returns the result and the fingerprint
+// of the key that signed it.
+func Verify(path string) (*Result, string, error) {
+	data, err := os.ReadFile(config.ExpandPath(path))
+	if err != nil {
+		return nil, "", err
+	}
+	var cert Certificate
+	if err := json.Unmarshal(data, &cert); err != nil {
+		return nil, "", fmt.Errorf("not a result file: %w", err)
+	}
+	publicKey, err := base64.StdEncoding.DecodeString(cert.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return nil, "", errors.New("the public key is malformed")
+	}
+	signature, err := base64.StdEncoding.DecodeString(cert.Signature)
+	if err != nil {
+		return nil, "", errors.New("the signature is malformed")
+	}
+	// The file is indented when saved; the signature covers the compact form
+	var signed bytes.Buffer
+	if err := json.Compact(&signed, cert.Result); err != nil {
+		return nil, "", fmt.Errorf("not a result file: %w", err)
+	}
+	if !ed25519.Verify(publicKey, signed.Bytes(), signature) {
+		return nil, "", errors.New("the signature does not match: the result has been changed since it was signed")
+	}
+
+	var result Result
+	if err := json.Unmarshal(cert.Result, &result); err != nil {
+		return nil, "", fmt.Errorf("not a result file: %w", err)
+	}
+	if err := result.check(); err != nil {
+		return nil, "", err
+	}
+	return &result, Fingerprint(publicKey), nil
+}
+
+// check makes sure a signed result is one of this version of the standard test and that its speed
+// follows from what was typed, scored again against the passage
+func (r *Result) check() error {
+	if r.Format != Format {
+		return fmt.Errorf("the result is of %q, not %s", r.Format, Format)
+	}
+	text := Text()
+	if r.TextHash != TextHash(text) {
+		return errors.New("the result was not typed on the standard test passage")
+	}
+	if r.Seconds != Seconds {
+		return fmt.Errorf("the test lasted %ds, not %ds", r.Seconds, Seconds)
+	}
+	duration := time.Duration(r.Results.DurationMs) * time.Millisecond
+	if duration <= 0 || duration > Seconds*time.Second {
+		return fmt.Errorf("the run lasted %s, outside the %ds of the test", duration, Seconds)
+	}
+	chars, uncorrected, err := score(r.Typed, text)
+	if err != nil {
+		return err
+	}
+	if chars != r.Results.Chars || uncorrected != r.Results.UncorrectedErrors {
+		return fmt.Errorf("the counts of %d characters and %d uncorrected errors do not match what was typed, which gives %d and %d",
+			r.Results.Chars, r.Results.UncorrectedErrors, chars, uncorrected)
+	}
+	// Only typing the whole passage ends the test before the time is up
+	if duration < Seconds*time.Second && chars < len(text) {
+		return errors.New("the run ended early without the passage being typed")
+	}
+	netWPM := session.CalculateNetWPM(chars, uncorrected, duration)
+	if math.Abs(netWPM-r.Results.NetWPM) > 0.01 {
+		return fmt.Errorf("the net speed of %.2f WPM does not follow from what was typed, which gives %.2f", r.Results.NetWPM, netWPM)
+	}
+	return nil
+}
+
+// score counts what was typed against the passage the way a run does: every character typed, and
+// those left wrong at the end. A mistyped character takes up as many bytes as the one it replaced.
+func score(typed, text string) (chars, uncorrected int, err error) {
+	if len(typed) > len(text) {
+		return 0, 0, errors.New("more was typed than the passage holds")
+	}
+	for i := 0; i < len(typed); {
+		_, size := utf8.DecodeRuneInString(text[i:])
+		if i+size > len(typed) {
+			return 0, 0, errors.New("the typed text does not line up with the passage")
+		}
+		if typed[i:i+size] != text[i:i+size] {
+			uncorrected++
+		}
+		i += size
+	}
+	return len(typed), uncorrected, nil
+}
@@ -0,0 +1,76 @@
+// Package standard runs the standard test: one fixed passage, three minutes, strict rules, and a
+// result file signed with the typist's own key, which anyone it is shown to can score again.
+package standard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"gti/src/assets"
+	"gti/src/internal/config"
+)
+
+const (
+	// Format names the layout of the result file, and changes whenever the rules of the test do
+	Format = "gti-standard-test/1"
+	// Seconds is how long the test lasts
+	Seconds = 180
+	// Mode is the session mode standard test records are saved under
+	Mode = "standard"
+)
+
+// Text is the passage every standard test is typed on: the embedded paragraphs in order, joined into
+// one line so the test never moves on to other text
+func Text() string {
+	names, err := fs.Glob(assets.Standard, "standard/*.txt")
+	if err != nil {
+		return ""
+	}
+	sort.Strings(names)
+
+	var paragraphs []string
+	for _, name := range names {
+		data, err := assets.Standard.ReadFile(name)
+		if err != nil {
+			continue
+		}
+		for _, paragraph := range strings.Split(string(data), "\n\n") {
+			if paragraph = strings.Join(strings.Fields(paragraph), " "); paragraph != "" {
+				paragraphs = append(paragraphs, paragraph)
+			}
+		}
+	}
+	return strings.Join(paragraphs, " ")
+}
+
+// TextHash is the SHA-256 of the passage, recorded in every result so a result typed on other text
+// cannot pass for one of the standard test
+func TextHash(text string) string {
+	hash := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(hash[:])
+}
+
+// LockedConfig is the configuration the standard test runs under: the defaults for everything that
+// could affect the score, keeping only the user's colours, key bindings and history settings
+func LockedConfig(user *config.Config) *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.Theme = user.Theme
+	cfg.Keybindings = user.Keybindings
+	cfg.History = user.History
+	// Emulating another layout only changes which keys are pressed, not what has to be typed
+	cfg.Keyboard.Emulate = user.Keyboard.Emulate
+	cfg.Keyboard.EmulateOnce = user.Keyboard.EmulateOnce
+	cfg.Language.Default = "english"
+	cfg.Keyboard.Paste = config.PasteReject
+	cfg.Keyboard.Accents = config.AccentsExact
+	cfg.Display.ShowGhost = false
+	cfg.Display.ChunkSummary = false
+	cfg.Results.AutoDismissSeconds = 0
+	cfg.Results.AutoChain = false
+	// Three minutes means three minutes: time away from the keys is not taken out of the test
+	cfg.Idle.Seconds = 0
+	return cfg
+}
@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// StdinFile is the file name that stands for standard input, as in `cat notes.txt | gti -c -`
+const StdinFile = "-"
+
+var (
+	stdinOnce sync.Once
+	stdinData []byte
+	stdinErr  error
+)
+
+// ReadFile reads the named file, or whatever was piped to gti when name is StdinFile. Standard input
+// can only be read once, so it is kept for every later load, such as a restart or the next paragraph.
+func ReadFile(name string) ([]byte, error) {
+	if name != StdinFile {
+		return os.ReadFile(name)
+	}
+	stdinOnce.Do(func() {
+		if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			stdinErr = fmt.Errorf("nothing was piped to gti, use it as in: cat notes.txt | gti -c -")
+			return
+		}
+		stdinData, stdinErr = io.ReadAll(os.Stdin)
+		if stdinErr == nil && len(stdinData) == 0 {
+			stdinErr = fmt.Errorf("standard input was empty")
+		}
+	})
+	return stdinData, stdinErr
+}
+
+// StdinRead reports whether standard input was taken for text, so keys must be read from the terminal instead
+func StdinRead() bool {
+	return stdinData != nil
+}
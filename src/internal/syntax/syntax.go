@@ -0,0 +1,117 @@
+package syntax
+
+import (
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+)
+
+// Kind is the broad token category of a single character of source text
+type Kind int
+
+const (
+	Plain Kind = iota
+	Keyword
+	String
+	Comment
+	Number
+	Name
+	Punctuation
+)
+
+// Highlighter tokenises source text into one Kind per byte so renderers can colour it
+// without disturbing the character alignment used for typing.
+type Highlighter struct {
+	lexer chroma.Lexer
+}
+
+// ForLanguage returns a highlighter for a language name or alias such as "go", "python" or "yaml"
+func ForLanguage(language string) *Highlighter {
+	return newHighlighter(lexers.Get(language))
+}
+
+// ForFile returns a highlighter chosen by the file's name or extension
+func ForFile(filename string) *Highlighter {
+	return newHighlighter(lexers.Match(filename))
+}
+
+func newHighlighter(lexer chroma.Lexer) *Highlighter {
+	if lexer == nil {
+		return nil
+	}
+	return &Highlighter{lexer: chroma.Coalesce(lexer)}
+}
+
+// Kinds returns the token kind of every byte in text. A nil highlighter or a lexer
+// error yields all Plain, so callers never have to special-case unknown languages.
+func (h *Highlighter) Kinds(text string) []Kind {
+	kinds := make([]Kind, len(text))
+	if h == nil {
+		return kinds
+	}
+
+	iter, err := h.lexer.Tokenise(&chroma.TokeniseOptions{State: "root"}, text)
+	if err != nil {
+		return kinds
+	}
+
+	pos := 0
+	for _, token := range iter.Tokens() {
+		kind := kindOf(token.Type)
+		for i := 0; i < len(token.Value) && pos < len(kinds); i++ {
+			kinds[pos] = kind
+			pos++
+		}
+	}
+	return kinds
+}
+
+// StripCommentLines drops the lines that hold nothing but comments, leaving code and blank lines alone.
+// Comments trailing code on the same line are kept, since removing them would change what the line looks like.
+func (h *Highlighter) StripCommentLines(text string) string {
+	if h == nil {
+		return text
+	}
+
+	kinds := h.Kinds(text)
+	var kept []string
+	pos := 0
+	for _, line := range strings.Split(text, "\n") {
+		commentOnly := false
+		for i := 0; i < len(line); i++ {
+			if line[i] == ' ' || line[i] == '\t' {
+				continue
+			}
+			if kinds[pos+i] != Comment {
+				commentOnly = false
+				break
+			}
+			commentOnly = true
+		}
+		if !commentOnly {
+			kept = append(kept, line)
+		}
+		pos += len(line) + 1
+	}
+	return strings.Join(kept, "\n")
+}
+
+func kindOf(t chroma.TokenType) Kind {
+	switch {
+	case t.InCategory(chroma.Keyword), t == chroma.NameBuiltin, t == chroma.NameKeyword:
+		return Keyword
+	case t.InCategory(chroma.Comment):
+		return Comment
+	case t.InSubCategory(chroma.LiteralString):
+		return String
+	case t.InSubCategory(chroma.LiteralNumber):
+		return Number
+	case t == chroma.NameFunction, t == chroma.NameClass, t == chroma.NameTag, t == chroma.NameAttribute, t == chroma.NameProperty:
+		return Name
+	case t.InCategory(chroma.Operator), t.InCategory(chroma.Punctuation):
+		return Punctuation
+	default:
+		return Plain
+	}
+}
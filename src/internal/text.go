@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"gti/src/internal/config"
+)
+
+var (
+	lineEndings = []string{"\r\n", "\n", "\r", "\n"}
+	curlyQuotes = []string{
+		"‘", "'", "’", "'", "‚", "'", "‛", "'",
+		"“", "\"", "”", "\"", "„", "\"", "‟", "\"",
+	}
+	dashes = []string{
+		"–", "-", "—", "-", "‒", "-", "―", "-", "−", "-",
+		"…", "...",
+	}
+	noBreakSpaces = []string{"\u00a0", " ", "\u202f", " "}
+	// invisibles are characters that show as nothing, so could never be typed
+	invisibles = []string{"\u200b", "", "\u200c", "", "\u200d", "", "\u2060", "", "\ufeff", "", "\u00ad", ""}
+)
+
+// typographic replaces the characters word processors and web pages put in text with the ones on a keyboard
+var typographic = strings.NewReplacer(slices.Concat(lineEndings, curlyQuotes, dashes, noBreakSpaces, invisibles)...)
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// NormalizeText makes pasted or downloaded text typeable: line endings become \n, curly quotes, dashes
+// and other typographic characters become their keyboard forms, trailing spaces go and runs of blank
+// lines shrink to one. Leading indentation is kept, so code stays intact.
+func NormalizeText(text string) string {
+	text = typographic.Replace(text)
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	text = blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.Trim(text, "\n")
+}
+
+var spaceRuns = regexp.MustCompile(`[ \t]+`)
+
+// CleanImported applies the clean-ups clean turns on to the text of a file about to be practised.
+// Line endings always become \n. Code keeps its tabs and the spacing within its lines, losing only
+// trailing whitespace.
+func CleanImported(text string, clean config.ImportConfig, code bool) string {
+	pairs := lineEndings
+	if clean.Quotes {
+		pairs = slices.Concat(pairs, curlyQuotes)
+	}
+	if clean.Dashes {
+		pairs = slices.Concat(pairs, dashes)
+	}
+	if clean.Whitespace {
+		pairs = slices.Concat(pairs, noBreakSpaces)
+	}
+	if clean.Control {
+		pairs = slices.Concat(pairs, invisibles)
+	}
+	text = strings.NewReplacer(pairs...).Replace(text)
+
+	if clean.Control {
+		text = strings.Map(func(r rune) rune {
+			if unicode.IsControl(r) && r != '\n' && r != '\t' {
+				return -1
+			}
+			return r
+		}, text)
+	}
+	if clean.Whitespace {
+		lines := strings.Split(text, "\n")
+		for i, line := range lines {
+			if !code {
+				line = spaceRuns.ReplaceAllString(line, " ")
+			}
+			lines[i] = strings.TrimRight(line, " \t")
+		}
+		text = strings.Join(lines, "\n")
+	}
+	return text
+}
@@ -0,0 +1,233 @@
+// Package tts reads words aloud through a speech engine installed on the system.
+// There is no speech synthesizer written in Go to fall back on, so when no engine is found
+// For says which one to install and the words simply go unspoken.
+package tts
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"unicode"
+
+	"gti/src/internal/config"
+)
+
+// maxRunes caps what is read aloud at once, so a long line of minified code cannot keep the engine talking
+const maxRunes = 200
+
+// engine builds the command that speaks text with the given settings
+type engine struct {
+	name    string
+	command string
+	args    func(cfg config.TTSConfig, text string) (args []string, env []string)
+}
+
+var engines = []engine{
+	{name: "espeak-ng", command: "espeak-ng", args: espeakArgs},
+	{name: "espeak", command: "espeak", args: espeakArgs},
+	{name: "spd-say", command: "spd-say", args: spdArgs},
+	{name: "say", command: "say", args: sayArgs},
+	{name: "sapi", command: "powershell", args: sapiArgs},
+}
+
+// Speaker reads text aloud with one engine. A nil Speaker is silent, so callers need not check whether speech works.
+type Speaker struct {
+	engine engine
+	cfg    config.TTSConfig
+	queue  chan string
+}
+
+var (
+	shared     *Speaker
+	sharedCfg  config.TTSConfig
+	sharedLock sync.Mutex
+)
+
+// For returns the speaker for cfg, reusing the previous one while the settings are unchanged,
+// or an error naming what to install when no engine can be found.
+func For(cfg config.TTSConfig) (*Speaker, error) {
+	sharedLock.Lock()
+	defer sharedLock.Unlock()
+	if shared != nil && sharedCfg == cfg {
+		return shared, nil
+	}
+
+	e, err := findEngine(cfg.Engine)
+	if err != nil {
+		return nil, err
+	}
+	s := &Speaker{engine: e, cfg: cfg, queue: make(chan string, 1)}
+	go s.run()
+	shared, sharedCfg = s, cfg
+	return s, nil
+}
+
+// Say reads text aloud without waiting. A word still waiting its turn is replaced, so speech keeps up with the typing.
+func (s *Speaker) Say(text string) {
+	if s == nil {
+		return
+	}
+	text = sanitize(text)
+	if text == "" {
+		return
+	}
+	for {
+		select {
+		case s.queue <- text:
+			return
+		default:
+		}
+		select {
+		case <-s.queue:
+		default:
+		}
+	}
+}
+
+// Engine names the speech engine the speaker uses
+func (s *Speaker) Engine() string {
+	return s.engine.name
+}
+
+func (s *Speaker) run() {
+	for text := range s.queue {
+		args, env := s.engine.args(s.cfg, text)
+		cmd := exec.Command(s.engine.command, args...)
+		if len(env) > 0 {
+			cmd.Env = append(os.Environ(), env...)
+		}
+		cmd.Run()
+	}
+}
+
+// ValidateEngine checks the engine name is known and returns error if not
+func ValidateEngine(name string) error {
+	if name == "auto" {
+		return nil
+	}
+	for _, e := range engines {
+		if e.name == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported speech engine '%s' (supported: auto, %s)", name, strings.Join(engineNames(), ", "))
+}
+
+func engineNames() []string {
+	names := make([]string, len(engines))
+	for i, e := range engines {
+		names[i] = e.name
+	}
+	return names
+}
+
+// findEngine returns the named engine if it is installed, or for auto the first one this system has
+func findEngine(name string) (engine, error) {
+	if err := ValidateEngine(name); err != nil {
+		return engine{}, err
+	}
+	if name != "auto" {
+		for _, e := range engines {
+			if e.name == name {
+				if _, err := exec.LookPath(e.command); err != nil {
+					return engine{}, fmt.Errorf("speech engine '%s' is not installed", name)
+				}
+				return e, nil
+			}
+		}
+	}
+
+	var candidates []string
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		candidates = []string{"espeak-ng", "espeak", "spd-say"}
+	case "darwin":
+		candidates = []string{"say", "espeak-ng", "espeak"}
+	case "windows":
+		candidates = []string{"sapi", "espeak-ng"}
+	}
+	for _, c := range candidates {
+		for _, e := range engines {
+			if e.name != c {
+				continue
+			}
+			if _, err := exec.LookPath(e.command); err == nil {
+				return e, nil
+			}
+		}
+	}
+	if runtime.GOOS == "linux" {
+		return engine{}, fmt.Errorf("no speech engine found, install espeak-ng or speech-dispatcher to hear words read aloud")
+	}
+	return engine{}, fmt.Errorf("reading words aloud is not supported on %s", runtime.GOOS)
+}
+
+// sanitize turns text into a single line that no engine can mistake for an option or an embedded command
+func sanitize(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || unicode.IsSpace(r) {
+			return ' '
+		}
+		return r
+	}, text)
+	// say reads [[...]] as a speech command, and espeak reads a leading dash as an option
+	text = strings.NewReplacer("[[", " ", "]]", " ").Replace(text)
+	text = strings.TrimLeft(strings.Join(strings.Fields(text), " "), "-")
+	if runes := []rune(text); len(runes) > maxRunes {
+		text = string(runes[:maxRunes])
+	}
+	return strings.TrimSpace(text)
+}
+
+// espeakArgs speaks at rate words per minute; espeak's amplitude runs from 0 to 200
+func espeakArgs(cfg config.TTSConfig, text string) ([]string, []string) {
+	args := []string{"-s", fmt.Sprint(cfg.Rate), "-a", fmt.Sprint(cfg.Volume * 2)}
+	if cfg.Voice != "" {
+		args = append(args, "-v", cfg.Voice)
+	}
+	return append(args, "--", text), nil
+}
+
+// spdArgs maps rate and volume onto speech-dispatcher's -100 to 100 scales, with 175 wpm as its normal speed
+func spdArgs(cfg config.TTSConfig, text string) ([]string, []string) {
+	rate := clamp((cfg.Rate-175)*100/275, -100, 100)
+	args := []string{"-w", "-r", fmt.Sprint(rate), "-i", fmt.Sprint(cfg.Volume*2 - 100)}
+	if cfg.Voice != "" {
+		args = append(args, "-y", cfg.Voice)
+	}
+	return append(args, "--", text), nil
+}
+
+// sayArgs sets the volume with say's own [[volm]] command, as it has no flag for it
+func sayArgs(cfg config.TTSConfig, text string) ([]string, []string) {
+	args := []string{"-r", fmt.Sprint(cfg.Rate)}
+	if cfg.Voice != "" {
+		args = append(args, "-v", cfg.Voice)
+	}
+	return append(args, "--", fmt.Sprintf("[[volm %.2f]] %s", float64(cfg.Volume)/100, text)), nil
+}
+
+// sapiArgs passes the text and voice through the environment, so nothing typed is ever parsed as PowerShell.
+// SAPI's rate runs from -10 to 10 with 0 close to 175 wpm.
+func sapiArgs(cfg config.TTSConfig, text string) ([]string, []string) {
+	script := fmt.Sprintf(`Add-Type -AssemblyName System.Speech; `+
+		`$s = New-Object System.Speech.Synthesis.SpeechSynthesizer; `+
+		`if ($env:GTI_TTS_VOICE) { $s.SelectVoice($env:GTI_TTS_VOICE) }; `+
+		`$s.Rate = %d; $s.Volume = %d; $s.Speak($env:GTI_TTS_TEXT)`,
+		clamp((cfg.Rate-175)/25, -10, 10), clamp(cfg.Volume, 0, 100))
+	args := []string{"-NoProfile", "-NonInteractive", "-Command", script}
+	return args, []string{"GTI_TTS_TEXT=" + text, "GTI_TTS_VOICE=" + cfg.Voice}
+}
+
+func clamp(n, lo, hi int) int {
+	if n < lo {
+		return lo
+	}
+	if n > hi {
+		return hi
+	}
+	return n
+}
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"gti/src/internal/config"
+	"gti/src/internal/session"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// graphHeight is how many rows the results graph is tall
+	graphHeight = 5
+	// graphMaxWidth keeps long runs from stretching the graph across wide terminals
+	graphMaxWidth = 60
+	// graphMinSeconds is the shortest run worth a graph
+	graphMinSeconds = 5
+)
+
+// graphBlocks fills a cell from the bottom up in eighths
+var graphBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// renderRunGraph charts the WPM of every second of the run as bars, with the running accuracy and
+// the seconds that had mistakes underneath. Runs longer than the graph is wide average neighbouring seconds.
+func renderRunGraph(samples []session.Sample, width int, colors config.ThemeColorsConfig) string {
+	if len(samples) < graphMinSeconds || width < 20 {
+		return ""
+	}
+	bg := lipgloss.Color(colors.Background)
+	axis := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.TextSecondary)).Background(bg)
+	bars := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Accent)).Background(bg)
+	marks := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Incorrect)).Background(bg)
+	accuracy := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Correct)).Background(bg)
+
+	columns := bucketSamples(samples, min(width-6, graphMaxWidth))
+	peak := 0.0
+	for _, c := range columns {
+		peak = math.Max(peak, c.WPM)
+	}
+	if peak <= 0 {
+		return ""
+	}
+
+	var lines []string
+	for row := graphHeight - 1; row >= 0; row-- {
+		label := "    "
+		switch row {
+		case graphHeight - 1:
+			label = fmt.Sprintf("%4.0f", peak)
+		case 0:
+			label = fmt.Sprintf("%4d", 0)
+		}
+		var line strings.Builder
+		for _, c := range columns {
+			// Eighths of a cell this column reaches above the bottom of the row
+			fill := int(math.Round(c.WPM/peak*float64(graphHeight*8))) - row*8
+			line.WriteRune(graphBlocks[max(0, min(fill, 8))])
+		}
+		lines = append(lines, axis.Render(label+" ")+bars.Render(line.String()))
+	}
+
+	// Accuracy gets a single row, scaled from the run's lowest point to 100%
+	lowest := 100.0
+	for _, c := range columns {
+		lowest = math.Min(lowest, c.Accuracy)
+	}
+	var acc strings.Builder
+	for _, c := range columns {
+		fill := 8
+		if lowest < 100 {
+			fill = 1 + int(math.Round((c.Accuracy-lowest)/(100-lowest)*7))
+		}
+		acc.WriteRune(graphBlocks[fill])
+	}
+	lines = append(lines, axis.Render("acc  ")+accuracy.Render(acc.String()))
+
+	var errors strings.Builder
+	for _, c := range columns {
+		if c.Errors > 0 {
+			errors.WriteRune('×')
+		} else {
+			errors.WriteRune(' ')
+		}
+	}
+	lines = append(lines, axis.Render("err  ")+marks.Render(errors.String()))
+	lines = append(lines, axis.Render(fmt.Sprintf("     %-*s", len(columns), fmt.Sprintf("0s%*ds", len(columns)-3, len(samples)))))
+	return strings.Join(lines, "\n")
+}
+
+// bucketSamples averages the samples down to at most width columns; errors are summed
+func bucketSamples(samples []session.Sample, width int) []session.Sample {
+	if len(samples) <= width {
+		return samples
+	}
+	columns := make([]session.Sample, width)
+	for i := range columns {
+		from, to := i*len(samples)/width, (i+1)*len(samples)/width
+		for _, s := range samples[from:to] {
+			columns[i].WPM += s.WPM
+			columns[i].Errors += s.Errors
+		}
+		columns[i].WPM /= float64(to - from)
+		columns[i].Accuracy = samples[to-1].Accuracy
+	}
+	return columns
+}
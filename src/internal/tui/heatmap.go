@@ -0,0 +1,219 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gti/src/internal/config"
+	"gti/src/internal/session"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// heatmapMinSamples is how often a key must have been due in both periods before its change counts
+	heatmapMinSamples = 20
+	// heatmapThreshold is the change in error rate, in percentage points, worth calling fixed or broken
+	heatmapThreshold = 2.0
+	// heatmapListLimit caps the fixed and broken lists below the keyboard
+	heatmapListLimit = 5
+)
+
+var heatmapRows = []string{"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./"}
+
+// heatmapShifted maps each shifted US QWERTY symbol to the key it is typed on
+var heatmapShifted = map[rune]rune{
+	'~': '`', '!': '1', '@': '2', '#': '3', '$': '4', '%': '5', '^': '6', '&': '7', '*': '8', '(': '9', ')': '0', '_': '-', '+': '=',
+	'{': '[', '}': ']', '|': '\\', ':': ';', '"': '\'', '<': ',', '>': '.', '?': '/',
+}
+
+// DateRange is an inclusive span of days; a zero From or To leaves that end open
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// ParseDateRange reads "2006-01-02..2006-01-31", with either side optional, or a single day
+func ParseDateRange(value string) (DateRange, error) {
+	from, to, isRange := strings.Cut(value, "..")
+	if !isRange {
+		to = from
+	}
+
+	var r DateRange
+	var err error
+	if from != "" {
+		if r.From, err = time.ParseInLocation("2006-01-02", from, time.Local); err != nil {
+			return r, fmt.Errorf("invalid date '%s', use YYYY-MM-DD", from)
+		}
+	}
+	if to != "" {
+		if r.To, err = time.ParseInLocation("2006-01-02", to, time.Local); err != nil {
+			return r, fmt.Errorf("invalid date '%s', use YYYY-MM-DD", to)
+		}
+		r.To = r.To.AddDate(0, 0, 1)
+	}
+	if !r.From.IsZero() && !r.To.IsZero() && !r.From.Before(r.To) {
+		return r, fmt.Errorf("date range '%s' ends before it starts", value)
+	}
+	return r, nil
+}
+
+func (r DateRange) contains(t time.Time) bool {
+	return (r.From.IsZero() || !t.Before(r.From)) && (r.To.IsZero() || t.Before(r.To))
+}
+
+func (r DateRange) String() string {
+	from, to := "start", "now"
+	if !r.From.IsZero() {
+		from = r.From.Format("2006-01-02")
+	}
+	if !r.To.IsZero() {
+		to = r.To.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	return from + " to " + to
+}
+
+// keyChange is one key's error rate in both periods
+type keyChange struct {
+	key    rune
+	before float64
+	after  float64
+	known  bool
+}
+
+func (c keyChange) delta() float64 {
+	return c.after - c.before
+}
+
+// keyTotals sums the per-character counts of the records in the range onto physical keys
+func keyTotals(records []*session.SessionRecord, r DateRange) (map[rune]session.KeyStat, int) {
+	totals := make(map[rune]session.KeyStat)
+	sessions := 0
+	for _, record := range records {
+		if !r.contains(record.Timestamp) || len(record.KeyStats) == 0 || record.Pasted {
+			continue
+		}
+		sessions++
+		for char, stat := range record.KeyStats {
+			runes := []rune(char)
+			if len(runes) != 1 {
+				continue
+			}
+			key := runes[0]
+			if base, ok := heatmapShifted[key]; ok {
+				key = base
+			} else if key >= 'A' && key <= 'Z' {
+				key += 'a' - 'A'
+			}
+			total := totals[key]
+			total.Typed += stat.Typed
+			total.Errors += stat.Errors
+			totals[key] = total
+		}
+	}
+	return totals, sessions
+}
+
+func errorRate(stat session.KeyStat) float64 {
+	if stat.Typed == 0 {
+		return 0
+	}
+	return float64(stat.Errors) / float64(stat.Typed) * 100
+}
+
+// RenderHeatmapComparison draws a keyboard coloured by how each key's error rate moved between two periods,
+// followed by the keys that improved and worsened the most
+func RenderHeatmapComparison(cfg *config.Config, records []*session.SessionRecord, before, after DateRange) string {
+	beforeTotals, beforeSessions := keyTotals(records, before)
+	afterTotals, afterSessions := keyTotals(records, after)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Error heatmap: %s (%d sessions) vs %s (%d sessions)\n\n", before, beforeSessions, after, afterSessions)
+	if beforeSessions == 0 || afterSessions == 0 {
+		b.WriteString("Not enough sessions with per-key data in both periods to compare.\n")
+		return b.String()
+	}
+
+	changes := make(map[rune]keyChange)
+	for _, row := range heatmapRows {
+		for _, key := range row {
+			old, new := beforeTotals[key], afterTotals[key]
+			changes[key] = keyChange{
+				key:    key,
+				before: errorRate(old),
+				after:  errorRate(new),
+				known:  old.Typed >= heatmapMinSamples && new.Typed >= heatmapMinSamples,
+			}
+		}
+	}
+
+	colors := cfg.Theme.Colors
+	for i, row := range heatmapRows {
+		b.WriteString(strings.Repeat(" ", i*2))
+		for _, key := range row {
+			change := changes[key]
+			style := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.TextSecondary))
+			switch {
+			case !change.known:
+				style = style.Faint(true)
+			case change.delta() <= -heatmapThreshold:
+				style = lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Background)).Background(lipgloss.Color(colors.Correct))
+			case change.delta() >= heatmapThreshold:
+				style = lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Background)).Background(lipgloss.Color(colors.Incorrect))
+			}
+			b.WriteString(style.Render(" "+string(key)+" ") + " ")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\nGreen: fewer errors   Red: more errors   Dim: too few presses to tell\n\n")
+
+	var fixed, broke []keyChange
+	for _, change := range changes {
+		if !change.known {
+			continue
+		}
+		if change.delta() <= -heatmapThreshold {
+			fixed = append(fixed, change)
+		} else if change.delta() >= heatmapThreshold {
+			broke = append(broke, change)
+		}
+	}
+	sort.Slice(fixed, func(i, j int) bool { return fixed[i].delta() < fixed[j].delta() })
+	sort.Slice(broke, func(i, j int) bool { return broke[i].delta() > broke[j].delta() })
+
+	b.WriteString(heatmapSummary(fixed, broke))
+	writeKeyChanges(&b, "Fixed", fixed)
+	writeKeyChanges(&b, "Broke", broke)
+	return b.String()
+}
+
+// heatmapSummary sums the comparison up in one sentence
+func heatmapSummary(fixed, broke []keyChange) string {
+	switch {
+	case len(fixed) > 0 && len(broke) > 0:
+		return fmt.Sprintf("You fixed '%c' but broke '%c'.\n", fixed[0].key, broke[0].key)
+	case len(fixed) > 0:
+		return fmt.Sprintf("You fixed '%c' and nothing got worse.\n", fixed[0].key)
+	case len(broke) > 0:
+		return fmt.Sprintf("Nothing improved noticeably, and '%c' got worse.\n", broke[0].key)
+	default:
+		return "No key changed by more than " + fmt.Sprintf("%.0f", heatmapThreshold) + " points.\n"
+	}
+}
+
+func writeKeyChanges(b *strings.Builder, title string, changes []keyChange) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n%s:\n", title)
+	for i, change := range changes {
+		if i == heatmapListLimit {
+			break
+		}
+		fmt.Fprintf(b, "  %c  %5.1f%% -> %5.1f%% errors (%+.1f)\n", change.key, change.before, change.after, math.Round(change.delta()*10)/10)
+	}
+}
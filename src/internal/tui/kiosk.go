@@ -0,0 +1,372 @@
+package tui
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gti/src/internal"
+	"gti/src/internal/config"
+	"gti/src/internal/keymap"
+	"gti/src/internal/session"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// KioskAttractSeconds is how long the title screen shows before the demo plays
+	KioskAttractSeconds = 10
+	// KioskDemoSeconds caps a demo run before the title screen comes back
+	KioskDemoSeconds = 45
+	// KioskDemoWPM is the speed of the self-playing demo
+	KioskDemoWPM = 55
+	// KioskDemoWords is the length of the demo passage
+	KioskDemoWords = 20
+	// KioskPasscodeSeconds abandons a half-typed passcode
+	KioskPasscodeSeconds = 15
+	kioskFrameInterval   = 500 * time.Millisecond
+)
+
+type kioskPhase int
+
+const (
+	kioskAttract kioskPhase = iota
+	kioskDemo
+	kioskPlaying
+	kioskPasscode
+)
+
+var kioskBanner = []string{
+	" ██████  ████████ ██ ",
+	"██          ██    ██ ",
+	"██   ███    ██    ██ ",
+	"██    ██    ██    ██ ",
+	" ██████     ██    ██ ",
+}
+
+const kioskTagline = "How fast can you type?"
+
+type KioskOptions struct {
+	Passcode    string
+	IdleSeconds int
+	Seconds     int
+}
+
+// KioskModel cycles an attract screen and a self-playing demo until a visitor presses a key,
+// then runs timed tests for them, returning to the attract screen once they walk away.
+// Leaving for the shell takes the passcode.
+type KioskModel struct {
+	config *config.Config
+	keys   *keymap.Bindings
+	opts   KioskOptions
+
+	phase       kioskPhase
+	returnPhase kioskPhase
+	phaseStart  time.Time
+	lastInput   time.Time
+	// game is the demo or visitor test currently shown, nil on the attract screen
+	game *Model
+	// demoRun tells a stale demo key tick from the current demo's
+	demoRun  int
+	frame    int
+	passcode string
+	wrong    bool
+	quitting bool
+	width    int
+	height   int
+}
+
+type kioskFrameMsg struct{}
+
+type kioskDemoKeyMsg struct {
+	run int
+}
+
+func NewKioskModel(cfg *config.Config, opts KioskOptions) KioskModel {
+	if opts.IdleSeconds <= 0 {
+		opts.IdleSeconds = cfg.Kiosk.IdleSeconds
+	}
+	if opts.Seconds <= 0 {
+		opts.Seconds = cfg.Kiosk.Seconds
+	}
+	return KioskModel{
+		config:     cfg,
+		keys:       keymap.NewBindings(cfg.Keybindings),
+		opts:       opts,
+		phase:      kioskAttract,
+		phaseStart: time.Now(),
+	}
+}
+
+func (k KioskModel) Init() tea.Cmd {
+	return tea.Batch(tea.EnterAltScreen, kioskFrame())
+}
+
+func kioskFrame() tea.Cmd {
+	return tea.Tick(kioskFrameInterval, func(time.Time) tea.Msg {
+		return kioskFrameMsg{}
+	})
+}
+
+func (k KioskModel) demoKey() tea.Cmd {
+	perChar := time.Minute / time.Duration(KioskDemoWPM*session.CharsPerWord)
+	// Vary the rhythm a little so the demo looks typed rather than printed
+	delay := time.Duration(float64(perChar) * (0.6 + internal.Float64()*0.8))
+	run := k.demoRun
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return kioskDemoKeyMsg{run: run}
+	})
+}
+
+func (k KioskModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		k.width = msg.Width
+		k.height = msg.Height
+		return k, k.forward(msg)
+	case tea.KeyMsg:
+		return k.handleKey(msg)
+	case kioskFrameMsg:
+		k.frame++
+		elapsed := time.Since(k.phaseStart)
+		switch k.phase {
+		case kioskAttract:
+			if elapsed >= KioskAttractSeconds*time.Second {
+				return k, tea.Batch(k.startDemo(), kioskFrame())
+			}
+		case kioskDemo:
+			if elapsed >= KioskDemoSeconds*time.Second {
+				k.startAttract()
+			}
+		case kioskPlaying:
+			if time.Since(k.lastInput) >= time.Duration(k.opts.IdleSeconds)*time.Second {
+				k.startAttract()
+			}
+		case kioskPasscode:
+			if time.Since(k.lastInput) >= KioskPasscodeSeconds*time.Second {
+				k.leavePasscode()
+			}
+		}
+		return k, kioskFrame()
+	case kioskDemoKeyMsg:
+		if k.phase != kioskDemo || msg.run != k.demoRun || k.game == nil {
+			return k, nil
+		}
+		sess := k.game.sess
+		text := sess.GetText()
+		if sess.GetPosition() >= len(text) {
+			return k, nil
+		}
+		// The position is a byte offset, so the next character may take several bytes
+		next, _ := utf8.DecodeRuneInString(text[sess.GetPosition():])
+		key := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{next}}
+		if next == ' ' {
+			key = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+		}
+		return k, tea.Batch(sess.HandleInput(key), k.demoKey())
+	case session.SessionCompleteMsg:
+		if k.phase == kioskDemo {
+			k.startAttract()
+			return k, nil
+		}
+		// The idle clock starts over on the results screen
+		k.lastInput = time.Now()
+		return k, k.forward(msg)
+	}
+	return k, k.forward(msg)
+}
+
+// forward passes a message to the test on screen; a test that tries to quit sends the kiosk back to the attract screen
+func (k *KioskModel) forward(msg tea.Msg) tea.Cmd {
+	if k.game == nil {
+		return nil
+	}
+
+	updated, cmd := k.game.Update(msg)
+	switch game := updated.(type) {
+	case Model:
+		k.game = &game
+	case *Model:
+		k.game = game
+	}
+
+	if k.game.quitting {
+		k.startAttract()
+		return nil
+	}
+	return cmd
+}
+
+func (k *KioskModel) handleKey(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	k.lastInput = time.Now()
+
+	if k.phase == kioskPasscode {
+		return k, k.handlePasscodeKey(key)
+	}
+	if k.keys.Is(key, keymap.ActionForceQuit) || k.keys.Is(key, keymap.ActionQuit) {
+		k.returnPhase = k.phase
+		k.phase = kioskPasscode
+		k.passcode = ""
+		k.wrong = false
+		return k, nil
+	}
+
+	switch k.phase {
+	case kioskAttract, kioskDemo:
+		// The key that wakes the kiosk only starts the test, it is not typed
+		return k, k.startPlaying()
+	default:
+		return k, k.forward(key)
+	}
+}
+
+func (k *KioskModel) handlePasscodeKey(key tea.KeyMsg) tea.Cmd {
+	switch key.Type {
+	case tea.KeyEsc:
+		k.leavePasscode()
+	case tea.KeyEnter:
+		if k.passcode == k.opts.Passcode {
+			k.quitting = true
+			return tea.Quit
+		}
+		k.passcode = ""
+		k.wrong = true
+	case tea.KeyBackspace:
+		if len(k.passcode) > 0 {
+			runes := []rune(k.passcode)
+			k.passcode = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes:
+		k.passcode += string(key.Runes)
+	}
+	return nil
+}
+
+// leavePasscode goes back to whatever was on screen when the passcode was asked for
+func (k *KioskModel) leavePasscode() {
+	k.phase = k.returnPhase
+	k.passcode = ""
+	k.wrong = false
+	if k.phase == kioskDemo {
+		// The demo's key ticks were dropped while the prompt was up
+		k.startAttract()
+	}
+}
+
+func (k *KioskModel) startAttract() {
+	k.phase = kioskAttract
+	k.phaseStart = time.Now()
+	k.game = nil
+}
+
+func (k *KioskModel) startDemo() tea.Cmd {
+	text := internal.GenerateWordsDynamic(KioskDemoWords, k.config.Language.Default)
+	sess := session.NewSession(k.config, session.DemoMode, session.WithText(text, nil, 0))
+	k.setGame(NewModel(k.config, ModelOptions{Session: sess}))
+	k.phase = kioskDemo
+	k.phaseStart = time.Now()
+	k.demoRun++
+	return tea.Batch(sess.Start(), k.demoKey())
+}
+
+func (k *KioskModel) startPlaying() tea.Cmd {
+	// Visitors' runs are not the owner's practice
+	k.setGame(NewModel(k.config, ModelOptions{Mode: "timed", Seconds: k.opts.Seconds, Unrecorded: true}))
+	k.phase = kioskPlaying
+	k.phaseStart = time.Now()
+	return k.game.sess.Start()
+}
+
+func (k *KioskModel) setGame(game Model) {
+	game.width = k.width
+	game.height = k.height
+	k.game = &game
+}
+
+func (k KioskModel) View() string {
+	if k.quitting {
+		return ""
+	}
+	if k.width < 40 || k.height < 10 {
+		return "Terminal too small. Please resize to at least 40x10."
+	}
+
+	switch k.phase {
+	case kioskPasscode:
+		return k.viewPasscode()
+	case kioskDemo:
+		return k.viewDemo()
+	case kioskPlaying:
+		return k.game.View()
+	default:
+		return k.viewAttract()
+	}
+}
+
+func (k KioskModel) viewAttract() string {
+	colors := k.config.Theme.Colors
+	banner := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(colors.Current)).
+		Background(lipgloss.Color(colors.Background)).
+		Bold(true).
+		Render(strings.Join(kioskBanner, "\n"))
+
+	// The tagline types itself out, then holds with a blinking cursor
+	typed := k.frame % (len(kioskTagline) + 8)
+	if typed > len(kioskTagline) {
+		typed = len(kioskTagline)
+	}
+	cursor := " "
+	if k.frame%2 == 0 {
+		cursor = "_"
+	}
+	tagline := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(colors.TextPrimary)).
+		Background(lipgloss.Color(colors.Background)).
+		Render(kioskTagline[:typed] + cursor + strings.Repeat(" ", len(kioskTagline)-typed))
+
+	prompt := "Press any key to start"
+	promptStyle := lipgloss.NewStyle().Background(lipgloss.Color(colors.Background))
+	if k.frame%4 < 2 {
+		promptStyle = promptStyle.Foreground(lipgloss.Color(colors.Correct)).Bold(true)
+	} else {
+		promptStyle = promptStyle.Foreground(lipgloss.Color(colors.TextPrimary)).Faint(true)
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center, banner, "", tagline, "", promptStyle.Render(prompt))
+	return k.place(content)
+}
+
+func (k KioskModel) viewDemo() string {
+	colors := k.config.Theme.Colors
+	header := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(colors.Current)).
+		Background(lipgloss.Color(colors.Background)).
+		Bold(true).
+		Render("DEMO - press any key to play")
+
+	content := lipgloss.JoinVertical(lipgloss.Center, header, "", k.game.sess.View(k.width, k.height-2))
+	return k.place(content)
+}
+
+func (k KioskModel) viewPasscode() string {
+	content := "Enter the passcode to exit kiosk mode\n\nPasscode: " + strings.Repeat("*", len([]rune(k.passcode))) + "_"
+	if k.wrong {
+		content += "\n\nWrong passcode"
+	}
+	content += "\n\nPress Esc to go back"
+	box := Model{config: k.config, width: k.width, height: k.height}
+	return box.createStyledBox(content, 4, 2)
+}
+
+func (k KioskModel) place(content string) string {
+	background := lipgloss.Color(k.config.Theme.Colors.Background)
+	placed := lipgloss.Place(k.width, k.height, lipgloss.Center, lipgloss.Center, content,
+		lipgloss.WithWhitespaceBackground(background))
+	return lipgloss.NewStyle().
+		Width(k.width).
+		Height(k.height).
+		Background(background).
+		Render(placed)
+}
@@ -0,0 +1,246 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"gti/src/internal/config"
+	"gti/src/internal/keymap"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// MenuItem is an entry of the start menu
+type MenuItem string
+
+const (
+	MenuPractice   MenuItem = "Practice"
+	MenuTimed      MenuItem = "Timed"
+	MenuQuote      MenuItem = "Quote"
+	MenuCode       MenuItem = "Code"
+	MenuChallenge  MenuItem = "Challenge"
+	MenuStatistics MenuItem = "Statistics"
+	MenuSettings   MenuItem = "Settings"
+	MenuQuit       MenuItem = "Quit"
+)
+
+var menuItems = []struct {
+	item MenuItem
+	desc string
+}{
+	{MenuPractice, "Generated words, a couple of chunks at a time"},
+	{MenuTimed, "Type against the clock"},
+	{MenuQuote, "Type famous quotes"},
+	{MenuCode, "Type code snippets"},
+	{MenuChallenge, "Level up through harder and harder texts"},
+	{MenuStatistics, "Your speed, accuracy and streaks"},
+	{MenuSettings, "See the current configuration"},
+	{MenuQuit, "Back to the shell"},
+}
+
+// MenuModel is the home screen shown by bare `gti` when start_menu is on. It returns the item picked
+// with Enter, except Settings, which it shows itself.
+type MenuModel struct {
+	config *config.Config
+	keys   *keymap.Bindings
+	cursor int
+	// Chosen is the item picked with Enter, MenuQuit when the menu was left
+	Chosen   MenuItem
+	settings bool
+	// settingsOffset scrolls the settings list
+	settingsOffset int
+	width          int
+	height         int
+}
+
+// NewMenuModel opens the menu on selected, so coming back from a session lands on the item that started it
+func NewMenuModel(cfg *config.Config, selected MenuItem) MenuModel {
+	m := MenuModel{
+		config: cfg,
+		keys:   keymap.NewBindings(cfg.Keybindings),
+		Chosen: MenuQuit,
+	}
+	for i, it := range menuItems {
+		if it.item == selected {
+			m.cursor = i
+		}
+	}
+	return m
+}
+
+func (m MenuModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case tea.KeyMsg:
+		if m.keys.Is(msg, keymap.ActionForceQuit) {
+			return m, tea.Quit
+		}
+		if m.settings {
+			return m.handleSettingsKey(msg)
+		}
+		switch {
+		case m.keys.Is(msg, keymap.ActionStatsQuit), m.keys.Is(msg, keymap.ActionBack):
+			return m, tea.Quit
+		case m.keys.Is(msg, keymap.ActionStatsUp):
+			m.cursor = (m.cursor + len(menuItems) - 1) % len(menuItems)
+		case m.keys.Is(msg, keymap.ActionStatsDown):
+			m.cursor = (m.cursor + 1) % len(menuItems)
+		case m.keys.Is(msg, keymap.ActionNext):
+			item := menuItems[m.cursor].item
+			if item == MenuSettings {
+				m.settings = true
+				m.settingsOffset = 0
+				return m, nil
+			}
+			m.Chosen = item
+			return m, tea.Quit
+		}
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress {
+			break
+		}
+		if m.settings {
+			return m.handleSettingsMouse(msg)
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.cursor = max(m.cursor-1, 0)
+		case tea.MouseButtonWheelDown:
+			m.cursor = min(m.cursor+1, len(menuItems)-1)
+		case tea.MouseButtonLeft:
+			i := msg.Y - m.menuTop()
+			if i < 0 || i >= len(menuItems) {
+				break
+			}
+			m.cursor = i
+			if menuItems[i].item == MenuSettings {
+				m.settings = true
+				m.settingsOffset = 0
+				return m, nil
+			}
+			m.Chosen = menuItems[i].item
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// menuTop is the screen row of the first menu item. The menu is centred below the banner and a blank
+// line, with a blank line and the footer under it, and lipgloss.Place puts the odd row of space below.
+func (m MenuModel) menuTop() int {
+	contentHeight := len(kioskBanner) + 1 + len(menuItems) + 2
+	gap := max(m.height-contentHeight, 0)
+	return gap - (gap+1)/2 + len(kioskBanner) + 1
+}
+
+// handleSettingsMouse scrolls the settings list with the wheel; a click goes back to the menu
+func (m MenuModel) handleSettingsMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	maxOffset := max(len(config.Keys(m.config))-m.settingsRows(), 0)
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.settingsOffset = max(m.settingsOffset-1, 0)
+	case tea.MouseButtonWheelDown:
+		m.settingsOffset = min(m.settingsOffset+1, maxOffset)
+	case tea.MouseButtonLeft:
+		m.settings = false
+	}
+	return m, nil
+}
+
+func (m MenuModel) handleSettingsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows := m.settingsRows()
+	maxOffset := max(len(config.Keys(m.config))-rows, 0)
+	switch {
+	case m.keys.Is(msg, keymap.ActionStatsQuit), m.keys.Is(msg, keymap.ActionBack), m.keys.Is(msg, keymap.ActionNext):
+		m.settings = false
+	case m.keys.Is(msg, keymap.ActionStatsUp):
+		m.settingsOffset = max(m.settingsOffset-1, 0)
+	case m.keys.Is(msg, keymap.ActionStatsDown):
+		m.settingsOffset = min(m.settingsOffset+1, maxOffset)
+	case m.keys.Is(msg, keymap.ActionPageUp):
+		m.settingsOffset = max(m.settingsOffset-rows, 0)
+	case m.keys.Is(msg, keymap.ActionPageDown):
+		m.settingsOffset = min(m.settingsOffset+rows, maxOffset)
+	}
+	return m, nil
+}
+
+// settingsRows is how many settings fit between the settings header and footer
+func (m MenuModel) settingsRows() int {
+	return max(m.height-6, 3)
+}
+
+func (m MenuModel) View() string {
+	if m.width < 40 || m.height < 10 {
+		return "Terminal too small. Please resize to at least 40x10."
+	}
+	if m.settings {
+		return m.viewSettings()
+	}
+
+	colors := m.config.Theme.Colors
+	bg := lipgloss.Color(colors.Background)
+	banner := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(colors.Current)).
+		Background(bg).
+		Bold(true).
+		Render(strings.Join(kioskBanner, "\n"))
+
+	var rows []string
+	for i, it := range menuItems {
+		label := fmt.Sprintf("  %-12s", it.item)
+		style := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.TextPrimary)).Background(bg)
+		if i == m.cursor {
+			label = fmt.Sprintf("> %-12s", it.item)
+			style = style.Foreground(lipgloss.Color(colors.Accent)).Bold(true)
+		}
+		desc := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.TextSecondary)).Background(bg).Render(it.desc)
+		rows = append(rows, style.Render(label)+desc)
+	}
+	menu := lipgloss.NewStyle().Background(bg).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+
+	footer := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.TextSecondary)).Background(bg).Faint(true).
+		Render(fmt.Sprintf("[%s/%s] Move   [%s] Select   [%s] Quit",
+			m.keys.Label(keymap.ActionStatsUp), m.keys.Label(keymap.ActionStatsDown),
+			m.keys.Label(keymap.ActionNext), m.keys.Label(keymap.ActionStatsQuit)))
+
+	content := lipgloss.JoinVertical(lipgloss.Center, banner, "", menu, "", footer)
+	return m.place(content)
+}
+
+// viewSettings lists every setting with its value, read-only; changes go through gti config set
+func (m MenuModel) viewSettings() string {
+	colors := m.config.Theme.Colors
+	bg := lipgloss.Color(colors.Background)
+	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.TextSecondary)).Background(bg)
+	valStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.TextPrimary)).Background(bg)
+	title := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Accent)).Background(bg).Bold(true)
+
+	keys := config.Keys(m.config)
+	end := min(m.settingsOffset+m.settingsRows(), len(keys))
+	var rows []string
+	for _, key := range keys[m.settingsOffset:end] {
+		value, _ := config.Get(m.config, key)
+		rows = append(rows, keyStyle.Render(fmt.Sprintf("%-36s ", key))+valStyle.Render(value))
+	}
+
+	header := title.Render("SETTINGS") + keyStyle.Render("  "+config.ConfigFile)
+	footer := keyStyle.Faint(true).Render(fmt.Sprintf("[%s/%s] Scroll   [%s] Back   Change a setting with: gti config set <key> <value>",
+		m.keys.Label(keymap.ActionStatsUp), m.keys.Label(keymap.ActionStatsDown), m.keys.Label(keymap.ActionBack)))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, header, "", lipgloss.JoinVertical(lipgloss.Left, rows...), "", footer)
+	return m.place(content)
+}
+
+func (m MenuModel) place(content string) string {
+	background := lipgloss.Color(m.config.Theme.Colors.Background)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content,
+		lipgloss.WithWhitespaceBackground(background))
+}
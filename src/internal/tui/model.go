@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"gti/src/internal"
+	"gti/src/internal/card"
 	"gti/src/internal/config"
+	"gti/src/internal/keymap"
 	"gti/src/internal/session"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -16,14 +18,16 @@ import (
 type Mode string
 
 const (
-	ModeTyping  Mode = "typing"
-	ModeHelp    Mode = "help"
-	ModeResults Mode = "results"
-	ModeQuit    Mode = "quit"
+	ModeTyping      Mode = "typing"
+	ModeHelp        Mode = "help"
+	ModeResults     Mode = "results"
+	ModePause       Mode = "pause"
+	ModeSaveSnippet Mode = "save-snippet"
 )
 
 type Model struct {
 	config    *config.Config
+	keys      *keymap.Bindings
 	mode      Mode
 	sess      *session.Session
 	startTime time.Time
@@ -31,6 +35,30 @@ type Model struct {
 	quitting  bool
 	width     int
 	height    int
+
+	snippetName string
+	notice      string
+	// shownResults counts result screens so a stale auto-dismiss tick can tell it is out of date
+	shownResults int
+	// resizes counts size changes, so only the tick after the last of a burst lays the screen out again
+	resizes                     int
+	pendingWidth, pendingHeight int
+	// opts are the options the model was started with, applied again to a rerun's new session
+	opts ModelOptions
+}
+
+// resizeSettle is how long the terminal has to keep its size before the screen is laid out for it;
+// dragging a window edge sends a stream of sizes and only the last one matters
+const resizeSettle = 80 * time.Millisecond
+
+// resizeSettledMsg applies the size of the resize it was scheduled for, if no other came after it
+type resizeSettledMsg struct {
+	resize int
+}
+
+// resultsTimeoutMsg auto-dismisses the results screen it was scheduled for
+type resultsTimeoutMsg struct {
+	shown int
 }
 
 type ModelOptions struct {
@@ -39,6 +67,14 @@ type ModelOptions struct {
 	Start   int
 	Seconds int
 	Session *session.Session
+	BotWPM  float64
+	// FocusReps loops mistyped words until typed cleanly this many times in a row, 0 to disable
+	FocusReps int
+	// TimingFile receives the keystroke timing export; AudioMarkers adds bell sync markers to it
+	TimingFile   string
+	AudioMarkers bool
+	// Unrecorded keeps the tests out of the history and the completion hook, reruns included
+	Unrecorded bool
 }
 
 func NewModel(cfg *config.Config, opts ModelOptions) Model {
@@ -56,26 +92,68 @@ func NewModel(cfg *config.Config, opts ModelOptions) Model {
 				TimeLimit: time.Duration(opts.Seconds) * time.Second,
 			})
 		} else {
-			paragraphs := session.LoadParagraphs(opts.File)
+			paragraphs := session.LoadParagraphs(opts.File, cfg.Import)
 			text := session.GetParagraphAtStart(paragraphs, opts.Start)
 			sess = session.NewSessionTimed(cfg, "custom-timed", text, paragraphs, opts.Start-1, opts.Seconds)
 		}
 	} else if opts.File != "" {
 		sess = session.NewSessionWithCustomText(cfg, opts.Mode, opts.File, opts.Start)
 	} else if opts.Seconds > 0 {
-		text := internal.GenerateWordsDynamic(session.DefaultWordCount, cfg.Language.Default)
+		_, timed := cfg.ForMode("timed")
+		text := internal.GenerateWordsDynamic(timed.Words(session.DefaultWordCount), cfg.Language.Default)
 		sess = session.NewSessionTimed(cfg, "timed", text, nil, 0, opts.Seconds)
 	} else {
 		sess = session.NewSession(cfg, opts.Mode)
 	}
 
+	applyOptions(sess, opts)
+
 	return Model{
 		config: cfg,
+		keys:   keymap.NewBindings(cfg.Keybindings),
 		mode:   ModeTyping,
 		sess:   sess,
+		opts:   opts,
 	}
 }
 
+// applyOptions sets up the opponent, focus loop, timing export and recording the options ask for
+func applyOptions(sess *session.Session, opts ModelOptions) {
+	if opts.BotWPM > 0 {
+		sess.SetBot(opts.BotWPM)
+	}
+	sess.SetFocusReps(opts.FocusReps)
+	if opts.TimingFile != "" {
+		sess.EnableTimingExport(opts.TimingFile, opts.AudioMarkers)
+	}
+	if opts.Unrecorded {
+		sess.Unrecorded()
+	}
+}
+
+// rerunSeconds are the lengths of the timed tests the results screen can start straight away
+var rerunSeconds = []struct {
+	action  keymap.Action
+	seconds int
+}{
+	{keymap.ActionRerun15, 15},
+	{keymap.ActionRerun30, 30},
+	{keymap.ActionRerun60, 60},
+	{keymap.ActionRerun120, 120},
+}
+
+// rerunTimed replaces the finished session with a timed test of fresh words lasting seconds
+func (m *Model) rerunTimed(seconds int) tea.Cmd {
+	_, timed := m.config.ForMode("timed")
+	text := internal.GenerateWordsDynamic(timed.Words(session.DefaultWordCount), m.config.Language.Default)
+	m.sess = session.NewSessionTimed(m.config, "timed", text, nil, 0, seconds)
+	applyOptions(m.sess, m.opts)
+	m.sess.MarkLayoutDirty()
+	m.mode = ModeTyping
+	m.notice = ""
+	return m.sess.Start()
+}
+
 func NewModelWithCustomText(cfg *config.Config, mode, file string, start int) Model {
 	return NewModel(cfg, ModelOptions{Mode: mode, File: file, Start: start})
 }
@@ -92,6 +170,11 @@ func NewModelWithSession(cfg *config.Config, sess *session.Session) Model {
 	return NewModel(cfg, ModelOptions{Session: sess})
 }
 
+// Session is the typing session the model runs
+func (m Model) Session() *session.Session {
+	return m.sess
+}
+
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		tea.EnterAltScreen,
@@ -104,19 +187,70 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		return m.handleKey(msg)
 	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-		m.sess.MarkLayoutDirty()
+		m.pendingWidth, m.pendingHeight = msg.Width, msg.Height
+		if m.width == 0 && m.height == 0 {
+			// The size sent at startup is laid out at once, there being nothing to show before it
+			m.applySize()
+			return m, nil
+		}
+		m.resizes++
+		resize := m.resizes
+		return m, tea.Tick(resizeSettle, func(time.Time) tea.Msg {
+			return resizeSettledMsg{resize: resize}
+		})
+	case resizeSettledMsg:
+		if msg.resize == m.resizes {
+			m.applySize()
+		}
 		return m, nil
 	case session.SessionCompleteMsg:
 		m.mode = ModeResults
-		return m, nil
+		m.shownResults++
+		return m, m.scheduleResultsDismiss()
+	case resultsTimeoutMsg:
+		if m.mode != ModeResults || msg.shown != m.shownResults {
+			return m, nil
+		}
+		if m.config.Results.AutoChain {
+			m.mode = ModeTyping
+			m.notice = ""
+			return m, m.sess.NextTest()
+		}
+		m.quitting = true
+		return m, tea.Quit
 	case session.TimerTickMsg:
 		return m, m.sess.UpdateTimer()
+	case session.IdleMsg:
+		if m.mode == ModeTyping || m.mode == ModeHelp {
+			m.mode = ModePause
+		}
+		return m, nil
+	case session.ChunkSummaryDoneMsg:
+		m.sess.EndChunkSummary(msg)
+		return m, nil
 	}
 	return m, nil
 }
 
+// applySize lays the screen out for the latest terminal size. The text is wrapped again for the new
+// width on the next frame, and its window is placed around the cursor, so the cursor stays in view.
+func (m *Model) applySize() {
+	m.width, m.height = m.pendingWidth, m.pendingHeight
+	m.sess.MarkLayoutDirty()
+}
+
+// scheduleResultsDismiss arranges for the results screen to close itself when auto-dismiss is configured
+func (m Model) scheduleResultsDismiss() tea.Cmd {
+	seconds := m.config.Results.AutoDismissSeconds
+	if seconds <= 0 {
+		return nil
+	}
+	shown := m.shownResults
+	return tea.Tick(time.Duration(seconds)*time.Second, func(time.Time) tea.Msg {
+		return resultsTimeoutMsg{shown: shown}
+	})
+}
+
 func (m Model) View() string {
 	if m.width < 40 || m.height < 10 {
 		return "Terminal too small. Please resize to at least 40x10.\nPress Ctrl+C to quit."
@@ -128,8 +262,10 @@ func (m Model) View() string {
 		return m.viewHelp()
 	case ModeResults:
 		return m.viewResults()
-	case ModeQuit:
-		return m.viewQuit()
+	case ModePause:
+		return m.viewPause()
+	case ModeSaveSnippet:
+		return m.viewSaveSnippet()
 	default:
 		return "Unknown mode"
 	}
@@ -140,66 +276,169 @@ func (m *Model) handleKey(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case ModeTyping:
 		return m.handleTypingKey(key)
 	case ModeHelp:
-		if key.String() == "esc" {
+		if m.keys.Is(key, keymap.ActionBack) {
 			m.mode = ModeTyping
 		}
 		return m, nil
 	case ModeResults:
-		if key.String() == "enter" {
+		if m.keys.Is(key, keymap.ActionNext) {
 			m.mode = ModeTyping
+			m.notice = ""
 			return m, m.sess.Restart()
 		}
-		if key.String() == "esc" {
+		if m.keys.Is(key, keymap.ActionRepeat) {
+			m.mode = ModeTyping
+			m.notice = ""
+			return m, m.sess.Repeat()
+		}
+		if m.keys.Is(key, keymap.ActionSaveCard) {
+			m.saveCard()
+			return m, nil
+		}
+		for _, rerun := range rerunSeconds {
+			if m.keys.Is(key, rerun.action) {
+				return m, m.rerunTimed(rerun.seconds)
+			}
+		}
+		if m.keys.Is(key, keymap.ActionSaveSnippet) && m.sess.IsCustomCode() {
+			m.mode = ModeSaveSnippet
+			m.snippetName = ""
+			return m, nil
+		}
+		if m.keys.Is(key, keymap.ActionBack) {
 			m.quitting = true
 			return m, tea.Quit
 		}
 		return m, nil
-	case ModeQuit:
-		if key.String() == "y" || key.String() == "Y" {
-			m.quitting = true
-			return m, tea.Quit
+	case ModePause:
+		return m.handlePauseKey(key)
+	case ModeSaveSnippet:
+		return m.handleSaveSnippetKey(key)
+	}
+	return m, nil
+}
+
+// handleSaveSnippetKey edits the snippet name and saves the typed code to the personal pack on Enter
+func (m *Model) handleSaveSnippetKey(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key.Type {
+	case tea.KeyEsc:
+		m.mode = ModeResults
+	case tea.KeyEnter:
+		if err := internal.SavePersonalSnippet(m.sess.GetLanguage(), m.snippetName, m.sess.GetText()); err != nil {
+			m.notice = "Could not save snippet: " + err.Error()
+		} else {
+			m.notice = fmt.Sprintf("Saved '%s' to your %s snippets", strings.TrimSpace(m.snippetName), m.sess.GetLanguage())
 		}
+		m.mode = ModeResults
+	case tea.KeyBackspace:
+		if len(m.snippetName) > 0 {
+			runes := []rune(m.snippetName)
+			m.snippetName = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.snippetName += string(key.Runes)
+	}
+	return m, nil
+}
+
+// saveCard saves a shareable card of the results to the Downloads folder
+func (m *Model) saveCard() {
+	file := card.DefaultFile()
+	if err := card.Save(file, session.NewResultsCalculator().BuildRecord(m.sess), m.config.Theme.Colors); err != nil {
+		m.notice = "Could not save the result card: " + err.Error()
+		return
+	}
+	m.notice = "Saved the result card to " + file
+}
+
+// handlePauseKey resumes, restarts, or ends the paused session, saving it as partial if asked to.
+// Quitting a timed test keeps it as partial too.
+func (m *Model) handlePauseKey(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case m.keys.Is(key, keymap.ActionForceQuit):
+		m.quitting = true
+		return m, tea.Quit
+	case m.keys.Is(key, keymap.ActionQuit) && !m.keys.Is(key, keymap.ActionPause):
+		return m.quitPaused()
+	case m.keys.Is(key, keymap.ActionBack) || m.keys.Is(key, keymap.ActionPause) || key.Type == tea.KeyEnter:
 		m.mode = ModeTyping
-		return m, nil
+		return m, m.sess.Unpause()
+	}
+
+	switch key.String() {
+	case "r", "R":
+		m.mode = ModeTyping
+		return m, m.sess.Restart()
+	case "s", "S":
+		m.sess.SavePartial()
+		m.quitting = true
+		return m, tea.Quit
+	case "q", "Q":
+		return m.quitPaused()
 	}
 	return m, nil
 }
 
+// quitPaused leaves from the pause screen, keeping the time practised in a timed test
+func (m *Model) quitPaused() (tea.Model, tea.Cmd) {
+	if m.sess.KeepsOnQuit() {
+		m.sess.SavePartial()
+	}
+	m.quitting = true
+	return m, tea.Quit
+}
+
 func (m *Model) handleTypingKey(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Handle scrolling for code mode
-	isCodeMode := strings.Contains(m.sess.GetMode(), "code") || m.sess.GetMode() == "snippet"
-	if isCodeMode {
-		switch key.Type {
-		case tea.KeyUp:
+	if m.sess.IsCodeMode() {
+		switch {
+		case m.keys.Is(key, keymap.ActionScrollUp):
 			m.sess.ScrollUp()
 			return m, nil
-		case tea.KeyDown:
+		case m.keys.Is(key, keymap.ActionScrollDown):
 			m.sess.ScrollDown()
 			return m, nil
-		case tea.KeyPgUp:
+		case m.keys.Is(key, keymap.ActionPageUp):
 			m.sess.ScrollUpPage()
 			return m, nil
-		case tea.KeyPgDown:
+		case m.keys.Is(key, keymap.ActionPageDown):
 			m.sess.ScrollDownPage()
 			return m, nil
 		}
 	}
 
-	switch key.String() {
-	case "ctrl+c":
+	switch {
+	case m.keys.Is(key, keymap.ActionForceQuit):
 		m.quitting = true
 		return m, tea.Quit
-	case "ctrl+q":
-		m.mode = ModeQuit
+	case m.keys.Is(key, keymap.ActionQuit) || m.keys.Is(key, keymap.ActionPause):
+		if m.sess.EndsOnPause() {
+			if cmd := m.sess.Stop(); cmd != nil {
+				return m, cmd
+			}
+		}
+		m.sess.Pause()
+		if !m.sess.IsPaused() {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		m.mode = ModePause
 		return m, nil
-	case "ctrl+h":
+	case m.keys.Is(key, keymap.ActionHelp):
 		m.mode = ModeHelp
 		return m, nil
-	case "ctrl+w":
+	case m.keys.Is(key, keymap.ActionTTS):
 		m.sess.ToggleContext()
 		return m, nil
-	case "esc":
+	case m.keys.Is(key, keymap.ActionCheatSheet):
+		m.sess.ToggleCheatSheet()
+		return m, nil
+	case m.keys.Is(key, keymap.ActionRestart):
 		return m, m.sess.Restart()
+	case m.keys.Is(key, keymap.ActionRepeat):
+		return m, m.sess.Repeat()
+	case m.keys.Is(key, keymap.ActionSkip):
+		return m, m.sess.NextTest()
 	default:
 		return m, m.sess.HandleInput(key)
 	}
@@ -244,30 +483,127 @@ func (m Model) createStyledBox(content string, paddingX, paddingY int) string {
 }
 
 func (m Model) viewHelp() string {
-	helpText := "Help overlay - Press ESC to close\n\nShortcuts:\nCtrl+Q: Quit\nCtrl+C: Force quit\nEsc: Restart\nCtrl+H: Help\nCtrl+W: TTS\nBackspace: Delete\nLeft/Right: Navigate segments"
+	helpText := fmt.Sprintf("Help overlay - Press %s to close\n\nShortcuts:\n%s: Pause\n%s: Force quit\n%s: Restart\n%s: Repeat the same text\n%s: Skip to new text\n%s: Help\n%s: TTS\n%s: Key cheat sheet\nBackspace: Delete\nLeft/Right: Navigate segments",
+		m.keys.Label(keymap.ActionBack), m.keys.Label(keymap.ActionPause), m.keys.Label(keymap.ActionForceQuit), m.keys.Label(keymap.ActionRestart), m.keys.Label(keymap.ActionRepeat), m.keys.Label(keymap.ActionSkip),
+		m.keys.Label(keymap.ActionHelp), m.keys.Label(keymap.ActionTTS), m.keys.Label(keymap.ActionCheatSheet))
 	return m.createStyledBox(helpText, 2, 1)
 }
 
 func (m Model) viewResults() string {
 	calculator := session.NewResultsCalculator()
-	results := calculator.CalculateResults(m.sess, m.sess.GetMode())
+	results := calculator.CalculateResults(m.sess)
 
+	// Raw WPM counts every character typed; net WPM takes off a word for each uncorrected error
 	content := fmt.Sprintf(`Results
 
-WPM: %.1f
-Accuracy: %.1f%%
-CPM: %.1f
-Duration: %.2fs
-Mistakes: %d
+Net WPM: %.1f   Raw WPM: %.1f
+Accuracy: %.1f%%   CPM: %.1f
+Duration: %.2fs   Mistakes: %d
+`, results.NetWPM, results.WPM, results.Accuracy, results.CPM, results.Duration.Seconds(), results.Mistakes)
+	if graph := renderRunGraph(m.sess.GetSamples(), m.width-12, m.config.Theme.Colors); graph != "" {
+		content += "\n" + graph + "\n"
+	}
+	if fatigue := m.sess.FatigueSummary(); fatigue != "" {
+		content += fatigue + "\n"
+	}
+	if percentile := m.sess.PercentileSummary(); percentile != "" {
+		content += percentile + "\n"
+	}
 
-Press Enter to restart or Esc to exit`, results.WPM, results.Accuracy, results.CPM, results.Duration.Seconds(), results.Mistakes)
+	if results.UnitName != "" {
+		content += fmt.Sprintf("%s accuracy: %.1f%% (%d/%d)\n", strings.Title(results.UnitName), session.CalculateAccuracy(results.Units, results.Units-results.CorrectUnits), results.CorrectUnits, results.Units)
+	}
+	if results.StructuralErrors > 0 {
+		content += fmt.Sprintf("Structural errors: %d (x%d weight)\n", results.StructuralErrors, session.StructuralMistakeWeight)
+	}
+	if mistakes := m.sess.MistakeSummary(); mistakes != "" {
+		content += "\n" + mistakes + "\n"
+	}
+	if words := m.sess.WordSpeedSummary(); words != "" {
+		content += "\n" + words + "\n"
+	}
+	if bot := m.sess.BotSummary(); bot != "" {
+		content += "\n" + bot + "\n"
+	}
+	if timing := m.sess.TimingExportSummary(); timing != "" {
+		content += "\n" + timing + "\n"
+	}
+	if result := m.sess.ProtocolResultSummary(); result != "" {
+		content += "\n" + result + "\n"
+	}
+	if result := m.sess.StandardResultSummary(); result != "" {
+		content += "\n" + result + "\n"
+	}
+	if lesson := m.sess.LessonResultSummary(); lesson != "" {
+		content += "\n" + lesson + "\n"
+	}
+	if pasted := m.sess.PasteResultSummary(); pasted != "" {
+		content += "\n" + pasted + "\n"
+	}
+	if hook := m.sess.HookResultSummary(); hook != "" {
+		content += "\n" + hook + "\n"
+	}
+	if m.notice != "" {
+		content += "\n" + m.notice + "\n"
+	}
+	if seconds := m.config.Results.AutoDismissSeconds; seconds > 0 {
+		if m.config.Results.AutoChain {
+			content += fmt.Sprintf("\nNext test starts in %ds", seconds)
+		} else {
+			content += fmt.Sprintf("\nClosing in %ds", seconds)
+		}
+	}
+	content += fmt.Sprintf("\nPress %s to restart, %s to repeat the same text or %s to exit", m.keys.Label(keymap.ActionNext), m.keys.Label(keymap.ActionRepeat), m.keys.Label(keymap.ActionBack))
+	content += fmt.Sprintf("\nPress %s to save a result card to share", m.keys.Label(keymap.ActionSaveCard))
+	content += fmt.Sprintf("\nPress %s/%s/%s/%s for a 15/30/60/120 second timed test",
+		m.keys.Label(keymap.ActionRerun15), m.keys.Label(keymap.ActionRerun30), m.keys.Label(keymap.ActionRerun60), m.keys.Label(keymap.ActionRerun120))
+	if m.sess.IsCustomCode() {
+		content += fmt.Sprintf("\nPress %s to save this code as a snippet", m.keys.Label(keymap.ActionSaveSnippet))
+	}
 
 	return m.createStyledBox(content, 4, 3)
 }
 
-func (m Model) viewQuit() string {
-	quitText := `Are you sure you want to quit?
+func (m Model) viewSaveSnippet() string {
+	content := fmt.Sprintf(`Save to your %s snippets
 
-"Quit?" (y/n)`
-	return m.createStyledBox(quitText, 4, 2)
+Name: %s_
+
+Press Enter to save or Esc to cancel`, m.sess.GetLanguage(), m.snippetName)
+	return m.createStyledBox(content, 4, 2)
+}
+
+func (m Model) viewPause() string {
+	results := session.NewResultsCalculator().CalculateResults(m.sess)
+
+	// Timed tests have no end to make progress towards, so they show how much of the time is used instead
+	progress := fmt.Sprintf("Progress: %.0f%%", m.sess.GetStatsSnapshot().Progress)
+	if limit := m.sess.GetTimeLimit(); limit > 0 {
+		progress = fmt.Sprintf("Time left: %s", (limit - results.Duration).Truncate(time.Second))
+	}
+
+	quit := "S: Save as a partial session and quit\nQ: Quit without saving"
+	if m.sess.KeepsOnQuit() {
+		quit = fmt.Sprintf("Q or %s: Quit, keeping what you typed as a partial session", m.keys.Label(keymap.ActionQuit))
+	}
+
+	title := "Paused"
+	if m.sess.IsIdle() {
+		title = fmt.Sprintf("Idle: paused after %ds without a key press", m.config.Idle.Seconds)
+	}
+
+	content := fmt.Sprintf(`%s
+
+Elapsed: %s
+WPM: %.1f
+Accuracy: %.1f%%
+Mistakes: %d
+Characters typed: %d
+%s
+
+%s or Enter: Resume
+R: Restart
+%s`, title, results.Duration.Truncate(time.Second), results.WPM, results.Accuracy, results.Mistakes, results.TotalChars, progress,
+		m.keys.Label(keymap.ActionBack), quit)
+	return m.createStyledBox(content, 4, 2)
 }
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"os"
@@ -10,7 +11,9 @@ import (
 	"time"
 
 	"gti/src/internal/config"
+	"gti/src/internal/keymap"
 	"gti/src/internal/session"
+	"gti/src/internal/vault"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -70,15 +73,35 @@ const (
 	ViewSession StatisticsView = "session"
 )
 
+// viewTabs are the views in the order the selector shows them
+var viewTabs = []struct {
+	view  StatisticsView
+	label string
+}{
+	{ViewSession, "SESSION"},
+	{ViewDaily, "DAILY"},
+	{ViewWeekly, "WEEKLY"},
+	{ViewAllTime, "ALL-TIME"},
+}
+
+// viewSelectorRow is the screen row of the view selector, below the title and its rule
+const viewSelectorRow = 2
+
 type StatisticsModel struct {
 	config   *config.Config
+	keys     *keymap.Bindings
 	view     StatisticsView
 	records  []*session.SessionRecord
 	stats    *Statistics
+	// lifetime is the daily rollups merged with the records, covering days since pruned from the history
+	lifetime []session.DailyRollup
 	width    int
 	height   int
 	quitting bool
 
+	// percentiles places speeds among typists, nil when that is turned off or the table is unreadable
+	percentiles session.PercentileTable
+
 	cachedView            StatisticsView
 	cachedFilteredRecords []*session.SessionRecord
 	cachedFilteredStats   *Statistics
@@ -118,6 +141,8 @@ type Statistics struct {
 
 	CurrentStreak int
 	LongestStreak int
+	// FreezesLeft is how many missed days this month the current streak can still survive
+	FreezesLeft int
 }
 
 type statsStyles struct {
@@ -210,10 +235,16 @@ func NewStatisticsModel(cfg *config.Config) StatisticsModel {
 	records, _ := session.LoadSessionRecords(cfg)
 
 	m := StatisticsModel{
-		config:  cfg,
-		view:    ViewAllTime,
-		records: records,
-		stats:   calculateStatistics(records),
+		config:   cfg,
+		keys:     keymap.NewBindings(cfg.Keybindings),
+		view:     ViewAllTime,
+		records:  records,
+		lifetime: session.LifetimeDays(cfg, records),
+	}
+	m.stats = calculateStatistics(records, cfg.Streaks)
+	applyLifetime(m.stats, m.lifetime)
+	if cfg.Results.Percentiles {
+		m.percentiles, _ = session.LoadPercentiles(cfg)
 	}
 	m.styles = newStatsStyles(cfg)
 
@@ -263,6 +294,16 @@ func (m StatisticsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		return m.handleKey(msg)
+	case tea.MouseMsg:
+		// A click on the view selector switches view; the wheel falls through to scroll the viewport
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			if msg.Y == viewSelectorRow {
+				if view, ok := m.viewAt(msg.X); ok {
+					m.setView(view)
+				}
+			}
+			return m, nil
+		}
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -304,7 +345,10 @@ func (m StatisticsModel) View() string {
 
 	viewportContent := m.viewport.View()
 
-	footer := "\n" + s.footer.Render("[q] Quit   [s] Switch View   [h/l] Navigate   [e] Export   [↑/↓] Scroll   [PgUp/PgDn] Page")
+	footer := "\n" + s.footer.Render(fmt.Sprintf("[%s] Quit   [%s] Switch View   [%s/%s] Navigate   [%s] Export   [%s/%s] Scroll   [%s/%s] Page",
+		m.keys.Label(keymap.ActionStatsQuit), m.keys.Label(keymap.ActionStatsSwitch), m.keys.Label(keymap.ActionStatsPrev), m.keys.Label(keymap.ActionStatsNext),
+		m.keys.Label(keymap.ActionStatsExport), m.keys.Label(keymap.ActionStatsUp), m.keys.Label(keymap.ActionStatsDown),
+		m.keys.Label(keymap.ActionPageUp), m.keys.Label(keymap.ActionPageDown)))
 
 	content := header + viewportContent + footer
 
@@ -312,32 +356,32 @@ func (m StatisticsModel) View() string {
 }
 
 func (m *StatisticsModel) handleKey(key tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch key.String() {
-	case "q", "ctrl+c":
+	switch {
+	case m.keys.Is(key, keymap.ActionStatsQuit):
 		m.quitting = true
 		return m, tea.Quit
-	case "s":
+	case m.keys.Is(key, keymap.ActionStatsSwitch):
 		m.switchView()
 		return m, nil
-	case "h":
+	case m.keys.Is(key, keymap.ActionStatsPrev):
 		m.previousView()
 		return m, nil
-	case "l":
+	case m.keys.Is(key, keymap.ActionStatsNext):
 		m.nextView()
 		return m, nil
-	case "e":
+	case m.keys.Is(key, keymap.ActionStatsExport):
 		m.exportStatistics()
 		return m, nil
-	case "up", "k":
+	case m.keys.Is(key, keymap.ActionStatsUp):
 		m.viewport.LineUp(1)
 		return m, nil
-	case "down", "j":
+	case m.keys.Is(key, keymap.ActionStatsDown):
 		m.viewport.LineDown(1)
 		return m, nil
-	case "pgup":
+	case m.keys.Is(key, keymap.ActionPageUp):
 		m.viewport.HalfViewUp()
 		return m, nil
-	case "pgdown":
+	case m.keys.Is(key, keymap.ActionPageDown):
 		m.viewport.HalfViewDown()
 		return m, nil
 	}
@@ -371,6 +415,15 @@ func (m *StatisticsModel) exportStatistics() {
 
 	filePath := filepath.Join(exportDir, filename)
 
+	if m.config.History.Encrypt {
+		// An encrypted history is not exported in the clear; 'gti decrypt' reads the file back
+		data, err := json.MarshalIndent(exportData, "", "  ")
+		if err != nil {
+			return
+		}
+		vault.WriteFile(m.config, filePath+".sealed", data)
+		return
+	}
 	if err := config.SaveJSONData(filePath, exportData); err != nil {
 		return
 	}
@@ -380,21 +433,13 @@ func (m *StatisticsModel) exportStatistics() {
 func (m *StatisticsModel) switchView() {
 	switch m.view {
 	case ViewAllTime:
-		m.view = ViewWeekly
+		m.setView(ViewWeekly)
 	case ViewWeekly:
-		m.view = ViewDaily
+		m.setView(ViewDaily)
 	case ViewDaily:
-		m.view = ViewSession
+		m.setView(ViewSession)
 	case ViewSession:
-		m.view = ViewAllTime
-	}
-
-	if m.cachedView != m.view {
-		m.cachedView = m.view
-		m.cachedFilteredRecords = m.getFilteredRecords()
-		m.cachedFilteredStats = calculateStatistics(m.cachedFilteredRecords)
-		m.viewport.SetContent(m.renderScrollableContent())
-		m.viewport.GotoTop()
+		m.setView(ViewAllTime)
 	}
 }
 
@@ -403,19 +448,23 @@ func (m *StatisticsModel) nextView() { m.switchView() }
 func (m *StatisticsModel) previousView() {
 	switch m.view {
 	case ViewAllTime:
-		m.view = ViewSession
+		m.setView(ViewSession)
 	case ViewSession:
-		m.view = ViewDaily
+		m.setView(ViewDaily)
 	case ViewDaily:
-		m.view = ViewWeekly
+		m.setView(ViewWeekly)
 	case ViewWeekly:
-		m.view = ViewAllTime
+		m.setView(ViewAllTime)
 	}
+}
 
+// setView shows view, refreshing the content scrolled to the top
+func (m *StatisticsModel) setView(view StatisticsView) {
+	m.view = view
 	if m.cachedView != m.view {
 		m.cachedView = m.view
 		m.cachedFilteredRecords = m.getFilteredRecords()
-		m.cachedFilteredStats = calculateStatistics(m.cachedFilteredRecords)
+		m.cachedFilteredStats = m.getFilteredStats()
 		m.viewport.SetContent(m.renderScrollableContent())
 		m.viewport.GotoTop()
 	}
@@ -465,7 +514,11 @@ func (m StatisticsModel) getFilteredRecords() []*session.SessionRecord {
 }
 
 func (m StatisticsModel) getFilteredStats() *Statistics {
-	return calculateStatistics(m.getFilteredRecords())
+	stats := calculateStatistics(m.getFilteredRecords(), m.config.Streaks)
+	if m.view == ViewAllTime {
+		applyLifetime(stats, m.lifetime)
+	}
+	return stats
 }
 
 func (m StatisticsModel) renderScrollableContent() string {
@@ -492,6 +545,13 @@ func (m StatisticsModel) renderScrollableContent() string {
 
 	if len(filteredStats.ValidSessions) >= 5 {
 		b.WriteString(m.renderTrendChartWithStats(filteredStats))
+		b.WriteString(m.renderTimeOfDay(filteredStats))
+	}
+
+	b.WriteString(m.renderFatigue(filteredStats))
+
+	if m.view == ViewAllTime {
+		b.WriteString(m.renderMonthlyTrend())
 	}
 
 	return b.String()
@@ -533,28 +593,34 @@ func (m StatisticsModel) renderStatistics() string {
 }
 
 func (m StatisticsModel) renderViewSelector() string {
-	s := m.styles
-
-	type item struct {
-		view  StatisticsView
-		label string
-	}
-	items := []item{
-		{ViewSession, "SESSION"},
-		{ViewDaily, "DAILY"},
-		{ViewWeekly, "WEEKLY"},
-		{ViewAllTime, "ALL-TIME"},
-	}
+	return strings.Join(m.viewSelectorParts(), " ")
+}
 
+func (m StatisticsModel) viewSelectorParts() []string {
+	s := m.styles
 	var parts []string
-	for _, it := range items {
-		if m.view == it.view {
-			parts = append(parts, s.viewOn.Render("> "+it.label))
+	for _, tab := range viewTabs {
+		if m.view == tab.view {
+			parts = append(parts, s.viewOn.Render("> "+tab.label))
 		} else {
-			parts = append(parts, s.viewOff.Render("  "+it.label))
+			parts = append(parts, s.viewOff.Render("  "+tab.label))
+		}
+	}
+	return parts
+}
+
+// viewAt is the view whose tab in the selector covers column x
+func (m StatisticsModel) viewAt(x int) (StatisticsView, bool) {
+	left := 0
+	for i, part := range m.viewSelectorParts() {
+		right := left + lipgloss.Width(part)
+		if x >= left && x < right {
+			return viewTabs[i].view, true
 		}
+		// Skip the space between tabs
+		left = right + 1
 	}
-	return strings.Join(parts, " ")
+	return "", false
 }
 
 func (m StatisticsModel) renderStatisticsSummaryWithStats(stats *Statistics) string {
@@ -598,6 +664,12 @@ func (m StatisticsModel) renderStatisticsSummaryWithStats(stats *Statistics) str
 			}
 		}
 		b.WriteString(fmt.Sprintf("  └─ %s %s\n", s.key.Render("Recent avg:"), s.val.Render(recent)))
+		if netWPM := session.AverageNetWPM(stats.ValidSessions); m.percentiles != nil && netWPM > 0 {
+			b.WriteString(fmt.Sprintf("     %s %s\n",
+				s.key.Render("Compared:"),
+				s.val.Render(fmt.Sprintf("%s at %.1f net wpm", m.percentiles.Rank(netWPM), netWPM)),
+			))
+		}
 
 		if stats.RecentValidCountUsed >= minSessionsForVariance && stats.VariancePercent > 0 {
 			varStyle := s.good
@@ -636,7 +708,11 @@ func (m StatisticsModel) renderStatisticsSummaryWithStats(stats *Statistics) str
 	b.WriteString("\n")
 
 	if stats.CurrentStreak > 0 || stats.LongestStreak > 0 {
-		b.WriteString(s.key.Render("Streaks (consecutive days with practice):"))
+		heading := "Streaks (consecutive days with practice):"
+		if policy := streakPolicy(m.config.Streaks); policy != "" {
+			heading = fmt.Sprintf("Streaks (days with practice in a row, %s):", policy)
+		}
+		b.WriteString(s.key.Render(heading))
 		b.WriteString("\n")
 		currentStreakStr := fmt.Sprintf("%d days", stats.CurrentStreak)
 		if stats.CurrentStreak > 0 {
@@ -645,7 +721,12 @@ func (m StatisticsModel) renderStatisticsSummaryWithStats(stats *Statistics) str
 			currentStreakStr = s.subtle.Render(currentStreakStr)
 		}
 		b.WriteString(fmt.Sprintf("  ├─ %s %s\n", s.key.Render("Current:"), currentStreakStr))
-		b.WriteString(fmt.Sprintf("  └─ %s %s\n", s.key.Render("Longest:"), s.val.Render(fmt.Sprintf("%d days", stats.LongestStreak))))
+		if m.config.Streaks.FreezesPerMonth > 0 {
+			b.WriteString(fmt.Sprintf("  ├─ %s %s\n", s.key.Render("Longest:"), s.val.Render(fmt.Sprintf("%d days", stats.LongestStreak))))
+			b.WriteString(fmt.Sprintf("  └─ %s %s\n", s.key.Render("Freezes:"), s.val.Render(fmt.Sprintf("%d of %d left this month", stats.FreezesLeft, m.config.Streaks.FreezesPerMonth))))
+		} else {
+			b.WriteString(fmt.Sprintf("  └─ %s %s\n", s.key.Render("Longest:"), s.val.Render(fmt.Sprintf("%d days", stats.LongestStreak))))
+		}
 		b.WriteString("\n")
 	}
 
@@ -700,6 +781,29 @@ func (m StatisticsModel) renderPerformanceAnalysisWithStats(stats *Statistics) s
 	return b.String()
 }
 
+// streakPolicy describes the rest days and freezes streaks are kept with, or "" when there are none
+func streakPolicy(streaks config.StreaksConfig) string {
+	var parts []string
+	rest := streaks.RestWeekdays()
+	if len(rest) > 0 {
+		var days []string
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if rest[day] {
+				days = append(days, strings.ToLower(day.String()[:3]))
+			}
+		}
+		parts = append(parts, strings.Join(days, ", ")+" off")
+	}
+	switch streaks.FreezesPerMonth {
+	case 0:
+	case 1:
+		parts = append(parts, "1 freeze a month")
+	default:
+		parts = append(parts, fmt.Sprintf("%d freezes a month", streaks.FreezesPerMonth))
+	}
+	return strings.Join(parts, ", ")
+}
+
 func (m StatisticsModel) renderAchievements() string {
 	s := m.styles
 	var b strings.Builder
@@ -716,6 +820,12 @@ func (m StatisticsModel) renderAchievements() string {
 		description string
 	}
 
+	// Streaks kept with rest days or freezes say so, as they are not unbroken runs of days
+	streakRule := ""
+	if policy := streakPolicy(m.config.Streaks); policy != "" {
+		streakRule = " (" + policy + ")"
+	}
+
 	achievements := []ach{
 		{m.stats.TotalSessions >= 1, "[*]", "First Steps", "Complete your first session"},
 		{m.stats.TotalSessions >= 10, "[+]", "Getting Started", "Complete 10 sessions"},
@@ -734,10 +844,10 @@ func (m StatisticsModel) renderAchievements() string {
 		{m.stats.TotalTime >= time.Hour, "[=]", "Time I", "Accumulate 1 hour total typing"},
 		{m.stats.TotalTime >= 24*time.Hour, "[=]", "Time II", "Accumulate 24 hours total typing"},
 
-		{m.stats.CurrentStreak >= 3, "[🔥]", "Streak I", "Maintain a 3-day practice streak"},
-		{m.stats.CurrentStreak >= 7, "[🔥]", "Streak II", "Maintain a 7-day practice streak"},
-		{m.stats.CurrentStreak >= 14, "[🔥]", "Streak III", "Maintain a 14-day practice streak"},
-		{m.stats.LongestStreak >= 30, "[🔥]", "Dedication", "Achieve a 30-day practice streak"},
+		{m.stats.CurrentStreak >= 3, "[🔥]", "Streak I", "Maintain a 3-day practice streak" + streakRule},
+		{m.stats.CurrentStreak >= 7, "[🔥]", "Streak II", "Maintain a 7-day practice streak" + streakRule},
+		{m.stats.CurrentStreak >= 14, "[🔥]", "Streak III", "Maintain a 14-day practice streak" + streakRule},
+		{m.stats.LongestStreak >= 30, "[🔥]", "Dedication", "Achieve a 30-day practice streak" + streakRule},
 
 		{m.stats.VariancePercent > 0 && m.stats.VariancePercent < 10, "[~]", "Consistent", "Maintain <10% WPM variance (recent)"},
 	}
@@ -930,8 +1040,183 @@ func (m StatisticsModel) renderTrendChartWithStats(stats *Statistics) string {
 	return b.String()
 }
 
-func calculateStatistics(records []*session.SessionRecord) *Statistics {
+// renderTimeOfDay charts average WPM by the hour and the weekday sessions started, and says when
+// practice goes best
+func (m StatisticsModel) renderTimeOfDay(stats *Statistics) string {
+	s := m.styles
+	t := session.AnalyzeTimeOfDay(stats.ValidSessions)
+	var b strings.Builder
+
+	b.WriteString(s.section.Render("TIME OF DAY"))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", 79))
+	b.WriteString("\n")
+
+	best := func(label string, fastest, accurate *session.TimeBucket, prefix string) {
+		if fastest == nil {
+			return
+		}
+		b.WriteString(s.good.Render(fmt.Sprintf("+ %s %s%s (%.1f wpm), most accurate %s%s (%.1f%%)", label,
+			prefix, fastest.Label, fastest.AvgWPM, prefix, accurate.Label, accurate.AvgAccuracy)))
+		b.WriteString("\n")
+	}
+	best("Fastest", t.FastestHour, t.MostAccurateHour, "around ")
+	best("Fastest", t.FastestWeekday, t.MostAccurateWeekday, "on ")
+	if t.FastestHour == nil && t.FastestWeekday == nil {
+		b.WriteString(s.subtle.Render("Practise at a few different times to see when you type best."))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	m.writeTimeBuckets(&b, t.Hours)
+	b.WriteString("\n")
+	m.writeTimeBuckets(&b, t.Weekdays)
+	b.WriteString("\n")
+	return b.String()
+}
+
+// fatigueTrendPercent is how far the latest sessions' slowdown must move from the earlier ones' to
+// count as a trend
+const fatigueTrendPercent = 2.0
+
+// renderFatigue reports how much sessions slow down in their final third, and whether that is
+// improving, or "" when no session in the view measured it
+func (m StatisticsModel) renderFatigue(stats *Statistics) string {
+	trend := session.AnalyzeFatigue(stats.ValidSessions)
+	if trend.Sessions == 0 {
+		return ""
+	}
+	s := m.styles
+	var b strings.Builder
+
+	b.WriteString(s.section.Render("FATIGUE (FINAL THIRD VS FIRST)"))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", 79))
+	b.WriteString("\n")
+
+	b.WriteString(fmt.Sprintf("%s %s\n", s.key.Render("Average slowdown:"),
+		s.val.Render(fmt.Sprintf("%.1f%% over %d sessions", trend.Average, trend.Sessions))))
+	if trend.RecentSessions > 0 {
+		latest := fmt.Sprintf("Latest %d sessions: %.1f%%", trend.RecentSessions, trend.Recent)
+		switch {
+		case trend.Recent <= trend.Earlier-fatigueTrendPercent:
+			b.WriteString(s.good.Render(fmt.Sprintf("+ %s, down from %.1f%% before: your endurance is building", latest, trend.Earlier)))
+		case trend.Recent >= trend.Earlier+fatigueTrendPercent:
+			b.WriteString(s.bad.Render(fmt.Sprintf("! %s, up from %.1f%% before: rest between runs, or build up to longer ones", latest, trend.Earlier)))
+		default:
+			b.WriteString(s.subtle.Render(latest + ", about the same as before"))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// writeTimeBuckets draws a WPM bar for each bucket holding sessions, scaled to the fastest
+func (m StatisticsModel) writeTimeBuckets(b *strings.Builder, buckets []session.TimeBucket) {
+	s := m.styles
+	peak := 0.0
+	for _, bucket := range buckets {
+		peak = math.Max(peak, bucket.AvgWPM)
+	}
+	const barMax = 30
+	for _, bucket := range buckets {
+		if bucket.Sessions == 0 {
+			continue
+		}
+		barLen := max(int(math.Round(bucket.AvgWPM/math.Max(peak, 1)*barMax)), 1)
+		b.WriteString(fmt.Sprintf("%-5s | %-30s %5.1f wpm %5.1f%%  %s\n", bucket.Label, strings.Repeat("█", barLen),
+			bucket.AvgWPM, bucket.AvgAccuracy, s.subtle.Render(fmt.Sprintf("(%d sessions)", bucket.Sessions))))
+	}
+}
+
+// monthlyTrendMonths caps how many months the lifetime trend shows
+const monthlyTrendMonths = 12
+
+// renderMonthlyTrend charts average WPM per month from the daily rollups, which reach back past pruned records
+func (m StatisticsModel) renderMonthlyTrend() string {
+	type month struct {
+		label    string
+		wpmSum   float64
+		sessions int
+		finished int
+	}
+	var months []month
+	for _, d := range m.lifetime {
+		label := d.Date[:7]
+		if len(months) == 0 || months[len(months)-1].label != label {
+			months = append(months, month{label: label})
+		}
+		months[len(months)-1].wpmSum += d.WPMSum
+		months[len(months)-1].sessions += d.Sessions
+		months[len(months)-1].finished += d.Finished
+	}
+	if len(months) < 2 {
+		return ""
+	}
+	if len(months) > monthlyTrendMonths {
+		months = months[len(months)-monthlyTrendMonths:]
+	}
+
+	s := m.styles
+	var b strings.Builder
+	b.WriteString(s.section.Render("LIFETIME TREND (MONTHLY AVERAGE WPM)"))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", 79))
+	b.WriteString("\n")
+
+	peak := 0.0
+	for _, mo := range months {
+		if mo.finished > 0 {
+			peak = math.Max(peak, mo.wpmSum/float64(mo.finished))
+		}
+	}
+	const barMax = 40
+	for _, mo := range months {
+		avg := 0.0
+		if mo.finished > 0 {
+			avg = mo.wpmSum / float64(mo.finished)
+		}
+		barLen := max(int(math.Round(avg/math.Max(peak, 1)*barMax)), 1)
+		b.WriteString(fmt.Sprintf("%s | %-40s %.1f wpm  %s\n", mo.label, strings.Repeat("█", barLen), avg,
+			s.subtle.Render(fmt.Sprintf("(%d sessions)", mo.sessions))))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// applyLifetime replaces the all-time totals with the lifetime rollups when they hold sessions the raw records no longer do
+func applyLifetime(stats *Statistics, days []session.DailyRollup) {
+	var total session.DailyRollup
+	for _, d := range days {
+		total.Sessions += d.Sessions
+		total.Finished += d.Finished
+		total.DurationMs += d.DurationMs
+		total.Mistakes += d.Mistakes
+		total.WPMSum += d.WPMSum
+		total.AccuracySum += d.AccuracySum
+		total.PeakWPM = math.Max(total.PeakWPM, d.PeakWPM)
+		total.BestAccuracy = math.Max(total.BestAccuracy, d.BestAccuracy)
+	}
+	if total.Sessions <= stats.TotalSessions {
+		return
+	}
+
+	stats.TotalSessions = total.Sessions
+	stats.TotalTime = time.Duration(total.DurationMs) * time.Millisecond
+	if total.Finished == 0 {
+		return
+	}
+	stats.RawAvgWPM = total.AvgWPM()
+	stats.RawAvgAccuracy = total.AccuracySum / float64(total.Finished)
+	stats.AvgMistakes = float64(total.Mistakes) / float64(total.Finished)
+	stats.RawPeakWPM = math.Max(stats.RawPeakWPM, total.PeakWPM)
+	stats.RawBestAccuracy = math.Max(stats.RawBestAccuracy, total.BestAccuracy)
+}
+
+func calculateStatistics(records []*session.SessionRecord, streaks config.StreaksConfig) *Statistics {
 	stats := &Statistics{}
+	records = session.CountedRecords(records)
 	totalSessions := len(records)
 	if totalSessions == 0 {
 		return stats
@@ -939,7 +1224,12 @@ func calculateStatistics(records []*session.SessionRecord) *Statistics {
 
 	calculateBasicStats(records, stats)
 
-	valid := filterValidSessions(records)
+	// Sessions cut short still count as practice towards a streak, but not towards speed
+	practised := filterValidSessions(records)
+	streak := session.CalculateStreaks(practised, streaks, time.Now())
+	stats.CurrentStreak, stats.LongestStreak, stats.FreezesLeft = streak.Current, streak.Longest, streak.FreezesLeft
+
+	valid := session.FinishedRecords(practised)
 	stats.ValidSessions = valid
 	stats.OutlierCount = totalSessions - len(valid)
 
@@ -953,16 +1243,26 @@ func calculateStatistics(records []*session.SessionRecord) *Statistics {
 
 	calculateImprovementRate(valid, stats)
 
-	stats.CurrentStreak, stats.LongestStreak = session.CalculateStreaks(valid)
-
 	return stats
 }
 
+// calculateBasicStats totals the time practised over every session, and the averages and bests over
+// those that were finished
 func calculateBasicStats(records []*session.SessionRecord, stats *Statistics) {
-	totalSessions := len(records)
+	var totalDurationMs int64
+	for _, r := range records {
+		totalDurationMs += r.DurationMs
+	}
+	stats.TotalSessions = len(records)
+	stats.TotalTime = time.Duration(totalDurationMs) * time.Millisecond
+
+	records = session.FinishedRecords(records)
+	finishedSessions := len(records)
+	if finishedSessions == 0 {
+		return
+	}
 	var totalWPM, totalAccuracy float64
 	var totalMistakes int
-	var totalDurationMs int64
 	var totalBackspaces int
 	var totalCorrectedErrors, totalUncorrectedErrors int
 
@@ -970,7 +1270,6 @@ func calculateBasicStats(records []*session.SessionRecord, stats *Statistics) {
 		totalWPM += r.WPM
 		totalAccuracy += r.Accuracy
 		totalMistakes += r.Mistakes
-		totalDurationMs += r.DurationMs
 		totalBackspaces += r.BackspaceCount
 		totalCorrectedErrors += r.CorrectedErrors
 		totalUncorrectedErrors += r.UncorrectedErrors
@@ -983,14 +1282,12 @@ func calculateBasicStats(records []*session.SessionRecord, stats *Statistics) {
 		}
 	}
 
-	stats.TotalSessions = totalSessions
-	stats.TotalTime = time.Duration(totalDurationMs) * time.Millisecond
-	stats.RawAvgWPM = totalWPM / float64(totalSessions)
-	stats.RawAvgAccuracy = totalAccuracy / float64(totalSessions)
-	stats.AvgMistakes = float64(totalMistakes) / float64(totalSessions)
-	stats.BackspaceRate = float64(totalBackspaces) / float64(totalSessions)
-	stats.AvgCorrectedErrors = float64(totalCorrectedErrors) / float64(totalSessions)
-	stats.AvgUncorrectedErrors = float64(totalUncorrectedErrors) / float64(totalSessions)
+	stats.RawAvgWPM = totalWPM / float64(finishedSessions)
+	stats.RawAvgAccuracy = totalAccuracy / float64(finishedSessions)
+	stats.AvgMistakes = float64(totalMistakes) / float64(finishedSessions)
+	stats.BackspaceRate = float64(totalBackspaces) / float64(finishedSessions)
+	stats.AvgCorrectedErrors = float64(totalCorrectedErrors) / float64(finishedSessions)
+	stats.AvgUncorrectedErrors = float64(totalUncorrectedErrors) / float64(finishedSessions)
 }
 
 func filterValidSessions(records []*session.SessionRecord) []*session.SessionRecord {
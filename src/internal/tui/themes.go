@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"gti/src/internal/config"
+	"gti/src/internal/keymap"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// galleryTyped, galleryMistake, galleryCurrent and galleryPending make up the sample line each theme is previewed on:
+// text already typed, one mistyped character, the cursor, and text still to come
+const (
+	galleryTyped   = "the quick brown "
+	galleryMistake = "f"
+	galleryCurrent = "o"
+	galleryPending = "x jumps over the lazy dog"
+	// galleryNameWidth fits the longest theme names
+	galleryNameWidth = 22
+	// galleryListRow is the screen row of the first listed theme, below the title
+	galleryListRow = 2
+)
+
+// GalleryTheme is one theme offered by the gallery
+type GalleryTheme struct {
+	Name   string
+	Colors config.ThemeColorsConfig
+	// Imported marks a theme from the user's themes folder rather than a built-in one
+	Imported bool
+}
+
+// ThemeGalleryModel lists the themes, each applied to a sample typing line, and returns the one picked with Enter
+type ThemeGalleryModel struct {
+	config *config.Config
+	keys   *keymap.Bindings
+	themes []GalleryTheme
+	cursor int
+	offset int
+	// Chosen is the theme applied with Enter, "" when the gallery was left without choosing
+	Chosen string
+	width  int
+	height int
+}
+
+func NewThemeGalleryModel(cfg *config.Config, themes []GalleryTheme) ThemeGalleryModel {
+	m := ThemeGalleryModel{
+		config: cfg,
+		keys:   keymap.NewBindings(cfg.Keybindings),
+		themes: themes,
+	}
+	// Open on the active theme
+	for i, t := range themes {
+		if t.Name == cfg.Theme.Active {
+			m.cursor = i
+		}
+	}
+	return m
+}
+
+func (m ThemeGalleryModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m ThemeGalleryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.move(0)
+	case tea.KeyMsg:
+		switch {
+		case m.keys.Is(msg, keymap.ActionForceQuit), m.keys.Is(msg, keymap.ActionStatsQuit), m.keys.Is(msg, keymap.ActionBack):
+			return m, tea.Quit
+		case m.keys.Is(msg, keymap.ActionNext):
+			if len(m.themes) > 0 {
+				m.Chosen = m.themes[m.cursor].Name
+			}
+			return m, tea.Quit
+		case m.keys.Is(msg, keymap.ActionStatsUp):
+			m.move(-1)
+		case m.keys.Is(msg, keymap.ActionStatsDown):
+			m.move(1)
+		case m.keys.Is(msg, keymap.ActionPageUp):
+			m.move(-m.visibleRows())
+		case m.keys.Is(msg, keymap.ActionPageDown):
+			m.move(m.visibleRows())
+		}
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress {
+			break
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.move(-1)
+		case tea.MouseButtonWheelDown:
+			m.move(1)
+		case tea.MouseButtonLeft:
+			// A click highlights a theme to preview; a click on the highlighted one applies it
+			i := m.offset + msg.Y - galleryListRow
+			if msg.Y < galleryListRow || i >= min(m.offset+m.visibleRows(), len(m.themes)) {
+				break
+			}
+			if i == m.cursor {
+				m.Chosen = m.themes[i].Name
+				return m, tea.Quit
+			}
+			m.move(i - m.cursor)
+		}
+	}
+	return m, nil
+}
+
+// move shifts the cursor, scrolling the list to keep it in view
+func (m *ThemeGalleryModel) move(delta int) {
+	m.cursor = max(0, min(len(m.themes)-1, m.cursor+delta))
+	rows := m.visibleRows()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+}
+
+// visibleRows is how many themes fit between the header and the preview panel
+func (m ThemeGalleryModel) visibleRows() int {
+	return max(m.height-12, 3)
+}
+
+func (m ThemeGalleryModel) View() string {
+	if len(m.themes) == 0 {
+		return "No themes found.\n"
+	}
+	colors := m.config.Theme.Colors
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(colors.Accent))
+	subtle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.TextSecondary))
+
+	var b strings.Builder
+	b.WriteString(title.Render("THEME GALLERY"))
+	b.WriteString(subtle.Render(fmt.Sprintf("  %d themes, active: %s", len(m.themes), m.config.Theme.Active)))
+	b.WriteString("\n\n")
+
+	end := min(m.offset+m.visibleRows(), len(m.themes))
+	for i := m.offset; i < end; i++ {
+		t := m.themes[i]
+		marker := "  "
+		if i == m.cursor {
+			marker = title.Render("> ")
+		}
+		name := t.Name
+		if t.Imported {
+			name += " *"
+		}
+		if t.Name == m.config.Theme.Active {
+			name += " (active)"
+		}
+		b.WriteString(marker + fmt.Sprintf("%-*s", galleryNameWidth, name) + " " + renderThemeSample(t.Colors) + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(renderThemeSwatches(m.themes[m.cursor]))
+	b.WriteString("\n\n")
+	b.WriteString(subtle.Render(fmt.Sprintf("[%s/%s] Move   [%s] Apply   [%s] Cancel   * imported",
+		m.keys.Label(keymap.ActionStatsUp), m.keys.Label(keymap.ActionStatsDown),
+		m.keys.Label(keymap.ActionNext), m.keys.Label(keymap.ActionBack))))
+	return b.String()
+}
+
+// renderThemeSample draws the sample typing line in the theme's own colors, as a session would
+func renderThemeSample(c config.ThemeColorsConfig) string {
+	bg := lipgloss.Color(c.Background)
+	style := func(fg string) lipgloss.Style {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(fg)).Background(bg)
+	}
+	return style(c.TextPrimary).Render(" ") +
+		style(c.Correct).Render(galleryTyped) +
+		style(c.Incorrect).Underline(true).Render(galleryMistake) +
+		lipgloss.NewStyle().Foreground(bg).Background(lipgloss.Color(c.Current)).Render(galleryCurrent) +
+		style(c.Pending).Render(galleryPending) +
+		style(c.TextPrimary).Render(" ")
+}
+
+// renderThemeSwatches shows every color of the highlighted theme by name
+func renderThemeSwatches(t GalleryTheme) string {
+	c := t.Colors
+	swatches := []struct{ label, color string }{
+		{"background", c.Background}, {"text", c.TextPrimary}, {"secondary", c.TextSecondary},
+		{"correct", c.Correct}, {"incorrect", c.Incorrect}, {"current", c.Current},
+		{"pending", c.Pending}, {"highlight", c.WordHighlight}, {"accent", c.Accent},
+		{"border", c.Border}, {"status bar", c.StatusBar},
+	}
+
+	var lines []string
+	var line []string
+	for i, s := range swatches {
+		block := lipgloss.NewStyle().Background(lipgloss.Color(s.color)).Render("  ")
+		line = append(line, fmt.Sprintf("%s %-10s %-7s", block, s.label, s.color))
+		if len(line) == 4 || i == len(swatches)-1 {
+			lines = append(lines, "  "+strings.Join(line, "  "))
+			line = nil
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,271 @@
+// Package vault encrypts the session history and exports for anyone who shares a machine. Data is
+// sealed to a public key, so sessions are saved without asking for anything, and only reading them
+// back needs the passphrase that unlocks the private key.
+package vault
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/x/term"
+	"gti/src/internal/config"
+)
+
+// sealedPrefix starts every sealed line, so sealed and plain lines can share a file
+const sealedPrefix = "gti-sealed:"
+
+// iterations is the PBKDF2 work factor for turning the passphrase into a key
+const iterations = 600000
+
+// PassphraseEnv names the environment variable read before asking for the passphrase
+const PassphraseEnv = "GTI_PASSPHRASE"
+
+// keyFile holds the public key in the clear and the private key sealed with the passphrase
+type keyFile struct {
+	Public     []byte `json:"public"`
+	Salt       []byte `json:"salt"`
+	Iterations int    `json:"iterations"`
+	Private    []byte `json:"private"`
+}
+
+// unlocked is the private key once the passphrase has been given, kept for the rest of the run
+var unlocked *ecdh.PrivateKey
+
+// KeyPath is where the key is kept, beside the history file
+func KeyPath(cfg *config.Config) string {
+	return filepath.Join(filepath.Dir(config.ExpandPath(cfg.History.File)), "history.key")
+}
+
+// HasKey reports whether a key has been made
+func HasKey(cfg *config.Config) bool {
+	_, err := os.Stat(KeyPath(cfg))
+	return err == nil
+}
+
+// Create makes a new key whose private half the passphrase unlocks. It will not replace a key,
+// since whatever was sealed to it could then never be read again.
+func Create(cfg *config.Config, passphrase string) error {
+	if HasKey(cfg) {
+		return fmt.Errorf("a key already exists at %s", KeyPath(cfg))
+	}
+	private, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	kf := keyFile{Public: private.PublicKey().Bytes(), Salt: make([]byte, 16), Iterations: iterations}
+	rand.Read(kf.Salt)
+	aead, err := passphraseCipher(passphrase, kf.Salt, kf.Iterations)
+	if err != nil {
+		return err
+	}
+	kf.Private = seal(aead, private.Bytes(), nil)
+
+	if err := config.EnsureDir(filepath.Dir(KeyPath(cfg))); err != nil {
+		return err
+	}
+	if err := config.SaveJSONData(KeyPath(cfg), kf); err != nil {
+		return err
+	}
+	unlocked = private
+	return nil
+}
+
+func loadKey(cfg *config.Config) (*keyFile, error) {
+	if !HasKey(cfg) {
+		return nil, fmt.Errorf("no encryption key at %s, run 'gti encrypt' to make one", KeyPath(cfg))
+	}
+	kf := &keyFile{}
+	if err := config.LoadJSONData(KeyPath(cfg), kf); err != nil {
+		return nil, fmt.Errorf("the key at %s is damaged: %w", KeyPath(cfg), err)
+	}
+	return kf, nil
+}
+
+// Unlock opens the private key with the passphrase from GTI_PASSPHRASE, or asks for it on the terminal
+func Unlock(cfg *config.Config) error {
+	if unlocked != nil {
+		return nil
+	}
+	kf, err := loadKey(cfg)
+	if err != nil {
+		return err
+	}
+	passphrase, err := Passphrase("Passphrase for the session history: ")
+	if err != nil {
+		return err
+	}
+	aead, err := passphraseCipher(passphrase, kf.Salt, kf.Iterations)
+	if err != nil {
+		return err
+	}
+	raw, err := open(aead, kf.Private, nil)
+	if err != nil {
+		return fmt.Errorf("wrong passphrase for the session history")
+	}
+	private, err := ecdh.X25519().NewPrivateKey(raw)
+	if err != nil {
+		return err
+	}
+	unlocked = private
+	return nil
+}
+
+// Unlocked reports whether the passphrase has been given in this run
+func Unlocked() bool {
+	return unlocked != nil
+}
+
+// Passphrase returns GTI_PASSPHRASE, or asks for the passphrase on the terminal without echoing it
+func Passphrase(prompt string) (string, error) {
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return "", fmt.Errorf("a passphrase is needed: set %s or run gti in a terminal", PassphraseEnv)
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if len(passphrase) == 0 {
+		return "", fmt.Errorf("no passphrase given")
+	}
+	return string(passphrase), nil
+}
+
+// IsSealed reports whether data is sealed
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(sealedPrefix))
+}
+
+// Seal encrypts data to the public key as a single line, under a key of its own agreed with a
+// one-off key pair, so sealing never needs the passphrase
+func Seal(cfg *config.Config, data []byte) ([]byte, error) {
+	kf, err := loadKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	recipient, err := ecdh.X25519().NewPublicKey(kf.Public)
+	if err != nil {
+		return nil, err
+	}
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := sharedCipher(ephemeral, recipient, ephemeral.PublicKey().Bytes(), kf.Public)
+	if err != nil {
+		return nil, err
+	}
+	sealed := append(ephemeral.PublicKey().Bytes(), seal(aead, data, nil)...)
+	return []byte(sealedPrefix + base64.StdEncoding.EncodeToString(sealed)), nil
+}
+
+// Open decrypts a line made by Seal, asking for the passphrase the first time
+func Open(cfg *config.Config, line []byte) ([]byte, error) {
+	if !IsSealed(line) {
+		return nil, fmt.Errorf("not sealed by gti")
+	}
+	if err := Unlock(cfg); err != nil {
+		return nil, err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(line[len(sealedPrefix):])))
+	if err != nil || len(sealed) < 32 {
+		return nil, fmt.Errorf("damaged sealed data")
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(sealed[:32])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := sharedCipher(unlocked, ephemeral, sealed[:32], unlocked.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	data, err := open(aead, sealed[32:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("sealed with another key")
+	}
+	return data, nil
+}
+
+// WriteFile writes data to path, sealed when the history is encrypted
+func WriteFile(cfg *config.Config, path string, data []byte) error {
+	if cfg.History.Encrypt {
+		sealed, err := Seal(cfg, data)
+		if err != nil {
+			return err
+		}
+		data = append(sealed, '\n')
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ReadFile reads a file written by WriteFile, sealed or not
+func ReadFile(cfg *config.Config, path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || !IsSealed(data) {
+		return data, err
+	}
+	return Open(cfg, data)
+}
+
+// sharedCipher is the cipher for one sealed item, keyed from the secret its one-off key pair shares
+// with the recipient, salted with both public keys
+func sharedCipher(private *ecdh.PrivateKey, public *ecdh.PublicKey, ephemeral, recipient []byte) (cipher.AEAD, error) {
+	shared, err := private.ECDH(public)
+	if err != nil {
+		return nil, err
+	}
+	salt := append(append([]byte(nil), ephemeral...), recipient...)
+	key, err := hkdf.Key(sha256.New, shared, salt, "gti sealed data", 32)
+	if err != nil {
+		return nil, err
+	}
+	return newGCM(key)
+}
+
+func passphraseCipher(passphrase string, salt []byte, iter int) (cipher.AEAD, error) {
+	if iter <= 0 {
+		return nil, errors.New("damaged key: no work factor")
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iter, 32)
+	if err != nil {
+		return nil, err
+	}
+	return newGCM(key)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts data under a fresh nonce, which leads the result
+func seal(aead cipher.AEAD, data, extra []byte) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	rand.Read(nonce)
+	return aead.Seal(nonce, nonce, data, extra)
+}
+
+func open(aead cipher.AEAD, sealed, extra []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("too short")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], extra)
+}
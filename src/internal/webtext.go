@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// minReadableWords is the fewest words a block of a web page needs to count as prose;
+// shorter ones are mostly menus, buttons, bylines and share links
+const minReadableWords = 6
+
+var (
+	htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+	// pageContent is the main part of a page, when the page marks it
+	pageContent = regexp.MustCompile(`(?is)<(?:article|main)\b[^>]*>(.*)</(?:article|main)\s*>`)
+	blockTag    = regexp.MustCompile(`(?i)</?(?:p|div|h[1-6]|li|ul|ol|dl|dt|dd|br|hr|tr|td|th|table|blockquote|pre|section|article|main|figure|figcaption)\b[^>]*>`)
+	anyTag      = regexp.MustCompile(`(?s)<[^>]*>`)
+	blockBreak  = regexp.MustCompile(`\n\s*\n`)
+)
+
+// skippedElements are dropped with everything inside them: code and styling, and the navigation and furniture around the content
+var skippedElements = func() []*regexp.Regexp {
+	var patterns []*regexp.Regexp
+	for _, tag := range []string{"head", "script", "style", "noscript", "template", "svg", "iframe",
+		"nav", "header", "footer", "aside", "form", "button", "select"} {
+		patterns = append(patterns, regexp.MustCompile(`(?is)<`+tag+`\b[^>]*>.*?</`+tag+`\s*>`))
+	}
+	return patterns
+}()
+
+// ExtractReadableText pulls the prose out of an HTML page, one paragraph per line with a blank line
+// between them. Scripts, styles and page furniture are dropped, the article or main element is
+// preferred when the page has one, and short or repeated blocks are left out as boilerplate.
+func ExtractReadableText(page string) string {
+	var paragraphs []string
+	seen := make(map[string]bool)
+	for _, paragraph := range htmlBlocks(page) {
+		if len(strings.Fields(paragraph)) < minReadableWords || seen[paragraph] {
+			continue
+		}
+		seen[paragraph] = true
+		paragraphs = append(paragraphs, paragraph)
+	}
+	return NormalizeText(strings.Join(paragraphs, "\n\n"))
+}
+
+// ExtractBookText is ExtractReadableText for a chapter of an e-book, where every block is part of the
+// text: short lines of dialogue and repeated scene breaks are kept
+func ExtractBookText(page string) string {
+	return NormalizeText(strings.Join(htmlBlocks(page), "\n\n"))
+}
+
+// htmlBlocks returns the text of each block element of page on one line, dropping the elements that hold no prose
+func htmlBlocks(page string) []string {
+	page = htmlComment.ReplaceAllString(page, "")
+	for _, skipped := range skippedElements {
+		page = skipped.ReplaceAllString(page, "")
+	}
+	if m := pageContent.FindStringSubmatch(page); m != nil {
+		page = m[1]
+	}
+	page = blockTag.ReplaceAllString(page, "\n\n")
+	// Inline elements run on into the text around them, as a browser shows them
+	page = anyTag.ReplaceAllString(page, "")
+
+	var blocks []string
+	for _, block := range blockBreak.Split(page, -1) {
+		if words := strings.Fields(html.UnescapeString(block)); len(words) > 0 {
+			blocks = append(blocks, strings.Join(words, " "))
+		}
+	}
+	return blocks
+}